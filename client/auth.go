@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
+)
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type refreshTokenRequest struct {
+	Value string `json:"value"`
+}
+
+type refreshTokenResponse struct {
+	AccessToken            string    `json:"access_token"`
+	AccessTokenExpiryDate  time.Time `json:"access_token_expiry_date"`
+	RefreshToken           string    `json:"refresh_token"`
+	RefreshTokenExpiryDate time.Time `json:"refresh_token_expiry_date"`
+}
+
+// Login function authenticates the user and saves the returned tokens so the
+// following requests are authenticated. If the user must complete two-factor
+// authentication or verify their email then no tokens are saved and the
+// caller must inspect the returned response.
+func (c *Client) Login(ctx context.Context, email string, password string) (*gateway_s.LoginResponseIDO, error) {
+	var res gateway_s.LoginResponseIDO
+	body, err := json.Marshal(&loginRequest{Email: email, Password: password})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/login", nil, "application/json", body, &res, false); err != nil {
+		return nil, err
+	}
+	if res.AccessToken != "" {
+		c.SetTokens(res.AccessToken, res.AccessTokenExpiryTime, res.RefreshToken, res.RefreshTokenExpiryTime)
+	}
+	return &res, nil
+}
+
+// SetTokens function saves the tokens of a previous login, ex: when the
+// tokens were persisted by the caller between runs.
+func (c *Client) SetTokens(accessToken string, accessTokenExpiresAt time.Time, refreshToken string, refreshTokenExpiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = accessToken
+	c.accessTokenExpiresAt = accessTokenExpiresAt
+	c.refreshToken = refreshToken
+	c.refreshTokenExpiresAt = refreshTokenExpiresAt
+}
+
+// Tokens function returns the current access and refresh tokens so the
+// caller may persist them.
+func (c *Client) Tokens() (accessToken string, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accessToken, c.refreshToken
+}
+
+// Refresh function renews the access token using the refresh token. The
+// refresh tokens are rotated by the server so the new pair is saved.
+func (c *Client) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshLocked(ctx)
+}
+
+// Logout function revokes the session on the server and forgets the tokens.
+func (c *Client) Logout(ctx context.Context) error {
+	err := c.doJSON(ctx, http.MethodPost, "/api/v1/logout", nil, nil, nil)
+	c.SetTokens("", time.Time{}, "", time.Time{})
+	return err
+}
+
+func (c *Client) refreshLocked(ctx context.Context) error {
+	if c.refreshToken == "" || (!c.refreshTokenExpiresAt.IsZero() && c.now().After(c.refreshTokenExpiresAt)) {
+		return ErrNotAuthenticated
+	}
+	body, err := json.Marshal(&refreshTokenRequest{Value: c.refreshToken})
+	if err != nil {
+		return err
+	}
+	var res refreshTokenResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/refresh-token", nil, "application/json", body, &res, false); err != nil {
+		return err
+	}
+	c.accessToken = res.AccessToken
+	c.accessTokenExpiresAt = res.AccessTokenExpiryDate
+	c.refreshToken = res.RefreshToken
+	c.refreshTokenExpiresAt = res.RefreshTokenExpiryDate
+	return nil
+}
+
+// authorization function returns the `Authorization` header of the request,
+// renewing the access token if it expires soon or if the server rejected the
+// `rejected` header. Concurrent requests rejected with the same token only
+// renew it once.
+func (c *Client) authorization(ctx context.Context, rejected string) (string, error) {
+	if c.APIKey != "" {
+		return "Api-Key " + c.APIKey, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accessToken == "" && c.refreshToken == "" {
+		return "", ErrNotAuthenticated
+	}
+	expiresSoon := !c.accessTokenExpiresAt.IsZero() && c.now().Add(c.RefreshLeeway).After(c.accessTokenExpiresAt)
+	if rejected == "JWT "+c.accessToken || c.accessToken == "" || expiresSoon {
+		if err := c.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return "JWT " + c.accessToken, nil
+}
+
+// canRefresh function returns true if a rejected request may be retried
+// with a renewed access token.
+func (c *Client) canRefresh() bool {
+	if c.APIKey != "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshToken != ""
+}
@@ -0,0 +1,240 @@
+// Package client provides a typed Go client for the Data Boutique API. The
+// responses are decoded into the same datastore types our handlers encode so
+// the client always stays in sync with the server.
+//
+// Example:
+//
+//	c := client.New("https://api.databoutique.ca")
+//	if _, err := c.Login(ctx, "frank@bmika.com", "password"); err != nil {
+//		return err
+//	}
+//	exec, err := c.CreateExecutable(ctx, &executable_c.ExecutableCreateRequestIDO{...})
+//	if err != nil {
+//		return err
+//	}
+//	exec, err = c.WaitForExecutable(ctx, exec.ID)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+// ErrNotAuthenticated is returned when an authenticated endpoint is called
+// before `Login`, `SetTokens` or without an API key.
+var ErrNotAuthenticated = errors.New("client is not authenticated")
+
+// APIError represents an unsuccessful response returned by the server.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response, ex: `400`.
+	StatusCode int
+
+	// Code is the machine-readable code of the error, ex: `validation_error`.
+	// It is empty if the server did not return our JSON error envelope.
+	Code string
+
+	// Message is the human-readable message of the error.
+	Message string
+
+	// Fields contains the field name and error message pairs returned by our
+	// validation, ex: `{"email": "missing value"}`.
+	Fields map[string]string
+
+	// RequestID is the ID of the request which failed, include it when
+	// reporting an issue so it can be found in the server logs.
+	RequestID string
+
+	// Body is the raw body of the response.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("databoutique: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), strings.TrimSpace(e.Body))
+}
+
+// Client is a Data Boutique API client. It is safe for concurrent use.
+type Client struct {
+	// BaseURL is the address of the server, ex: `https://api.databoutique.ca`.
+	BaseURL string
+
+	// HTTPClient is used to make the requests, defaults to a client with a
+	// 60 second timeout.
+	HTTPClient *http.Client
+
+	// APIKey is used to authenticate the requests instead of the access token
+	// returned by `Login`. API keys are never renewed.
+	APIKey string
+
+	// RefreshLeeway is how long before the access token expires that the
+	// client renews it, defaults to one minute.
+	RefreshLeeway time.Duration
+
+	// now returns the current time and is replaced by our tests.
+	now func() time.Time
+
+	mu                    sync.Mutex
+	accessToken           string
+	accessTokenExpiresAt  time.Time
+	refreshToken          string
+	refreshTokenExpiresAt time.Time
+}
+
+// New constructor returns the client for the server at `baseURL`.
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		HTTPClient:    &http.Client{Timeout: 60 * time.Second},
+		RefreshLeeway: time.Minute,
+		now:           time.Now,
+	}
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey function returns the context which sends the
+// `Idempotency-Key` header with the `POST` requests, so a request retried
+// with the same key and body, ex: after a timeout, is only processed once by
+// the server.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+type ifMatchContextKey struct{}
+
+// withVersion function returns the context which sends the version of the
+// record being updated in the `If-Match` header, so the server rejects the
+// update with a `412` error if somebody else modified the record since.
+func withVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, ifMatchContextKey{}, versioning.ETag(version))
+}
+
+// ListOptions are the pagination, sorting and search parameters supported by
+// our list endpoints. Zero values are not sent so the server defaults apply.
+type ListOptions struct {
+	Cursor    string
+	PageSize  int64
+	SortField string
+	SortOrder int8 // 1=ascending | -1=descending
+	Search    string
+}
+
+func (o *ListOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	if o.Cursor != "" {
+		v.Set("cursor", o.Cursor)
+	}
+	if o.PageSize != 0 {
+		v.Set("page_size", strconv.FormatInt(o.PageSize, 10))
+	}
+	if o.SortField != "" {
+		v.Set("sort_field", o.SortField)
+	}
+	if o.SortOrder != 0 {
+		v.Set("sort_order", strconv.Itoa(int(o.SortOrder)))
+	}
+	if o.Search != "" {
+		v.Set("search", o.Search)
+	}
+	return v
+}
+
+// doJSON function sends the JSON encoded `in` (if not nil) to the endpoint
+// and decodes the response into `out` (if not nil).
+func (c *Client) doJSON(ctx context.Context, method string, path string, query url.Values, in any, out any) error {
+	var body []byte
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+	return c.do(ctx, method, path, query, "application/json", body, out, true)
+}
+
+// do function sends the request and decodes the response. If the request is
+// authenticated then the access token is renewed beforehand when it is about
+// to expire, and once more if the server rejected it as expired.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, contentType string, body []byte, out any, authenticated bool) error {
+	var rejected string
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if len(query) > 0 {
+			req.URL.RawQuery = query.Encode()
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req.Header.Set("Accept", "application/json")
+		if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" && method == http.MethodPost {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		if etag, ok := ctx.Value(ifMatchContextKey{}).(string); ok && method == http.MethodPut {
+			req.Header.Set("If-Match", etag)
+		}
+
+		if authenticated {
+			authorization, err := c.authorization(ctx, rejected)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Authorization", authorization)
+		}
+
+		res, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+		b, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if res.StatusCode == http.StatusUnauthorized && authenticated && attempt == 0 && c.canRefresh() {
+			rejected = req.Header.Get("Authorization")
+			continue
+		}
+		if res.StatusCode >= 300 {
+			return newAPIError(res.StatusCode, b)
+		}
+		if out == nil || len(bytes.TrimSpace(b)) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(b, out); err != nil {
+			return fmt.Errorf("databoutique: failed decoding response of %s %s: %w", method, path, err)
+		}
+		return nil
+	}
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{StatusCode: statusCode, Body: string(body)}
+	var envelope httperror.ErrorResponse
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		e.Code = envelope.Code
+		e.Message = envelope.Message
+		e.Fields = envelope.Fields
+		e.RequestID = envelope.RequestID
+	}
+	return e
+}
@@ -0,0 +1,461 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	executable_c "github.com/bartmika/databoutique-backend/internal/app/executable/controller"
+	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+	executable "github.com/bartmika/databoutique-backend/internal/app/executable/httptransport"
+	gateway_c "github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
+	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
+	gateway "github.com/bartmika/databoutique-backend/internal/app/gateway/httptransport"
+	program_c "github.com/bartmika/databoutique-backend/internal/app/program/controller"
+	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
+	program "github.com/bartmika/databoutique-backend/internal/app/program/httptransport"
+	tenant_c "github.com/bartmika/databoutique-backend/internal/app/tenant/controller"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	tenant "github.com/bartmika/databoutique-backend/internal/app/tenant/httptransport"
+	uploaddirectory_c "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/controller"
+	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
+	uploaddirectory "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/httptransport"
+	uploadfile_c "github.com/bartmika/databoutique-backend/internal/app/uploadfile/controller"
+	uploadfile_s "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
+	uploadfile "github.com/bartmika/databoutique-backend/internal/app/uploadfile/httptransport"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+// The following fake controllers are called by our real handlers. The
+// methods not needed by our tests panic through the embedded nil interface.
+
+type fakeGatewayController struct {
+	gateway_c.GatewayController
+	srv *testServer
+}
+
+func (c *fakeGatewayController) Login(ctx context.Context, email, password string) (*gateway_s.LoginResponseIDO, error) {
+	access, refresh := c.srv.issueTokens()
+	return &gateway_s.LoginResponseIDO{
+		User:                   &user_s.User{Email: email},
+		AccessToken:            access,
+		AccessTokenExpiryTime:  time.Now().Add(time.Hour),
+		RefreshToken:           refresh,
+		RefreshTokenExpiryTime: time.Now().Add(24 * time.Hour),
+	}, nil
+}
+
+func (c *fakeGatewayController) RefreshToken(ctx context.Context, value string) (*user_s.User, string, time.Time, string, time.Time, error) {
+	c.srv.mu.Lock()
+	valid := value == c.srv.refreshToken
+	c.srv.refreshCount++
+	c.srv.mu.Unlock()
+	if !valid {
+		return nil, "", time.Time{}, "", time.Time{}, errors.New("invalid refresh token")
+	}
+	access, refresh := c.srv.issueTokens()
+	return &user_s.User{Email: "frank@bmika.com"}, access, time.Now().Add(time.Hour), refresh, time.Now().Add(24 * time.Hour), nil
+}
+
+type fakeProgramController struct {
+	program_c.ProgramController
+}
+
+func (c *fakeProgramController) ListByFilter(ctx context.Context, f *program_s.ProgramPaginationListFilter) (*program_s.ProgramPaginationListResult, error) {
+	return &program_s.ProgramPaginationListResult{
+		Results: []*program_s.Program{{ID: primitive.NewObjectID(), Name: f.SearchText}},
+	}, nil
+}
+
+type fakeExecutableController struct {
+	executable_c.ExecutableController
+	mu      sync.Mutex
+	exec    *executable_s.Executable
+	pending int // Number of `GetByID` calls before the executable is processed.
+}
+
+func (c *fakeExecutableController) Create(ctx context.Context, req *executable_c.ExecutableCreateRequestIDO) (*executable_s.Executable, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.exec = &executable_s.Executable{
+		ID:        primitive.NewObjectID(),
+		ProgramID: req.ProgramID,
+		Question:  req.Question,
+		Status:    executable_s.ExecutableStatusProcessing,
+	}
+	return c.exec, nil
+}
+
+func (c *fakeExecutableController) GetByID(ctx context.Context, id primitive.ObjectID) (*executable_s.Executable, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending > 0 {
+		c.pending--
+	} else {
+		c.exec.Status = executable_s.ExecutableStatusActive
+		c.exec.Messages = []*executable_s.Message{{Content: "42", FromExecutable: true}}
+	}
+	return c.exec, nil
+}
+
+type fakeUploadDirectoryController struct {
+	uploaddirectory_c.UploadDirectoryController
+}
+
+func (c *fakeUploadDirectoryController) GetByID(ctx context.Context, id primitive.ObjectID) (*uploaddirectory_s.UploadDirectory, error) {
+	return &uploaddirectory_s.UploadDirectory{ID: id, Name: "Reports"}, nil
+}
+
+type fakeUploadFileController struct {
+	uploadfile_c.UploadFileController
+	content  string
+	fileType string
+}
+
+func (c *fakeUploadFileController) Create(ctx context.Context, req *uploadfile_c.UploadFileCreateRequestIDO) (*uploadfile_s.UploadFile, error) {
+	b, err := io.ReadAll(req.File)
+	if err != nil {
+		return nil, err
+	}
+	c.content = string(b)
+	c.fileType = req.FileType
+	return &uploadfile_s.UploadFile{
+		ID:                primitive.NewObjectID(),
+		Name:              req.Name,
+		Filename:          req.FileName,
+		UploadDirectoryID: req.UploadDirectoryID,
+	}, nil
+}
+
+type fakeTenantController struct {
+	tenant_c.TenantController
+}
+
+func (c *fakeTenantController) GetByID(ctx context.Context, id primitive.ObjectID) (*tenant_s.Tenant, error) {
+	return &tenant_s.Tenant{ID: id, Name: "Data Boutique"}, nil
+}
+
+// UpdateByID function updates the tenant which is always at version 2.
+func (c *fakeTenantController) UpdateByID(ctx context.Context, t *tenant_s.Tenant) (*tenant_s.Tenant, error) {
+	if err := versioning.CheckExpectedVersion(ctx, 2); err != nil {
+		return nil, err
+	}
+	t.Version = 3
+	return t, nil
+}
+
+const testAPIKey = "test-api-key"
+
+// testServer runs our real handlers behind a simplified version of our JWT
+// middleware which only accepts the latest issued access token or our test
+// API key.
+type testServer struct {
+	*httptest.Server
+	executable   *fakeExecutableController
+	uploadFile   *fakeUploadFileController
+	mu           sync.Mutex
+	tokenCount   int
+	accessToken  string
+	refreshToken string
+	refreshCount int
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := &testServer{
+		executable: &fakeExecutableController{},
+		uploadFile: &fakeUploadFileController{},
+	}
+	gate := &gateway.Handler{Logger: logger, Controller: &fakeGatewayController{srv: srv}}
+	prog := &program.Handler{Logger: logger, Controller: &fakeProgramController{}}
+	exec := &executable.Handler{Logger: logger, Controller: srv.executable}
+	updir := &uploaddirectory.Handler{Logger: logger, Controller: &fakeUploadDirectoryController{}}
+	upfile := &uploadfile.Handler{Controller: srv.uploadFile}
+	org := &tenant.Handler{Logger: logger, Controller: &fakeTenantController{}}
+
+	withID := func(fn func(w http.ResponseWriter, r *http.Request, id string)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { fn(w, r, router.Param(r, "id")) }
+	}
+	rt := router.New([]router.Route{
+		{Method: http.MethodPost, Pattern: "/api/v1/login", Handler: gate.Login, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/api/v1/refresh-token", Handler: gate.RefreshToken, IsPublic: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/programs", Handler: prog.List},
+		{Method: http.MethodPost, Pattern: "/api/v1/executables", Handler: exec.Create},
+		{Method: http.MethodGet, Pattern: "/api/v1/executable/{id}", Handler: withID(exec.GetByID)},
+		{Method: http.MethodGet, Pattern: "/api/v1/upload-directory/{id}", Handler: withID(updir.GetByID)},
+		{Method: http.MethodPost, Pattern: "/api/v1/upload-files", Handler: upfile.Create},
+		{Method: http.MethodGet, Pattern: "/api/v1/tenant/{id}", Handler: withID(org.GetByID)},
+		{Method: http.MethodPut, Pattern: "/api/v1/tenant/{id}", Handler: withID(org.UpdateByID), IsVersioned: true},
+	})
+
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, params, _ := rt.Match(r.Method, r.URL.Path)
+		if route == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if !route.IsPublic {
+			srv.mu.Lock()
+			authorization := r.Header.Get("Authorization")
+			ok := authorization == "JWT "+srv.accessToken || authorization == "Api-Key "+testAPIKey
+			srv.mu.Unlock()
+			if !ok {
+				http.Error(w, "attempting to access a protected endpoint", http.StatusUnauthorized)
+				return
+			}
+		}
+		ctx := router.WithMatch(r.Context(), route, params)
+		if version, ok := versioning.ParseETag(r.Header.Get("If-Match")); ok && route.IsVersioned {
+			ctx = versioning.WithExpectedVersion(ctx, version)
+		}
+		route.Handler(w, r.WithContext(ctx))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (srv *testServer) issueTokens() (string, string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.tokenCount++
+	srv.accessToken = fmt.Sprintf("access-%d", srv.tokenCount)
+	srv.refreshToken = fmt.Sprintf("refresh-%d", srv.tokenCount)
+	return srv.accessToken, srv.refreshToken
+}
+
+func (srv *testServer) refreshes() int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.refreshCount
+}
+
+// expireAccessToken function makes our server reject the current access
+// token as if it expired early, ex: the session was rotated.
+func (srv *testServer) expireAccessToken() {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.accessToken = "expired"
+}
+
+func TestLoginAndTokenRenewal(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	c := New(srv.URL)
+
+	if _, err := c.ListPrograms(ctx, nil); !errors.Is(err, ErrNotAuthenticated) {
+		t.Fatalf("expected ErrNotAuthenticated but got %v", err)
+	}
+
+	res, err := c.Login(ctx, "frank@bmika.com", "secret")
+	if err != nil {
+		t.Fatalf("failed login: %v", err)
+	}
+	if res.User == nil || res.User.Email != "frank@bmika.com" {
+		t.Fatalf("expected user in login response but got %+v", res.User)
+	}
+
+	list, err := c.ListPrograms(ctx, &ListOptions{Search: "Analysis"})
+	if err != nil {
+		t.Fatalf("failed listing programs: %v", err)
+	}
+	if len(list.Results) != 1 || list.Results[0].Name != "Analysis" {
+		t.Fatalf("expected search to be sent but got %+v", list.Results)
+	}
+
+	// Rejected access tokens are renewed once and the request retried.
+	srv.expireAccessToken()
+	if _, err := c.ListPrograms(ctx, nil); err != nil {
+		t.Fatalf("expected request to be retried after renewal but got %v", err)
+	}
+	if srv.refreshes() != 1 {
+		t.Fatalf("expected 1 refresh but got %d", srv.refreshes())
+	}
+
+	// Access tokens about to expire are renewed before the request.
+	c.now = func() time.Time { return time.Now().Add(59*time.Minute + 30*time.Second) }
+	if _, err := c.ListPrograms(ctx, nil); err != nil {
+		t.Fatalf("failed listing programs: %v", err)
+	}
+	if srv.refreshes() != 2 {
+		t.Fatalf("expected 2 refreshes but got %d", srv.refreshes())
+	}
+	c.now = time.Now
+
+	// Requests fail once the refresh token was revoked.
+	srv.mu.Lock()
+	srv.refreshToken = "revoked"
+	srv.accessToken = "expired"
+	srv.mu.Unlock()
+	_, err = c.ListPrograms(ctx, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError but got %v", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := newTestServer(t)
+	c := New(srv.URL)
+
+	_, err := c.Login(context.Background(), "frank@bmika.com", "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError but got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Fields["password"] != "missing value" {
+		t.Fatalf("expected password validation error but got %+v", apiErr)
+	}
+}
+
+func TestWaitForExecutable(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	c := New(srv.URL)
+	if _, err := c.Login(ctx, "frank@bmika.com", "secret"); err != nil {
+		t.Fatalf("failed login: %v", err)
+	}
+
+	defer func(d time.Duration) { ExecutablePollInterval = d }(ExecutablePollInterval)
+	ExecutablePollInterval = time.Millisecond
+	srv.executable.pending = 2
+
+	exec, err := c.CreateExecutable(ctx, &executable_c.ExecutableCreateRequestIDO{
+		ProgramID: primitive.NewObjectID(),
+		Question:  "What is the answer?",
+	})
+	if err != nil {
+		t.Fatalf("failed creating executable: %v", err)
+	}
+	if exec.Status != executable_s.ExecutableStatusProcessing {
+		t.Fatalf("expected processing executable but got status %d", exec.Status)
+	}
+
+	exec, err = c.WaitForExecutable(ctx, exec.ID)
+	if err != nil {
+		t.Fatalf("failed waiting for executable: %v", err)
+	}
+	if exec.Status != executable_s.ExecutableStatusActive || len(exec.Messages) != 1 || exec.Messages[0].Content != "42" {
+		t.Fatalf("expected answered executable but got %+v", exec)
+	}
+
+	// Waiting stops when the context is cancelled.
+	srv.executable.mu.Lock()
+	srv.executable.exec.Status = executable_s.ExecutableStatusProcessing
+	srv.executable.pending = 1000
+	srv.executable.mu.Unlock()
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.WaitForExecutable(ctx, exec.ID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded but got %v", err)
+	}
+}
+
+func TestCreateUploadFile(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	c := New(srv.URL)
+	if _, err := c.Login(ctx, "frank@bmika.com", "secret"); err != nil {
+		t.Fatalf("failed login: %v", err)
+	}
+
+	dirID := primitive.NewObjectID()
+	dir, err := c.GetUploadDirectory(ctx, dirID)
+	if err != nil {
+		t.Fatalf("failed getting upload directory: %v", err)
+	}
+	if dir.ID != dirID || dir.Name != "Reports" {
+		t.Fatalf("unexpected upload directory %+v", dir)
+	}
+
+	f, err := c.CreateUploadFile(ctx, &uploadfile_c.UploadFileCreateRequestIDO{
+		Name:              "Q3 Report",
+		FileName:          "q3 \"final\".csv",
+		FileType:          "text/csv",
+		File:              newTestFile("a,b\n1,2\n"),
+		UploadDirectoryID: dirID,
+	})
+	if err != nil {
+		t.Fatalf("failed uploading file: %v", err)
+	}
+	if f.Name != "Q3 Report" || f.Filename != "q3 \"final\".csv" || f.UploadDirectoryID != dirID {
+		t.Fatalf("unexpected upload file %+v", f)
+	}
+	if srv.uploadFile.content != "a,b\n1,2\n" || srv.uploadFile.fileType != "text/csv" {
+		t.Fatalf("expected file to be uploaded but got %q of type %q", srv.uploadFile.content, srv.uploadFile.fileType)
+	}
+}
+
+func TestUpdateTenant(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	c := New(srv.URL)
+	if _, err := c.Login(ctx, "frank@bmika.com", "secret"); err != nil {
+		t.Fatalf("failed login: %v", err)
+	}
+
+	org, err := c.UpdateTenant(ctx, &tenant_s.Tenant{ID: primitive.NewObjectID(), Name: "Data Boutique", Version: 2})
+	if err != nil {
+		t.Fatalf("failed updating tenant: %v", err)
+	}
+	if org.Version != 3 {
+		t.Fatalf("expected version 3 but got %d", org.Version)
+	}
+
+	// Updating a stale copy of the tenant is rejected.
+	var apiErr *APIError
+	_, err = c.UpdateTenant(ctx, &tenant_s.Tenant{ID: org.ID, Name: "Data Boutique", Version: 1})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 error but got %v", err)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	c := New(srv.URL)
+	c.APIKey = testAPIKey
+
+	id := primitive.NewObjectID()
+	org, err := c.GetTenant(ctx, id)
+	if err != nil {
+		t.Fatalf("failed getting tenant: %v", err)
+	}
+	if org.ID != id || org.Name != "Data Boutique" {
+		t.Fatalf("unexpected tenant %+v", org)
+	}
+
+	// Rejected API keys are never renewed.
+	c.APIKey = "revoked"
+	_, err = c.GetTenant(ctx, id)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized but got %v", err)
+	}
+	if srv.refreshes() != 0 {
+		t.Fatalf("expected no refresh but got %d", srv.refreshes())
+	}
+}
+
+// testFile implements `multipart.File` for our uploads.
+type testFile struct {
+	*strings.Reader
+}
+
+func (testFile) Close() error { return nil }
+
+func newTestFile(content string) testFile {
+	return testFile{strings.NewReader(content)}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	executable_c "github.com/bartmika/databoutique-backend/internal/app/executable/controller"
+	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+)
+
+// ErrExecutableFailed is returned by `WaitForExecutable` when the server
+// failed processing the executable.
+var ErrExecutableFailed = errors.New("executable failed processing")
+
+// ExecutablePollInterval is how often `WaitForExecutable` checks the status
+// of the executable.
+var ExecutablePollInterval = 5 * time.Second
+
+// ListExecutables function returns a page of the executables of the tenant.
+func (c *Client) ListExecutables(ctx context.Context, opts *ListOptions) (*executable_s.ExecutablePaginationListResult, error) {
+	var res executable_s.ExecutablePaginationListResult
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/executables", opts.values(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetExecutable function returns the executable.
+func (c *Client) GetExecutable(ctx context.Context, id primitive.ObjectID) (*executable_s.Executable, error) {
+	var res executable_s.Executable
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/executable/"+id.Hex(), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateExecutable function submits the question to the program. The answer
+// is processed in the background so use `WaitForExecutable` to wait for it.
+func (c *Client) CreateExecutable(ctx context.Context, req *executable_c.ExecutableCreateRequestIDO) (*executable_s.Executable, error) {
+	var res executable_s.Executable
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/executables", nil, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SubmitQuestion function submits a follow-up question to the executable. The
+// answer is processed in the background so use `WaitForExecutable` to wait
+// for it.
+func (c *Client) SubmitQuestion(ctx context.Context, req *executable_c.QuestionSubmissionOperationRequestIDO) (*executable_s.Executable, error) {
+	var res executable_s.Executable
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/executables/operations/question-submission", nil, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteExecutable function deletes the executable.
+func (c *Client) DeleteExecutable(ctx context.Context, id primitive.ObjectID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/v1/executable/"+id.Hex(), nil, nil, nil)
+}
+
+// WaitForExecutable function polls the executable until the server finished
+// processing it or the context expires. If processing failed then the
+// executable is returned with `ErrExecutableFailed`.
+func (c *Client) WaitForExecutable(ctx context.Context, id primitive.ObjectID) (*executable_s.Executable, error) {
+	for {
+		exec, err := c.GetExecutable(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch exec.Status {
+		case executable_s.ExecutableStatusProcessing:
+		case executable_s.ExecutableStatusFailed:
+			return exec, ErrExecutableFailed
+		default:
+			return exec, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(ExecutablePollInterval):
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	program_c "github.com/bartmika/databoutique-backend/internal/app/program/controller"
+	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
+)
+
+// ListPrograms function returns a page of the programs of the tenant.
+func (c *Client) ListPrograms(ctx context.Context, opts *ListOptions) (*program_s.ProgramPaginationListResult, error) {
+	var res program_s.ProgramPaginationListResult
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/programs", opts.values(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetProgram function returns the program.
+func (c *Client) GetProgram(ctx context.Context, id primitive.ObjectID) (*program_s.Program, error) {
+	var res program_s.Program
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/program/"+id.Hex(), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateProgram function creates the program.
+func (c *Client) CreateProgram(ctx context.Context, req *program_c.ProgramCreateRequestIDO) (*program_s.Program, error) {
+	var res program_s.Program
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/programs", nil, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateProgram function updates the program if it still has the `version`
+// it was retrieved with.
+func (c *Client) UpdateProgram(ctx context.Context, req *program_c.ProgramUpdateRequestIDO, version int64) (*program_s.Program, error) {
+	var res program_s.Program
+	if err := c.doJSON(withVersion(ctx, version), http.MethodPut, "/api/v1/program/"+req.ID.Hex(), nil, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteProgram function deletes the program.
+func (c *Client) DeleteProgram(ctx context.Context, id primitive.ObjectID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/v1/program/"+id.Hex(), nil, nil, nil)
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+)
+
+// TenantListOptions are the parameters supported by the tenant list
+// endpoint. Zero values are not sent so the server defaults apply.
+type TenantListOptions struct {
+	Cursor   primitive.ObjectID
+	PageSize int64
+	Search   string
+	Status   int8
+}
+
+func (o *TenantListOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	if !o.Cursor.IsZero() {
+		v.Set("cursor", o.Cursor.Hex())
+	}
+	if o.PageSize != 0 {
+		v.Set("page_size", strconv.FormatInt(o.PageSize, 10))
+	}
+	if o.Search != "" {
+		v.Set("search", o.Search)
+	}
+	if o.Status != 0 {
+		v.Set("status", strconv.Itoa(int(o.Status)))
+	}
+	return v
+}
+
+// ListTenants function returns a page of the tenants. Only executives may
+// list the tenants.
+func (c *Client) ListTenants(ctx context.Context, opts *TenantListOptions) (*tenant_s.TenantListResult, error) {
+	var res tenant_s.TenantListResult
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/tenants", opts.values(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetTenant function returns the tenant.
+func (c *Client) GetTenant(ctx context.Context, id primitive.ObjectID) (*tenant_s.Tenant, error) {
+	var res tenant_s.Tenant
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/tenant/"+id.Hex(), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateTenant function updates the tenant if it still has the version it
+// was retrieved with.
+func (c *Client) UpdateTenant(ctx context.Context, t *tenant_s.Tenant) (*tenant_s.Tenant, error) {
+	var res tenant_s.Tenant
+	if err := c.doJSON(withVersion(ctx, t.Version), http.MethodPut, "/api/v1/tenant/"+t.ID.Hex(), nil, t, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	uploaddirectory_c "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/controller"
+	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
+)
+
+// ListUploadDirectories function returns a page of the upload directories of
+// the tenant.
+func (c *Client) ListUploadDirectories(ctx context.Context, opts *ListOptions) (*uploaddirectory_s.UploadDirectoryPaginationListResult, error) {
+	var res uploaddirectory_s.UploadDirectoryPaginationListResult
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/upload-directories", opts.values(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetUploadDirectory function returns the upload directory.
+func (c *Client) GetUploadDirectory(ctx context.Context, id primitive.ObjectID) (*uploaddirectory_s.UploadDirectory, error) {
+	var res uploaddirectory_s.UploadDirectory
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/upload-directory/"+id.Hex(), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateUploadDirectory function creates the upload directory.
+func (c *Client) CreateUploadDirectory(ctx context.Context, req *uploaddirectory_c.UploadDirectoryCreateRequestIDO) (*uploaddirectory_s.UploadDirectory, error) {
+	var res uploaddirectory_s.UploadDirectory
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/upload-directories", nil, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateUploadDirectory function updates the upload directory if it still
+// has the `version` it was retrieved with.
+func (c *Client) UpdateUploadDirectory(ctx context.Context, req *uploaddirectory_c.UploadDirectoryUpdateRequestIDO, version int64) (*uploaddirectory_s.UploadDirectory, error) {
+	var res uploaddirectory_s.UploadDirectory
+	if err := c.doJSON(withVersion(ctx, version), http.MethodPut, "/api/v1/upload-directory/"+req.ID.Hex(), nil, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteUploadDirectory function deletes the upload directory.
+func (c *Client) DeleteUploadDirectory(ctx context.Context, id primitive.ObjectID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/v1/upload-directory/"+id.Hex(), nil, nil, nil)
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	uploadfile_c "github.com/bartmika/databoutique-backend/internal/app/uploadfile/controller"
+	uploadfile_s "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
+)
+
+// UploadFileListOptions are the parameters supported by the upload file list
+// endpoint. Zero values are not sent so the server defaults apply.
+type UploadFileListOptions struct {
+	Cursor            string
+	PageSize          int64
+	Name              string
+	Description       string
+	UploadDirectoryID primitive.ObjectID
+}
+
+func (o *UploadFileListOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	if o.Cursor != "" {
+		v.Set("cursor", o.Cursor)
+	}
+	if o.PageSize != 0 {
+		v.Set("page_size", strconv.FormatInt(o.PageSize, 10))
+	}
+	if o.Name != "" {
+		v.Set("name", o.Name)
+	}
+	if o.Description != "" {
+		v.Set("description", o.Description)
+	}
+	if !o.UploadDirectoryID.IsZero() {
+		v.Set("upload_directory_id", o.UploadDirectoryID.Hex())
+	}
+	return v
+}
+
+// ListUploadFiles function returns a page of the upload files of the tenant.
+func (c *Client) ListUploadFiles(ctx context.Context, opts *UploadFileListOptions) (*uploadfile_s.UploadFilePaginationListResult, error) {
+	var res uploadfile_s.UploadFilePaginationListResult
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/upload-files", opts.values(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetUploadFile function returns the upload file.
+func (c *Client) GetUploadFile(ctx context.Context, id primitive.ObjectID) (*uploadfile_s.UploadFile, error) {
+	var res uploadfile_s.UploadFile
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/upload-file/"+id.Hex(), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateUploadFile function uploads the file, ex: `File: osFile, FileName:
+// "report.pdf", FileType: "application/pdf"`.
+func (c *Client) CreateUploadFile(ctx context.Context, req *uploadfile_c.UploadFileCreateRequestIDO) (*uploadfile_s.UploadFile, error) {
+	fields := map[string]string{
+		"name":        req.Name,
+		"description": req.Description,
+	}
+	if !req.UploadDirectoryID.IsZero() {
+		fields["upload_directory_id"] = req.UploadDirectoryID.Hex()
+	}
+	contentType, body, err := multipartBody(fields, req.FileName, req.FileType, req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var res uploadfile_s.UploadFile
+	if err := c.do(ctx, http.MethodPost, "/api/v1/upload-files", nil, contentType, body, &res, true); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateUploadFile function updates the upload file. The file is only
+// replaced if `req.File` is not nil and the upload file must still have the
+// `version` it was retrieved with.
+func (c *Client) UpdateUploadFile(ctx context.Context, req *uploadfile_c.UploadFileUpdateRequestIDO, version int64) (*uploadfile_s.UploadFile, error) {
+	fields := map[string]string{
+		"id":          req.ID.Hex(),
+		"name":        req.Name,
+		"description": req.Description,
+	}
+	if !req.UploadDirectoryID.IsZero() {
+		fields["upload_directory_id"] = req.UploadDirectoryID.Hex()
+	}
+	contentType, body, err := multipartBody(fields, req.FileName, req.FileType, req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var res uploadfile_s.UploadFile
+	if err := c.do(withVersion(ctx, version), http.MethodPut, "/api/v1/upload-file/"+req.ID.Hex(), nil, contentType, body, &res, true); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteUploadFile function deletes the upload file.
+func (c *Client) DeleteUploadFile(ctx context.Context, id primitive.ObjectID) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/v1/upload-file/"+id.Hex(), nil, nil, nil)
+}
+
+// multipartBody function returns the `multipart/form-data` content type and
+// body of the form. The body is buffered so the request can be retried after
+// renewing the access token.
+func multipartBody(fields map[string]string, fileName string, fileType string, file io.Reader) (string, []byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if file != nil {
+		if fileType == "" {
+			fileType = "application/octet-stream"
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(fileName)))
+		header.Set("Content-Type", fileType)
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return "", nil, err
+	}
+	return mw.FormDataContentType(), buf.Bytes(), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
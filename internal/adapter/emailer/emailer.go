@@ -0,0 +1,41 @@
+package emailer
+
+import (
+	"context"
+	"log"
+	"log/slog"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer/filedrop"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer/memory"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer/smtp"
+	c "github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+// Emailer is the email transport used to deliver our emails.
+type Emailer interface {
+	Send(ctx context.Context, sender, subject, recipient, htmlContent string) error
+	GetSenderEmail() string
+	GetDomainName() string
+}
+
+// NewEmailer function returns the email transport selected in our
+// configuration.
+func NewEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider) Emailer {
+	switch cfg.Emailer.Backend {
+	case "mailgun":
+		return mailgun.NewEmailer(cfg, logger, uuidp)
+	case "smtp":
+		return smtp.NewEmailer(cfg, logger, uuidp)
+	case "filedrop":
+		return filedrop.NewEmailer(cfg, logger, uuidp)
+	case "memory":
+		return memory.NewEmailer(cfg.Emailer.SenderEmail, cfg.AppServer.DomainName)
+	default:
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatalf("unsupported emailer backend: %s", cfg.Emailer.Backend)
+		return nil
+	}
+}
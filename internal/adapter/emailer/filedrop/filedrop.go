@@ -0,0 +1,77 @@
+// Package filedrop provides an emailer which writes every email as a HTML
+// file into a directory instead of delivering it. This is useful for local
+// development as you can open the emails in your browser.
+package filedrop
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+type Emailer struct {
+	UUID          uuid.Provider
+	Logger        *slog.Logger
+	directoryPath string
+	senderEmail   string
+	domainName    string
+}
+
+func NewEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider) *Emailer {
+	logger.Debug("file-drop emailer initializing...")
+	if err := os.MkdirAll(cfg.Emailer.FileDropDirectoryPath, 0755); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatalf("failed creating email directory: %v", err)
+	}
+	logger.Debug("file-drop emailer was initialized.",
+		slog.String("directory", cfg.Emailer.FileDropDirectoryPath))
+
+	return &Emailer{
+		UUID:          uuidp,
+		Logger:        logger,
+		directoryPath: cfg.Emailer.FileDropDirectoryPath,
+		senderEmail:   cfg.Emailer.SenderEmail,
+		domainName:    cfg.AppServer.DomainName,
+	}
+}
+
+func (me *Emailer) Send(ctx context.Context, sender, subject, recipient, body string) error {
+	// The email headers are written as a HTML comment at the top of the
+	// file so the file can be opened directly in the browser.
+	content := fmt.Sprintf("<!--\nFrom: %s\nTo: %s\nSubject: %s\nDate: %s\n-->\n%s",
+		html.EscapeString(sender),
+		html.EscapeString(recipient),
+		html.EscapeString(subject),
+		time.Now().Format(time.RFC1123Z),
+		body)
+
+	filename := fmt.Sprintf("%s-%s.html", time.Now().UTC().Format("20060102T150405"), me.UUID.NewUUID())
+	fp := filepath.Join(me.directoryPath, filename)
+	if err := os.WriteFile(fp, []byte(content), 0644); err != nil {
+		me.Logger.Error("emailer failed writing file", slog.Any("err", err))
+		return err
+	}
+
+	me.Logger.Info("email written to file",
+		slog.String("subject", subject),
+		slog.String("recipient", recipient),
+		slog.String("filepath", fp))
+	return nil
+}
+
+func (me *Emailer) GetSenderEmail() string {
+	return me.senderEmail
+}
+
+func (me *Emailer) GetDomainName() string {
+	return me.domainName
+}
@@ -0,0 +1,42 @@
+package filedrop
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+func TestSend(t *testing.T) {
+	cfg := &c.Conf{}
+	cfg.Emailer.FileDropDirectoryPath = filepath.Join(t.TempDir(), "emails")
+	cfg.Emailer.SenderEmail = "no-reply@example.com"
+	me := NewEmailer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), uuid.NewProvider())
+
+	if err := me.Send(context.Background(), me.GetSenderEmail(), "Hello", "jane@example.com", "<p>Hi Jane</p>"); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+
+	files, err := os.ReadDir(cfg.Emailer.FileDropDirectoryPath)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file but got %v", len(files))
+	}
+	b, err := os.ReadFile(filepath.Join(cfg.Emailer.FileDropDirectoryPath, files[0].Name()))
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	for _, expected := range []string{"To: jane@example.com", "Subject: Hello", "<p>Hi Jane</p>"} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("file is missing %q, got %v", expected, string(b))
+		}
+	}
+}
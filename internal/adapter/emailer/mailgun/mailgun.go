@@ -11,13 +11,7 @@ import (
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
 )
 
-type Emailer interface {
-	Send(ctx context.Context, sender, subject, recipient, htmlContent string) error
-	GetSenderEmail() string
-	GetDomainName() string
-}
-
-type mailgunEmailer struct {
+type Emailer struct {
 	Mailgun     *mailgun.MailgunImpl
 	UUID        uuid.Provider
 	Logger      *slog.Logger
@@ -25,7 +19,7 @@ type mailgunEmailer struct {
 	domainName  string
 }
 
-func NewEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider) Emailer {
+func NewEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider) *Emailer {
 	// Defensive code: Make sure we have access to the file before proceeding any further with the code.
 	logger.Debug("mailgun emailer initializing...")
 	mg := mailgun.NewMailgun(cfg.Emailer.Domain, cfg.Emailer.APIKey)
@@ -33,7 +27,7 @@ func NewEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider) Emailer {
 
 	mg.SetAPIBase(cfg.Emailer.APIBase) // Override to support our custom email requirements.
 
-	return &mailgunEmailer{
+	return &Emailer{
 		Mailgun:     mg,
 		UUID:        uuidp,
 		Logger:      logger,
@@ -42,7 +36,7 @@ func NewEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider) Emailer {
 	}
 }
 
-func (me *mailgunEmailer) Send(ctx context.Context, sender, subject, recipient, body string) error {
+func (me *Emailer) Send(ctx context.Context, sender, subject, recipient, body string) error {
 	me.Logger.Debug("sent email",
 		slog.String("sender", sender),
 		slog.String("subject", subject),
@@ -68,10 +62,10 @@ func (me *mailgunEmailer) Send(ctx context.Context, sender, subject, recipient,
 	return nil
 }
 
-func (me *mailgunEmailer) GetSenderEmail() string {
+func (me *Emailer) GetSenderEmail() string {
 	return me.senderEmail
 }
 
-func (me *mailgunEmailer) GetDomainName() string {
+func (me *Emailer) GetDomainName() string {
 	return me.domainName
 }
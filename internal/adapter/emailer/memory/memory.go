@@ -0,0 +1,93 @@
+// Package memory provides an emailer which keeps the sent emails in memory
+// instead of delivering them. It is intended to be used in our unit tests
+// so we can make assertions about the emails which were sent.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Message is an email which was sent through the emailer.
+type Message struct {
+	Sender      string
+	Subject     string
+	Recipient   string
+	HTMLContent string
+	SentAt      time.Time
+}
+
+type Emailer struct {
+	mu          sync.Mutex
+	messages    []*Message
+	senderEmail string
+	domainName  string
+}
+
+func NewEmailer(senderEmail, domainName string) *Emailer {
+	return &Emailer{
+		messages:    make([]*Message, 0),
+		senderEmail: senderEmail,
+		domainName:  domainName,
+	}
+}
+
+func (me *Emailer) Send(ctx context.Context, sender, subject, recipient, body string) error {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	me.messages = append(me.messages, &Message{
+		Sender:      sender,
+		Subject:     subject,
+		Recipient:   recipient,
+		HTMLContent: body,
+		SentAt:      time.Now(),
+	})
+	return nil
+}
+
+func (me *Emailer) GetSenderEmail() string {
+	return me.senderEmail
+}
+
+func (me *Emailer) GetDomainName() string {
+	return me.domainName
+}
+
+// Messages function returns all the emails which were sent in order.
+func (me *Emailer) Messages() []*Message {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	res := make([]*Message, len(me.messages))
+	copy(res, me.messages)
+	return res
+}
+
+// MessagesTo function returns the emails which were sent to the recipient.
+func (me *Emailer) MessagesTo(recipient string) []*Message {
+	res := make([]*Message, 0)
+	for _, m := range me.Messages() {
+		if m.Recipient == recipient {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// Last function returns the most recently sent email or nil if no emails
+// were sent.
+func (me *Emailer) Last() *Message {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	if len(me.messages) == 0 {
+		return nil
+	}
+	return me.messages[len(me.messages)-1]
+}
+
+// Reset function removes all the sent emails.
+func (me *Emailer) Reset() {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	me.messages = make([]*Message, 0)
+}
@@ -0,0 +1,146 @@
+// Package smtp provides an emailer which delivers emails through any SMTP
+// server.
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+type Emailer struct {
+	UUID           uuid.Provider
+	Logger         *slog.Logger
+	host           string
+	port           string
+	username       string
+	password       string
+	hasImplicitTLS bool
+	senderEmail    string
+	domainName     string
+}
+
+func NewEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider) *Emailer {
+	logger.Debug("smtp emailer initializing...")
+	logger.Debug("smtp emailer was initialized.",
+		slog.String("host", cfg.Emailer.SMTPHost),
+		slog.String("port", cfg.Emailer.SMTPPort))
+
+	return &Emailer{
+		UUID:           uuidp,
+		Logger:         logger,
+		host:           cfg.Emailer.SMTPHost,
+		port:           cfg.Emailer.SMTPPort,
+		username:       cfg.Emailer.SMTPUsername,
+		password:       cfg.Emailer.SMTPPassword,
+		hasImplicitTLS: cfg.Emailer.SMTPHasImplicitTLS,
+		senderEmail:    cfg.Emailer.SenderEmail,
+		domainName:     cfg.AppServer.DomainName,
+	}
+}
+
+func (me *Emailer) Send(ctx context.Context, sender, subject, recipient, body string) error {
+	me.Logger.Debug("sending email",
+		slog.String("sender", sender),
+		slog.String("subject", subject),
+		slog.String("recipient", recipient))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	if err := me.send(ctx, sender, recipient, me.buildMessage(sender, subject, recipient, body)); err != nil {
+		me.Logger.Error("emailer failed sending", slog.Any("err", err))
+		return err
+	}
+	me.Logger.Debug("emailer sent", slog.String("recipient", recipient))
+	return nil
+}
+
+func (me *Emailer) send(ctx context.Context, sender, recipient string, msg []byte) error {
+	addr := net.JoinHostPort(me.host, me.port)
+	tlsConfig := &tls.Config{ServerName: me.host}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if me.hasImplicitTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, me.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Hello(me.domainName); err != nil {
+		return err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok && !me.hasImplicitTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if me.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", me.username, me.password, me.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(sender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(recipient); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage function returns the MIME encoded HTML email.
+func (me *Emailer) buildMessage(sender, subject, recipient, body string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender)
+	fmt.Fprintf(&buf, "To: %s\r\n", recipient)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", me.UUID.NewUUID(), me.domainName)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+func (me *Emailer) GetSenderEmail() string {
+	return me.senderEmail
+}
+
+func (me *Emailer) GetDomainName() string {
+	return me.domainName
+}
@@ -0,0 +1,149 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// ErrLeaseLost is the cause of the context returned by `KeepAlive` when the
+// lease was taken over by another instance.
+var ErrLeaseLost = errors.New("lease lost to another instance")
+
+// Leaser hands out leases so only one of our replicas works on a resource at
+// a time, ex: an OpenAI run. A lease expires unless its owner renews it so
+// the resource is picked up by another replica if the owner crashes.
+type Leaser interface {
+	// Acquire function claims the lease of the key for this instance and
+	// returns false if the lease is held by anyone, including this instance.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// Renew function extends the lease held by this instance and returns
+	// false if the lease was lost to another instance.
+	Renew(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// Release function frees the lease if it is held by this instance.
+	Release(ctx context.Context, key string) error
+}
+
+// MongoDBLeaser keeps the leases in MongoDB so they are shared by all our
+// replicas. The expired leases are deleted by MongoDB.
+type MongoDBLeaser struct {
+	Logger     *slog.Logger
+	Collection *mongo.Collection
+	owner      string
+}
+
+func NewLeaser(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Leaser {
+	uc := client.Database(appCfg.DB.Name).Collection("leases")
+
+	_, err := uc.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "lease_until", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	// Identify this instance uniquely even if it restarts with the same
+	// hostname, ex: a container restarted by docker.
+	hostname, _ := os.Hostname()
+	return &MongoDBLeaser{
+		Logger:     loggerp,
+		Collection: uc,
+		owner:      fmt.Sprintf("%s-%s", hostname, primitive.NewObjectID().Hex()),
+	}
+}
+
+func (l *MongoDBLeaser) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	// Only a missing or expired lease matches so the upsert of a lease held
+	// by anyone fails on the duplicate `_id` which makes the claim atomic.
+	filter := bson.M{"_id": key, "lease_until": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"owner": l.owner, "lease_until": now.Add(ttl)}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := l.Collection.FindOneAndUpdate(ctx, filter, update, opts).Err()
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		l.Logger.ErrorContext(ctx, "database acquire lease error", slog.String("key", key), slog.Any("error", err))
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *MongoDBLeaser) Renew(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	filter := bson.M{"_id": key, "owner": l.owner}
+	update := bson.M{"$set": bson.M{"lease_until": time.Now().Add(ttl)}}
+
+	res, err := l.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		l.Logger.ErrorContext(ctx, "database renew lease error", slog.String("key", key), slog.Any("error", err))
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (l *MongoDBLeaser) Release(ctx context.Context, key string) error {
+	if _, err := l.Collection.DeleteOne(ctx, bson.M{"_id": key, "owner": l.owner}); err != nil {
+		l.Logger.ErrorContext(ctx, "database release lease error", slog.String("key", key), slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// KeepAlive function renews the acquired lease of the key until the returned
+// function is called, which releases the lease. The lease is renewed three
+// times per `ttl` so a single failed renewal does not lose it. The returned
+// context is cancelled with `ErrLeaseLost` if the lease is taken over by
+// another instance so the work stops instead of running twice.
+func KeepAlive(ctx context.Context, l Leaser, loggerp *slog.Logger, key string, ttl time.Duration) (context.Context, func()) {
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			// DEVELOPERS NOTE: We renew even after `ctx` is cancelled since
+			// the work is still running until the returned function is called.
+			ok, err := l.Renew(context.WithoutCancel(ctx), key, ttl)
+			if err == nil && !ok {
+				loggerp.WarnContext(ctx, "lease lost to another instance", slog.String("key", key))
+				cancel(ErrLeaseLost)
+				return
+			}
+		}
+	}()
+
+	return leaseCtx, func() {
+		close(done)
+		<-stopped
+		cancel(context.Canceled)
+		if err := l.Release(context.WithoutCancel(ctx), key); err != nil {
+			loggerp.WarnContext(ctx, "failed releasing lease", slog.String("key", key), slog.Any("error", err))
+		}
+	}
+}
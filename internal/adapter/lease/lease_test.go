@@ -0,0 +1,76 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeLeaser struct {
+	mu       sync.Mutex
+	renewals int
+	released bool
+	lost     bool
+}
+
+func (l *fakeLeaser) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (l *fakeLeaser) Renew(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.renewals++
+	return !l.lost, nil
+}
+
+func (l *fakeLeaser) Release(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released = true
+	return nil
+}
+
+func TestKeepAlive(t *testing.T) {
+	l := &fakeLeaser{}
+	ctx, cancel := context.WithCancel(context.Background())
+	leaseCtx, release := KeepAlive(ctx, l, slog.New(slog.NewTextHandler(io.Discard, nil)), "executable:1", 30*time.Millisecond)
+
+	// The lease is still renewed after the context is cancelled since the
+	// work only stops once released.
+	time.Sleep(25 * time.Millisecond)
+	cancel()
+	time.Sleep(25 * time.Millisecond)
+	release()
+	if !errors.Is(context.Cause(leaseCtx), context.Canceled) {
+		t.Fatalf("expected the context to be cancelled on release but got %v", context.Cause(leaseCtx))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.renewals < 2 {
+		t.Fatalf("expected the lease to be renewed at least twice but got %d", l.renewals)
+	}
+	if !l.released {
+		t.Fatal("expected the lease to be released")
+	}
+}
+
+func TestKeepAliveCancelsOnLeaseLost(t *testing.T) {
+	l := &fakeLeaser{lost: true}
+	leaseCtx, release := KeepAlive(context.Background(), l, slog.New(slog.NewTextHandler(io.Discard, nil)), "executable:1", 30*time.Millisecond)
+	defer release()
+
+	select {
+	case <-leaseCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the context to be cancelled once the lease is lost")
+	}
+	if !errors.Is(context.Cause(leaseCtx), ErrLeaseLost) {
+		t.Fatalf("expected the lease to be lost but got %v", context.Cause(leaseCtx))
+	}
+}
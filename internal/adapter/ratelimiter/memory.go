@@ -0,0 +1,97 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryLimiter keeps the token buckets in the memory of this instance. The
+// buckets idle for longer than the idle timeout are evicted and at most
+// `maxBuckets` buckets are kept.
+type MemoryLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	idleTimeout time.Duration
+	maxBuckets  int
+	sweptAt     time.Time
+
+	// now returns the current time and is replaced by our tests.
+	now func() time.Time
+}
+
+func NewMemoryLimiter(idleTimeout time.Duration, maxBuckets int) *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:     make(map[string]*bucket),
+		idleTimeout: idleTimeout,
+		maxBuckets:  maxBuckets,
+		now:         time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit c.RateLimit) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.evictIdleBuckets(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		l.evictBucketIfFull()
+		b = &bucket{tokens: float64(limit.Requests), updatedAt: now}
+		l.buckets[key] = b
+	}
+	b.tokens = refill(b.tokens, now.Sub(b.updatedAt), limit)
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return newResult(allowed, b.tokens, limit), nil
+}
+
+// Len function returns the number of buckets currently kept.
+func (l *MemoryLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// evictIdleBuckets function removes the buckets which were not used during
+// the idle timeout. A bucket idle for longer than its period is full again
+// so evicting it does not change the outcome of the following requests.
+func (l *MemoryLimiter) evictIdleBuckets(now time.Time) {
+	if now.Sub(l.sweptAt) < l.idleTimeout {
+		return
+	}
+	l.sweptAt = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updatedAt) >= l.idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// evictBucketIfFull function removes an arbitrary bucket once the maximum
+// number of buckets is reached. The evicted client gets a full bucket again
+// which is preferable to rejecting the requests of every new client.
+func (l *MemoryLimiter) evictBucketIfFull() {
+	if l.maxBuckets <= 0 || len(l.buckets) < l.maxBuckets {
+		return
+	}
+	for key := range l.buckets {
+		delete(l.buckets, key)
+		if len(l.buckets) < l.maxBuckets {
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewMemoryLimiter(10*time.Minute, 100)
+	l.now = func() time.Time { return now }
+	limit := c.RateLimit{Requests: 3, Period: time.Minute}
+
+	// The bucket allows a burst up to its capacity.
+	for i := 2; i >= 0; i-- {
+		res, _ := l.Allow(ctx, "ip:1.2.3.4", limit)
+		if !res.Allowed || res.Remaining != i {
+			t.Fatalf("expected request to be allowed with %d remaining but got %+v", i, res)
+		}
+	}
+	res, _ := l.Allow(ctx, "ip:1.2.3.4", limit)
+	if res.Allowed || res.RetryAfter != 20*time.Second {
+		t.Fatalf("expected request to be rejected for 20s but got %+v", res)
+	}
+
+	// Other keys have their own bucket.
+	if res, _ := l.Allow(ctx, "ip:5.6.7.8", limit); !res.Allowed {
+		t.Fatalf("expected request of another key to be allowed but got %+v", res)
+	}
+
+	// The bucket refills at the rate of the limit.
+	now = now.Add(20 * time.Second)
+	if res, _ := l.Allow(ctx, "ip:1.2.3.4", limit); !res.Allowed {
+		t.Fatalf("expected request to be allowed after refill but got %+v", res)
+	}
+	if res, _ := l.Allow(ctx, "ip:1.2.3.4", limit); res.Allowed {
+		t.Fatalf("expected request to be rejected but got %+v", res)
+	}
+
+	// The idle buckets are evicted.
+	now = now.Add(10 * time.Minute)
+	if res, _ := l.Allow(ctx, "user:1", limit); !res.Allowed || l.Len() != 1 {
+		t.Fatalf("expected idle buckets to be evicted but got %d buckets", l.Len())
+	}
+}
+
+func TestMemoryLimiterMaxBuckets(t *testing.T) {
+	ctx := context.Background()
+	l := NewMemoryLimiter(10*time.Minute, 3)
+	limit := c.RateLimit{Requests: 1, Period: time.Minute}
+
+	// The buckets never exceed the maximum even before they are idle.
+	for i := 0; i < 10; i++ {
+		if res, _ := l.Allow(ctx, fmt.Sprintf("ip:10.0.0.%d", i), limit); !res.Allowed {
+			t.Fatalf("expected request of a new key to be allowed but got %+v", res)
+		}
+		if n := l.Len(); n > 3 {
+			t.Fatalf("expected at most 3 buckets but got %d", n)
+		}
+	}
+
+	// The existing buckets are still enforced.
+	if res, _ := l.Allow(ctx, "ip:10.0.0.9", limit); res.Allowed {
+		t.Fatalf("expected request to be rejected but got %+v", res)
+	}
+}
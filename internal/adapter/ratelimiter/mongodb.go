@@ -0,0 +1,87 @@
+package ratelimiter
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// MongoDBLimiter keeps the token buckets in MongoDB so the limits are shared
+// by all our replicas. The buckets idle for longer than the idle timeout are
+// deleted by MongoDB.
+type MongoDBLimiter struct {
+	Logger      *slog.Logger
+	Collection  *mongo.Collection
+	idleTimeout time.Duration
+}
+
+func NewMongoDBLimiter(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) *MongoDBLimiter {
+	uc := client.Database(appCfg.DB.Name).Collection("rate_limit_buckets")
+
+	_, err := uc.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	return &MongoDBLimiter{
+		Logger:      loggerp,
+		Collection:  uc,
+		idleTimeout: appCfg.RateLimit.IdleTimeout,
+	}
+}
+
+func (l *MongoDBLimiter) Allow(ctx context.Context, key string, limit c.RateLimit) (*Result, error) {
+	now := time.Now()
+	capacity := float64(limit.Requests)
+	tokensPerMillisecond := capacity / float64(limit.Period.Milliseconds())
+
+	// The bucket is refilled and the token taken in a single atomic update
+	// so concurrent requests of our replicas cannot take the same token.
+	// Please note subtracting dates returns milliseconds and the elapsed
+	// time is never negative in case the clocks of our replicas drift.
+	elapsed := bson.D{{Key: "$max", Value: bson.A{0, bson.D{{Key: "$subtract", Value: bson.A{now, bson.D{{Key: "$ifNull", Value: bson.A{"$updated_at", now}}}}}}}}}
+	hasToken := bson.D{{Key: "$gte", Value: bson.A{"$tokens", 1}}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "tokens", Value: bson.D{{Key: "$min", Value: bson.A{capacity, bson.D{{Key: "$add", Value: bson.A{
+				bson.D{{Key: "$ifNull", Value: bson.A{"$tokens", capacity}}},
+				bson.D{{Key: "$multiply", Value: bson.A{elapsed, tokensPerMillisecond}}},
+			}}}}}}},
+			{Key: "updated_at", Value: now},
+			{Key: "expires_at", Value: now.Add(l.idleTimeout)},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "allowed", Value: hasToken},
+			{Key: "tokens", Value: bson.D{{Key: "$cond", Value: bson.A{hasToken, bson.D{{Key: "$subtract", Value: bson.A{"$tokens", 1}}}, "$tokens"}}}},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var b struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	err := l.Collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&b)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request created the bucket at the same time so the
+		// retry will update the existing bucket.
+		err = l.Collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&b)
+	}
+	if err != nil {
+		l.Logger.ErrorContext(ctx, "database update rate limit bucket error", slog.Any("error", err))
+		return nil, err
+	}
+	return newResult(b.Allowed, b.Tokens, limit), nil
+}
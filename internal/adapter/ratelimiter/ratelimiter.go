@@ -0,0 +1,70 @@
+package ratelimiter
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	// Allowed indicates a token was taken so the request may proceed.
+	Allowed bool
+
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+
+	// RetryAfter is how long until the next token is available if the
+	// request was not allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter keeps the token buckets used to rate limit our API calls.
+type Limiter interface {
+	// Allow function takes a token from the bucket of the key, creating a
+	// full bucket if it does not exist yet.
+	Allow(ctx context.Context, key string, limit c.RateLimit) (*Result, error)
+}
+
+// NewLimiter function returns the rate limiter backend selected in our
+// configuration.
+func NewLimiter(cfg *c.Conf, logger *slog.Logger, client *mongo.Client) Limiter {
+	switch cfg.RateLimit.Backend {
+	case "memory":
+		return NewMemoryLimiter(cfg.RateLimit.IdleTimeout, cfg.RateLimit.MaxBuckets)
+	case "mongodb":
+		return NewMongoDBLimiter(cfg, logger, client)
+	default:
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatalf("unsupported rate limiter backend: %s", cfg.RateLimit.Backend)
+		return nil
+	}
+}
+
+// refill function returns the tokens of the bucket after `elapsed` time has
+// passed since it was last updated.
+func refill(tokens float64, elapsed time.Duration, limit c.RateLimit) float64 {
+	if elapsed > 0 {
+		tokens += float64(elapsed) * float64(limit.Requests) / float64(limit.Period)
+	}
+	if capacity := float64(limit.Requests); tokens > capacity {
+		return capacity
+	}
+	return tokens
+}
+
+// newResult function returns the result of the bucket with the `tokens` left
+// once the token of the request was taken, if it was allowed.
+func newResult(allowed bool, tokens float64, limit c.RateLimit) *Result {
+	res := &Result{Allowed: allowed, Remaining: int(tokens)}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) * float64(limit.Period) / float64(limit.Requests))
+	}
+	return res
+}
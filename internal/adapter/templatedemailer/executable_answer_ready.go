@@ -0,0 +1,57 @@
+package templatedemailer
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"path"
+
+	"log/slog"
+)
+
+func (impl *templatedEmailer) SendExecutableAnswerReadyEmail(email, firstName, programName, question, executableID string, isSuccessful bool) error {
+	impl.Logger.Debug("sending executable answer ready email...")
+
+	// FOR TESTING PURPOSES ONLY.
+	fp := path.Join("templates", "executable_answer_ready.html")
+	tmpl, err := template.ParseFiles(fp)
+	if err != nil {
+		impl.Logger.Error("parsing error", slog.Any("error", err))
+		return err
+	}
+
+	var processed bytes.Buffer
+
+	// Render the HTML template with our data.
+	data := struct {
+		Email          string
+		FirstName      string
+		ProgramName    string
+		Question       string
+		ExecutableLink string
+		IsSuccessful   bool
+	}{
+		Email:          email,
+		FirstName:      firstName,
+		ProgramName:    programName,
+		Question:       question,
+		ExecutableLink: "https://" + impl.Emailer.GetDomainName() + "/executable/" + executableID,
+		IsSuccessful:   isSuccessful,
+	}
+	if err := tmpl.Execute(&processed, data); err != nil {
+		impl.Logger.Error("template execution error", slog.Any("error", err))
+		return err
+	}
+	body := processed.String() // DEVELOPERS NOTE: Convert our long sequence of data into a string.
+
+	subject := "Your answer from " + programName + " is ready"
+	if !isSuccessful {
+		subject = "Your question to " + programName + " could not be answered"
+	}
+	if err := impl.Emailer.Send(context.Background(), impl.Emailer.GetSenderEmail(), subject, email, body); err != nil {
+		impl.Logger.Error("sending error", slog.Any("error", err))
+		return err
+	}
+	impl.Logger.Debug("executable answer ready email sent")
+	return nil
+}
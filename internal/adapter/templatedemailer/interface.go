@@ -1,9 +1,10 @@
 package templatedemailer
 
 import (
-	mg "github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
 	"log/slog"
+	"time"
 
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer"
 	c "github.com/bartmika/databoutique-backend/internal/config"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
 )
@@ -13,15 +14,17 @@ type TemplatedEmailer interface {
 	SendNewUserTemporaryPasswordEmail(email, firstName, temporaryPassword string) error
 	SendVerificationEmail(email, verificationCode, firstName string) error
 	SendForgotPasswordEmail(email, verificationCode, firstName string) error
+	SendUserInvitationEmail(email, firstName, tenantName, inviterName, token string, expiresAt time.Time) error
+	SendExecutableAnswerReadyEmail(email, firstName, programName, question, executableID string, isSuccessful bool) error
 }
 
 type templatedEmailer struct {
 	UUID    uuid.Provider
 	Logger  *slog.Logger
-	Emailer mg.Emailer
+	Emailer emailer.Emailer
 }
 
-func NewTemplatedEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider, emailer mg.Emailer) TemplatedEmailer {
+func NewTemplatedEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider, emailerp emailer.Emailer) TemplatedEmailer {
 	// Defensive code: Make sure we have access to the file before proceeding any further with the code.
 	logger.Debug("templated emailer initializing...")
 	logger.Debug("templated emailer initialized")
@@ -29,6 +32,6 @@ func NewTemplatedEmailer(cfg *c.Conf, logger *slog.Logger, uuidp uuid.Provider,
 	return &templatedEmailer{
 		UUID:    uuidp,
 		Logger:  logger,
-		Emailer: emailer,
+		Emailer: emailerp,
 	}
 }
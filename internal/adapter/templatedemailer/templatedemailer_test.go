@@ -0,0 +1,131 @@
+package templatedemailer
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer/memory"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+// TestMain function runs the tests from the project root directory because
+// the email templates are loaded with relative paths.
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func newTestTemplatedEmailer() (*templatedEmailer, *memory.Emailer) {
+	me := memory.NewEmailer("no-reply@example.com", "app.example.com")
+	te := &templatedEmailer{
+		UUID:    uuid.NewProvider(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Emailer: me,
+	}
+	return te, me
+}
+
+func TestSendVerificationEmail(t *testing.T) {
+	te, me := newTestTemplatedEmailer()
+
+	if err := te.SendVerificationEmail("jane@example.com", "code-123", "Jane"); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	msgs := me.MessagesTo("jane@example.com")
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 email but got %v", len(msgs))
+	}
+	if msgs[0].Sender != "no-reply@example.com" {
+		t.Errorf("sender is wrong, got %v", msgs[0].Sender)
+	}
+}
+
+func TestSendUserInvitationEmail(t *testing.T) {
+	te, me := newTestTemplatedEmailer()
+
+	err := te.SendUserInvitationEmail("bob@example.com", "Bob", "Acme", "Jane Doe", "abc.def+ghi", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	msg := me.Last()
+	if msg == nil {
+		t.Fatal("expected an email to be sent")
+	}
+	if msg.Recipient != "bob@example.com" {
+		t.Errorf("recipient is wrong, got %v", msg.Recipient)
+	}
+	if msg.Subject != "You have been invited to join Acme" {
+		t.Errorf("subject is wrong, got %v", msg.Subject)
+	}
+	if !strings.Contains(msg.HTMLContent, "https://app.example.com/accept-invitation?q=abc.def%2Bghi") {
+		t.Errorf("invitation link is missing, got %v", msg.HTMLContent)
+	}
+
+	me.Reset()
+	if len(me.Messages()) != 0 {
+		t.Error("expected no emails after reset")
+	}
+}
+
+func TestSendExecutableAnswerReadyEmail(t *testing.T) {
+	te, me := newTestTemplatedEmailer()
+
+	if err := te.SendExecutableAnswerReadyEmail("jane@example.com", "Jane", "Contracts", "What is due?", "65a1b2c3d4e5f6a7b8c9d0e1", true); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if err := te.SendExecutableAnswerReadyEmail("jane@example.com", "Jane", "Contracts", "What is due?", "65a1b2c3d4e5f6a7b8c9d0e1", false); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	msgs := me.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 emails but got %v", len(msgs))
+	}
+	if msgs[0].Subject != "Your answer from Contracts is ready" {
+		t.Errorf("subject is wrong, got %v", msgs[0].Subject)
+	}
+	if msgs[1].Subject != "Your question to Contracts could not be answered" {
+		t.Errorf("subject is wrong, got %v", msgs[1].Subject)
+	}
+	if !strings.Contains(msgs[0].HTMLContent, "https://app.example.com/executable/65a1b2c3d4e5f6a7b8c9d0e1") {
+		t.Errorf("executable link is missing, got %v", msgs[0].HTMLContent)
+	}
+}
+
+func TestSendExecutableAnswerReadyEmailEscapesHTML(t *testing.T) {
+	te, me := newTestTemplatedEmailer()
+
+	if err := te.SendExecutableAnswerReadyEmail("jane@example.com", "Jane", "<b>Contracts</b>", `<a href="https://evil.example.com">Click</a>`, "65a1b2c3d4e5f6a7b8c9d0e1", true); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	content := me.Last().HTMLContent
+	if strings.Contains(content, "evil.example.com\">") || strings.Contains(content, "<b>Contracts</b>") {
+		t.Errorf("expected the user input to be escaped, got %v", content)
+	}
+	if !strings.Contains(content, "&lt;a href=") {
+		t.Errorf("expected the question to be shown escaped, got %v", content)
+	}
+}
+
+func TestSendUserInvitationEmailEscapesHTML(t *testing.T) {
+	te, me := newTestTemplatedEmailer()
+
+	err := te.SendUserInvitationEmail("bob@example.com", "<i>Bob</i>", `<a href="https://evil.example.com">Acme</a>`, "<script>Jane</script>", "abc.def+ghi", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	content := me.Last().HTMLContent
+	for _, injected := range []string{"<i>Bob</i>", "evil.example.com\">", "<script>"} {
+		if strings.Contains(content, injected) {
+			t.Errorf("expected %q to be escaped, got %v", injected, content)
+		}
+	}
+	if !strings.Contains(content, "https://app.example.com/accept-invitation?q=abc.def%2Bghi") {
+		t.Errorf("invitation link is missing, got %v", content)
+	}
+}
@@ -0,0 +1,55 @@
+package templatedemailer
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/url"
+	"path"
+	"time"
+
+	"log/slog"
+)
+
+func (impl *templatedEmailer) SendUserInvitationEmail(email, firstName, tenantName, inviterName, token string, expiresAt time.Time) error {
+	impl.Logger.Debug("sending user invitation email...")
+
+	// FOR TESTING PURPOSES ONLY.
+	fp := path.Join("templates", "user_invitation.html")
+	tmpl, err := template.ParseFiles(fp)
+	if err != nil {
+		impl.Logger.Error("parsing error", slog.Any("error", err))
+		return err
+	}
+
+	var processed bytes.Buffer
+
+	// Render the HTML template with our data.
+	data := struct {
+		Email          string
+		FirstName      string
+		TenantName     string
+		InviterName    string
+		InvitationLink string
+		ExpiresAt      string
+	}{
+		Email:          email,
+		FirstName:      firstName,
+		TenantName:     tenantName,
+		InviterName:    inviterName,
+		InvitationLink: "https://" + impl.Emailer.GetDomainName() + "/accept-invitation?q=" + url.QueryEscape(token),
+		ExpiresAt:      expiresAt.UTC().Format("January 2, 2006 at 15:04 UTC"),
+	}
+	if err := tmpl.Execute(&processed, data); err != nil {
+		impl.Logger.Error("template execution error", slog.Any("error", err))
+		return err
+	}
+	body := processed.String() // DEVELOPERS NOTE: Convert our long sequence of data into a string.
+
+	if err := impl.Emailer.Send(context.Background(), impl.Emailer.GetSenderEmail(), "You have been invited to join "+tenantName, email, body); err != nil {
+		impl.Logger.Error("sending error", slog.Any("error", err))
+		return err
+	}
+	impl.Logger.Debug("user invitation email sent")
+	return nil
+}
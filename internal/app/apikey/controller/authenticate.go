@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+)
+
+// The duration we trust a previously verified key before we compare the
+// key against the hash again.
+const verifiedKeyExpiry = 5 * time.Minute
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// Authenticate function verifies the plain-text key and returns the key
+// and the user whom created the key. Requests made with the key will act
+// on behalf of this user within the limits of the key's scopes.
+func (impl *APIKeyControllerImpl) Authenticate(ctx context.Context, rawKey string) (*apikey_s.APIKey, *user_s.User, error) {
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != apikey_s.APIKeyPrefix {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	m, err := impl.APIKeyStorer.GetByPrefix(ctx, parts[1])
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database get by prefix error", slog.Any("error", err))
+		return nil, nil, err
+	}
+	if m == nil {
+		impl.Logger.WarnContext(ctx, "api key does not exist", slog.String("prefix", parts[1]))
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if m.Status != apikey_s.APIKeyStatusActive || m.IsExpired() {
+		impl.Logger.WarnContext(ctx, "api key revoked or expired", slog.String("prefix", m.Prefix))
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	// Verify the key against our hash unless it was recently verified.
+	sum := sha256.Sum256([]byte(rawKey))
+	fingerprint := hex.EncodeToString(sum[:])
+	if expiresAt, ok := impl.verified.Load(fingerprint); !ok || time.Now().After(expiresAt.(time.Time)) {
+		if match, _ := impl.Password.ComparePasswordAndHash(rawKey, m.KeyHash); !match {
+			impl.Logger.WarnContext(ctx, "api key does not match hash", slog.String("prefix", m.Prefix))
+			return nil, nil, ErrInvalidAPIKey
+		}
+		impl.verified.Store(fingerprint, time.Now().Add(verifiedKeyExpiry))
+	}
+
+	u, err := impl.UserStorer.GetByID(ctx, m.CreatedByUserID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
+		return nil, nil, err
+	}
+	if u == nil || u.Status != user_s.UserStatusActive {
+		impl.Logger.WarnContext(ctx, "api key owner does not exist or is archived", slog.String("prefix", m.Prefix))
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	// Keys are scoped to the tenant they were created for.
+	u.TenantID = m.TenantID
+	u.TenantName = m.TenantName
+
+	// Keep track of when the key was last used.
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	if err := impl.APIKeyStorer.UpdateLastUsedByID(ctx, m.ID, time.Now(), ipAddress); err != nil {
+		impl.Logger.WarnContext(ctx, "update last used error", slog.Any("error", err))
+	}
+
+	return m, u, nil
+}
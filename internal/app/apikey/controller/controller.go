@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
+	"github.com/bartmika/databoutique-backend/internal/provider/password"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+// APIKeyController Interface for API key business logic controller.
+type APIKeyController interface {
+	Create(ctx context.Context, requestData *APIKeyCreateRequestIDO) (*APIKeyCreateResponseIDO, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*apikey_s.APIKey, error)
+	UpdateByID(ctx context.Context, requestData *APIKeyUpdateRequestIDO) (*apikey_s.APIKey, error)
+	List(ctx context.Context) (*apikey_s.APIKeyListResult, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	Authenticate(ctx context.Context, rawKey string) (*apikey_s.APIKey, *user_s.User, error)
+}
+
+type APIKeyControllerImpl struct {
+	Config       *config.Conf
+	Logger       *slog.Logger
+	UUID         uuid.Provider
+	Password     password.Provider
+	Kmutex       kmutex.Provider
+	DbClient     *mongo.Client
+	UserStorer   user_s.UserStorer
+	APIKeyStorer apikey_s.APIKeyStorer
+	AuditEvent   auditevent_c.AuditEventController
+
+	// verified keeps track of recently verified keys so we do not have to
+	// run the expensive password hash comparison on every API call.
+	verified sync.Map
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	uuidp uuid.Provider,
+	passwordp password.Provider,
+	kmux kmutex.Provider,
+	client *mongo.Client,
+	usr_storer user_s.UserStorer,
+	apikey_storer apikey_s.APIKeyStorer,
+	auditevent auditevent_c.AuditEventController,
+) APIKeyController {
+	s := &APIKeyControllerImpl{
+		Config:       appCfg,
+		Logger:       loggerp,
+		UUID:         uuidp,
+		Password:     passwordp,
+		Kmutex:       kmux,
+		DbClient:     client,
+		UserStorer:   usr_storer,
+		APIKeyStorer: apikey_storer,
+		AuditEvent:   auditevent,
+	}
+	s.Logger.Debug("apikey controller initialization started...")
+	s.Logger.Debug("apikey controller initialized")
+	return s
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+	"github.com/bartmika/databoutique-backend/internal/provider/password"
+)
+
+// The fakes below keep their records in memory. The methods not overridden
+// panic since the embedded interfaces are nil.
+
+type fakeAPIKeyStorer struct {
+	apikey_s.APIKeyStorer
+	keys map[primitive.ObjectID]*apikey_s.APIKey
+}
+
+func (s *fakeAPIKeyStorer) Create(ctx context.Context, m *apikey_s.APIKey) error {
+	s.keys[m.ID] = m
+	return nil
+}
+
+func (s *fakeAPIKeyStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*apikey_s.APIKey, error) {
+	return s.keys[id], nil
+}
+
+func (s *fakeAPIKeyStorer) GetByPrefix(ctx context.Context, prefix string) (*apikey_s.APIKey, error) {
+	for _, m := range s.keys {
+		if m.Prefix == prefix {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeAPIKeyStorer) UpdateByID(ctx context.Context, m *apikey_s.APIKey) error {
+	s.keys[m.ID] = m
+	return nil
+}
+
+func (s *fakeAPIKeyStorer) UpdateLastUsedByID(ctx context.Context, id primitive.ObjectID, lastUsedAt time.Time, ipAddress string) error {
+	s.keys[id].LastUsedAt = lastUsedAt
+	return nil
+}
+
+func newTestController(users ...*user_s.User) (*APIKeyControllerImpl, *fakeAPIKeyStorer) {
+	ks := &fakeAPIKeyStorer{keys: make(map[primitive.ObjectID]*apikey_s.APIKey)}
+	return &APIKeyControllerImpl{
+		Config:       &config.Conf{},
+		Logger:       fakes.NewLogger(),
+		Password:     password.NewProvider(),
+		UserStorer:   fakes.NewUserStorer(users...),
+		APIKeyStorer: ks,
+		AuditEvent:   &fakes.AuditEvent{},
+	}, ks
+}
+
+func newAdministratorContext(u *user_s.User, tid primitive.ObjectID) context.Context {
+	ctx := context.WithValue(context.Background(), constants.SessionUserTenantID, tid)
+	ctx = context.WithValue(ctx, constants.SessionUserID, u.ID)
+	ctx = context.WithValue(ctx, constants.SessionUserRole, u.Role)
+	return ctx
+}
+
+func TestAuthenticate(t *testing.T) {
+	owner := &user_s.User{ID: primitive.NewObjectID(), TenantID: primitive.NewObjectID(), Role: user_s.UserRoleManagement, Status: user_s.UserStatusActive}
+	impl, ks := newTestController(owner)
+	tid := primitive.NewObjectID()
+	ctx := newAdministratorContext(owner, tid)
+
+	res, err := impl.Create(ctx, &APIKeyCreateRequestIDO{Name: "CI", Scopes: []string{"programs:read"}})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !strings.HasPrefix(res.Key, apikey_s.APIKeyPrefix+"_"+res.APIKey.Prefix+"_") || res.APIKey.KeyHash == res.Key {
+		t.Fatalf("unexpected key %q for %+v", res.Key, res.APIKey)
+	}
+
+	key, u, err := impl.Authenticate(context.Background(), res.Key)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if key.ID != res.APIKey.ID || u.ID != owner.ID || u.TenantID != tid || key.LastUsedAt.IsZero() {
+		t.Fatalf("expected the key to act on behalf of its owner in its tenant but got %+v %+v", key, u)
+	}
+
+	// Malformed keys and keys not matching the hash are rejected.
+	secret := strings.TrimPrefix(res.Key, apikey_s.APIKeyPrefix+"_"+res.APIKey.Prefix+"_")
+	for _, rawKey := range []string{
+		"",
+		res.APIKey.Prefix,
+		apikey_s.APIKeyPrefix + "_" + res.APIKey.Prefix,
+		"xyz_" + res.APIKey.Prefix + "_" + secret,
+		apikey_s.APIKeyPrefix + "_unknown_" + secret,
+		res.Key + "x",
+	} {
+		if _, _, err := impl.Authenticate(context.Background(), rawKey); err != ErrInvalidAPIKey {
+			t.Fatalf("expected %q to be invalid but got %v", rawKey, err)
+		}
+	}
+
+	// Revoked and expired keys are rejected even if recently verified.
+	stored := ks.keys[res.APIKey.ID]
+	stored.Status = apikey_s.APIKeyStatusRevoked
+	if _, _, err := impl.Authenticate(context.Background(), res.Key); err != ErrInvalidAPIKey {
+		t.Fatalf("expected a revoked key to be invalid but got %v", err)
+	}
+	stored.Status = apikey_s.APIKeyStatusActive
+	stored.HasExpiry = true
+	stored.ExpiresAt = time.Now().Add(-time.Second)
+	if _, _, err := impl.Authenticate(context.Background(), res.Key); err != ErrInvalidAPIKey {
+		t.Fatalf("expected an expired key to be invalid but got %v", err)
+	}
+
+	// Keys of archived users are rejected.
+	stored.HasExpiry = false
+	owner.Status = user_s.UserStatusArchived
+	if _, _, err := impl.Authenticate(context.Background(), res.Key); err != ErrInvalidAPIKey {
+		t.Fatalf("expected the key of an archived user to be invalid but got %v", err)
+	}
+}
+
+func TestUpdateByIDRejectsPastExpiry(t *testing.T) {
+	owner := &user_s.User{ID: primitive.NewObjectID(), Role: user_s.UserRoleManagement, Status: user_s.UserStatusActive}
+	impl, ks := newTestController(owner)
+	ctx := newAdministratorContext(owner, primitive.NewObjectID())
+
+	res, err := impl.Create(ctx, &APIKeyCreateRequestIDO{Name: "CI", Scopes: []string{"programs:read"}})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	req := &APIKeyUpdateRequestIDO{
+		ID:        res.APIKey.ID,
+		Name:      "CI",
+		Scopes:    []string{"programs:write"},
+		HasExpiry: true,
+		ExpiresAt: time.Now().Add(-time.Hour),
+		Status:    apikey_s.APIKeyStatusActive,
+	}
+	if _, err := impl.UpdateByID(ctx, req); err == nil {
+		t.Fatal("expected an expiry in the past to be rejected")
+	}
+	if ks.keys[res.APIKey.ID].HasExpiry {
+		t.Fatal("expected the key to be unchanged")
+	}
+
+	req.ExpiresAt = time.Now().Add(time.Hour)
+	m, err := impl.UpdateByID(ctx, req)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !m.HasExpiry || m.IsExpired() || !m.HasScope("programs:write") || m.HasScope("programs:read") {
+		t.Fatalf("unexpected key %+v", m)
+	}
+}
+
+func TestIsValidScope(t *testing.T) {
+	for scope, want := range map[string]bool{
+		"programs:read":   true,
+		"programs:write":  true,
+		"programs:delete": false,
+		"unknown:read":    false,
+		"programs":        false,
+		"programs:read:x": false,
+		"":                false,
+	} {
+		if got := isValidScope(scope); got != want {
+			t.Errorf("isValidScope(%q) = %v, want %v", scope, got, want)
+		}
+	}
+}
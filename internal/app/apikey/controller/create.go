@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+type APIKeyCreateRequestIDO struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Scopes      []string  `json:"scopes"`
+	HasExpiry   bool      `json:"has_expiry"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// APIKeyCreateResponseIDO includes the plain-text key which is only ever
+// returned once upon creation.
+type APIKeyCreateResponseIDO struct {
+	APIKey *apikey_s.APIKey `json:"api_key"`
+	Key    string           `json:"key"`
+}
+
+func validateScopes(scopes []string, e map[string]string) {
+	if len(scopes) == 0 {
+		e["scopes"] = "missing value"
+		return
+	}
+	for _, scope := range scopes {
+		if !isValidScope(scope) {
+			e["scopes"] = fmt.Sprintf("invalid scope: %s", scope)
+			return
+		}
+	}
+}
+
+func isValidScope(scope string) bool {
+	parts := strings.Split(scope, ":")
+	if len(parts) != 2 || (parts[1] != "read" && parts[1] != "write") {
+		return false
+	}
+	for _, resource := range apikey_s.APIKeyResources {
+		if resource == parts[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func (impl *APIKeyControllerImpl) validateCreateRequest(dirtyData *APIKeyCreateRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.Name == "" {
+		e["name"] = "missing value"
+	}
+	validateScopes(dirtyData.Scopes, e)
+	if dirtyData.HasExpiry && dirtyData.ExpiresAt.Before(time.Now()) {
+		e["expires_at"] = "must be in the future"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (impl *APIKeyControllerImpl) Create(ctx context.Context, requestData *APIKeyCreateRequestIDO) (*APIKeyCreateResponseIDO, error) {
+	//
+	// Get variables from our user authenticated session.
+	//
+
+	tid, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+	tenantName, _ := ctx.Value(constants.SessionUserTenantName).(string)
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	if err := impl.checkPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	//
+	// Perform our validation and return validation error on any issues detected.
+	//
+
+	if err := impl.validateCreateRequest(requestData); err != nil {
+		impl.Logger.WarnContext(ctx, "validation error", slog.Any("error", err))
+		return nil, err
+	}
+
+	//
+	// Generate our key. The format is `dbk_<prefix>_<secret>` where the
+	// prefix is saved in plain-text so we can lookup the key and the full
+	// key is saved as a hash.
+	//
+
+	prefix, secret, err := generateKey()
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "generate api key error", slog.Any("error", err))
+		return nil, err
+	}
+	rawKey := fmt.Sprintf("%s_%s_%s", apikey_s.APIKeyPrefix, prefix, secret)
+
+	keyHash, err := impl.Password.GenerateHashFromPassword(rawKey)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "hashing error", slog.Any("error", err))
+		return nil, err
+	}
+
+	m := &apikey_s.APIKey{
+		ID:                    primitive.NewObjectID(),
+		TenantID:              tid,
+		TenantName:            tenantName,
+		Name:                  requestData.Name,
+		Description:           requestData.Description,
+		Prefix:                prefix,
+		KeyHash:               keyHash,
+		KeyHashAlgorithm:      impl.Password.AlgorithmName(),
+		Scopes:                requestData.Scopes,
+		HasExpiry:             requestData.HasExpiry,
+		Status:                apikey_s.APIKeyStatusActive,
+		CreatedAt:             time.Now(),
+		CreatedByUserID:       userID,
+		CreatedByUserName:     userName,
+		CreatedFromIPAddress:  ipAddress,
+		ModifiedAt:            time.Now(),
+		ModifiedByUserID:      userID,
+		ModifiedByUserName:    userName,
+		ModifiedFromIPAddress: ipAddress,
+	}
+	if requestData.HasExpiry {
+		m.ExpiresAt = requestData.ExpiresAt
+	}
+
+	if err := impl.APIKeyStorer.Create(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeAPIKey, m.ID, nil, m); err != nil {
+		return nil, err
+	}
+
+	return &APIKeyCreateResponseIDO{
+		APIKey: m,
+		Key:    rawKey,
+	}, nil
+}
+
+func generateKey() (string, string, error) {
+	p := make([]byte, 5)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", err
+	}
+	s := make([]byte, 32)
+	if _, err := rand.Read(s); err != nil {
+		return "", "", err
+	}
+	prefix := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(p))
+	secret := base64.RawURLEncoding.EncodeToString(s)
+	return prefix, secret, nil
+}
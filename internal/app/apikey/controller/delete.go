@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+)
+
+func (impl *APIKeyControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	// STEP 1: Lookup the record or error.
+	m, err := impl.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// STEP 2: Delete from database.
+	if err := impl.APIKeyStorer.DeleteByID(ctx, id); err != nil {
+		impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeAPIKey, m.ID, m, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// checkPermission function returns an error if the authenticated user is
+// not an administrator. Only administrators are allowed to manage keys.
+func (impl *APIKeyControllerImpl) checkPermission(ctx context.Context) error {
+	role, _ := ctx.Value(constants.SessionUserRole).(int8)
+	switch role {
+	case user_s.UserRoleExecutive, user_s.UserRoleManagement:
+		return nil
+	default:
+		impl.Logger.WarnContext(ctx, "you do not have permission to manage api keys", slog.Int("role", int(role)))
+		return httperror.NewForForbiddenWithSingleField("message", "you do not have permission to manage api keys")
+	}
+}
+
+func (impl *APIKeyControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*apikey_s.APIKey, error) {
+	tid, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+
+	if err := impl.checkPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	// Retrieve from our database the record for the specific id.
+	m, err := impl.APIKeyStorer.GetByID(ctx, id)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if m == nil || m.TenantID != tid {
+		return nil, httperror.NewForSingleField(http.StatusNotFound, "id", "does not exist")
+	}
+	return m, nil
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+type APIKeyUpdateRequestIDO struct {
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Scopes      []string           `json:"scopes"`
+	HasExpiry   bool               `json:"has_expiry"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+	Status      int8               `json:"status"`
+}
+
+func (impl *APIKeyControllerImpl) validateUpdateRequest(dirtyData *APIKeyUpdateRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.ID.IsZero() {
+		e["id"] = "missing value"
+	}
+	if dirtyData.Name == "" {
+		e["name"] = "missing value"
+	}
+	validateScopes(dirtyData.Scopes, e)
+	if dirtyData.HasExpiry && dirtyData.ExpiresAt.Before(time.Now()) {
+		e["expires_at"] = "must be in the future"
+	}
+	if dirtyData.Status != apikey_s.APIKeyStatusActive && dirtyData.Status != apikey_s.APIKeyStatusRevoked {
+		e["status"] = "invalid value"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (impl *APIKeyControllerImpl) UpdateByID(ctx context.Context, requestData *APIKeyUpdateRequestIDO) (*apikey_s.APIKey, error) {
+	//
+	// Get variables from our user authenticated session.
+	//
+
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	if err := impl.validateUpdateRequest(requestData); err != nil {
+		impl.Logger.WarnContext(ctx, "validation error", slog.Any("error", err))
+		return nil, err
+	}
+
+	m, err := impl.GetByID(ctx, requestData.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Return `412 precondition failed` if the record was modified since the client retrieved it.
+	if err := versioning.CheckExpectedVersion(ctx, m.Version); err != nil {
+		return nil, err
+	}
+	before := *m
+
+	m.Name = requestData.Name
+	m.Description = requestData.Description
+	m.Scopes = requestData.Scopes
+	m.HasExpiry = requestData.HasExpiry
+	m.ExpiresAt = time.Time{}
+	if requestData.HasExpiry {
+		m.ExpiresAt = requestData.ExpiresAt
+	}
+	m.Status = requestData.Status
+	m.ModifiedAt = time.Now()
+	m.ModifiedByUserID = userID
+	m.ModifiedByUserName = userName
+	m.ModifiedFromIPAddress = ipAddress
+
+	if err := impl.APIKeyStorer.UpdateByID(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeAPIKey, m.ID, &before, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,127 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	APIKeyStatusActive  = 1
+	APIKeyStatusRevoked = 2
+
+	// APIKeyPrefix is prepended to every API key so keys can be identified
+	// by secret scanners and by our `Authorization` header processing.
+	APIKeyPrefix = "dbk"
+)
+
+// Scopes are formatted as `<resource>:<action>` where the action is either
+// `read` (for `GET` requests) or `write` (for every other method).
+var APIKeyResources = []string{
+	"tenants",
+	"users",
+	"how-hear-about-us-items",
+	"attachments",
+	"assistant-files",
+	"assistants",
+	"assistant-threads",
+	"assistant-messages",
+	"program-categories",
+	"upload-directories",
+	"upload-files",
+	"programs",
+	"executables",
+}
+
+type APIKey struct {
+	ID                    primitive.ObjectID `bson:"_id" json:"id"`
+	TenantID              primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	TenantName            string             `bson:"tenant_name" json:"tenant_name"`
+	Name                  string             `bson:"name" json:"name"`
+	Description           string             `bson:"description" json:"description"`
+	Prefix                string             `bson:"prefix" json:"prefix"`
+	KeyHash               string             `bson:"key_hash" json:"-"`
+	KeyHashAlgorithm      string             `bson:"key_hash_algorithm" json:"-"`
+	Scopes                []string           `bson:"scopes" json:"scopes"`
+	HasExpiry             bool               `bson:"has_expiry" json:"has_expiry"`
+	ExpiresAt             time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsedAt            time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	LastUsedFromIPAddress string             `bson:"last_used_from_ip_address,omitempty" json:"last_used_from_ip_address,omitempty"`
+	Status                int8               `bson:"status" json:"status"`
+	CreatedAt             time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID       primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id,omitempty"`
+	CreatedByUserName     string             `bson:"created_by_user_name" json:"created_by_user_name"`
+	CreatedFromIPAddress  string             `bson:"created_from_ip_address" json:"created_from_ip_address"`
+	ModifiedAt            time.Time          `bson:"modified_at" json:"modified_at"`
+	ModifiedByUserID      primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
+	ModifiedByUserName    string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
+	ModifiedFromIPAddress string             `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version               int64              `bson:"version" json:"version"`
+}
+
+// IsExpired function returns true if the API key has an expiry which has passed.
+func (m *APIKey) IsExpired() bool {
+	return m.HasExpiry && time.Now().After(m.ExpiresAt)
+}
+
+// HasScope function returns true if the API key was granted the scope.
+func (m *APIKey) HasScope(scope string) bool {
+	for _, s := range m.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeyListResult struct {
+	Results []*APIKey `json:"results"`
+}
+
+// APIKeyStorer Interface for API keys.
+type APIKeyStorer interface {
+	Create(ctx context.Context, m *APIKey) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	UpdateByID(ctx context.Context, m *APIKey) error
+	UpdateLastUsedByID(ctx context.Context, id primitive.ObjectID, lastUsedAt time.Time, ipAddress string) error
+	ListByTenantID(ctx context.Context, tid primitive.ObjectID) (*APIKeyListResult, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+type APIKeyStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) APIKeyStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("api_keys")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "prefix", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &APIKeyStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,18 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (impl APIKeyStorerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	_, err := impl.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
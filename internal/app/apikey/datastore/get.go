@@ -0,0 +1,42 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (impl APIKeyStorerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*APIKey, error) {
+	filter := bson.D{{"_id", id}}
+
+	var result APIKey
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (impl APIKeyStorerImpl) GetByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	filter := bson.D{{"prefix", prefix}}
+
+	var result APIKey
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by prefix error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
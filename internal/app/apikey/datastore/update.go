@@ -0,0 +1,59 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+func (impl APIKeyStorerImpl) UpdateByID(ctx context.Context, m *APIKey) error {
+	// Only update the record if nobody else updated it since it was retrieved.
+	filter := versioning.Filter(m.ID, m.Version)
+	m.Version++
+
+	update := bson.M{ // DEVELOPERS NOTE: https://stackoverflow.com/a/60946010
+		"$set": m,
+	}
+
+	// execute the UpdateOne() function to update the first matching document
+	res, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		m.Version--
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	if res.MatchedCount == 0 {
+		m.Version--
+		return versioning.ErrConflict
+	}
+	return nil
+}
+
+// UpdateLastUsedByID function will update the last used time of the API key
+// only if it was not updated in the past minute. We do this so our API calls
+// do not perform a write for every request.
+func (impl APIKeyStorerImpl) UpdateLastUsedByID(ctx context.Context, id primitive.ObjectID, lastUsedAt time.Time, ipAddress string) error {
+	filter := bson.M{
+		"_id": id,
+		"$or": []bson.M{
+			{"last_used_at": bson.M{"$exists": false}},
+			{"last_used_at": bson.M{"$lt": lastUsedAt.Add(-1 * time.Minute)}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"last_used_at":              lastUsedAt,
+			"last_used_from_ip_address": ipAddress,
+		},
+	}
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update last used error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
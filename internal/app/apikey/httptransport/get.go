@@ -0,0 +1,38 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.GetByID(ctx, objectID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", versioning.ETag(res.Version))
+	MarshalDetailResponse(res, w)
+}
+
+func MarshalDetailResponse(res *apikey_s.APIKey, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
@@ -0,0 +1,21 @@
+package httptransport
+
+import (
+	"log/slog"
+
+	apikey_c "github.com/bartmika/databoutique-backend/internal/app/apikey/controller"
+)
+
+// Handler Creates http request handler
+type Handler struct {
+	Logger     *slog.Logger
+	Controller apikey_c.APIKeyController
+}
+
+// NewHandler Constructor
+func NewHandler(loggerp *slog.Logger, c apikey_c.APIKeyController) *Handler {
+	return &Handler{
+		Logger:     loggerp,
+		Controller: c,
+	}
+}
@@ -0,0 +1,28 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.Controller.List(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalListResponse(res, w)
+}
+
+func MarshalListResponse(res *apikey_s.APIKeyListResult, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
@@ -12,6 +12,7 @@ import (
 	assistant_s "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
 	t_s "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
 	assistantfile_s "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
@@ -44,6 +45,7 @@ type AssistantControllerImpl struct {
 	AssistantFileStorer assistantfile_s.AssistantFileStorer
 	AssistantStorer     t_s.AssistantStorer
 	TemplatedEmailer    templatedemailer.TemplatedEmailer
+	AuditEvent          auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -59,6 +61,7 @@ func NewController(
 	usr_storer user_s.UserStorer,
 	af_storer assistantfile_s.AssistantFileStorer,
 	a_storer assistant_s.AssistantStorer,
+	auditevent auditevent_c.AuditEventController,
 ) AssistantController {
 	s := &AssistantControllerImpl{
 		Config:              appCfg,
@@ -73,6 +76,7 @@ func NewController(
 		UserStorer:          usr_storer,
 		AssistantFileStorer: af_storer,
 		AssistantStorer:     a_storer,
+		AuditEvent:          auditevent,
 	}
 	s.Logger.Debug("assistant controller initialization started...")
 	s.Logger.Debug("assistant controller initialized")
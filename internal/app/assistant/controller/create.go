@@ -11,7 +11,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	assistant_s "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 	"github.com/sashabaranov/go-openai"
 )
@@ -84,7 +86,7 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -114,7 +116,7 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 
 		// Save to our database.
 		if err := impl.AssistantStorer.Create(sessCtx, m); err != nil {
-			impl.Logger.Error("database create error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -127,11 +129,11 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 			// Step 1: Lookup original.
 			assistantFile, err := impl.AssistantFileStorer.GetByID(sessCtx, assistantFileID)
 			if err != nil {
-				impl.Logger.Error("fetching assistant file error", slog.Any("error", err))
+				impl.Logger.ErrorContext(ctx, "fetching assistant file error", slog.Any("error", err))
 				return nil, err
 			}
 			if assistantFile == nil {
-				impl.Logger.Error("assistant file does not exist error", slog.Any("assistantFileID", assistantFileID))
+				impl.Logger.ErrorContext(ctx, "assistant file does not exist error", slog.Any("assistantFileID", assistantFileID))
 				return nil, httperror.NewForBadRequestWithSingleField("assistant_file_ids", assistantFileID.Hex()+" assistant file id does not exist")
 			}
 			af := &assistant_s.AssistantFileOption{
@@ -146,7 +148,7 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 
 		// Save to our database.
 		if err := impl.AssistantStorer.UpdateByID(sessCtx, m); err != nil {
-			impl.Logger.Error("database update error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database update error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -156,7 +158,7 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tid)
 		if err != nil {
-			impl.Logger.Error("failed getting openai credentials",
+			impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -165,14 +167,14 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("openai initializing...")
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
-		impl.Logger.Debug("openai initialized")
+		impl.Logger.DebugContext(ctx, "openai initializing...")
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+		impl.Logger.DebugContext(ctx, "openai initialized")
 
 		// Get all the files which we will pre-train the LLM.
 		afIDs := m.GetAssistantFileIDs()
 
-		impl.Logger.Debug("beginning to create assistant...",
+		impl.Logger.DebugContext(ctx, "beginning to create assistant...",
 			slog.String("tenant_id", tid.Hex()),
 			slog.Any("name", m.Name),
 			slog.Any("model", m.Model),
@@ -182,7 +184,7 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 			slog.String("tenant_id", tid.Hex()))
 
 		// Create an assistant.
-		assistant, err := client.CreateAssistant(context.Background(), openai.AssistantRequest{
+		assistant, err := client.CreateAssistant(context.WithoutCancel(ctx), openai.AssistantRequest{
 			Name:         &m.Name,
 			Model:        m.Model,
 			Instructions: &m.Instructions,
@@ -190,7 +192,7 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 			FileIDs:      afIDs,
 		})
 		if err != nil {
-			impl.Logger.Error("failed creating assistant",
+			impl.Logger.ErrorContext(ctx, "failed creating assistant",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("name", assistant.Name),
 				slog.Any("model", assistant.Model),
@@ -201,17 +203,21 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 			return nil, err
 		}
 		if isStructEmpty(assistant) {
-			impl.Logger.Error("no openai assistant returned", slog.Any("assistant", assistant))
+			impl.Logger.ErrorContext(ctx, "no openai assistant returned", slog.Any("assistant", assistant))
 			return "", errors.New("no openai file returned")
 		}
 
-		impl.Logger.Debug("finished creating assistant",
+		impl.Logger.DebugContext(ctx, "finished creating assistant",
 			slog.String("tenant_id", tid.Hex()),
 			slog.Any("assistant_id", assistant.ID))
 
 		m.OpenAIAssistantID = assistant.ID
 		if err := impl.AssistantStorer.UpdateByID(sessCtx, m); err != nil {
-			impl.Logger.Error("database update error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database update error", slog.Any("error", err))
+			return nil, err
+		}
+
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeAssistant, m.ID, nil, m); err != nil {
 			return nil, err
 		}
 
@@ -225,7 +231,7 @@ func (impl *AssistantControllerImpl) Create(ctx context.Context, requestData *As
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -5,11 +5,12 @@ import (
 	"errors"
 	"log/slog"
 
-	"github.com/sashabaranov/go-openai"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 )
 
 func (impl *AssistantControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
@@ -22,7 +23,7 @@ func (impl *AssistantControllerImpl) DeleteByID(ctx context.Context, id primitiv
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -34,24 +35,27 @@ func (impl *AssistantControllerImpl) DeleteByID(ctx context.Context, id primitiv
 		// STEP 1: Lookup the record or error.
 		assistant, err := impl.GetByID(sessCtx, id)
 		if err != nil {
-			impl.Logger.Error("database get by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 			return nil, err
 		}
 		if assistant == nil {
-			impl.Logger.Error("database returns nothing from get by id")
+			impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 			return nil, err
 		}
 
 		// STEP 2: Delete from database.
 		if err := impl.AssistantStorer.DeleteByID(sessCtx, id); err != nil {
-			impl.Logger.Error("database delete by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeAssistant, assistant.ID, assistant, nil); err != nil {
 			return nil, err
 		}
 
 		// STEP 3: Delete from OpenAI.
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tenantID)
 		if err != nil {
-			impl.Logger.Error("failed file upload to openai",
+			impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -60,7 +64,7 @@ func (impl *AssistantControllerImpl) DeleteByID(ctx context.Context, id primitiv
 			return nil, errors.New("no openai credentials returned")
 		}
 		if err := impl.deleteOpanAI(sessCtx, assistant.OpenAIAssistantID, creds.APIKey, creds.OrgKey); err != nil {
-			impl.Logger.Error("failed deleting assitant from openai", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "failed deleting assitant from openai", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -69,7 +73,7 @@ func (impl *AssistantControllerImpl) DeleteByID(ctx context.Context, id primitiv
 
 	// Start a transaction
 	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return err
 	}
@@ -78,13 +82,13 @@ func (impl *AssistantControllerImpl) DeleteByID(ctx context.Context, id primitiv
 }
 
 func (impl *AssistantControllerImpl) deleteOpanAI(ctx context.Context, assitantID string, apikey string, orgKey string) error {
-	impl.Logger.Debug("openai initializing...")
-	client := openai.NewOrgClient(apikey, orgKey)
-	impl.Logger.Debug("openai initialized")
+	impl.Logger.DebugContext(ctx, "openai initializing...")
+	client := metrics.NewOpenAIOrgClient(apikey, orgKey)
+	impl.Logger.DebugContext(ctx, "openai initialized")
 	if _, err := client.DeleteAssistant(ctx, assitantID); err != nil {
-		impl.Logger.Error("failed deleting open ai assitant", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "failed deleting open ai assitant", slog.Any("error", err))
 		return err
 	}
-	impl.Logger.Debug("deleted openai assitant from assistant api", slog.Any("assistant_id", assitantID))
+	impl.Logger.DebugContext(ctx, "deleted openai assitant from assistant api", slog.Any("assistant_id", assitantID))
 	return nil
 }
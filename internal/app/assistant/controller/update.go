@@ -10,8 +10,11 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	assistant_s "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 	"github.com/sashabaranov/go-openai"
 )
 
@@ -74,7 +77,7 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -86,13 +89,18 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 		// Lookup the assistant in our database, else return a `400 Bad Request` error.
 		ou, err := impl.AssistantStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if ou == nil {
-			impl.Logger.Warn("assistant does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "assistant does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, ou.Version); err != nil {
+			return nil, err
+		}
+		before := *ou
 
 		//
 		// Update base.
@@ -109,7 +117,7 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 		ou.ModifiedFromIPAddress = ipAddress
 
 		if err := impl.AssistantStorer.UpdateByID(sessCtx, ou); err != nil {
-			impl.Logger.Error("assistant update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "assistant update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -122,11 +130,11 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 			// Step 1: Lookup original.
 			assistantFile, err := impl.AssistantFileStorer.GetByID(sessCtx, assistantFileID)
 			if err != nil {
-				impl.Logger.Error("fetching assistant file error", slog.Any("error", err))
+				impl.Logger.ErrorContext(ctx, "fetching assistant file error", slog.Any("error", err))
 				return nil, err
 			}
 			if assistantFile == nil {
-				impl.Logger.Error("assistant file does not exist error", slog.Any("assistantFileID", assistantFileID))
+				impl.Logger.ErrorContext(ctx, "assistant file does not exist error", slog.Any("assistantFileID", assistantFileID))
 				return nil, httperror.NewForBadRequestWithSingleField("assistant_file_ids", assistantFileID.Hex()+" assistant file id does not exist")
 			}
 			af := &assistant_s.AssistantFileOption{
@@ -145,7 +153,7 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tid)
 		if err != nil {
-			impl.Logger.Error("failed to get OpenAI credentials",
+			impl.Logger.ErrorContext(ctx, "failed to get OpenAI credentials",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -154,19 +162,19 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("openai initializing...")
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
-		impl.Logger.Debug("openai initialized")
+		impl.Logger.DebugContext(ctx, "openai initializing...")
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+		impl.Logger.DebugContext(ctx, "openai initialized")
 
 		assistant, err := client.RetrieveAssistant(sessCtx, ou.OpenAIAssistantID)
 		if err != nil {
-			impl.Logger.Error("failed to get OpenAI credentials",
+			impl.Logger.ErrorContext(ctx, "failed to get OpenAI credentials",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if isStructEmpty(assistant) {
-			impl.Logger.Error("no openai assistant returned")
+			impl.Logger.ErrorContext(ctx, "no openai assistant returned")
 			return "", errors.New("no openai assistant returned")
 		}
 
@@ -188,12 +196,16 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 			// Metadata
 		}
 		if _, err := client.ModifyAssistant(sessCtx, assistant.ID, modReq); err != nil {
-			impl.Logger.Error("failed modify assistant",
+			impl.Logger.ErrorContext(ctx, "failed modify assistant",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeAssistant, ou.ID, &before, ou); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -204,7 +216,7 @@ func (impl *AssistantControllerImpl) UpdateByID(ctx context.Context, requestData
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
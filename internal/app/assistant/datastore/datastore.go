@@ -42,6 +42,7 @@ type Assistant struct {
 	ModifiedByUserID      primitive.ObjectID     `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
 	ModifiedByUserName    string                 `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedFromIPAddress string                 `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version               int64                  `bson:"version" json:"version"`
 	AssistantFiles        []*AssistantFileOption `bson:"assistant_files" json:"assistant_files,omitempty"`
 	OpenAIAssistantID     string                 `bson:"openai_assistant_id" json:"openai_assistant_id"` // https://platform.openai.com/docs/assistants/tools/supported-files
 }
@@ -48,7 +48,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 func MarshalCreateResponse(res *assistant_s.Assistant, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
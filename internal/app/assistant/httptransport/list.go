@@ -66,7 +66,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 
 func MarshalListResponse(res *assistant_s.AssistantPaginationListResult, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
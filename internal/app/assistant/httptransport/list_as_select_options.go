@@ -30,7 +30,7 @@ func (h *Handler) ListAsSelectOptionByFilter(w http.ResponseWriter, r *http.Requ
 
 func MarshalListAsSelectOptionResponse(res []*sub_s.AssistantAsSelectOption, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -8,6 +8,7 @@ import (
 	assistant_c "github.com/bartmika/databoutique-backend/internal/app/assistant/controller"
 	assistant_s "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func UnmarshalUpdateRequest(ctx context.Context, r *http.Request) (*assistant_c.AssistantUpdateRequestIDO, error) {
@@ -40,12 +41,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(org.Version))
 	MarshalUpdateResponse(org, w)
 }
 
 func MarshalUpdateResponse(res *assistant_s.Assistant, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -7,10 +7,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
-	mg "github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer"
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	assistantfile_s "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
 	domain "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
@@ -33,11 +34,12 @@ type AssistantFileControllerImpl struct {
 	Logger              *slog.Logger
 	UUID                uuid.Provider
 	S3                  s3_storage.S3Storager
-	Emailer             mg.Emailer
+	Emailer             emailer.Emailer
 	DbClient            *mongo.Client
 	TenantStorer        tenant_s.TenantStorer
 	AssistantFileStorer assistantfile_s.AssistantFileStorer
 	UserStorer          user_s.UserStorer
+	AuditEvent          auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -46,21 +48,23 @@ func NewController(
 	uuidp uuid.Provider,
 	s3 s3_storage.S3Storager,
 	client *mongo.Client,
-	emailer mg.Emailer,
+	emailerp emailer.Emailer,
 	t_storer tenant_s.TenantStorer,
 	org_storer assistantfile_s.AssistantFileStorer,
 	usr_storer user_s.UserStorer,
+	auditevent auditevent_c.AuditEventController,
 ) AssistantFileController {
 	s := &AssistantFileControllerImpl{
 		Config:              appCfg,
 		Logger:              loggerp,
 		UUID:                uuidp,
 		S3:                  s3,
-		Emailer:             emailer,
+		Emailer:             emailerp,
 		DbClient:            client,
 		TenantStorer:        t_storer,
 		AssistantFileStorer: org_storer,
 		UserStorer:          usr_storer,
+		AuditEvent:          auditevent,
 	}
 	s.Logger.Debug("assistantfile controller initialization started...")
 	s.Logger.Debug("assistantfile controller initialized")
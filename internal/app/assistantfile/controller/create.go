@@ -15,7 +15,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	a_d "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -23,11 +25,11 @@ import (
 // https://freedium.cfd/https://story.tomasen.org/openais-assistant-api-in-go-a-practical-guide-4b9e7243ebff
 
 type AssistantFileCreateRequestIDO struct {
-	Name        string
-	Description string
-	FileName    string
-	FileType    string
-	File        multipart.File
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	FileName    string         `json:"-"`
+	FileType    string         `json:"-"`
+	File        multipart.File `json:"file"`
 }
 
 func validateCreateRequest(dirtyData *AssistantFileCreateRequestIDO) error {
@@ -65,7 +67,7 @@ func (impl *AssistantFileControllerImpl) Create(ctx context.Context, req *Assist
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -80,7 +82,7 @@ func (impl *AssistantFileControllerImpl) Create(ctx context.Context, req *Assist
 		objectKey := fmt.Sprintf("tenant/%v/%v/%v", tenantID.Hex(), directory, req.FileName)
 
 		// For debugging purposes only.
-		impl.Logger.Debug("pre-upload meta",
+		impl.Logger.DebugContext(ctx, "pre-upload meta",
 			slog.String("FileName", req.FileName),
 			slog.String("FileType", req.FileType),
 			slog.String("Directory", directory),
@@ -94,19 +96,19 @@ func (impl *AssistantFileControllerImpl) Create(ctx context.Context, req *Assist
 		)
 
 		// go func(file multipart.File, objkey string) {
-		// 	impl.Logger.Debug("beginning private s3 file upload...")
+		// 	impl.Logger.DebugContext(ctx, "beginning private s3 file upload...")
 		// 	if err := impl.S3.UploadContentFromMulipart(context.Background(), objkey, file); err != nil {
-		// 		impl.Logger.Error("private s3 file upload error", slog.Any("error", err))
+		// 		impl.Logger.ErrorContext(ctx, "private s3 file upload error", slog.Any("error", err))
 		// 		// Do not return an error, simply continue this function as there might
 		// 		// be a case were the file was removed on the s3 bucket by ourselves
 		// 		// or some other reason.
 		// 	}
-		// 	impl.Logger.Debug("Finished private s3 file upload")
+		// 	impl.Logger.DebugContext(ctx, "Finished private s3 file upload")
 		// }(req.File, objectKey)
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tenantID)
 		if err != nil {
-			impl.Logger.Error("failed file upload to openai",
+			impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -115,20 +117,20 @@ func (impl *AssistantFileControllerImpl) Create(ctx context.Context, req *Assist
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("beginning openai file upload...", slog.String("tenant_id", tenantID.Hex()))
+		impl.Logger.DebugContext(ctx, "beginning openai file upload...", slog.String("tenant_id", tenantID.Hex()))
 		fileID, err := impl.uploadContentFromMulipart(context.Background(), req.FileName, req.File, creds.APIKey, creds.OrgKey)
 		if err != nil {
-			impl.Logger.Error("failed file upload to openai",
+			impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if fileID == "" {
-			impl.Logger.Debug("no openai `file_id` returned",
+			impl.Logger.DebugContext(ctx, "no openai `file_id` returned",
 				slog.String("tenant_id", tenantID.Hex()))
 			return nil, errors.New("failed file upload to openai as no `file_id` was returned")
 		}
-		impl.Logger.Debug("finished file upload to openai",
+		impl.Logger.DebugContext(ctx, "finished file upload to openai",
 			slog.String("tenant_id", tenantID.Hex()),
 			slog.Any("file_id", fileID))
 
@@ -152,18 +154,21 @@ func (impl *AssistantFileControllerImpl) Create(ctx context.Context, req *Assist
 			OpenAIFileID:       fileID,
 		}
 		if err := impl.AssistantFileStorer.Create(sessCtx, res); err != nil {
-			impl.Logger.Error("assistant file create error",
+			impl.Logger.ErrorContext(ctx, "assistant file create error",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeAssistantFile, res.ID, nil, res); err != nil {
+			return nil, err
+		}
 		return res, nil
 	}
 
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -178,28 +183,28 @@ func isStructEmpty(s interface{}) bool {
 }
 
 func (impl *AssistantFileControllerImpl) uploadContentFromMulipart(ctx context.Context, filename string, file multipart.File, apikey string, orgKey string) (string, error) {
-	impl.Logger.Debug("openai initializing...")
-	client := openai.NewOrgClient(apikey, orgKey)
-	impl.Logger.Debug("openai initialized")
+	impl.Logger.DebugContext(ctx, "openai initializing...")
+	client := metrics.NewOpenAIOrgClient(apikey, orgKey)
+	impl.Logger.DebugContext(ctx, "openai initialized")
 
 	// Read the contents of the file into a byte slice
 	fileBytes, err := ioutil.ReadAll(file)
 	if err != nil {
-		impl.Logger.Error("failed converting multipart file into file bytes", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "failed converting multipart file into file bytes", slog.Any("error", err))
 		return "", err
 	}
 
-	openAIFile, err := client.CreateFileBytes(context.Background(), openai.FileBytesRequest{
+	openAIFile, err := client.CreateFileBytes(context.WithoutCancel(ctx), openai.FileBytesRequest{
 		Name:    filename,
 		Bytes:   fileBytes,
 		Purpose: openai.PurposeAssistants,
 	})
 	if err != nil {
-		impl.Logger.Error("failed uploaded openai file", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "failed uploaded openai file", slog.Any("error", err))
 		return "", err
 	}
 	if isStructEmpty(openAIFile) {
-		impl.Logger.Error("no openai file returned")
+		impl.Logger.ErrorContext(ctx, "no openai file returned")
 		return "", errors.New("no openai file returned")
 	}
 
@@ -5,13 +5,14 @@ import (
 	"errors"
 	"log/slog"
 
-	"github.com/sashabaranov/go-openai"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	attch_d "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -22,7 +23,7 @@ func (impl *AssistantFileControllerImpl) DeleteByID(ctx context.Context, id prim
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		impl.Logger.Error("authenticated user is not staff role error",
+		impl.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
@@ -34,7 +35,7 @@ func (impl *AssistantFileControllerImpl) DeleteByID(ctx context.Context, id prim
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -45,24 +46,28 @@ func (impl *AssistantFileControllerImpl) DeleteByID(ctx context.Context, id prim
 
 		// Update the database.
 		assistantfile, err := impl.GetByID(sessCtx, id)
-		assistantfile.Status = attch_d.StatusArchived
 		if err != nil {
-			impl.Logger.Error("database get by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 			return nil, err
 		}
 		if assistantfile == nil {
-			impl.Logger.Error("database returns nothing from get by id")
+			impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 			return nil, err
 		}
+		before := *assistantfile
+		assistantfile.Status = attch_d.StatusArchived
 		// // Security: Prevent deletion of root user(s).
 		// if assistantfile.Type == attch_d.RootType {
-		// 	impl.Logger.Warn("root assistantfile cannot be deleted error")
+		// 	impl.Logger.WarnContext(ctx, "root assistantfile cannot be deleted error")
 		// 	return httperror.NewForForbiddenWithSingleField("role", "root assistantfile cannot be deleted")
 		// }
 
 		// Save to the database the modified assistantfile.
 		if err := impl.AssistantFileStorer.UpdateByID(sessCtx, assistantfile); err != nil {
-			impl.Logger.Error("database update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeAssistantFile, assistantfile.ID, &before, assistantfile); err != nil {
 			return nil, err
 		}
 		return nil, nil
@@ -70,7 +75,7 @@ func (impl *AssistantFileControllerImpl) DeleteByID(ctx context.Context, id prim
 
 	// Start a transaction
 	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return err
 	}
@@ -86,7 +91,7 @@ func (impl *AssistantFileControllerImpl) PermanentlyDeleteByID(ctx context.Conte
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		impl.Logger.Error("authenticated user is not staff role error",
+		impl.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
@@ -98,7 +103,7 @@ func (impl *AssistantFileControllerImpl) PermanentlyDeleteByID(ctx context.Conte
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -110,17 +115,17 @@ func (impl *AssistantFileControllerImpl) PermanentlyDeleteByID(ctx context.Conte
 		// Update the database.
 		assistantfile, err := impl.GetByID(sessCtx, id)
 		if err != nil {
-			impl.Logger.Error("database get by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 			return nil, err
 		}
 		if assistantfile == nil {
-			impl.Logger.Error("database returns nothing from get by id")
-			return nil, errors.New("does not exist")
+			impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tenantID)
 		if err != nil {
-			impl.Logger.Error("failed file upload to openai",
+			impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -131,21 +136,24 @@ func (impl *AssistantFileControllerImpl) PermanentlyDeleteByID(ctx context.Conte
 
 		// // Proceed to delete the physical files from AWS s3.
 		// if err := impl.S3.DeleteByKeys(ctx, []string{assistantfile.ObjectKey}); err != nil {
-		// 	impl.Logger.Warn("s3 delete by keys error", slog.Any("error", err))
+		// 	impl.Logger.WarnContext(ctx, "s3 delete by keys error", slog.Any("error", err))
 		// 	// Do not return an error, simply continue this function as there might
 		// 	// be a case were the file was removed on the s3 bucket by ourselves
 		// 	// or some other reason.
 		// }
-		// impl.Logger.Debug("deleted from s3", slog.Any("assistantfile_id", id))
+		// impl.Logger.DebugContext(ctx, "deleted from s3", slog.Any("assistantfile_id", id))
 
 		if err := impl.AssistantFileStorer.DeleteByID(sessCtx, assistantfile.ID); err != nil {
-			impl.Logger.Error("database delete by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+			return nil, err
+		}
+		impl.Logger.DebugContext(ctx, "deleted from database", slog.Any("assistantfile_id", id))
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeAssistantFile, assistantfile.ID, assistantfile, nil); err != nil {
 			return nil, err
 		}
-		impl.Logger.Debug("deleted from database", slog.Any("assistantfile_id", id))
 
 		if err := impl.deleteOpanAIFile(sessCtx, assistantfile.OpenAIFileID, creds.APIKey, creds.OrgKey); err != nil {
-			impl.Logger.Error("failed deleting file from openai", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "failed deleting file from openai", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -154,7 +162,7 @@ func (impl *AssistantFileControllerImpl) PermanentlyDeleteByID(ctx context.Conte
 
 	// Start a transaction
 	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return err
 	}
@@ -163,13 +171,13 @@ func (impl *AssistantFileControllerImpl) PermanentlyDeleteByID(ctx context.Conte
 }
 
 func (impl *AssistantFileControllerImpl) deleteOpanAIFile(ctx context.Context, fileID string, apikey string, orgKey string) error {
-	impl.Logger.Debug("openai initializing...")
-	client := openai.NewOrgClient(apikey, orgKey)
-	impl.Logger.Debug("openai initialized")
+	impl.Logger.DebugContext(ctx, "openai initializing...")
+	client := metrics.NewOpenAIOrgClient(apikey, orgKey)
+	impl.Logger.DebugContext(ctx, "openai initialized")
 	if err := client.DeleteFile(ctx, fileID); err != nil {
-		impl.Logger.Error("failed deleting open ai file", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "failed deleting open ai file", slog.Any("error", err))
 		return err
 	}
-	impl.Logger.Debug("deleted openai file from assistant api", slog.Any("assistantfile_id", fileID))
+	impl.Logger.DebugContext(ctx, "deleted openai file from assistant api", slog.Any("assistantfile_id", fileID))
 	return nil
 }
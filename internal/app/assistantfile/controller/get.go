@@ -6,6 +6,7 @@ import (
 	"time"
 
 	domain "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -16,7 +17,7 @@ func (c *AssistantFileControllerImpl) GetByID(ctx context.Context, id primitive.
 	//
 	// If user is not administrator nor belongs to the assistantfile then error.
 	// if userRole != user_d.UserRoleRoot && id != userAssistantFileID {
-	// 	c.Logger.Error("authenticated user is not staff role nor belongs to the assistantfile error",
+	// 	c.Logger.ErrorContext(ctx, "authenticated user is not staff role nor belongs to the assistantfile error",
 	// 		slog.Any("userRole", userRole),
 	// 		slog.Any("userAssistantFileID", userAssistantFileID))
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this assistantfile")
@@ -25,14 +26,17 @@ func (c *AssistantFileControllerImpl) GetByID(ctx context.Context, id primitive.
 	// Retrieve from our database the record for the specific id.
 	m, err := c.AssistantFileStorer.GetByID(ctx, id)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
 
 	// Generate the URL.
 	fileURL, err := c.S3.GetPresignedURL(ctx, m.ObjectKey, 5*time.Minute)
 	if err != nil {
-		c.Logger.Error("s3 failed get presigned url error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "s3 failed get presigned url error", slog.Any("error", err))
 		return nil, err
 	}
 
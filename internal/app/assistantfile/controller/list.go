@@ -23,20 +23,20 @@ func (c *AssistantFileControllerImpl) ListByFilter(ctx context.Context, f *domai
 		f.TenantID = orgID // Force tenant tenancy restrictions.
 	}
 
-	c.Logger.Debug("fetching assistant files now...", slog.Any("userID", userID))
+	c.Logger.DebugContext(ctx, "fetching assistant files now...", slog.Any("userID", userID))
 
 	aa, err := c.AssistantFileStorer.ListByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
-	c.Logger.Debug("fetched assistant files", slog.Any("aa", aa))
+	c.Logger.DebugContext(ctx, "fetched assistant files", slog.Any("aa", aa))
 
 	// for _, a := range aa.Results {
 	// 	// Generate the URL.
 	// 	fileURL, err := c.S3.GetPresignedURL(ctx, a.ObjectKey, 5*time.Minute)
 	// 	if err != nil {
-	// 		c.Logger.Error("s3 failed get presigned url error", slog.Any("error", err))
+	// 		c.Logger.ErrorContext(ctx, "s3 failed get presigned url error", slog.Any("error", err))
 	// 		return nil, err
 	// 	}
 	// 	a.ObjectURL = fileURL
@@ -51,19 +51,19 @@ func (c *AssistantFileControllerImpl) ListAsSelectOptionByFilter(ctx context.Con
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		c.Logger.Error("authenticated user is not staff role error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
 	}
 
-	c.Logger.Debug("fetching assistant files now...", slog.Any("userID", userID))
+	c.Logger.DebugContext(ctx, "fetching assistant files now...", slog.Any("userID", userID))
 
 	m, err := c.AssistantFileStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
-	c.Logger.Debug("fetched assistant files", slog.Any("m", m))
+	c.Logger.DebugContext(ctx, "fetched assistant files", slog.Any("m", m))
 	return m, err
 }
@@ -13,18 +13,20 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	a_d "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type AssistantFileUpdateRequestIDO struct {
-	ID          primitive.ObjectID
-	Name        string
-	Description string
-	FileName    string
-	FileType    string
-	File        multipart.File
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	FileName    string             `json:"-"`
+	FileType    string             `json:"-"`
+	File        multipart.File     `json:"file"`
 }
 
 func validateUpdateRequest(dirtyData *AssistantFileUpdateRequestIDO) error {
@@ -53,16 +55,21 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 	// Fetch the original assistantfile.
 	os, err := impl.AssistantFileStorer.GetByID(ctx, req.ID)
 	if err != nil {
-		impl.Logger.Error("database get by id error",
+		impl.Logger.ErrorContext(ctx, "database get by id error",
 			slog.Any("error", err),
 			slog.Any("assistantfile_id", req.ID))
 		return nil, err
 	}
 	if os == nil {
-		impl.Logger.Error("assistantfile does not exist error",
+		impl.Logger.ErrorContext(ctx, "assistantfile does not exist error",
 			slog.Any("assistantfile_id", req.ID))
-		return nil, httperror.NewForBadRequestWithSingleField("message", "assistantfile does not exist")
+		return nil, httperror.NewForNotFoundWithSingleField("message", "assistantfile does not exist")
 	}
+	// Return `412 precondition failed` if the record was modified since the client retrieved it.
+	if err := versioning.CheckExpectedVersion(ctx, os.Version); err != nil {
+		return nil, err
+	}
+	before := *os
 
 	// Extract from our session the following data.
 	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
@@ -73,7 +80,7 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 
 	// If user is not administrator nor belongs to the assistantfile then error.
 	if userRole != user_d.UserRoleExecutive {
-		impl.Logger.Error("authenticated user is not staff role nor belongs to the assistantfile error",
+		impl.Logger.ErrorContext(ctx, "authenticated user is not staff role nor belongs to the assistantfile error",
 			slog.Any("userRole", userRole),
 			slog.Any("userTenantID", userTenantID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this assistantfile")
@@ -85,7 +92,7 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -103,7 +110,7 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 			objectKey := fmt.Sprintf("tenant/%v/%v/%v", userTenantID.Hex(), directory, req.FileName)
 
 			// For debugging purposes only.
-			impl.Logger.Debug("pre-upload meta",
+			impl.Logger.DebugContext(ctx, "pre-upload meta",
 				slog.String("FileName", req.FileName),
 				slog.String("FileType", req.FileType),
 				slog.String("Directory", directory),
@@ -118,7 +125,7 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 
 			// 	// Proceed to delete the physical files from AWS s3.
 			// 	if err := impl.S3.DeleteByKeys(ctx, []string{os.ObjectKey}); err != nil {
-			// 		impl.Logger.Warn("s3 delete by keys error", slog.Any("error", err))
+			// 		impl.Logger.WarnContext(ctx, "s3 delete by keys error", slog.Any("error", err))
 			// 		// Do not return an error, simply continue this function as there might
 			// 		// be a case were the file was removed on the s3 bucket by ourselves
 			// 		// or some other reason.
@@ -131,14 +138,14 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 			// 	objectKey := fmt.Sprintf("tenant/%v/%v/%v", tenantID.Hex(), directory, req.FileName)
 			//
 			// 	// go func(file multipart.File, objkey string) {
-			// 	// 	impl.Logger.Debug("beginning private s3 image upload...")
+			// 	// 	impl.Logger.DebugContext(ctx, "beginning private s3 image upload...")
 			// 	// 	if err := impl.S3.UploadContentFromMulipart(context.Background(), objkey, file); err != nil {
-			// 	// 		impl.Logger.Error("private s3 upload error", slog.Any("error", err))
+			// 	// 		impl.Logger.ErrorContext(ctx, "private s3 upload error", slog.Any("error", err))
 			// 	// 		// Do not return an error, simply continue this function as there might
 			// 	// 		// be a case were the file was removed on the s3 bucket by ourselves
 			// 	// 		// or some other reason.
 			// 	// 	}
-			// 	// 	impl.Logger.Debug("Finished private s3 image upload")
+			// 	// 	impl.Logger.DebugContext(ctx, "Finished private s3 image upload")
 			// 	// }(req.File, objectKey)
 			//
 			// 	// Update file.
@@ -147,7 +154,7 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 
 			creds, err := impl.TenantStorer.GetOpenAICredentialsByID(ctx, userTenantID)
 			if err != nil {
-				impl.Logger.Error("failed file upload to openai",
+				impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 					slog.String("tenant_id", userTenantID.Hex()),
 					slog.Any("error", err))
 				return nil, err
@@ -156,20 +163,20 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 				return nil, errors.New("no openai credentials returned")
 			}
 
-			impl.Logger.Debug("beginning openai file upload...", slog.String("tenant_id", userTenantID.Hex()))
+			impl.Logger.DebugContext(ctx, "beginning openai file upload...", slog.String("tenant_id", userTenantID.Hex()))
 			fileID, err := impl.uploadContentFromMulipart(context.Background(), req.FileName, req.File, creds.APIKey, creds.OrgKey)
 			if err != nil {
-				impl.Logger.Error("failed file upload to openai",
+				impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 					slog.String("tenant_id", userTenantID.Hex()),
 					slog.Any("error", err))
 				return nil, err
 			}
 			if fileID == "" {
-				impl.Logger.Debug("no openai `file_id` returned",
+				impl.Logger.DebugContext(ctx, "no openai `file_id` returned",
 					slog.String("tenant_id", userTenantID.Hex()))
 				return nil, errors.New("failed file upload to openai as no `file_id` was returned")
 			}
-			impl.Logger.Debug("finished file upload to openai",
+			impl.Logger.DebugContext(ctx, "finished file upload to openai",
 				slog.String("tenant_id", userTenantID.Hex()),
 				slog.Any("file_id", fileID))
 		}
@@ -183,7 +190,10 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 
 		// Save to the database the modified assistantfile.
 		if err := impl.AssistantFileStorer.UpdateByID(ctx, os); err != nil {
-			impl.Logger.Error("database update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeAssistantFile, os.ID, &before, os); err != nil {
 			return nil, err
 		}
 
@@ -200,7 +210,7 @@ func (impl *AssistantFileControllerImpl) UpdateByID(ctx context.Context, req *As
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -37,6 +37,7 @@ type AssistantFile struct {
 	ModifiedAt         time.Time          `bson:"modified_at,omitempty" json:"modified_at,omitempty"`
 	ModifiedByUserName string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedByUserID   primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id"`
+	Version            int64              `bson:"version" json:"version"`
 	ObjectKey          string             `bson:"object_key" json:"object_key"`
 	ObjectURL          string             `bson:"object_url" json:"object_url"`
 	Status             int8               `bson:"status" json:"status"`
@@ -10,6 +10,7 @@ import (
 	sub_c "github.com/bartmika/databoutique-backend/internal/app/assistantfile/controller"
 	sub_s "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -77,12 +78,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, idStr strin
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(assistantfile.Version))
 	MarshalUpdateResponse(assistantfile, w)
 }
 
 func MarshalUpdateResponse(res *sub_s.AssistantFile, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
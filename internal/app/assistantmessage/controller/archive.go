@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	assistantmessage_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -18,18 +19,22 @@ func (impl *AssistantMessageControllerImpl) ArchiveByID(ctx context.Context, id
 	// Lookup the assistantmessage in our database, else return a `400 Bad Request` error.
 	ou, err := impl.AssistantMessageStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if ou == nil {
-		impl.Logger.Warn("assistantmessage does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "assistantmessage does not exist validation error")
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
+	before := *ou
 
 	ou.Status = assistantmessage_s.AssistantMessageStatusArchived
 
 	if err := impl.AssistantMessageStorer.UpdateByID(ctx, ou); err != nil {
-		impl.Logger.Error("assistantmessage update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "assistantmessage update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeAssistantMessage, ou.ID, &before, ou); err != nil {
 		return nil, err
 	}
 	return ou, nil
@@ -7,15 +7,18 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/bartmika/databoutique-backend/internal/adapter/lease"
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
 	assistant_s "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
 	assistantfile "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
 	assistantmessage_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
 	assistantthread_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
 	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
 	"github.com/bartmika/databoutique-backend/internal/provider/password"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
@@ -31,6 +34,8 @@ type AssistantMessageController interface {
 	PublicListAsSelectOptionByFilter(ctx context.Context, f *assistantmessage_s.AssistantMessagePaginationListFilter) ([]*assistantmessage_s.AssistantMessageAsSelectOption, error)
 	ArchiveByID(ctx context.Context, id primitive.ObjectID) (*assistantmessage_s.AssistantMessage, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	ResumeInterruptedRuns(ctx context.Context) error
+	RunResumeWorker(ctx context.Context)
 }
 
 type AssistantMessageControllerImpl struct {
@@ -40,6 +45,8 @@ type AssistantMessageControllerImpl struct {
 	S3                     s3_storage.S3Storager
 	Password               password.Provider
 	Kmutex                 kmutex.Provider
+	Background             background.Provider
+	Leaser                 lease.Leaser
 	DbClient               *mongo.Client
 	TenantStorer           tenant_s.TenantStorer
 	UserStorer             user_s.UserStorer
@@ -48,6 +55,7 @@ type AssistantMessageControllerImpl struct {
 	AssistantStorer        assistant_s.AssistantStorer
 	AssistantThreadStorer  assistantthread_s.AssistantThreadStorer
 	AssistantMessageStorer assistantmessage_s.AssistantMessageStorer
+	AuditEvent             auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -57,6 +65,8 @@ func NewController(
 	s3 s3_storage.S3Storager,
 	passwordp password.Provider,
 	kmux kmutex.Provider,
+	bg background.Provider,
+	leaser lease.Leaser,
 	temailer templatedemailer.TemplatedEmailer,
 	client *mongo.Client,
 	t_storer tenant_s.TenantStorer,
@@ -65,6 +75,7 @@ func NewController(
 	a_storer assistant_s.AssistantStorer,
 	at_storer assistantthread_s.AssistantThreadStorer,
 	am_storer assistantmessage_s.AssistantMessageStorer,
+	auditevent auditevent_c.AuditEventController,
 ) AssistantMessageController {
 	s := &AssistantMessageControllerImpl{
 		Config:                 appCfg,
@@ -73,6 +84,8 @@ func NewController(
 		S3:                     s3,
 		Password:               passwordp,
 		Kmutex:                 kmux,
+		Background:             bg,
+		Leaser:                 leaser,
 		TemplatedEmailer:       temailer,
 		DbClient:               client,
 		TenantStorer:           t_storer,
@@ -81,6 +94,7 @@ func NewController(
 		AssistantStorer:        a_storer,
 		AssistantThreadStorer:  at_storer,
 		AssistantMessageStorer: am_storer,
+		AuditEvent:             auditevent,
 	}
 	s.Logger.Debug("assistant message controller initialization started...")
 	s.Logger.Debug("assistant message controller initialized")
@@ -12,7 +12,11 @@ import (
 
 	am_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
 	at_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/logger"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 	"github.com/sashabaranov/go-openai"
 )
@@ -66,7 +70,7 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 	//
 
 	if err := impl.validateCreateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -74,7 +78,7 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 	// case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 	// 	break
 	// default:
-	// 	impl.Logger.Error("you do not have permission to create a client")
+	// 	impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	// }
 
@@ -84,25 +88,30 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
 	defer session.EndSession(ctx)
 
+	// The OpenAI submission is only started after our transaction commits so
+	// the background job never works on records which do not exist yet.
+	var submitToOpenAI func(ctx context.Context)
+	var queued *am_s.AssistantMessage
+
 	// Define a transaction function with a series of operations
 	transactionFunc := func(sessCtx mongo.SessionContext) (interface{}, error) {
 		// Fetch our thread.
 
 		at, err := impl.AssistantThreadStorer.GetByID(sessCtx, requestData.AssistantThreadID)
 		if err != nil {
-			impl.Logger.Error("failed getting assistant thread by id",
+			impl.Logger.ErrorContext(ctx, "failed getting assistant thread by id",
 				slog.Any("error", err))
 			return nil, err
 		}
 		if at == nil {
 			err := fmt.Errorf("no assistant thread found with id: %s", requestData.AssistantThreadID.Hex())
-			impl.Logger.Error("", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -110,7 +119,7 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tid)
 		if err != nil {
-			impl.Logger.Error("failed getting openai credentials",
+			impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -119,7 +128,7 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
 
 		// The next lines of code will involve two steps:
 		// Step 1: Create our question message of what the user asked.
@@ -150,7 +159,10 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 
 		// Save to our database.
 		if err := impl.AssistantMessageStorer.Create(sessCtx, am1); err != nil {
-			impl.Logger.Error("failed creating question message", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "failed creating question message", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeAssistantMessage, am1.ID, nil, am1); err != nil {
 			return nil, err
 		}
 
@@ -178,17 +190,30 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 
 		// Save to our database.
 		if err := impl.AssistantMessageStorer.Create(sessCtx, am2); err != nil {
-			impl.Logger.Error("failed creating assistant response message", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "failed creating assistant response message", slog.Any("error", err))
 			return nil, err
 		}
 
-		// Submit the following into the background of this web-application.
-		// This function will run independently of this function call.
-		go func(lg *slog.Logger, amStorer am_s.AssistantMessageStorer, c *openai.Client, openAIAssistantID string, openAIAssistantThreadID string, text string, res *am_s.AssistantMessage) {
-			if err := CreateOpenAIMessageInBackground(lg, amStorer, c, openAIAssistantID, openAIAssistantThreadID, text, res); err != nil {
-				impl.Logger.Error("failed polling openai", slog.Any("error", err))
+		// Prepare the OpenAI submission which will run in the background once
+		// our transaction commits.
+		lg := logger.FromContext(ctx, impl.Logger)
+		queued = am2
+		submitToOpenAI = func(jobCtx context.Context) {
+			bgCtx, span := tracing.Start(jobCtx, "assistant_message.create.openai")
+			defer span.End()
+			metrics.IncBackgroundJob("assistant_message_openai")
+			defer metrics.DecBackgroundJob("assistant_message_openai")
+			if err := CreateOpenAIMessageInBackground(bgCtx, lg, impl.AssistantMessageStorer, client, at.OpenAIAssistantID, at.OpenAIAssistantThreadID, requestData.Text, am2); err != nil {
+				if jobCtx.Err() != nil {
+					// Interrupted by a graceful shutdown, the run will be
+					// resumed after restart, or by losing the claim to
+					// another instance which now processes the message.
+					return
+				}
+				impl.Logger.ErrorContext(bgCtx, "failed polling openai", slog.Any("error", err))
+				span.RecordError(err)
 			}
-		}(impl.Logger, impl.AssistantMessageStorer, client, at.OpenAIAssistantID, at.OpenAIAssistantThreadID, requestData.Text, am2)
+		}
 
 		return am1, nil
 	}
@@ -196,11 +221,15 @@ func (impl *AssistantMessageControllerImpl) Create(ctx context.Context, requestD
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
 
+	// Submit the following into the background of this web-application.
+	// This function will run independently of this function call.
+	impl.runInBackgroundForOpenAI(ctx, "assistant_message.create.openai", queued, submitToOpenAI)
+
 	return result.(*am_s.AssistantMessage), nil
 }
 
@@ -215,7 +244,7 @@ func (impl *AssistantMessageControllerImpl) pollInBackground(client *openai.Clie
 	// 	// retrieve the status of the run
 	// 	run, err = client.RetrieveRun(ctx, at.OpenAIAssistantThreadID, run.ID)
 	// 	if err != nil {
-	// 		impl.Logger.Error("failed retrieving run",
+	// 		impl.Logger.ErrorContext(ctx, "failed retrieving run",
 	// 			slog.Any("error", err))
 	// 		return err
 	// 	}
@@ -223,18 +252,18 @@ func (impl *AssistantMessageControllerImpl) pollInBackground(client *openai.Clie
 	//
 	// at.Status = assistantthread_s.AssistantThreadStatusActive
 	// if err := impl.AssistantThreadStorer.UpdateByID(ctx, at); err != nil {
-	// 	impl.Logger.Error("database create error", slog.Any("error", err))
+	// 	impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
 	// 	return err
 	// }
 	//
-	// impl.Logger.Debug("finished running assistant thread",
+	// impl.Logger.DebugContext(ctx, "finished running assistant thread",
 	// 	slog.Any("status", assistantthread_s.AssistantThreadStatusActive))
 	//
-	// impl.Logger.Debug("fetcheing list messages for assistant thread", slog.Any("assistant_thread_id", at.ID))
+	// impl.Logger.DebugContext(ctx, "fetcheing list messages for assistant thread", slog.Any("assistant_thread_id", at.ID))
 	//
 	// msgs, err := client.ListMessage(context.Background(), at.OpenAIAssistantThreadID, nil, nil, nil, nil)
 	// if err != nil {
-	// 	impl.Logger.Error("failed listing message",
+	// 	impl.Logger.ErrorContext(ctx, "failed listing message",
 	// 		slog.Any("error", err))
 	// 	return err
 	// }
@@ -249,7 +278,7 @@ func (impl *AssistantMessageControllerImpl) pollInBackground(client *openai.Clie
 	//
 	// at.AssistantThreadMessages = append(at.AssistantThreadMessages, atm)
 	// if err := impl.AssistantThreadStorer.UpdateByID(ctx, at); err != nil {
-	// 	impl.Logger.Error("database create error", slog.Any("error", err))
+	// 	impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
 	// 	return err
 	// }
 	return nil
@@ -5,23 +5,28 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log/slog"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 )
 
 func (impl *AssistantMessageControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	// STEP 1: Lookup the record or error.
 	assistantmessage, err := impl.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return err
 	}
 	if assistantmessage == nil {
-		impl.Logger.Error("database returns nothing from get by id")
+		impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 		return err
 	}
 
 	// STEP 2: Delete from database.
 	if err := impl.AssistantMessageStorer.DeleteByID(ctx, id); err != nil {
-		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeAssistantMessage, assistantmessage.ID, assistantmessage, nil); err != nil {
 		return err
 	}
 	return nil
@@ -7,14 +7,18 @@ import (
 	"log/slog"
 
 	assistantmessage_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 func (c *AssistantMessageControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*assistantmessage_s.AssistantMessage, error) {
 	// Retrieve from our database the record for the specific id.
 	m, err := c.AssistantMessageStorer.GetByID(ctx, id)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
 	return m, err
 }
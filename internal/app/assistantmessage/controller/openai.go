@@ -2,18 +2,21 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
 
 	am_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 // CreateOpenAIMessageInBackground function runs in background context to submit
 // to OpenAI a `CreateMessage` API call and update our database with the latest
 // response.
 func CreateOpenAIMessageInBackground(
+	ctx context.Context,
 	logger *slog.Logger,
 	amStorer am_s.AssistantMessageStorer,
 	client *openai.Client,
@@ -22,7 +25,6 @@ func CreateOpenAIMessageInBackground(
 	message string,
 	am *am_s.AssistantMessage,
 ) error {
-	ctx := context.Background()
 	var err error
 	_, err = client.CreateMessage(ctx,
 		openAIThreadID,
@@ -37,7 +39,7 @@ func CreateOpenAIMessageInBackground(
 	}
 	logger.Debug("submitted create message to openai")
 
-	run, err := client.CreateRun(context.Background(), openAIThreadID, openai.RunRequest{
+	run, err := client.CreateRun(ctx, openAIThreadID, openai.RunRequest{
 		AssistantID: openAIAssistantID,
 	})
 	if err != nil {
@@ -47,19 +49,64 @@ func CreateOpenAIMessageInBackground(
 	}
 	logger.Debug("submitted create run to openai")
 
+	// Save the run ID before we start polling so that if this process is
+	// stopped (ex: graceful shutdown) the run can be resumed after restart.
+	err = updateAssistantMessageWithRetry(ctx, logger, amStorer, am, func(am *am_s.AssistantMessage) {
+		am.OpenAIRunID = run.ID
+		am.OpenAIAssistantThreadID = openAIThreadID
+	})
+	if err != nil {
+		logger.Error("failed checkpointing assistant message",
+			slog.Any("error", err))
+		return err
+	}
+
+	return AwaitOpenAIRun(ctx, logger, amStorer, client, am)
+}
+
+// openAIRunPollInterval is how long to wait between polling OpenAI for the
+// status of a run.
+const openAIRunPollInterval = 25 * time.Second
+
+// AwaitOpenAIRun function polls OpenAI until the checkpointed run of the
+// assistant message finishes and then saves the reply. If the context is
+// cancelled (ex: graceful shutdown) the function returns the context error
+// and leaves the checkpoint in place so the run can be resumed later.
+func AwaitOpenAIRun(
+	ctx context.Context,
+	logger *slog.Logger,
+	amStorer am_s.AssistantMessageStorer,
+	client *openai.Client,
+	am *am_s.AssistantMessage,
+) error {
+	openAIThreadID := am.OpenAIAssistantThreadID
+
 	// Continue to loop through the following code and polling openai every 25
 	// seconds to see if the `CreateMessage` request has been executed for our
 	// particular assistant.
-	for run.Status != openai.RunStatusCompleted {
-		time.Sleep(25 * time.Second) // Sleep for 25 seconds.
-
+	for {
 		// retrieve the status of the run
-		run, err = client.RetrieveRun(ctx, openAIThreadID, run.ID)
+		run, err := client.RetrieveRun(ctx, openAIThreadID, am.OpenAIRunID)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			logger.Error("failed retrieving run from openai",
 				slog.Any("error", err))
 			return err
 		}
+		if run.Status == openai.RunStatusCompleted {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("stopped polling openai run, will resume after restart",
+				slog.Any("assistant_message_id", am.ID),
+				slog.String("run_id", am.OpenAIRunID))
+			return ctx.Err()
+		case <-time.After(openAIRunPollInterval):
+		}
 	}
 	logger.Debug("openai finished running")
 
@@ -67,7 +114,7 @@ func CreateOpenAIMessageInBackground(
 	// `thread_id` and return all the messages so far. Then extract most
 	// recent message and save it into our system.
 
-	msgs, err := client.ListMessage(context.Background(), openAIThreadID, nil, nil, nil, nil)
+	msgs, err := client.ListMessage(ctx, openAIThreadID, nil, nil, nil, nil)
 	if err != nil {
 		logger.Error("failed listing message",
 			slog.Any("error", err))
@@ -77,10 +124,13 @@ func CreateOpenAIMessageInBackground(
 	msg := msgs.Messages[0]
 
 	// Update our record with the latest message.
-	am.Status = am_s.AssistantMessageStatusActive
-	am.Text = msg.Content[0].Text.Value
-	am.ModifiedAt = time.Now()
-	if err := amStorer.UpdateByID(ctx, am); err != nil {
+	err = updateAssistantMessageWithRetry(ctx, logger, amStorer, am, func(am *am_s.AssistantMessage) {
+		am.Status = am_s.AssistantMessageStatusActive
+		am.Text = msg.Content[0].Text.Value
+		am.OpenAIRunID = ""
+		am.ModifiedAt = time.Now()
+	})
+	if err != nil {
 		logger.Error("failed updating assistant message by id",
 			slog.Any("error", err))
 		return err
@@ -89,3 +139,43 @@ func CreateOpenAIMessageInBackground(
 
 	return nil
 }
+
+// The number of times our background processing retries saving its changes
+// to an assistant message which was updated by somebody else in the meantime.
+const assistantMessageUpdateMaxAttempts = 3
+
+// updateAssistantMessageWithRetry function applies the changes of our
+// background processing to the assistant message. If the assistant message
+// was updated in the meantime then the changes are applied again to the
+// latest version instead of overwriting the other changes.
+func updateAssistantMessageWithRetry(
+	ctx context.Context,
+	logger *slog.Logger,
+	amStorer am_s.AssistantMessageStorer,
+	am *am_s.AssistantMessage,
+	apply func(am *am_s.AssistantMessage),
+) error {
+	latest := am
+	for attempt := 1; ; attempt++ {
+		apply(latest)
+		err := amStorer.UpdateByID(ctx, latest)
+		if err == nil {
+			*am = *latest
+			return nil
+		}
+		if !errors.Is(err, versioning.ErrConflict) || attempt == assistantMessageUpdateMaxAttempts {
+			return err
+		}
+
+		logger.Warn("assistant message was modified, retrying update",
+			slog.Any("assistant_message_id", am.ID),
+			slog.Int("attempt", attempt))
+		latest, err = amStorer.GetByID(ctx, am.ID)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			return versioning.ErrConflict
+		}
+	}
+}
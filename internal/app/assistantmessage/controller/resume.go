@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/lease"
+	am_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/logger"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
+)
+
+const (
+	// The duration a queued assistant message being processed is hidden from
+	// our other replicas; the lease is renewed until the processing finishes.
+	processingLease = 2 * time.Minute
+
+	// The interval we look for interrupted assistant messages, ex: the
+	// messages of a replica which crashed or was stopped during a deploy.
+	resumePollInterval = time.Minute
+)
+
+func processingLeaseKey(am *am_s.AssistantMessage) string {
+	return "assistant_message:" + am.ID.Hex()
+}
+
+// runInBackgroundForOpenAI function claims the assistant message for this
+// instance, refreshes `am` from our database and submits the OpenAI `job` to
+// our background provider. Returns false if the message was not submitted,
+// ex: it is already processed by another instance. The context of the job is
+// cancelled if the claim is lost to another instance.
+func (impl *AssistantMessageControllerImpl) runInBackgroundForOpenAI(ctx context.Context, name string, am *am_s.AssistantMessage, job func(jobCtx context.Context)) bool {
+	key := processingLeaseKey(am)
+	acquired, err := impl.Leaser.Acquire(ctx, key, processingLease)
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "failed claiming assistant message, will resume later",
+			slog.Any("assistant_message_id", am.ID),
+			slog.Any("error", err))
+		return false
+	}
+	if !acquired {
+		return false
+	}
+	if !impl.refreshQueuedAssistantMessage(ctx, am) {
+		if err := impl.Leaser.Release(context.WithoutCancel(ctx), key); err != nil {
+			impl.Logger.WarnContext(ctx, "failed releasing assistant message", slog.Any("error", err))
+		}
+		return false
+	}
+
+	err = impl.Background.Go(ctx, name, func(jobCtx context.Context) {
+		leaseCtx, release := lease.KeepAlive(jobCtx, impl.Leaser, impl.Logger, key, processingLease)
+		defer release()
+		job(leaseCtx)
+	})
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "openai job not started", slog.Any("error", err))
+		if err := impl.Leaser.Release(context.WithoutCancel(ctx), key); err != nil {
+			impl.Logger.WarnContext(ctx, "failed releasing assistant message", slog.Any("error", err))
+		}
+		return false
+	}
+	return true
+}
+
+// refreshQueuedAssistantMessage function re-reads the claimed message into
+// `am` since our copy may be stale, ex: another instance finished processing
+// it in the meantime. Returns false if the message is no longer queued.
+func (impl *AssistantMessageControllerImpl) refreshQueuedAssistantMessage(ctx context.Context, am *am_s.AssistantMessage) bool {
+	fresh, err := impl.AssistantMessageStorer.GetByID(ctx, am.ID)
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "failed getting assistant message, will resume later",
+			slog.Any("assistant_message_id", am.ID),
+			slog.Any("error", err))
+		return false
+	}
+	if fresh == nil || fresh.Status != am_s.AssistantMessageStatusQueued {
+		return false
+	}
+	*am = *fresh
+	return true
+}
+
+// RunResumeWorker function resumes the interrupted assistant messages until
+// the context is cancelled.
+func (impl *AssistantMessageControllerImpl) RunResumeWorker(ctx context.Context) {
+	ticker := time.NewTicker(resumePollInterval)
+	defer ticker.Stop()
+	for {
+		if err := impl.ResumeInterruptedRuns(ctx); err != nil && ctx.Err() == nil {
+			impl.Logger.ErrorContext(ctx, "failed resuming assistant messages", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResumeInterruptedRuns function restarts polling OpenAI for every queued
+// assistant message which was checkpointed but is not claimed by any of our
+// instances, ex: the instance was stopped. Queued messages without a
+// checkpoint never reached OpenAI so they are marked as errored for the user
+// to resubmit.
+func (impl *AssistantMessageControllerImpl) ResumeInterruptedRuns(ctx context.Context) error {
+	f := &am_s.AssistantMessagePaginationListFilter{
+		PageSize:  100,
+		SortField: "created_at",
+		SortOrder: am_s.OrderAscending,
+		Status:    am_s.AssistantMessageStatusQueued,
+	}
+	for {
+		res, err := impl.AssistantMessageStorer.ListByFilter(ctx, f)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "failed listing interrupted assistant messages",
+				slog.Any("error", err))
+			return err
+		}
+		for _, am := range res.Results {
+			// Give the instance which just queued the message the time to
+			// claim it.
+			if time.Since(am.ModifiedAt) < processingLease {
+				continue
+			}
+			if am.OpenAIRunID == "" {
+				impl.markInterruptedAsErrored(ctx, am)
+				continue
+			}
+			impl.resumeInterruptedRun(ctx, am)
+		}
+		if !res.HasNextPage {
+			return nil
+		}
+		f.Cursor = res.NextCursor
+	}
+}
+
+// markInterruptedAsErrored function marks the unclaimed message which never
+// reached OpenAI as errored.
+func (impl *AssistantMessageControllerImpl) markInterruptedAsErrored(ctx context.Context, am *am_s.AssistantMessage) {
+	key := processingLeaseKey(am)
+	acquired, err := impl.Leaser.Acquire(ctx, key, processingLease)
+	if err != nil || !acquired {
+		return
+	}
+	defer impl.Leaser.Release(context.WithoutCancel(ctx), key)
+	if !impl.refreshQueuedAssistantMessage(ctx, am) || am.OpenAIRunID != "" {
+		return
+	}
+
+	am.Status = am_s.AssistantMessageStatusError
+	am.ModifiedAt = time.Now()
+	if err := impl.AssistantMessageStorer.UpdateByID(ctx, am); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed updating assistant message",
+			slog.Any("assistant_message_id", am.ID),
+			slog.Any("error", err))
+	}
+}
+
+func (impl *AssistantMessageControllerImpl) resumeInterruptedRun(ctx context.Context, am *am_s.AssistantMessage) {
+	resumed := impl.runInBackgroundForOpenAI(ctx, "assistant_message.resume.openai", am, func(jobCtx context.Context) {
+		bgCtx, span := tracing.Start(jobCtx, "assistant_message.resume.openai")
+		defer span.End()
+		metrics.IncBackgroundJob("assistant_message_openai")
+		defer metrics.DecBackgroundJob("assistant_message_openai")
+
+		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(bgCtx, am.TenantID)
+		if err != nil || creds == nil {
+			impl.Logger.ErrorContext(bgCtx, "failed getting openai credentials",
+				slog.String("tenant_id", am.TenantID.Hex()),
+				slog.Any("error", err))
+			return
+		}
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+
+		if err := AwaitOpenAIRun(bgCtx, logger.FromContext(bgCtx, impl.Logger), impl.AssistantMessageStorer, client, am); err != nil {
+			if jobCtx.Err() != nil {
+				return
+			}
+			impl.Logger.ErrorContext(bgCtx, "failed polling openai", slog.Any("error", err))
+			span.RecordError(err)
+		}
+	})
+	if resumed {
+		impl.Logger.InfoContext(ctx, "resumed interrupted assistant message",
+			slog.Any("assistant_message_id", am.ID),
+			slog.String("run_id", am.OpenAIRunID))
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	assistantmessage_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type AssistantMessageUpdateRequestIDO struct {
@@ -39,7 +41,7 @@ func (impl *AssistantMessageControllerImpl) UpdateByID(ctx context.Context, requ
 	//
 
 	if err := impl.validateUpdateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -56,7 +58,7 @@ func (impl *AssistantMessageControllerImpl) UpdateByID(ctx context.Context, requ
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -66,7 +68,7 @@ func (impl *AssistantMessageControllerImpl) UpdateByID(ctx context.Context, requ
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -82,13 +84,18 @@ func (impl *AssistantMessageControllerImpl) UpdateByID(ctx context.Context, requ
 		// Lookup the assistantmessage in our database, else return a `400 Bad Request` error.
 		hh, err := impl.AssistantMessageStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if hh == nil {
-			impl.Logger.Warn("assistantmessage does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "assistantmessage does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, hh.Version); err != nil {
+			return nil, err
+		}
+		before := *hh
 
 		////
 		//// Update primary record.
@@ -105,7 +112,7 @@ func (impl *AssistantMessageControllerImpl) UpdateByID(ctx context.Context, requ
 		hh.Text = requestData.Text
 
 		if err := impl.AssistantMessageStorer.UpdateByID(sessCtx, hh); err != nil {
-			impl.Logger.Error("assistantmessage update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "assistantmessage update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -115,6 +122,10 @@ func (impl *AssistantMessageControllerImpl) UpdateByID(ctx context.Context, requ
 
 		//
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeAssistantMessage, hh.ID, &before, hh); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -125,7 +136,7 @@ func (impl *AssistantMessageControllerImpl) UpdateByID(ctx context.Context, requ
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -27,6 +27,7 @@ type AssistantMessage struct {
 	AssistantThreadID       primitive.ObjectID `bson:"assistant_thread_id" json:"assistant_thread_id"`
 	OpenAIAssistantID       string             `bson:"openai_assistant_id" json:"openai_assistant_id"`
 	OpenAIAssistantThreadID string             `bson:"openai_assistant_thread_id" json:"openai_assistant_thread_id"`
+	OpenAIRunID             string             `bson:"openai_run_id" json:"openai_run_id,omitempty"` // Checkpoint of the in-progress run so it can be resumed after a restart.
 	Text                    string             `bson:"text" json:"text"`
 	Status                  int8               `bson:"status" json:"status"`
 	PublicID                uint64             `bson:"public_id" json:"public_id"`
@@ -39,6 +40,7 @@ type AssistantMessage struct {
 	ModifiedByUserID        primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
 	ModifiedByUserName      string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedFromIPAddress   string             `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version                 int64              `bson:"version" json:"version"`
 }
 
 type AssistantMessageListResult struct {
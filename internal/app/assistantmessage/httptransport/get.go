@@ -8,6 +8,7 @@ import (
 
 	assistantmessage_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request, id string) {
@@ -25,12 +26,13 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request, id string) {
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(res.Version))
 	MarshalDetailResponse(res, w)
 }
 
 func MarshalDetailResponse(res *assistantmessage_s.AssistantMessage, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -14,9 +14,11 @@ import (
 	assistantmessage_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
 	assistantthread_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
 	t_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
 	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
 	"github.com/bartmika/databoutique-backend/internal/provider/password"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
@@ -40,6 +42,7 @@ type AssistantThreadControllerImpl struct {
 	S3                     s3_storage.S3Storager
 	Password               password.Provider
 	Kmutex                 kmutex.Provider
+	Background             background.Provider
 	DbClient               *mongo.Client
 	TenantStorer           tenant_s.TenantStorer
 	UserStorer             user_s.UserStorer
@@ -48,6 +51,7 @@ type AssistantThreadControllerImpl struct {
 	AssistantThreadStorer  t_s.AssistantThreadStorer
 	AssistantMessageStorer assistantmessage_s.AssistantMessageStorer
 	TemplatedEmailer       templatedemailer.TemplatedEmailer
+	AuditEvent             auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -57,6 +61,7 @@ func NewController(
 	s3 s3_storage.S3Storager,
 	passwordp password.Provider,
 	kmux kmutex.Provider,
+	bg background.Provider,
 	client *mongo.Client,
 	temailer templatedemailer.TemplatedEmailer,
 	t_storer tenant_s.TenantStorer,
@@ -65,6 +70,7 @@ func NewController(
 	a_storer assistant_s.AssistantStorer,
 	at_storer assistantthread_s.AssistantThreadStorer,
 	am_storer assistantmessage_s.AssistantMessageStorer,
+	auditevent auditevent_c.AuditEventController,
 ) AssistantThreadController {
 	s := &AssistantThreadControllerImpl{
 		Config:                 appCfg,
@@ -73,6 +79,7 @@ func NewController(
 		S3:                     s3,
 		Password:               passwordp,
 		Kmutex:                 kmux,
+		Background:             bg,
 		TemplatedEmailer:       temailer,
 		DbClient:               client,
 		TenantStorer:           t_storer,
@@ -81,6 +88,7 @@ func NewController(
 		AssistantStorer:        a_storer,
 		AssistantThreadStorer:  at_storer,
 		AssistantMessageStorer: am_storer,
+		AuditEvent:             auditevent,
 	}
 	s.Logger.Debug("assistant thread controller initialization started...")
 	s.Logger.Debug("assistant thread controller initialized")
@@ -15,7 +15,11 @@ import (
 	am_c "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/controller"
 	am_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
 	at_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/logger"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -82,12 +86,16 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
 	defer session.EndSession(ctx)
 
+	// The OpenAI submission is only started after our transaction commits so
+	// the background job never works on records which do not exist yet.
+	var submitToOpenAI func(ctx context.Context)
+
 	// Define a transaction function with a series of operations
 	transactionFunc := func(sessCtx mongo.SessionContext) (interface{}, error) {
 
@@ -96,25 +104,25 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 
 		a, err := impl.AssistantStorer.GetByID(sessCtx, requestData.AssistantID)
 		if err != nil {
-			impl.Logger.Error("failed getting assistant by id",
+			impl.Logger.ErrorContext(ctx, "failed getting assistant by id",
 				slog.Any("error", err))
 			return nil, err
 		}
 		if a == nil {
 			err := fmt.Errorf("no assistant found with id: %s", requestData.AssistantID.Hex())
-			impl.Logger.Error("", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "", slog.Any("error", err))
 			return nil, err
 		}
 
 		u, err := impl.UserStorer.GetByID(sessCtx, requestData.UserID)
 		if err != nil {
-			impl.Logger.Error("failed getting user by id",
+			impl.Logger.ErrorContext(ctx, "failed getting user by id",
 				slog.Any("error", err))
 			return nil, err
 		}
 		if u == nil {
 			err := fmt.Errorf("no user found with id: %s", requestData.UserID.Hex())
-			impl.Logger.Error("", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -147,7 +155,7 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 
 		// Save to our database.
 		if err := impl.AssistantThreadStorer.Create(sessCtx, at); err != nil {
-			impl.Logger.Error("database create error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -158,7 +166,7 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tid)
 		if err != nil {
-			impl.Logger.Error("failed getting openai credentials",
+			impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -167,28 +175,28 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
 
 		// Create a thread in OpenAI.
 		thread, err := client.CreateThread(sessCtx, openai.ThreadRequest{})
 		if err != nil {
-			impl.Logger.Error("failed creating assistantthread",
+			impl.Logger.ErrorContext(ctx, "failed creating assistantthread",
 				slog.String("tenant_id", tid.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if isStructEmpty(thread) {
-			impl.Logger.Error("no openai assistant thread returned", slog.Any("assistant_thread", thread))
+			impl.Logger.ErrorContext(ctx, "no openai assistant thread returned", slog.Any("assistant_thread", thread))
 			return "", errors.New("no openai assistant thread returned")
 		}
 
-		impl.Logger.Debug("create openai thread",
+		impl.Logger.DebugContext(ctx, "create openai thread",
 			slog.String("thread_id", thread.ID),
 			slog.String("tenant_id", tid.Hex()))
 
 		at.OpenAIAssistantThreadID = thread.ID
 		if err := impl.AssistantThreadStorer.UpdateByID(sessCtx, at); err != nil {
-			impl.Logger.Error("database update error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database update error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -221,7 +229,7 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 
 		// Save to our database.
 		if err := impl.AssistantMessageStorer.Create(sessCtx, am1); err != nil {
-			impl.Logger.Error("failed creating initial message", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "failed creating initial message", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -249,17 +257,32 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 
 		// Save to our database.
 		if err := impl.AssistantMessageStorer.Create(sessCtx, am2); err != nil {
-			impl.Logger.Error("failed creating assistant response message", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "failed creating assistant response message", slog.Any("error", err))
 			return nil, err
 		}
 
-		// Submit the following into the background of this web-application.
-		// This function will run independently of this function call.
-		go func(lg *slog.Logger, amStorer am_s.AssistantMessageStorer, c *openai.Client, openAIAssistantID string, openAIAssistantThreadID string, text string, res *am_s.AssistantMessage) {
-			if err := am_c.CreateOpenAIMessageInBackground(lg, amStorer, c, openAIAssistantID, openAIAssistantThreadID, text, res); err != nil {
-				impl.Logger.Error("failed polling openai", slog.Any("error", err))
+		// Prepare the OpenAI submission which will run in the background once
+		// our transaction commits.
+		lg := logger.FromContext(ctx, impl.Logger)
+		submitToOpenAI = func(jobCtx context.Context) {
+			bgCtx, span := tracing.Start(jobCtx, "assistant_message.create.openai")
+			defer span.End()
+			metrics.IncBackgroundJob("assistant_message_openai")
+			defer metrics.DecBackgroundJob("assistant_message_openai")
+			if err := am_c.CreateOpenAIMessageInBackground(bgCtx, lg, impl.AssistantMessageStorer, client, at.OpenAIAssistantID, at.OpenAIAssistantThreadID, requestData.Message, am2); err != nil {
+				if jobCtx.Err() != nil {
+					// Interrupted by a graceful shutdown, the run will be
+					// resumed after restart.
+					return
+				}
+				impl.Logger.ErrorContext(bgCtx, "failed polling openai", slog.Any("error", err))
+				span.RecordError(err)
 			}
-		}(impl.Logger, impl.AssistantMessageStorer, client, at.OpenAIAssistantID, at.OpenAIAssistantThreadID, requestData.Message, am2)
+		}
+
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeAssistantThread, at.ID, nil, at); err != nil {
+			return nil, err
+		}
 
 		// ////
 		// //// Exit our transaction successfully.
@@ -271,11 +294,17 @@ func (impl *AssistantThreadControllerImpl) Create(ctx context.Context, requestDa
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
 
+	// Submit the following into the background of this web-application.
+	// This function will run independently of this function call.
+	if err := impl.Background.Go(ctx, "assistant_message.create.openai", submitToOpenAI); err != nil {
+		impl.Logger.WarnContext(ctx, "openai job not started", slog.Any("error", err))
+	}
+
 	return result.(*at_s.AssistantThread), nil
 }
 
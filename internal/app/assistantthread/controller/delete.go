@@ -8,8 +8,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
-	"github.com/sashabaranov/go-openai"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 )
 
 func (impl *AssistantThreadControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
@@ -22,7 +23,7 @@ func (impl *AssistantThreadControllerImpl) DeleteByID(ctx context.Context, id pr
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -34,24 +35,27 @@ func (impl *AssistantThreadControllerImpl) DeleteByID(ctx context.Context, id pr
 		// STEP 1: Lookup the record or error.
 		assistantthread, err := impl.GetByID(sessCtx, id)
 		if err != nil {
-			impl.Logger.Error("database get by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 			return nil, err
 		}
 		if assistantthread == nil {
-			impl.Logger.Error("database returns nothing from get by id", slog.Any("id", id))
+			impl.Logger.ErrorContext(ctx, "database returns nothing from get by id", slog.Any("id", id))
 			return nil, err
 		}
 
 		// STEP 2: Delete from database.
 		if err := impl.AssistantThreadStorer.DeleteByID(sessCtx, id); err != nil {
-			impl.Logger.Error("database delete by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeAssistantThread, assistantthread.ID, assistantthread, nil); err != nil {
 			return nil, err
 		}
 
 		// STEP 3: Delete from OpenAI.
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tenantID)
 		if err != nil {
-			impl.Logger.Error("failed file upload to openai",
+			impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -60,7 +64,7 @@ func (impl *AssistantThreadControllerImpl) DeleteByID(ctx context.Context, id pr
 			return nil, errors.New("no openai credentials returned")
 		}
 		if err := impl.deleteOpanAI(sessCtx, assistantthread.OpenAIAssistantThreadID, creds.APIKey, creds.OrgKey); err != nil {
-			impl.Logger.Error("failed deleting assitant from openai", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "failed deleting assitant from openai", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -69,7 +73,7 @@ func (impl *AssistantThreadControllerImpl) DeleteByID(ctx context.Context, id pr
 
 	// Start a transaction
 	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return err
 	}
@@ -78,14 +82,14 @@ func (impl *AssistantThreadControllerImpl) DeleteByID(ctx context.Context, id pr
 }
 
 func (impl *AssistantThreadControllerImpl) deleteOpanAI(ctx context.Context, atID string, apikey string, orgKey string) error {
-	impl.Logger.Debug("openai initializing...")
-	client := openai.NewOrgClient(apikey, orgKey)
-	impl.Logger.Debug("openai initialized")
+	impl.Logger.DebugContext(ctx, "openai initializing...")
+	client := metrics.NewOpenAIOrgClient(apikey, orgKey)
+	impl.Logger.DebugContext(ctx, "openai initialized")
 	if _, err := client.DeleteThread(ctx, atID); err != nil {
-		impl.Logger.Error("failed deleting open ai assitant", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "failed deleting open ai assitant", slog.Any("error", err))
 		return err
 	}
-	impl.Logger.Debug("deleted openai assitant from assistantthread api",
+	impl.Logger.DebugContext(ctx, "deleted openai assitant from assistantthread api",
 		slog.Any("assistantthread_id", atID))
 	return nil
 }
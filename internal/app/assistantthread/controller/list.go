@@ -19,7 +19,7 @@ func (c *AssistantThreadControllerImpl) ListByFilter(ctx context.Context, f *t_s
 	// Apply filtering based on ownership and role.
 	f.TenantID = tenantID // Manditory
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -38,7 +38,7 @@ func (c *AssistantThreadControllerImpl) ListByFilter(ctx context.Context, f *t_s
 
 	m, err := c.AssistantThreadStorer.ListByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -51,7 +51,7 @@ func (c *AssistantThreadControllerImpl) ListByFilter(ctx context.Context, f *t_s
 // 	// Apply filtering based on ownership and role.
 // 	f.TenantID = tenantID // Manditory
 //
-// 	c.Logger.Debug("listing using filter options:",
+// 	c.Logger.DebugContext(ctx, "listing using filter options:",
 // 		slog.Any("Cursor", f.Cursor),
 // 		slog.Int64("PageSize", f.PageSize),
 // 		slog.String("SortField", f.SortField),
@@ -61,7 +61,7 @@ func (c *AssistantThreadControllerImpl) ListByFilter(ctx context.Context, f *t_s
 //
 // 	m, err := c.AssistantThreadStorer.LiteListByFilter(ctx, f)
 // 	if err != nil {
-// 		c.Logger.Error("database list by filter error", slog.Any("error", err))
+// 		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 // 		return nil, err
 // 	}
 // 	return m, err
@@ -74,7 +74,7 @@ func (c *AssistantThreadControllerImpl) ListAsSelectOptionByFilter(ctx context.C
 	// Apply filtering based on ownership and role.
 	f.TenantID = tenantID // Manditory
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -85,7 +85,7 @@ func (c *AssistantThreadControllerImpl) ListAsSelectOptionByFilter(ctx context.C
 	// Filtering the database.
 	m, err := c.AssistantThreadStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
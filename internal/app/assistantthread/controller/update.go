@@ -9,8 +9,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	assistantthread_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type AssistantThreadUpdateRequestIDO struct {
@@ -67,7 +69,7 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -79,13 +81,18 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 		// Lookup the assistantthread in our database, else return a `400 Bad Request` error.
 		ou, err := impl.AssistantThreadStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if ou == nil {
-			impl.Logger.Warn("assistantthread does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "assistantthread does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, ou.Version); err != nil {
+			return nil, err
+		}
+		before := *ou
 
 		//
 		// Update base.
@@ -102,7 +109,7 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 		ou.ModifiedFromIPAddress = ipAddress
 
 		if err := impl.AssistantThreadStorer.UpdateByID(sessCtx, ou); err != nil {
-			impl.Logger.Error("assistantthread update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "assistantthread update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -115,11 +122,11 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 		// 	// Step 1: Lookup original.
 		// 	assistantthreadFile, err := impl.AssistantThreadFileStorer.GetByID(sessCtx, assistantthreadFileID)
 		// 	if err != nil {
-		// 		impl.Logger.Error("fetching assistantthread file error", slog.Any("error", err))
+		// 		impl.Logger.ErrorContext(ctx, "fetching assistantthread file error", slog.Any("error", err))
 		// 		return nil, err
 		// 	}
 		// 	if assistantthreadFile == nil {
-		// 		impl.Logger.Error("assistantthread file does not exist error", slog.Any("assistantthreadFileID", assistantthreadFileID))
+		// 		impl.Logger.ErrorContext(ctx, "assistantthread file does not exist error", slog.Any("assistantthreadFileID", assistantthreadFileID))
 		// 		return nil, httperror.NewForBadRequestWithSingleField("assistantthread_file_ids", assistantthreadFileID.Hex()+" assistantthread file id does not exist")
 		// 	}
 		// 	af := &assistantthread_s.AssistantThreadFileOption{
@@ -138,7 +145,7 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 		//
 		// creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tid)
 		// if err != nil {
-		// 	impl.Logger.Error("failed to get OpenAI credentials",
+		// 	impl.Logger.ErrorContext(ctx, "failed to get OpenAI credentials",
 		// 		slog.String("tenant_id", tid.Hex()),
 		// 		slog.Any("error", err))
 		// 	return nil, err
@@ -147,19 +154,19 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 		// 	return nil, errors.New("no openai credentials returned")
 		// }
 		//
-		// impl.Logger.Debug("openai initializing...")
+		// impl.Logger.DebugContext(ctx, "openai initializing...")
 		// client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
-		// impl.Logger.Debug("openai initialized")
+		// impl.Logger.DebugContext(ctx, "openai initialized")
 		//
 		// assistantthread, err := client.RetrieveAssistantThread(sessCtx, ou.OpenAIAssistantThreadID)
 		// if err != nil {
-		// 	impl.Logger.Error("failed to get OpenAI credentials",
+		// 	impl.Logger.ErrorContext(ctx, "failed to get OpenAI credentials",
 		// 		slog.String("tenant_id", tid.Hex()),
 		// 		slog.Any("error", err))
 		// 	return nil, err
 		// }
 		// if isStructEmpty(assistantthread) {
-		// 	impl.Logger.Error("no openai assistantthread returned")
+		// 	impl.Logger.ErrorContext(ctx, "no openai assistantthread returned")
 		// 	return "", errors.New("no openai assistantthread returned")
 		// }
 		//
@@ -181,12 +188,16 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 		// 	// Metadata
 		// }
 		// if _, err := client.ModifyAssistantThread(sessCtx, assistantthread.ID, modReq); err != nil {
-		// 	impl.Logger.Error("failed modify assistantthread",
+		// 	impl.Logger.ErrorContext(ctx, "failed modify assistantthread",
 		// 		slog.String("tenant_id", tid.Hex()),
 		// 		slog.Any("error", err))
 		// 	return nil, err
 		// }
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeAssistantThread, ou.ID, &before, ou); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -197,7 +208,7 @@ func (impl *AssistantThreadControllerImpl) UpdateByID(ctx context.Context, reque
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -6,20 +6,29 @@ import (
 	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func (impl AssistantThreadStorerImpl) UpdateByID(ctx context.Context, m *AssistantThread) error {
-	filter := bson.D{{"_id", m.ID}}
+	// Only update the record if nobody else updated it since it was retrieved.
+	filter := versioning.Filter(m.ID, m.Version)
+	m.Version++
 
 	update := bson.M{ // DEVELOPERS NOTE: https://stackoverflow.com/a/60946010
 		"$set": m,
 	}
 
 	// execute the UpdateOne() function to update the first matching document
-	_, err := impl.Collection.UpdateOne(ctx, filter, update)
+	res, err := impl.Collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		impl.Logger.Error("database update by user id error", slog.Any("error", err))
+		m.Version--
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	if res.MatchedCount == 0 {
+		m.Version--
+		return versioning.ErrConflict
 	}
-
 	return nil
 }
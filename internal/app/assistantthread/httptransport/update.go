@@ -8,6 +8,7 @@ import (
 	assistantthread_c "github.com/bartmika/databoutique-backend/internal/app/assistantthread/controller"
 	assistantthread_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func UnmarshalUpdateRequest(ctx context.Context, r *http.Request) (*assistantthread_c.AssistantThreadUpdateRequestIDO, error) {
@@ -40,12 +41,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(org.Version))
 	MarshalUpdateResponse(org, w)
 }
 
 func MarshalUpdateResponse(res *assistantthread_s.AssistantThread, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -7,10 +7,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
-	mg "github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer"
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	attachment_s "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
 	domain "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
@@ -32,10 +33,11 @@ type AttachmentControllerImpl struct {
 	Logger           *slog.Logger
 	UUID             uuid.Provider
 	S3               s3_storage.S3Storager
-	Emailer          mg.Emailer
+	Emailer          emailer.Emailer
 	DbClient         *mongo.Client
 	AttachmentStorer attachment_s.AttachmentStorer
 	UserStorer       user_s.UserStorer
+	AuditEvent       auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -44,19 +46,21 @@ func NewController(
 	uuidp uuid.Provider,
 	s3 s3_storage.S3Storager,
 	client *mongo.Client,
-	emailer mg.Emailer,
+	emailerp emailer.Emailer,
 	org_storer attachment_s.AttachmentStorer,
 	usr_storer user_s.UserStorer,
+	auditevent auditevent_c.AuditEventController,
 ) AttachmentController {
 	s := &AttachmentControllerImpl{
 		Config:           appCfg,
 		Logger:           loggerp,
 		UUID:             uuidp,
 		S3:               s3,
-		Emailer:          emailer,
+		Emailer:          emailerp,
 		DbClient:         client,
 		AttachmentStorer: org_storer,
 		UserStorer:       usr_storer,
+		AuditEvent:       auditevent,
 	}
 	s.Logger.Debug("attachment controller initialization started...")
 	s.Logger.Debug("attachment controller initialized")
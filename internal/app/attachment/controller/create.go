@@ -11,18 +11,19 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	a_d "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 type AttachmentCreateRequestIDO struct {
-	Name          string
-	Description   string
-	OwnershipID   primitive.ObjectID
-	OwnershipType int8
-	FileName      string
-	FileType      string
-	File          multipart.File
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	OwnershipID   primitive.ObjectID `json:"ownership_id"`
+	OwnershipType int8               `json:"ownership_type"`
+	FileName      string             `json:"-"`
+	FileType      string             `json:"-"`
+	File          multipart.File     `json:"file"`
 }
 
 func ValidateCreateRequest(dirtyData *AttachmentCreateRequestIDO) error {
@@ -79,7 +80,7 @@ func (c *AttachmentControllerImpl) Create(ctx context.Context, req *AttachmentCr
 	objectKey := fmt.Sprintf("org/%v/%v/%v/%v", orgID.Hex(), directory, req.OwnershipID.Hex(), req.FileName)
 
 	// For debugging purposes only.
-	c.Logger.Debug("pre-upload meta",
+	c.Logger.DebugContext(ctx, "pre-upload meta",
 		slog.String("FileName", req.FileName),
 		slog.String("FileType", req.FileType),
 		slog.String("Directory", directory),
@@ -89,14 +90,14 @@ func (c *AttachmentControllerImpl) Create(ctx context.Context, req *AttachmentCr
 	)
 
 	go func(file multipart.File, objkey string) {
-		c.Logger.Debug("beginning private s3 file upload...")
+		c.Logger.DebugContext(ctx, "beginning private s3 file upload...")
 		if err := c.S3.UploadContentFromMulipart(context.Background(), objkey, file); err != nil {
-			c.Logger.Error("private s3 file upload error", slog.Any("error", err))
+			c.Logger.ErrorContext(ctx, "private s3 file upload error", slog.Any("error", err))
 			// Do not return an error, simply continue this function as there might
 			// be a case were the file was removed on the s3 bucket by ourselves
 			// or some other reason.
 		}
-		c.Logger.Debug("Finished private s3 file upload")
+		c.Logger.DebugContext(ctx, "Finished private s3 file upload")
 	}(req.File, objectKey)
 
 	// Create our meta record in the database.
@@ -121,7 +122,10 @@ func (c *AttachmentControllerImpl) Create(ctx context.Context, req *AttachmentCr
 	}
 	err := c.AttachmentStorer.Create(ctx, res)
 	if err != nil {
-		c.Logger.Error("attachment create error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "attachment create error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := c.AuditEvent.Record(ctx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeAttachment, res.ID, nil, res); err != nil {
 		return nil, err
 	}
 	return res, nil
@@ -2,12 +2,12 @@ package controller
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	attch_d "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
@@ -20,7 +20,7 @@ func (impl *AttachmentControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		impl.Logger.Error("authenticated user is not staff role error",
+		impl.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
@@ -28,24 +28,28 @@ func (impl *AttachmentControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 	// Update the database.
 	attachment, err := impl.GetByID(ctx, id)
-	attachment.Status = attch_d.StatusArchived
 	if err != nil {
-		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return err
 	}
 	if attachment == nil {
-		impl.Logger.Error("database returns nothing from get by id")
+		impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 		return err
 	}
+	before := *attachment
+	attachment.Status = attch_d.StatusArchived
 	// // Security: Prevent deletion of root user(s).
 	// if attachment.Type == attch_d.RootType {
-	// 	impl.Logger.Warn("root attachment cannot be deleted error")
+	// 	impl.Logger.WarnContext(ctx, "root attachment cannot be deleted error")
 	// 	return httperror.NewForForbiddenWithSingleField("role", "root attachment cannot be deleted")
 	// }
 
 	// Save to the database the modified attachment.
 	if err := impl.AttachmentStorer.UpdateByID(ctx, attachment); err != nil {
-		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeAttachment, attachment.ID, &before, attachment); err != nil {
 		return err
 	}
 	return nil
@@ -58,7 +62,7 @@ func (impl *AttachmentControllerImpl) PermanentlyDeleteByID(ctx context.Context,
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		impl.Logger.Error("authenticated user is not staff role error",
+		impl.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
@@ -67,28 +71,31 @@ func (impl *AttachmentControllerImpl) PermanentlyDeleteByID(ctx context.Context,
 	// Update the database.
 	attachment, err := impl.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return err
 	}
 	if attachment == nil {
-		impl.Logger.Error("database returns nothing from get by id")
-		return errors.New("does not exist")
+		impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
+		return httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
 
 	// Proceed to delete the physical files from AWS s3.
 	if err := impl.S3.DeleteByKeys(ctx, []string{attachment.ObjectKey}); err != nil {
-		impl.Logger.Warn("s3 delete by keys error", slog.Any("error", err))
+		impl.Logger.WarnContext(ctx, "s3 delete by keys error", slog.Any("error", err))
 		// Do not return an error, simply continue this function as there might
 		// be a case were the file was removed on the s3 bucket by ourselves
 		// or some other reason.
 	}
-	impl.Logger.Debug("deleted from s3", slog.Any("attachment_id", id))
+	impl.Logger.DebugContext(ctx, "deleted from s3", slog.Any("attachment_id", id))
 
 	if err := impl.AttachmentStorer.DeleteByID(ctx, attachment.ID); err != nil {
-		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+		return err
+	}
+	impl.Logger.DebugContext(ctx, "deleted from database", slog.Any("attachment_id", id))
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeAttachment, attachment.ID, attachment, nil); err != nil {
 		return err
 	}
-	impl.Logger.Debug("deleted from database", slog.Any("attachment_id", id))
 
 	// Update exercise.
 	if !attachment.OwnershipID.IsZero() {
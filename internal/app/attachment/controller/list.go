@@ -24,20 +24,20 @@ func (c *AttachmentControllerImpl) ListByFilter(ctx context.Context, f *domain.A
 		f.TenantID = orgID // Force tenant tenancy restrictions.
 	}
 
-	c.Logger.Debug("fetching attachments now...", slog.Any("userID", userID))
+	c.Logger.DebugContext(ctx, "fetching attachments now...", slog.Any("userID", userID))
 
 	aa, err := c.AttachmentStorer.ListByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
-	c.Logger.Debug("fetched attachments", slog.Any("aa", aa))
+	c.Logger.DebugContext(ctx, "fetched attachments", slog.Any("aa", aa))
 
 	for _, a := range aa.Results {
 		// Generate the URL.
 		fileURL, err := c.S3.GetPresignedURL(ctx, a.ObjectKey, 5*time.Minute)
 		if err != nil {
-			c.Logger.Error("s3 failed get presigned url error", slog.Any("error", err))
+			c.Logger.ErrorContext(ctx, "s3 failed get presigned url error", slog.Any("error", err))
 			return nil, err
 		}
 		a.ObjectURL = fileURL
@@ -52,19 +52,19 @@ func (c *AttachmentControllerImpl) ListAsSelectOptionByFilter(ctx context.Contex
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		c.Logger.Error("authenticated user is not staff role error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
 	}
 
-	c.Logger.Debug("fetching attachments now...", slog.Any("userID", userID))
+	c.Logger.DebugContext(ctx, "fetching attachments now...", slog.Any("userID", userID))
 
 	m, err := c.AttachmentStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
-	c.Logger.Debug("fetched attachments", slog.Any("m", m))
+	c.Logger.DebugContext(ctx, "fetched attachments", slog.Any("m", m))
 	return m, err
 }
@@ -12,20 +12,22 @@ import (
 
 	a_d "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
 	domain "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type AttachmentUpdateRequestIDO struct {
-	ID            primitive.ObjectID
-	Name          string
-	Description   string
-	OwnershipID   primitive.ObjectID
-	OwnershipType int8
-	FileName      string
-	FileType      string
-	File          multipart.File
+	ID            primitive.ObjectID `json:"id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	OwnershipID   primitive.ObjectID `json:"ownership_id"`
+	OwnershipType int8               `json:"ownership_type"`
+	FileName      string             `json:"-"`
+	FileType      string             `json:"-"`
+	File          multipart.File     `json:"file"`
 }
 
 func ValidateUpdateRequest(dirtyData *AttachmentUpdateRequestIDO) error {
@@ -60,16 +62,21 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 	// Fetch the original attachment.
 	os, err := c.AttachmentStorer.GetByID(ctx, req.ID)
 	if err != nil {
-		c.Logger.Error("database get by id error",
+		c.Logger.ErrorContext(ctx, "database get by id error",
 			slog.Any("error", err),
 			slog.Any("attachment_id", req.ID))
 		return nil, err
 	}
 	if os == nil {
-		c.Logger.Error("attachment does not exist error",
+		c.Logger.ErrorContext(ctx, "attachment does not exist error",
 			slog.Any("attachment_id", req.ID))
-		return nil, httperror.NewForBadRequestWithSingleField("message", "attachment does not exist")
+		return nil, httperror.NewForNotFoundWithSingleField("message", "attachment does not exist")
 	}
+	// Return `412 precondition failed` if the record was modified since the client retrieved it.
+	if err := versioning.CheckExpectedVersion(ctx, os.Version); err != nil {
+		return nil, err
+	}
+	before := *os
 
 	// Extract from our session the following data.
 	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
@@ -79,7 +86,7 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 
 	// If user is not administrator nor belongs to the attachment then error.
 	if userRole != user_d.UserRoleExecutive {
-		c.Logger.Error("authenticated user is not staff role nor belongs to the attachment error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role nor belongs to the attachment error",
 			slog.Any("userRole", userRole),
 			slog.Any("userTenantID", userTenantID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this attachment")
@@ -89,7 +96,7 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 	if req.File != nil {
 		// Proceed to delete the physical files from AWS s3.
 		if err := c.S3.DeleteByKeys(ctx, []string{os.ObjectKey}); err != nil {
-			c.Logger.Warn("s3 delete by keys error", slog.Any("error", err))
+			c.Logger.WarnContext(ctx, "s3 delete by keys error", slog.Any("error", err))
 			// Do not return an error, simply continue this function as there might
 			// be a case were the file was removed on the s3 bucket by ourselves
 			// or some other reason.
@@ -103,7 +110,7 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 		case a_d.OwnershipTypeTenant:
 			directory = "tenant"
 		default:
-			c.Logger.Error("unsupported ownership type format", slog.Any("ownership_type", req.OwnershipType))
+			c.Logger.ErrorContext(ctx, "unsupported ownership type format", slog.Any("ownership_type", req.OwnershipType))
 			return nil, fmt.Errorf("unsuported iownership type  of %v, please pick another type", req.OwnershipType)
 		}
 
@@ -111,14 +118,14 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 		objectKey := fmt.Sprintf("org/%v/%v/%v/%v", userTenantID.Hex(), directory, req.OwnershipID.Hex(), req.FileName)
 
 		go func(file multipart.File, objkey string) {
-			c.Logger.Debug("beginning private s3 image upload...")
+			c.Logger.DebugContext(ctx, "beginning private s3 image upload...")
 			if err := c.S3.UploadContentFromMulipart(context.Background(), objkey, file); err != nil {
-				c.Logger.Error("private s3 upload error", slog.Any("error", err))
+				c.Logger.ErrorContext(ctx, "private s3 upload error", slog.Any("error", err))
 				// Do not return an error, simply continue this function as there might
 				// be a case were the file was removed on the s3 bucket by ourselves
 				// or some other reason.
 			}
-			c.Logger.Debug("Finished private s3 image upload")
+			c.Logger.DebugContext(ctx, "Finished private s3 image upload")
 		}(req.File, objectKey)
 
 		// Update file.
@@ -137,7 +144,10 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 
 	// Save to the database the modified attachment.
 	if err := c.AttachmentStorer.UpdateByID(ctx, os); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := c.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeAttachment, os.ID, &before, os); err != nil {
 		return nil, err
 	}
 
@@ -152,18 +162,18 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 }
 
 // func (c *AttachmentControllerImpl) updateAttachmentNameForAllUsers(ctx context.Context, ns *domain.Attachment) error {
-// 	c.Logger.Debug("Beginning to update attachment name for all uses")
+// 	c.Logger.DebugContext(ctx, "Beginning to update attachment name for all uses")
 // 	f := &user_d.UserListFilter{AttachmentID: ns.ID}
 // 	uu, err := c.UserStorer.ListByFilter(ctx, f)
 // 	if err != nil {
-// 		c.Logger.Error("database update by id error", slog.Any("error", err))
+// 		c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
 // 		return err
 // 	}
 // 	for _, u := range uu.Results {
 // 		u.AttachmentName = ns.Name
 // 		log.Println("--->", u)
 // 		// if err := c.UserStorer.UpdateByID(ctx, u); err != nil {
-// 		// 	c.Logger.Error("database update by id error", slog.Any("error", err))
+// 		// 	c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
 // 		// 	return err
 // 		// }
 // 	}
@@ -171,18 +181,18 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 // }
 //
 // func (c *AttachmentControllerImpl) updateAttachmentNameForAllComicSubmissions(ctx context.Context, ns *domain.Attachment) error {
-// 	c.Logger.Debug("Beginning to update attachment name for all submissions")
+// 	c.Logger.DebugContext(ctx, "Beginning to update attachment name for all submissions")
 // 	f := &domain.ComicSubmissionListFilter{AttachmentID: ns.ID}
 // 	uu, err := c.ComicSubmissionStorer.ListByFilter(ctx, f)
 // 	if err != nil {
-// 		c.Logger.Error("database update by id error", slog.Any("error", err))
+// 		c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
 // 		return err
 // 	}
 // 	for _, u := range uu.Results {
 // 		u.AttachmentName = ns.Name
 // 		log.Println("--->", u)
 // 		// if err := c.ComicSubmissionStorer.UpdateByID(ctx, u); err != nil {
-// 		// 	c.Logger.Error("database update by id error", slog.Any("error", err))
+// 		// 	c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
 // 		// 	return err
 // 		// }
 // 	}
@@ -35,6 +35,7 @@ type Attachment struct {
 	ModifiedAt         time.Time          `bson:"modified_at,omitempty" json:"modified_at,omitempty"`
 	ModifiedByUserName string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedByUserID   primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id"`
+	Version            int64              `bson:"version" json:"version"`
 	Name               string             `bson:"name" json:"name"`
 	Description        string             `bson:"description" json:"description"`
 	Filename           string             `bson:"filename" json:"filename"`
@@ -65,7 +65,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 
 func MarshalListResponse(res *sub_s.AttachmentListResult, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -92,7 +92,7 @@ func (h *Handler) ListAsSelectOptionByFilter(w http.ResponseWriter, r *http.Requ
 
 func MarshalListAsSelectOptionResponse(res []*sub_s.AttachmentAsSelectOption, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
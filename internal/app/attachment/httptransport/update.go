@@ -11,6 +11,7 @@ import (
 	sub_c "github.com/bartmika/databoutique-backend/internal/app/attachment/controller"
 	sub_s "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -81,12 +82,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(attachment.Version))
 	MarshalUpdateResponse(attachment, w)
 }
 
 func MarshalUpdateResponse(res *sub_s.Attachment, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
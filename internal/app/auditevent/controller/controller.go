@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// AuditEventController Interface for the audit log business logic controller.
+type AuditEventController interface {
+	// Record function appends an audit event for the authenticated user in
+	// the context. The `before` and `after` values are the resource before
+	// and after the action; either may be nil for creates and deletes.
+	Record(ctx context.Context, action string, resourceType string, resourceID primitive.ObjectID, before interface{}, after interface{}) error
+	ListByFilter(ctx context.Context, f *auditevent_s.AuditEventPaginationListFilter) (*auditevent_s.AuditEventPaginationListResult, error)
+	ExportAsCSVByFilter(ctx context.Context, f *auditevent_s.AuditEventPaginationListFilter, w io.Writer) error
+}
+
+type AuditEventControllerImpl struct {
+	Config           *config.Conf
+	Logger           *slog.Logger
+	AuditEventStorer auditevent_s.AuditEventStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	auditevent_storer auditevent_s.AuditEventStorer,
+) AuditEventController {
+	s := &AuditEventControllerImpl{
+		Config:           appCfg,
+		Logger:           loggerp,
+		AuditEventStorer: auditevent_storer,
+	}
+	s.Logger.Debug("audit event controller initialization started...")
+	s.Logger.Debug("audit event controller initialized")
+	return s
+}
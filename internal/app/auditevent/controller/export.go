@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+)
+
+// maxExportRows is the upper limit of rows a single CSV export may contain.
+const maxExportRows = 100_000
+
+var csvHeader = []string{
+	"created_at",
+	"tenant_id",
+	"tenant_name",
+	"user_id",
+	"user_name",
+	"user_role",
+	"ip_address",
+	"action",
+	"resource_type",
+	"resource_id",
+	"changes",
+}
+
+func (impl *AuditEventControllerImpl) ExportAsCSVByFilter(ctx context.Context, f *auditevent_s.AuditEventPaginationListFilter, w io.Writer) error {
+	if err := impl.checkPermission(ctx); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	// Iterate through every page of the filtered results.
+	var count int
+	for {
+		res, err := impl.AuditEventStorer.ListByFilter(ctx, f)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
+			return err
+		}
+		for _, ae := range res.Results {
+			changes, err := json.Marshal(ae.Changes)
+			if err != nil {
+				return err
+			}
+			row := []string{
+				ae.CreatedAt.UTC().Format(time.RFC3339),
+				ae.TenantID.Hex(),
+				escapeCSVFormula(ae.TenantName),
+				ae.UserID.Hex(),
+				escapeCSVFormula(ae.UserName),
+				strconv.Itoa(int(ae.UserRole)),
+				ae.IPAddress,
+				ae.Action,
+				ae.ResourceType,
+				ae.ResourceID.Hex(),
+				escapeCSVFormula(string(changes)),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+			count++
+		}
+		if !res.HasNextPage || count >= maxExportRows {
+			break
+		}
+		f.Cursor = res.NextCursor
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeCSVFormula function prefixes the user controlled values which a
+// spreadsheet would evaluate as a formula, ex: `=HYPERLINK(...)`, so they
+// are displayed as text instead.
+func escapeCSVFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,21 @@
+package controller
+
+import "testing"
+
+func TestEscapeCSVFormula(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                  "",
+		"Alice Smith":       "Alice Smith",
+		`{"name":"Acme"}`:   `{"name":"Acme"}`,
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1 555 0100":       "'+1 555 0100",
+		"-2+3":              "'-2+3",
+		"@SUM(A1:A2)":       "'@SUM(A1:A2)",
+		"\t=1":              "'\t=1",
+		"Acme =1":           "Acme =1",
+	} {
+		if got := escapeCSVFormula(in); got != want {
+			t.Errorf("escapeCSVFormula(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// checkPermission function returns an error if the authenticated user is not
+// allowed to read the audit log.
+func (impl *AuditEventControllerImpl) checkPermission(ctx context.Context) error {
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userRole, _ := ctx.Value(constants.SessionUserRole).(int8)
+	if userRole != user_s.UserRoleExecutive {
+		impl.Logger.ErrorContext(ctx, "authenticated user is not executive role error",
+			slog.Any("role", userRole),
+			slog.Any("userID", userID))
+		return httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
+	}
+	return nil
+}
+
+func (impl *AuditEventControllerImpl) ListByFilter(ctx context.Context, f *auditevent_s.AuditEventPaginationListFilter) (*auditevent_s.AuditEventPaginationListResult, error) {
+	if err := impl.checkPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	res, err := impl.AuditEventStorer.ListByFilter(ctx, f)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+)
+
+const redactedValue = "[REDACTED]"
+
+// ignoredFields are the book-keeping fields which change on every update and
+// are already captured by the audit event itself.
+var ignoredFields = map[string]bool{
+	"modified_at":              true,
+	"modified_by_user_id":      true,
+	"modified_by_user_name":    true,
+	"modified_from_ip_address": true,
+	"version":                  true,
+}
+
+// sensitiveFieldFragments are the field name fragments whose values must never
+// be written to the audit log.
+var sensitiveFieldFragments = []string{"password", "secret", "token", "hash", "api_key", "org_key"}
+
+func (impl *AuditEventControllerImpl) Record(ctx context.Context, action string, resourceType string, resourceID primitive.ObjectID, before interface{}, after interface{}) error {
+	// Get variables from our user authenticated session.
+	tid, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+	tenantName, _ := ctx.Value(constants.SessionUserTenantName).(string)
+	role, _ := ctx.Value(constants.SessionUserRole).(int8)
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	b, err := toMap(before)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed marshalling audit event resource", slog.Any("error", err))
+		return err
+	}
+	a, err := toMap(after)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed marshalling audit event resource", slog.Any("error", err))
+		return err
+	}
+
+	// DEVELOPERS NOTE:
+	// Executives may act on resources of other tenants and some actions
+	// (ex: accepting an invitation) happen without a session, therefore we
+	// attach the event to the tenant which owns the resource when known.
+	for _, m := range []map[string]interface{}{a, b} {
+		if v, ok := m["tenant_id"].(string); ok {
+			if id, err := primitive.ObjectIDFromHex(v); err == nil && !id.IsZero() {
+				tid = id
+				tenantName, _ = m["tenant_name"].(string)
+				break
+			}
+		}
+	}
+
+	ae := &auditevent_s.AuditEvent{
+		ID:           primitive.NewObjectID(),
+		TenantID:     tid,
+		TenantName:   tenantName,
+		UserID:       userID,
+		UserName:     userName,
+		UserRole:     role,
+		IPAddress:    ipAddress,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Changes:      diff(b, a),
+		CreatedAt:    time.Now(),
+	}
+	if err := impl.AuditEventStorer.Create(ctx, ae); err != nil {
+		impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// diff function returns the top-level fields which differ between the JSON
+// representations of the resource before and after the action. Fields hidden
+// from the API (ex: `json:"-"`) are therefore never recorded.
+func diff(b map[string]interface{}, a map[string]interface{}) map[string]*auditevent_s.AuditEventChange {
+	changes := make(map[string]*auditevent_s.AuditEventChange)
+	for k, bv := range b {
+		if ignoredFields[k] {
+			continue
+		}
+		av, ok := a[k]
+		if ok && reflect.DeepEqual(av, bv) {
+			continue
+		}
+		changes[k] = &auditevent_s.AuditEventChange{Before: redact(k, bv), After: redact(k, av)}
+	}
+	for k, av := range a {
+		if ignoredFields[k] {
+			continue
+		}
+		if _, ok := b[k]; ok {
+			continue // Handled above.
+		}
+		changes[k] = &auditevent_s.AuditEventChange{Before: nil, After: redact(k, av)}
+	}
+	return changes
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return m, nil
+	}
+	bin, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bin, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// redact function hides the value of sensitive fields, including the ones
+// nested inside objects.
+func redact(key string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	k := strings.ToLower(key)
+	for _, fragment := range sensitiveFieldFragments {
+		if strings.Contains(k, fragment) {
+			return redactedValue
+		}
+	}
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(vv))
+		for nk, nv := range vv {
+			out[nk] = redact(nk, nv)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(vv))
+		for i, nv := range vv {
+			out[i] = redact(key, nv)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type testResource struct {
+	ID           primitive.ObjectID `json:"id"`
+	Name         string             `json:"name"`
+	Status       int8               `json:"status"`
+	PasswordHash string             `json:"password_hash"`
+	OTPSecret    string             `json:"-"`
+	ModifiedAt   string             `json:"modified_at"`
+}
+
+func TestDiff(t *testing.T) {
+	before := &testResource{ID: primitive.NewObjectID(), Name: "Alpha", Status: 1, PasswordHash: "old", OTPSecret: "a", ModifiedAt: "yesterday"}
+	after := *before
+	after.Name = "Beta"
+	after.PasswordHash = "new"
+	after.OTPSecret = "b"
+	after.ModifiedAt = "today"
+
+	b, _ := toMap(before)
+	a, _ := toMap(&after)
+	changes := diff(b, a)
+
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes but got %d: %v", len(changes), changes)
+	}
+	if c := changes["name"]; c == nil || c.Before != "Alpha" || c.After != "Beta" {
+		t.Fatalf("unexpected name change %+v", c)
+	}
+	if c := changes["password_hash"]; c == nil || c.Before != redactedValue || c.After != redactedValue {
+		t.Fatalf("expected password hash to be redacted but got %+v", c)
+	}
+}
+
+func TestDiffCreateAndDelete(t *testing.T) {
+	r := &testResource{ID: primitive.NewObjectID(), Name: "Alpha"}
+	m, _ := toMap(r)
+
+	var nilResource *testResource
+	empty, err := toMap(nilResource)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+
+	created := diff(empty, m)
+	if c := created["name"]; c == nil || c.Before != nil || c.After != "Alpha" {
+		t.Fatalf("unexpected create change %+v", c)
+	}
+	deleted := diff(m, empty)
+	if c := deleted["name"]; c == nil || c.Before != "Alpha" || c.After != nil {
+		t.Fatalf("unexpected delete change %+v", c)
+	}
+}
+
+func TestRedactNested(t *testing.T) {
+	v := redact("oidc", map[string]interface{}{"client_id": "abc", "client_secret": "shh"})
+	m := v.(map[string]interface{})
+	if m["client_id"] != "abc" || m["client_secret"] != redactedValue {
+		t.Fatalf("unexpected redaction %v", m)
+	}
+}
@@ -0,0 +1,97 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	AuditEventActionCreate  = "create"
+	AuditEventActionUpdate  = "update"
+	AuditEventActionArchive = "archive"
+	AuditEventActionDelete  = "delete"
+
+	AuditEventResourceTypeAPIKey             = "api_key"
+	AuditEventResourceTypeAssistant          = "assistant"
+	AuditEventResourceTypeAssistantFile      = "assistant_file"
+	AuditEventResourceTypeAssistantMessage   = "assistant_message"
+	AuditEventResourceTypeAssistantThread    = "assistant_thread"
+	AuditEventResourceTypeAttachment         = "attachment"
+	AuditEventResourceTypeExecutable         = "executable"
+	AuditEventResourceTypeHowHearAboutUsItem = "how_hear_about_us_item"
+	AuditEventResourceTypeInvitation         = "invitation"
+	AuditEventResourceTypeProgram            = "program"
+	AuditEventResourceTypeProgramCategory    = "program_category"
+	AuditEventResourceTypeTenant             = "tenant"
+	AuditEventResourceTypeUploadDirectory    = "upload_directory"
+	AuditEventResourceTypeUploadFile         = "upload_file"
+	AuditEventResourceTypeUser               = "user"
+	AuditEventResourceTypeWebhook            = "webhook"
+)
+
+// AuditEvent represents a single mutating action performed by a user. These
+// records are append-only and must never be updated or deleted.
+type AuditEvent struct {
+	ID           primitive.ObjectID           `bson:"_id" json:"id"`
+	TenantID     primitive.ObjectID           `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	TenantName   string                       `bson:"tenant_name" json:"tenant_name"`
+	UserID       primitive.ObjectID           `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	UserName     string                       `bson:"user_name" json:"user_name"`
+	UserRole     int8                         `bson:"user_role" json:"user_role"`
+	IPAddress    string                       `bson:"ip_address" json:"ip_address"`
+	Action       string                       `bson:"action" json:"action"`
+	ResourceType string                       `bson:"resource_type" json:"resource_type"`
+	ResourceID   primitive.ObjectID           `bson:"resource_id" json:"resource_id"`
+	Changes      map[string]*AuditEventChange `bson:"changes" json:"changes"`
+	CreatedAt    time.Time                    `bson:"created_at" json:"created_at"`
+}
+
+// AuditEventChange represents the before and after value of a single field.
+type AuditEventChange struct {
+	Before interface{} `bson:"before" json:"before"`
+	After  interface{} `bson:"after" json:"after"`
+}
+
+// AuditEventStorer Interface for audit events. There are no update or delete
+// functions on purpose as the collection is append-only.
+type AuditEventStorer interface {
+	Create(ctx context.Context, m *AuditEvent) error
+	ListByFilter(ctx context.Context, f *AuditEventPaginationListFilter) (*AuditEventPaginationListResult, error)
+}
+
+type AuditEventStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) AuditEventStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("audit_events")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "resource_type", Value: 1}, {Key: "resource_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &AuditEventStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
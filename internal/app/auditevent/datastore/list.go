@@ -0,0 +1,100 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (impl AuditEventStorerImpl) ListByFilter(ctx context.Context, f *AuditEventPaginationListFilter) (*AuditEventPaginationListResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+
+	filter, err := impl.newPaginationFilter(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add filter conditions to the filter
+	if !f.TenantID.IsZero() {
+		filter["tenant_id"] = f.TenantID
+	}
+	if !f.UserID.IsZero() {
+		filter["user_id"] = f.UserID
+	}
+	if !f.ResourceID.IsZero() {
+		filter["resource_id"] = f.ResourceID
+	}
+	if f.ResourceType != "" {
+		filter["resource_type"] = f.ResourceType
+	}
+	if f.Action != "" {
+		filter["action"] = f.Action
+	}
+
+	// Create a slice to store conditions
+	var conditions []bson.M
+	if !f.CreatedAtGTE.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$gte": f.CreatedAtGTE}})
+	}
+	if !f.CreatedAtLTE.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$lte": f.CreatedAtLTE}})
+	}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+
+	impl.Logger.Debug("fetching audit event list",
+		slog.String("Cursor", f.Cursor),
+		slog.Int64("PageSize", f.PageSize),
+		slog.String("SortField", f.SortField),
+		slog.Any("SortOrder", f.SortOrder),
+		slog.Any("TenantID", f.TenantID),
+	)
+
+	// Include additional filters for our cursor-based pagination pertaining to sorting and limit.
+	options, err := impl.newPaginationOptions(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute the query
+	cursor, err := impl.Collection.Find(ctx, filter, options)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// Retrieve the documents and check if there is a next page
+	results := []*AuditEvent{}
+	hasNextPage := false
+	for cursor.Next(ctx) {
+		document := &AuditEvent{}
+		if err := cursor.Decode(document); err != nil {
+			return nil, err
+		}
+		results = append(results, document)
+		// Stop fetching documents if we have reached the desired page size
+		if int64(len(results)) >= f.PageSize {
+			hasNextPage = true
+			break
+		}
+	}
+
+	// Get the next cursor and encode it
+	var nextCursor string
+	if hasNextPage {
+		nextCursor, err = impl.newPaginatorNextCursor(f, results)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &AuditEventPaginationListResult{
+		Results:     results,
+		NextCursor:  nextCursor,
+		HasNextPage: hasNextPage,
+	}, nil
+}
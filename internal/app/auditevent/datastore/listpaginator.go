@@ -0,0 +1,140 @@
+package datastore
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bartmika/timekit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	OrderAscending  = 1
+	OrderDescending = -1
+)
+
+type AuditEventPaginationListFilter struct {
+	// Pagination related.
+	Cursor    string
+	PageSize  int64
+	SortField string
+	SortOrder int8 // 1=ascending | -1=descending
+
+	// Filter related.
+	TenantID     primitive.ObjectID
+	UserID       primitive.ObjectID
+	ResourceID   primitive.ObjectID
+	ResourceType string
+	Action       string
+
+	CreatedAtGTE time.Time
+	CreatedAtLTE time.Time
+}
+
+// AuditEventPaginationListResult represents the paginated list results for
+// the audit event records.
+type AuditEventPaginationListResult struct {
+	Results     []*AuditEvent `json:"results"`
+	NextCursor  string        `json:"next_cursor"`
+	HasNextPage bool          `json:"has_next_page"`
+}
+
+// newPaginationFilter will create the mongodb filter to apply the cursor or
+// or ignore it depending if a cursor was specified in the filter.
+func (impl AuditEventStorerImpl) newPaginationFilter(f *AuditEventPaginationListFilter) (bson.M, error) {
+	if len(f.Cursor) > 0 {
+		// STEP 1: Decode the cursor which is encoded in a base64 format.
+		decodedCursor, err := base64.RawStdEncoding.DecodeString(f.Cursor)
+		if err != nil {
+			return bson.M{}, fmt.Errorf("Failed to decode string: %v", err)
+		}
+
+		// STEP 2: Pick the specific cursor to build or else error.
+		switch f.SortField {
+		case "created_at":
+			// STEP 3: Build for `created_at` field.
+			return impl.newPaginationFilterBasedOnTimestamp(f, string(decodedCursor))
+		default:
+			return nil, fmt.Errorf("unsupported sort field for `%v`, only supported field is `created_at`", f.SortField)
+		}
+	}
+	return bson.M{}, nil
+}
+
+func (impl AuditEventStorerImpl) newPaginationFilterBasedOnTimestamp(f *AuditEventPaginationListFilter, decodedCursor string) (bson.M, error) {
+	// Extract our cursor into two parts which we need to use.
+	arr := strings.Split(decodedCursor, "|")
+	if len(arr) < 2 {
+		return nil, fmt.Errorf("cursor is corrupted for the value `%v`", decodedCursor)
+	}
+
+	// The first part will contain the timestamp we left off at. The second
+	// part will be last ID we left off at.
+	timestampStr := arr[0]
+	lastID, err := primitive.ObjectIDFromHex(arr[1])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to convert into mongodb object id: %v, from the decoded cursor of: %v", err, decodedCursor)
+	}
+
+	timestamp, err := timekit.ParseJavaScriptTimeString(timestampStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse javascript timestamp: `%v`", err)
+	}
+
+	switch f.SortOrder {
+	case OrderAscending:
+		filter := bson.M{}
+		filter["$or"] = []bson.M{
+			{f.SortField: bson.M{"$gt": timestamp}},
+			{f.SortField: timestamp, "_id": bson.M{"$gt": lastID}},
+		}
+		return filter, nil
+	case OrderDescending:
+		filter := bson.M{}
+		filter["$or"] = []bson.M{
+			{f.SortField: bson.M{"$lt": timestamp}},
+			{f.SortField: timestamp, "_id": bson.M{"$lt": lastID}},
+		}
+		return filter, nil
+	default:
+		return nil, fmt.Errorf("unsupported sort order for `%v`, only supported values are `1` or `-1`", f.SortOrder)
+	}
+}
+
+// newPaginatorOptions will generate the mongodb options which will support the
+// paginator in ordering the data to work.
+func (impl AuditEventStorerImpl) newPaginationOptions(f *AuditEventPaginationListFilter) (*options.FindOptions, error) {
+	options := options.Find().SetLimit(f.PageSize)
+	if f.SortField != "" {
+		options = options.
+			SetSort(bson.D{
+				{Key: f.SortField, Value: f.SortOrder},
+				{Key: "_id", Value: f.SortOrder}, // Include _id in sorting for consistency
+			})
+	}
+	return options, nil
+}
+
+// newPaginatorNextCursor will return the base64 encoded next cursor which works
+// with our paginator.
+func (impl AuditEventStorerImpl) newPaginatorNextCursor(f *AuditEventPaginationListFilter, results []*AuditEvent) (string, error) {
+	// Get the last document's _id as the next cursor
+	lastDatum := results[len(results)-1]
+
+	var nextCursor string
+	switch f.SortField {
+	case "created_at":
+		timestamp := lastDatum.CreatedAt.UnixMilli()
+		nextCursor = fmt.Sprintf("%v|%v", timestamp, lastDatum.ID.Hex())
+	default:
+		return "", fmt.Errorf("unsupported sort field in options for `%v`, only supported field is `created_at`", f.SortField)
+	}
+
+	// Encode to base64 without the `=` symbol that would corrupt when we
+	// use the http url argument.
+	return base64.RawStdEncoding.EncodeToString([]byte(nextCursor)), nil
+}
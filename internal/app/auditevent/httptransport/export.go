@@ -0,0 +1,35 @@
+package httptransport
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) ExportAsCSV(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	f, err := newFilterFromRequest(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	f.PageSize = 250
+
+	// Buffer the export so an error halfway through does not result in a
+	// truncated file being sent with a successful status code.
+	var buf bytes.Buffer
+	if err := h.Controller.ExportAsCSVByFilter(ctx, f, &buf); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-events-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
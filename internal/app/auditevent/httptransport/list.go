@@ -0,0 +1,95 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// newFilterFromRequest function converts the url parameters into the filter
+// used by both the list and the export.
+func newFilterFromRequest(r *http.Request) (*auditevent_s.AuditEventPaginationListFilter, error) {
+	f := &auditevent_s.AuditEventPaginationListFilter{
+		Cursor:    "",
+		PageSize:  25,
+		SortField: "created_at",
+		SortOrder: auditevent_s.OrderDescending,
+	}
+
+	// Here is where you extract url parameters.
+	query := r.URL.Query()
+
+	f.Cursor = query.Get("cursor")
+
+	pageSize := query.Get("page_size")
+	if pageSize != "" {
+		pageSize, _ := strconv.ParseInt(pageSize, 10, 64)
+		if pageSize == 0 || pageSize > 250 {
+			pageSize = 250
+		}
+		f.PageSize = pageSize
+	}
+
+	for key, dst := range map[string]*primitive.ObjectID{
+		"tenant_id":   &f.TenantID,
+		"user_id":     &f.UserID,
+		"resource_id": &f.ResourceID,
+	} {
+		if v := query.Get(key); v != "" {
+			id, err := primitive.ObjectIDFromHex(v)
+			if err != nil {
+				return nil, httperror.NewForBadRequestWithSingleField(key, "invalid value")
+			}
+			*dst = id
+		}
+	}
+
+	for key, dst := range map[string]*time.Time{
+		"created_at_gte": &f.CreatedAtGTE,
+		"created_at_lte": &f.CreatedAtLTE,
+	} {
+		if v := query.Get(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, httperror.NewForBadRequestWithSingleField(key, "invalid value, expected RFC 3339 format")
+			}
+			*dst = t
+		}
+	}
+
+	f.ResourceType = query.Get("resource_type")
+	f.Action = query.Get("action")
+
+	return f, nil
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	f, err := newFilterFromRequest(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	m, err := h.Controller.ListByFilter(ctx, f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalListResponse(m, w)
+}
+
+func MarshalListResponse(res *auditevent_s.AuditEventPaginationListResult, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log/slog"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
@@ -17,18 +18,22 @@ func (impl *ExecutableControllerImpl) ArchiveByID(ctx context.Context, id primit
 	// Lookup the executable in our database, else return a `400 Bad Request` error.
 	ou, err := impl.ExecutableStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if ou == nil {
-		impl.Logger.Warn("executable does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "executable does not exist validation error")
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
+	before := *ou
 
 	ou.Status = executable_s.ExecutableStatusArchived
 
 	if err := impl.ExecutableStorer.UpdateByID(ctx, ou); err != nil {
-		impl.Logger.Error("executable update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "executable update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeExecutable, ou.ID, &before, ou); err != nil {
 		return nil, err
 	}
 	return ou, nil
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/lease"
+	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
+)
+
+const (
+	// The duration an executable being processed is hidden from our other
+	// replicas; the lease is renewed until the processing finishes.
+	processingLease = 2 * time.Minute
+
+	// The interval we look for interrupted executables, ex: the executables
+	// of a replica which crashed or was stopped during a rolling deploy.
+	resumePollInterval = time.Minute
+)
+
+func processingLeaseKey(exec *executable_s.Executable) string {
+	return "executable:" + exec.ID.Hex()
+}
+
+// runInBackgroundForOpenAI function claims the executable for this instance
+// and submits the OpenAI `job` to our background provider and notifies the
+// user once it finishes. If the job is interrupted by a graceful shutdown
+// then the executable is left in the processing state so it will be resumed
+// by `ResumeInterruptedRuns`. Returns false if the executable was not
+// submitted, ex: it is already processed by another instance.
+func (impl *ExecutableControllerImpl) runInBackgroundForOpenAI(
+	ctx context.Context,
+	name string,
+	exec *executable_s.Executable,
+	job func(ctx context.Context, exec *executable_s.Executable) error,
+	successEventType string,
+) bool {
+	// Claim the executable so our other replicas do not process it at the
+	// same time, else they would create duplicate assistants and threads.
+	key := processingLeaseKey(exec)
+	acquired, err := impl.Leaser.Acquire(ctx, key, processingLease)
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "failed claiming executable, will resume later",
+			slog.Any("executable_id", exec.ID),
+			slog.Any("error", err))
+		return false
+	}
+	if !acquired {
+		return false
+	}
+
+	// Re-read the executable now that we hold the lease since our copy may
+	// be stale, ex: another instance finished the run in the meantime.
+	fresh, err := impl.ExecutableStorer.GetByID(ctx, exec.ID)
+	if err != nil || fresh == nil || fresh.Status != executable_s.ExecutableStatusProcessing {
+		if err != nil {
+			impl.Logger.WarnContext(ctx, "failed getting executable, will resume later",
+				slog.Any("executable_id", exec.ID),
+				slog.Any("error", err))
+		}
+		if err := impl.Leaser.Release(context.WithoutCancel(ctx), key); err != nil {
+			impl.Logger.WarnContext(ctx, "failed releasing executable", slog.Any("error", err))
+		}
+		return false
+	}
+	exec = fresh
+
+	err = impl.Background.Go(ctx, name, func(jobCtx context.Context) {
+		leaseCtx, release := lease.KeepAlive(jobCtx, impl.Leaser, impl.Logger, key, processingLease)
+		defer release()
+
+		bgCtx, span := tracing.Start(leaseCtx, name)
+		defer span.End()
+		metrics.IncBackgroundJob(openAIQueue)
+		defer metrics.DecBackgroundJob(openAIQueue)
+
+		if err := job(bgCtx, exec); err != nil {
+			// Interrupted by a graceful shutdown or by losing the lease to
+			// another instance which is now processing the executable.
+			if leaseCtx.Err() != nil {
+				impl.Logger.WarnContext(bgCtx, "openai job interrupted",
+					slog.Any("executable_id", exec.ID),
+					slog.String("run_id", exec.OpenAIRunID),
+					slog.Any("cause", context.Cause(leaseCtx)))
+				return
+			}
+			impl.Logger.ErrorContext(bgCtx, "failed submitting to openai", slog.Any("error", err))
+			span.RecordError(err)
+			ex := impl.markExecutableAsFailed(bgCtx, exec)
+			impl.sendAnswerReadyEmail(bgCtx, ex, false)
+			impl.publishWebhookEvent(bgCtx, ex, webhook_s.EventTypeExecutableFailed)
+			return
+		}
+		impl.sendAnswerReadyEmail(bgCtx, exec, true)
+		impl.publishWebhookEvent(bgCtx, exec, successEventType)
+	})
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "openai job not started, will resume later",
+			slog.Any("executable_id", exec.ID),
+			slog.Any("error", err))
+		if err := impl.Leaser.Release(context.WithoutCancel(ctx), key); err != nil {
+			impl.Logger.WarnContext(ctx, "failed releasing executable", slog.Any("error", err))
+		}
+		return false
+	}
+	return true
+}
+
+// RunResumeWorker function resumes the interrupted executables until the
+// context is cancelled.
+func (impl *ExecutableControllerImpl) RunResumeWorker(ctx context.Context) {
+	ticker := time.NewTicker(resumePollInterval)
+	defer ticker.Stop()
+	for {
+		if err := impl.ResumeInterruptedRuns(ctx); err != nil && ctx.Err() == nil {
+			impl.Logger.ErrorContext(ctx, "failed resuming executables", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResumeInterruptedRuns function restarts the OpenAI processing of every
+// executable which is still processing but not claimed by any of our
+// instances, ex: the instance was stopped. Executables with a checkpointed
+// run will continue polling the run, the others will be resubmitted to
+// OpenAI.
+func (impl *ExecutableControllerImpl) ResumeInterruptedRuns(ctx context.Context) error {
+	f := &executable_s.ExecutablePaginationListFilter{
+		PageSize:  100,
+		SortField: "created_at",
+		SortOrder: executable_s.OrderAscending,
+		Status:    executable_s.ExecutableStatusProcessing,
+	}
+	for {
+		res, err := impl.ExecutableStorer.ListByFilter(ctx, f)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "failed listing interrupted executables",
+				slog.Any("error", err))
+			return err
+		}
+		for _, exec := range res.Results {
+			// Give the instance which just started processing the
+			// executable the time to claim it.
+			if time.Since(exec.ModifiedAt) < processingLease {
+				continue
+			}
+			impl.resumeInterruptedRun(ctx, exec)
+		}
+		if !res.HasNextPage {
+			return nil
+		}
+		f.Cursor = res.NextCursor
+	}
+}
+
+func (impl *ExecutableControllerImpl) resumeInterruptedRun(ctx context.Context, exec *executable_s.Executable) {
+	successEventType := webhook_s.EventTypeExecutableCompleted
+	if findPendingMessage(exec) != nil {
+		successEventType = webhook_s.EventTypeExecutableQuestionAnswered
+	}
+
+	if impl.runInBackgroundForOpenAI(ctx, "executable.resume.openai", exec, impl.resumeInterruptedJob, successEventType) {
+		impl.Logger.InfoContext(ctx, "resumed interrupted executable",
+			slog.Any("executable_id", exec.ID),
+			slog.String("run_id", exec.OpenAIRunID))
+	}
+}
+
+// resumeInterruptedJob function continues the processing where it was
+// interrupted based on the executable read after claiming it, ex: the run
+// may have been checkpointed since the executable was listed.
+func (impl *ExecutableControllerImpl) resumeInterruptedJob(ctx context.Context, exec *executable_s.Executable) error {
+	switch {
+	case exec.OpenAIRunID != "":
+		return impl.resumeOpenAIRun(ctx, exec)
+	case findPendingMessage(exec) != nil:
+		return impl.processQuestionSubmissionInBackgroundForOpenAI(ctx, exec)
+	default:
+		return impl.createExecutableInBackgroundForOpenAI(ctx, exec)
+	}
+}
+
+// resumeOpenAIRun function continues waiting on the checkpointed run.
+func (impl *ExecutableControllerImpl) resumeOpenAIRun(ctx context.Context, exec *executable_s.Executable) error {
+	// Lock this executable until OpenAI finishes executing.
+	impl.Kmutex.Lockf("openai_executable_%s", exec.ID.Hex())
+	defer impl.Kmutex.Unlockf("openai_executable_%s", exec.ID.Hex())
+
+	return impl.awaitOpenAIRun(ctx, exec)
+}
@@ -7,16 +7,21 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/bartmika/databoutique-backend/internal/adapter/lease"
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
 	uploadfile_ds "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	webhook_c "github.com/bartmika/databoutique-backend/internal/app/webhook/controller"
 	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
 	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 	"github.com/bartmika/databoutique-backend/internal/provider/password"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
 )
@@ -32,6 +37,8 @@ type ExecutableController interface {
 	ArchiveByID(ctx context.Context, id primitive.ObjectID) (*executable_s.Executable, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
 	QuestionSubmissionOperation(ctx context.Context, requestData *QuestionSubmissionOperationRequestIDO) (*executable_s.Executable, error)
+	ResumeInterruptedRuns(ctx context.Context) error
+	RunResumeWorker(ctx context.Context)
 }
 
 type ExecutableControllerImpl struct {
@@ -41,6 +48,8 @@ type ExecutableControllerImpl struct {
 	S3                    s3_storage.S3Storager
 	Password              password.Provider
 	Kmutex                kmutex.Provider
+	Background            background.Provider
+	Leaser                lease.Leaser
 	DbClient              *mongo.Client
 	TenantStorer          tenant_s.TenantStorer
 	UserStorer            user_s.UserStorer
@@ -49,6 +58,8 @@ type ExecutableControllerImpl struct {
 	ProgramStorer         program_s.ProgramStorer
 	ExecutableStorer      executable_s.ExecutableStorer
 	TemplatedEmailer      templatedemailer.TemplatedEmailer
+	Webhook               webhook_c.WebhookController
+	AuditEvent            auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -58,6 +69,8 @@ func NewController(
 	s3 s3_storage.S3Storager,
 	passwordp password.Provider,
 	kmux kmutex.Provider,
+	bg background.Provider,
+	leaser lease.Leaser,
 	temailer templatedemailer.TemplatedEmailer,
 	client *mongo.Client,
 	t_storer tenant_s.TenantStorer,
@@ -66,6 +79,8 @@ func NewController(
 	uploadfile_storer uploadfile_ds.UploadFileStorer,
 	program_s program_s.ProgramStorer,
 	executable_s executable_s.ExecutableStorer,
+	webhook webhook_c.WebhookController,
+	auditevent auditevent_c.AuditEventController,
 ) ExecutableController {
 	s := &ExecutableControllerImpl{
 		Config:                appCfg,
@@ -74,6 +89,8 @@ func NewController(
 		S3:                    s3,
 		Password:              passwordp,
 		Kmutex:                kmux,
+		Background:            bg,
+		Leaser:                leaser,
 		TemplatedEmailer:      temailer,
 		DbClient:              client,
 		TenantStorer:          t_storer,
@@ -82,8 +99,15 @@ func NewController(
 		UploadFileStorer:      uploadfile_storer,
 		ProgramStorer:         program_s,
 		ExecutableStorer:      executable_s,
+		Webhook:               webhook,
+		AuditEvent:            auditevent,
 	}
 	s.Logger.Debug("executable controller initialization started...")
+
+	// Report the number of executables by status whenever our metrics are
+	// scraped.
+	metrics.SetExecutableStatusCounter(s.countByStatus)
+
 	s.Logger.Debug("executable controller initialized")
 	return s
 }
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
@@ -10,9 +9,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
@@ -70,7 +71,7 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 	//
 
 	if err := impl.validateCreateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -78,7 +79,7 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 	// case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 	// 	break
 	// default:
-	// 	impl.Logger.Error("you do not have permission to create a client")
+	// 	impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	// }
 
@@ -88,7 +89,7 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -103,25 +104,23 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 
 		u, err := impl.UserStorer.GetByID(sessCtx, requestData.UserID)
 		if err != nil {
-			impl.Logger.Error("failed getting user",
+			impl.Logger.ErrorContext(ctx, "failed getting user",
 				slog.Any("error", err))
 			return nil, err
 		}
 		if u == nil {
-			err := fmt.Errorf("user does not exist for id: %v", requestData.UserID.Hex())
-			impl.Logger.Error("user does not exist", slog.Any("error", err))
-			return nil, err
+			impl.Logger.WarnContext(ctx, "user does not exist", slog.String("user_id", requestData.UserID.Hex()))
+			return nil, httperror.NewForBadRequestWithSingleField("user_id", "does not exist")
 		}
 		p, err := impl.ProgramStorer.GetByID(sessCtx, requestData.ProgramID)
 		if err != nil {
-			impl.Logger.Error("failed getting program",
+			impl.Logger.ErrorContext(ctx, "failed getting program",
 				slog.Any("error", err))
 			return nil, err
 		}
 		if p == nil {
-			err := fmt.Errorf("program does not exist for id: %v", requestData.ProgramID.Hex())
-			impl.Logger.Error("program does not exist", slog.Any("error", err))
-			return nil, err
+			impl.Logger.WarnContext(ctx, "program does not exist", slog.String("program_id", requestData.ProgramID.Hex()))
+			return nil, httperror.NewForBadRequestWithSingleField("program_id", "does not exist")
 		}
 
 		// Handle the two cases, either the customer provides the files or we
@@ -130,7 +129,7 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 		if p.BusinessFunction == program_s.ProgramBusinessFunctionCustomerDocumentReview {
 			uploadFolders, err = impl.UploadDirectoryStorer.ListByIDs(sessCtx, requestData.UploadDirectoryIDs)
 			if err != nil {
-				impl.Logger.Error("failed getting folders",
+				impl.Logger.ErrorContext(ctx, "failed getting folders",
 					slog.Any("upload_directory_ids", requestData.UploadDirectoryIDs),
 					slog.Any("error", err))
 				return nil, err
@@ -147,7 +146,7 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 			uploadFolderIDs := p.GetUploadDirectoryIDs()
 			uploadFolders, err = impl.UploadDirectoryStorer.ListByIDs(sessCtx, uploadFolderIDs)
 			if err != nil {
-				impl.Logger.Error("failed getting folders",
+				impl.Logger.ErrorContext(ctx, "failed getting folders",
 					slog.Any("upload_directory_ids", requestData.UploadDirectoryIDs),
 					slog.Any("error", err))
 				return nil, err
@@ -201,7 +200,7 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 				}
 				dirfiles, err := impl.UploadFileStorer.ListByUploadDirectoryID(ctx, folder.ID)
 				if err != nil {
-					impl.Logger.Error("failed getting files within folder",
+					impl.Logger.ErrorContext(ctx, "failed getting files within folder",
 						slog.Any("upload_directory_id", folder.ID),
 						slog.Any("error", err))
 					return nil, err
@@ -232,11 +231,15 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 
 		// Save to our database.
 		if err := impl.ExecutableStorer.Create(sessCtx, exec); err != nil {
-			impl.Logger.Error("database create error",
+			impl.Logger.ErrorContext(ctx, "database create error",
 				slog.Any("error", err))
 			return nil, err
 		}
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeExecutable, exec.ID, nil, exec); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -247,7 +250,7 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -260,12 +263,10 @@ func (impl *ExecutableControllerImpl) Create(ctx context.Context, requestData *E
 	////
 
 	// Submit the following into the background of this web-application.
-	// This function will run independently of this function call.
-	go func(ex *executable_s.Executable) {
-		if err := impl.createExecutableInBackgroundForOpenAI(ex); err != nil {
-			impl.Logger.Error("failed submitting to openai", slog.Any("error", err))
-		}
-	}(exec)
+	// This function will run independently of this function call. Please
+	// note we detach from the request cancellation but keep its values so
+	// the background logs and spans belong to this API call.
+	impl.runInBackgroundForOpenAI(ctx, "executable.create.openai", exec, impl.createExecutableInBackgroundForOpenAI, webhook_s.EventTypeExecutableCompleted)
 
 	return exec, nil
 }
@@ -5,11 +5,12 @@ import (
 	"errors"
 	"log/slog"
 
-	"github.com/sashabaranov/go-openai"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 )
 
 func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
@@ -31,7 +32,7 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 	// case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 	// 	break
 	// default:
-	// 	impl.Logger.Error("you do not have permission to create a client")
+	// 	impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	// }
 
@@ -41,7 +42,7 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -49,7 +50,7 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 	// Define a transaction function with a series of operations
 	transactionFunc := func(sessCtx mongo.SessionContext) (interface{}, error) {
-		impl.Logger.Debug("begging to delete executable...",
+		impl.Logger.DebugContext(ctx, "begging to delete executable...",
 			slog.String("executable_id", id.Hex()))
 
 		////
@@ -58,11 +59,11 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 		exec, err := impl.ExecutableStorer.GetByID(sessCtx, id)
 		if err != nil {
-			impl.Logger.Error("database get by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 			return nil, err
 		}
 		if exec == nil {
-			impl.Logger.Error("database returns nothing from get by id")
+			impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 			return nil, err
 		}
 
@@ -74,7 +75,7 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, exec.TenantID)
 		if err != nil {
-			impl.Logger.Error("failed getting openai credentials",
+			impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
@@ -83,48 +84,48 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("openai initializing...")
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
-		impl.Logger.Debug("openai initialized")
+		impl.Logger.DebugContext(ctx, "openai initializing...")
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+		impl.Logger.DebugContext(ctx, "openai initialized")
 
 		// --- Assistant --- //
 
 		// Only delete the assistant from OpenAI if the program this executable
 		// is based on is customer document review.
 		if exec.ProgramBusinessFunction == program_s.ProgramBusinessFunctionCustomerDocumentReview {
-			impl.Logger.Debug("beginning to delete assistant from openai...",
+			impl.Logger.DebugContext(ctx, "beginning to delete assistant from openai...",
 				slog.Any("executable_id", exec.ID))
 
 			if _, err := client.DeleteAssistant(sessCtx, exec.OpenAIAssistantID); err != nil {
-				impl.Logger.Error("failed deleting assistant from openai",
+				impl.Logger.ErrorContext(ctx, "failed deleting assistant from openai",
 					slog.String("assistant_id", exec.OpenAIAssistantID),
 					slog.String("executable_id", id.Hex()),
 					slog.Any("error", err))
 				return nil, err
 			}
 
-			impl.Logger.Debug("delete assistant from openai",
+			impl.Logger.DebugContext(ctx, "delete assistant from openai",
 				slog.String("assistant_id", id.Hex()),
 				slog.String("executable_id", exec.ID.Hex()))
 		} else {
-			impl.Logger.Debug("skipped deleting assistant from openai",
+			impl.Logger.DebugContext(ctx, "skipped deleting assistant from openai",
 				slog.String("executable_id", exec.ID.Hex()))
 		}
 
 		// --- Threads --- //
 
-		impl.Logger.Debug("beginning to delete thread(s) from openai...",
+		impl.Logger.DebugContext(ctx, "beginning to delete thread(s) from openai...",
 			slog.String("thread_id", exec.OpenAIAssistantThreadID))
 
 		if _, err := client.DeleteThread(sessCtx, exec.OpenAIAssistantThreadID); err != nil {
-			impl.Logger.Error("failed deleting thread from openai",
+			impl.Logger.ErrorContext(ctx, "failed deleting thread from openai",
 				slog.String("executable_id", exec.ID.Hex()),
 				slog.String("thread_id", exec.OpenAIAssistantThreadID),
 				slog.Any("error", err))
 			return nil, err
 		}
 
-		impl.Logger.Debug("delete thread from openai",
+		impl.Logger.DebugContext(ctx, "delete thread from openai",
 			slog.String("executable_id", exec.ID.Hex()),
 			slog.String("thread_id", exec.OpenAIAssistantThreadID))
 
@@ -133,11 +134,14 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 		////
 
 		if err := impl.ExecutableStorer.DeleteByID(sessCtx, id); err != nil {
-			impl.Logger.Error("database delete by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeExecutable, exec.ID, exec, nil); err != nil {
 			return nil, err
 		}
 
-		impl.Logger.Debug("delete executable",
+		impl.Logger.DebugContext(ctx, "delete executable",
 			slog.String("executable_id", exec.ID.Hex()))
 
 		return nil, nil
@@ -145,7 +149,7 @@ func (impl *ExecutableControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 	// Start a transaction
 	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return err
 	}
@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 func (impl *ExecutableControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*executable_s.Executable, error) {
@@ -18,10 +19,13 @@ func (impl *ExecutableControllerImpl) GetByID(ctx context.Context, id primitive.
 	// Retrieve from our database the record for the specific id.
 	m, err := impl.ExecutableStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("failed getting executable",
+		impl.Logger.ErrorContext(ctx, "failed getting executable",
 			slog.String("id", id.Hex()),
 			slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
 	return m, err
 }
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+)
+
+// openAIQueue is the name of the background job queue used for submitting
+// our executables to OpenAI.
+const openAIQueue = "executable_openai"
+
+// countByStatus function returns the number of executables keyed by the
+// human readable status for our metrics.
+func (impl *ExecutableControllerImpl) countByStatus(ctx context.Context) (map[string]int64, error) {
+	counts, err := impl.ExecutableStorer.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int64, len(counts))
+	for status, count := range counts {
+		res[statusLabel(status)] += count
+	}
+	return res, nil
+}
+
+func statusLabel(status int8) string {
+	switch status {
+	case executable_s.ExecutableStatusActive:
+		return "active"
+	case executable_s.ExecutableStatusProcessing:
+		return "processing"
+	case executable_s.ExecutableStatusArchived:
+		return "archived"
+	case executable_s.ExecutableStatusFailed:
+		return "failed"
+	default:
+		return strconv.Itoa(int(status))
+	}
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+)
+
+// markExecutableAsFailed function marks the executable and its pending
+// messages as failed so the user knows to resubmit their question. Please
+// note we reload the executable because the failed transaction was rolled
+// back but our copy of the executable was modified.
+func (impl *ExecutableControllerImpl) markExecutableAsFailed(ctx context.Context, exec *executable_s.Executable) *executable_s.Executable {
+	ex, err := impl.ExecutableStorer.GetByID(ctx, exec.ID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed getting executable",
+			slog.Any("executable_id", exec.ID),
+			slog.Any("error", err))
+		return exec
+	}
+	if ex == nil {
+		return exec
+	}
+	err = impl.updateExecutableWithRetry(ctx, ex, func(ex *executable_s.Executable) {
+		for _, message := range ex.Messages {
+			if message.Status == executable_s.ExecutableStatusProcessing {
+				message.Status = executable_s.ExecutableStatusFailed
+			}
+		}
+		ex.Status = executable_s.ExecutableStatusFailed
+		ex.ModifiedAt = time.Now()
+	})
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed updating executable",
+			slog.Any("executable_id", exec.ID),
+			slog.Any("error", err))
+	}
+	return ex
+}
+
+// publishWebhookEvent function notifies the webhooks of the tenant which
+// subscribed to the event type.
+func (impl *ExecutableControllerImpl) publishWebhookEvent(ctx context.Context, exec *executable_s.Executable, eventType string) {
+	if err := impl.Webhook.Publish(ctx, exec.TenantID, eventType, exec); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed publishing webhook event",
+			slog.Any("executable_id", exec.ID),
+			slog.String("event_type", eventType),
+			slog.Any("error", err))
+	}
+}
+
+// sendAnswerReadyEmail function emails the user of the executable that
+// OpenAI finished processing their question, unless the user turned off
+// these emails.
+func (impl *ExecutableControllerImpl) sendAnswerReadyEmail(ctx context.Context, exec *executable_s.Executable, isSuccessful bool) {
+	u, err := impl.UserStorer.GetByID(ctx, exec.UserID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed getting user",
+			slog.Any("user_id", exec.UserID),
+			slog.Any("error", err))
+		return
+	}
+	if u == nil || u.IsAnswerReadyEmailDisabled {
+		return
+	}
+
+	// Send the most recent question which was asked.
+	question := exec.Question
+	for _, message := range exec.Messages {
+		if !message.FromExecutable {
+			question = message.Content
+		}
+	}
+
+	if err := impl.TemplatedEmailer.SendExecutableAnswerReadyEmail(u.Email, u.FirstName, exec.ProgramName, question, exec.ID.Hex(), isSuccessful); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed sending answer ready email",
+			slog.Any("executable_id", exec.ID),
+			slog.Any("error", err))
+	}
+}
@@ -10,13 +10,14 @@ import (
 
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 	"github.com/sashabaranov/go-openai"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec *executable_s.Executable) error {
-	ctx := context.Background()
+func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(ctx context.Context, exec *executable_s.Executable) error {
 
 	// Lock this executable until OpenAI finishes executing.
 	impl.Kmutex.Lockf("openai_executable_%s", exec.ID.Hex())
@@ -28,7 +29,7 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -43,20 +44,20 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 
 		p, err := impl.ProgramStorer.GetByID(sessCtx, exec.ProgramID)
 		if err != nil {
-			impl.Logger.Error("failed getting program",
+			impl.Logger.ErrorContext(ctx, "failed getting program",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if p == nil {
 			err := fmt.Errorf("program does not exist for id: %v", exec.ProgramID.Hex())
-			impl.Logger.Error("program does not exist", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "program does not exist", slog.Any("error", err))
 			return nil, err
 		}
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, exec.TenantID)
 		if err != nil {
-			impl.Logger.Error("failed getting openai credentials",
+			impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
@@ -65,9 +66,9 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("openai initializing...")
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
-		impl.Logger.Debug("openai initialized")
+		impl.Logger.DebugContext(ctx, "openai initializing...")
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+		impl.Logger.DebugContext(ctx, "openai initialized")
 
 		// Get all the files which we will pre-train the LLM.
 		fileIDs := exec.GetOpenAIFileIDs()
@@ -79,11 +80,11 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 		// --- CASE 1 --- //
 
 		if p.BusinessFunction == program_s.ProgramBusinessFunctionCustomerDocumentReview {
-			impl.Logger.Debug("beginning to create assistant...",
+			impl.Logger.DebugContext(ctx, "beginning to create assistant...",
 				slog.Any("executable_id", exec.ID))
 
 			aname := fmt.Sprintf("program_%s_executable_%s", exec.ProgramID.Hex(), exec.ID.Hex())
-			assistant, err := client.CreateAssistant(context.Background(), openai.AssistantRequest{
+			assistant, err := client.CreateAssistant(ctx, openai.AssistantRequest{
 				Name:         &aname,
 				Model:        p.Model,
 				Instructions: &p.Instructions,
@@ -91,7 +92,7 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 				FileIDs:      fileIDs,
 			})
 			if err != nil {
-				impl.Logger.Error("failed creating assistant",
+				impl.Logger.ErrorContext(ctx, "failed creating assistant",
 					slog.Any("executable_id", exec.ID),
 					slog.Any("name", assistant.Name),
 					slog.Any("model", assistant.Model),
@@ -102,7 +103,7 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 				return nil, err
 			}
 			if isStructEmpty(assistant) {
-				impl.Logger.Error("no openai assistant returned",
+				impl.Logger.ErrorContext(ctx, "no openai assistant returned",
 					slog.Any("executable_id", exec.ID),
 					slog.Any("assistant", assistant))
 				return "", errors.New("no openai file returned")
@@ -110,18 +111,18 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 
 			exec.OpenAIAssistantID = assistant.ID
 
-			impl.Logger.Debug("finished creating assistant",
+			impl.Logger.DebugContext(ctx, "finished creating assistant",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("assistant_id", assistant.ID))
 		}
 
 		if p.BusinessFunction == program_s.ProgramBusinessFunctionAdmintorDocumentReview {
-			impl.Logger.Debug("reusing existing assistant...",
+			impl.Logger.DebugContext(ctx, "reusing existing assistant...",
 				slog.Any("executable_id", exec.ID))
 
 			exec.OpenAIAssistantID = p.OpenAIAssistantID
 
-			impl.Logger.Debug("finished reusing assistant",
+			impl.Logger.DebugContext(ctx, "finished reusing assistant",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("assistant_id", p.OpenAIAssistantID))
 		}
@@ -133,13 +134,13 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 		// Create a thread in OpenAI.
 		thread, err := client.CreateThread(sessCtx, openai.ThreadRequest{})
 		if err != nil {
-			impl.Logger.Error("failed creating assistant thread",
+			impl.Logger.ErrorContext(ctx, "failed creating assistant thread",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if isStructEmpty(thread) {
-			impl.Logger.Error("no openai assistant thread returned",
+			impl.Logger.ErrorContext(ctx, "no openai assistant thread returned",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("assistant_thread", thread))
 			return "", errors.New("no openai assistant thread returned")
@@ -147,7 +148,7 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 
 		exec.OpenAIAssistantThreadID = thread.ID
 
-		impl.Logger.Debug("create openai thread",
+		impl.Logger.DebugContext(ctx, "create openai thread",
 			slog.String("thread_id", thread.ID),
 			slog.Any("executable_id", exec.ID))
 
@@ -164,88 +165,45 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 				Content: exec.Question,
 			})
 		if err != nil {
-			impl.Logger.Error("failed created message from openai",
+			impl.Logger.ErrorContext(ctx, "failed created message from openai",
 				slog.String("thread_id", thread.ID),
 				slog.Any("error", err))
 			return nil, err
 		}
-		impl.Logger.Debug("submitted create message to openai")
+		impl.Logger.DebugContext(ctx, "submitted create message to openai")
 
 		// --- Run message creation --- //
 
-		run, err := client.CreateRun(context.Background(), exec.OpenAIAssistantThreadID, openai.RunRequest{
+		run, err := client.CreateRun(ctx, exec.OpenAIAssistantThreadID, openai.RunRequest{
 			AssistantID: exec.OpenAIAssistantID,
 		})
 		if err != nil {
-			impl.Logger.Error("failed executing run from openai",
+			impl.Logger.ErrorContext(ctx, "failed executing run from openai",
 				slog.Any("error", err))
 			return nil, err
 		}
-		impl.Logger.Debug("openai running message processing...")
-
-		// --- Poll in foreground for completion by openai --- //
-
-		// Continue to loop through the following code and polling openai every 25
-		// seconds to see if the `CreateMessage` request has been executed for our
-		// particular assistant.
-		for run.Status != openai.RunStatusCompleted {
-			time.Sleep(25 * time.Second) // Sleep for 25 seconds.
-
-			// retrieve the status of the run
-			run, err = client.RetrieveRun(ctx, exec.OpenAIAssistantThreadID, run.ID)
-			if err != nil {
-				impl.Logger.Error("failed retrieving run from openai",
-					slog.Any("error", err))
-				return nil, err
-			}
-		}
-		impl.Logger.Debug("openai finished running for message processing")
-
-		// --- Get message list --- //
-
-		// The following code will fetch the latest messages for the particular
-		// `thread_id` and return all the messages so far. Then extract most
-		// recent message and save it into our system.
-		impl.Logger.Debug("fetching recent messages from openai...")
-
-		msgs, err := client.ListMessage(context.Background(), exec.OpenAIAssistantThreadID, nil, nil, nil, nil)
-		if err != nil {
-			impl.Logger.Error("failed listing message",
-				slog.Any("error", err))
-			return nil, err
-		}
-		impl.Logger.Debug("received recent messages from openai")
-		msg := msgs.Messages[0]
-
-		// Create the message.
-		message := &executable_s.Message{}
-		message.ID = primitive.NewObjectID()
-		message.OpenAIMessageID = msg.ID
-		message.Content = msg.Content[0].Text.Value
-		message.CreatedAt = time.Now()
-		message.Status = executable_s.ExecutableStatusActive
-		message.FromExecutable = true
-
-		// Save the message to the executable.
-		exec.Messages = append(exec.Messages, message)
+		impl.Logger.DebugContext(ctx, "openai running message processing...")
 
 		////
-		//// Update database record.
+		//// Checkpoint the run.
 		////
 
-		// Set the status that this executive is active.
-		exec.Status = executable_s.ExecutableStatusActive
-
-		// Update the executable.
-		if err := impl.ExecutableStorer.UpdateByID(sessCtx, exec); err != nil {
-			impl.Logger.Error("failed updating executable",
+		// Save the run ID before we start polling so that if this process is
+		// stopped (ex: graceful shutdown) the run can be resumed after restart.
+		assistantID, threadID := exec.OpenAIAssistantID, exec.OpenAIAssistantThreadID
+		err = impl.updateExecutableWithRetry(sessCtx, exec, func(ex *executable_s.Executable) {
+			ex.OpenAIAssistantID = assistantID
+			ex.OpenAIAssistantThreadID = threadID
+			ex.OpenAIRunID = run.ID
+			ex.Status = executable_s.ExecutableStatusProcessing
+		})
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "failed checkpointing executable",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
 		}
 
-		impl.Logger.Debug("updated executable with latest message")
-
 		////
 		//// Exit our transaction successfully.
 		////
@@ -255,12 +213,23 @@ func (impl *ExecutableControllerImpl) createExecutableInBackgroundForOpenAI(exec
 
 	// Start a transaction
 	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return err
 	}
 
-	return nil
+	// Wait outside of the transaction for OpenAI to finish the run.
+	return impl.awaitOpenAIRun(ctx, exec)
+}
+
+// isRunStatusTerminated function returns true if the run will never complete.
+func isRunStatusTerminated(status openai.RunStatus) bool {
+	switch status {
+	case openai.RunStatusFailed, openai.RunStatusCancelling, openai.RunStatusExpired, "cancelled": // The library does not define the `cancelled` status.
+		return true
+	default:
+		return false
+	}
 }
 
 func isStructEmpty(s interface{}) bool {
@@ -269,8 +238,7 @@ func isStructEmpty(s interface{}) bool {
 	return reflect.DeepEqual(val.Interface(), zeroVal.Interface())
 }
 
-func (impl *ExecutableControllerImpl) processQuestionSubmissionInBackgroundForOpenAI(exec *executable_s.Executable) error {
-	ctx := context.Background()
+func (impl *ExecutableControllerImpl) processQuestionSubmissionInBackgroundForOpenAI(ctx context.Context, exec *executable_s.Executable) error {
 
 	// Lock this executable until OpenAI finishes executing.
 	impl.Kmutex.Lockf("openai_executable_%s", exec.ID.Hex())
@@ -282,7 +250,7 @@ func (impl *ExecutableControllerImpl) processQuestionSubmissionInBackgroundForOp
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -297,20 +265,20 @@ func (impl *ExecutableControllerImpl) processQuestionSubmissionInBackgroundForOp
 
 		p, err := impl.ProgramStorer.GetByID(sessCtx, exec.ProgramID)
 		if err != nil {
-			impl.Logger.Error("failed getting program",
+			impl.Logger.ErrorContext(ctx, "failed getting program",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if p == nil {
 			err := fmt.Errorf("program does not exist for id: %v", exec.ProgramID.Hex())
-			impl.Logger.Error("program does not exist", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "program does not exist", slog.Any("error", err))
 			return nil, err
 		}
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, exec.TenantID)
 		if err != nil {
-			impl.Logger.Error("failed getting openai credentials",
+			impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
@@ -319,27 +287,21 @@ func (impl *ExecutableControllerImpl) processQuestionSubmissionInBackgroundForOp
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("openai initializing...")
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
-		impl.Logger.Debug("openai initialized")
+		impl.Logger.DebugContext(ctx, "openai initializing...")
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+		impl.Logger.DebugContext(ctx, "openai initialized")
 
 		////
 		//// Create assistant message.
 		////
 		// --- Find the pending question --- //
 
-		var pendingMessage *executable_s.Message
-		for _, message := range exec.Messages {
-			if message.Status == executable_s.ExecutableStatusProcessing {
-				pendingMessage = message
-				break
-			}
-		}
+		pendingMessage := findPendingMessage(exec)
 
 		// Defensive code.
 		if pendingMessage == nil {
 			err := fmt.Errorf("could not find pending message in executable ID: %v", exec.ID.Hex())
-			impl.Logger.Error("no pending messages",
+			impl.Logger.ErrorContext(ctx, "no pending messages",
 				slog.Any("error", err))
 			return nil, err
 		}
@@ -353,96 +315,212 @@ func (impl *ExecutableControllerImpl) processQuestionSubmissionInBackgroundForOp
 				Content: pendingMessage.Content,
 			})
 		if err != nil {
-			impl.Logger.Error("failed created message from openai",
+			impl.Logger.ErrorContext(ctx, "failed created message from openai",
 				slog.String("thread_id", exec.OpenAIAssistantThreadID),
 				slog.Any("error", err))
 			return nil, err
 		}
-		impl.Logger.Debug("submitted create message to openai")
+		impl.Logger.DebugContext(ctx, "submitted create message to openai")
 
 		// --- Run message creation --- //
 
-		run, err := client.CreateRun(context.Background(), exec.OpenAIAssistantThreadID, openai.RunRequest{
+		run, err := client.CreateRun(ctx, exec.OpenAIAssistantThreadID, openai.RunRequest{
 			AssistantID: exec.OpenAIAssistantID,
 		})
 		if err != nil {
-			impl.Logger.Error("failed executing run from openai",
+			impl.Logger.ErrorContext(ctx, "failed executing run from openai",
 				slog.Any("error", err))
 			return nil, err
 		}
-		impl.Logger.Debug("openai running message processing...")
-
-		// --- Poll in foreground for completion by openai --- //
-
-		// Continue to loop through the following code and polling openai every 25
-		// seconds to see if the `CreateMessage` request has been executed for our
-		// particular assistant.
-		for run.Status != openai.RunStatusCompleted {
-			time.Sleep(25 * time.Second) // Sleep for 25 seconds.
-
-			// retrieve the status of the run
-			run, err = client.RetrieveRun(ctx, exec.OpenAIAssistantThreadID, run.ID)
-			if err != nil {
-				impl.Logger.Error("failed retrieving run from openai",
-					slog.Any("error", err))
-				return nil, err
-			}
-		}
-		impl.Logger.Debug("openai finished running for message processing")
-
-		// --- Get message list --- //
+		impl.Logger.DebugContext(ctx, "openai running message processing...")
 
-		// The following code will fetch the latest messages for the particular
-		// `thread_id` and return all the messages so far. Then extract most
-		// recent message and save it into our system.
-		impl.Logger.Debug("fetching recent messages from openai...")
+		////
+		//// Checkpoint the run.
+		////
 
-		msgs, err := client.ListMessage(context.Background(), exec.OpenAIAssistantThreadID, nil, nil, nil, nil)
+		// Save the run ID before we start polling so that if this process is
+		// stopped (ex: graceful shutdown) the run can be resumed after restart.
+		err = impl.updateExecutableWithRetry(sessCtx, exec, func(ex *executable_s.Executable) {
+			ex.OpenAIRunID = run.ID
+			ex.Status = executable_s.ExecutableStatusProcessing
+		})
 		if err != nil {
-			impl.Logger.Error("failed listing message",
+			impl.Logger.ErrorContext(ctx, "failed checkpointing executable",
+				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
 			return nil, err
 		}
-		impl.Logger.Debug("received recent messages from openai")
-		msg := msgs.Messages[0]
-
-		// Populate the pending message contents from OpenAI.
-		pendingMessage.OpenAIMessageID = msg.ID
-		pendingMessage.Content = msg.Content[0].Text.Value
-		pendingMessage.CreatedAt = time.Now()
-		pendingMessage.Status = executable_s.ExecutableStatusActive
-		pendingMessage.FromExecutable = true
 
 		////
-		//// Update database record.
+		//// Exit our transaction successfully.
 		////
 
-		// Set the status that this executive is active.
-		exec.Status = executable_s.ExecutableStatusActive
+		return exec, nil
+	}
+
+	// Start a transaction
+	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
+		impl.Logger.ErrorContext(ctx, "session failed error",
+			slog.Any("error", err))
+		return err
+	}
 
-		// Update the executable.
-		if err := impl.ExecutableStorer.UpdateByID(sessCtx, exec); err != nil {
-			impl.Logger.Error("failed updating executable",
+	// Wait outside of the transaction for OpenAI to finish the run.
+	return impl.awaitOpenAIRun(ctx, exec)
+}
+
+// openAIRunPollInterval is how long to wait between polling OpenAI for the
+// status of a run.
+const openAIRunPollInterval = 25 * time.Second
+
+// awaitOpenAIRun function polls OpenAI until the checkpointed run of the
+// executable finishes and then saves the answer. If the context is cancelled
+// (ex: graceful shutdown) the function returns the context error and leaves
+// the checkpoint in place so the run can be resumed later.
+func (impl *ExecutableControllerImpl) awaitOpenAIRun(ctx context.Context, exec *executable_s.Executable) error {
+	creds, err := impl.TenantStorer.GetOpenAICredentialsByID(ctx, exec.TenantID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
+			slog.Any("executable_id", exec.ID),
+			slog.Any("error", err))
+		return err
+	}
+	if creds == nil {
+		return errors.New("no openai credentials returned")
+	}
+	client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+
+	// --- Poll in background for completion by openai --- //
+
+	// Continue to loop through the following code and polling openai every 25
+	// seconds to see if the `CreateMessage` request has been executed for our
+	// particular assistant.
+	var run openai.Run
+	for {
+		run, err = client.RetrieveRun(ctx, exec.OpenAIAssistantThreadID, exec.OpenAIRunID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			impl.Logger.ErrorContext(ctx, "failed retrieving run from openai",
+				slog.Any("error", err))
+			return err
+		}
+		if run.Status == openai.RunStatusCompleted {
+			break
+		}
+		if isRunStatusTerminated(run.Status) {
+			metrics.ObserveOpenAIRun(string(run.Status), time.Since(time.Unix(run.CreatedAt, 0)))
+			err := fmt.Errorf("openai run ended with status: %v", run.Status)
+			impl.Logger.ErrorContext(ctx, "openai run did not complete",
 				slog.Any("executable_id", exec.ID),
 				slog.Any("error", err))
-			return nil, err
+			return err
 		}
 
-		impl.Logger.Debug("updated executable with latest message")
+		select {
+		case <-ctx.Done():
+			impl.Logger.InfoContext(ctx, "stopped polling openai run, will resume after restart",
+				slog.Any("executable_id", exec.ID),
+				slog.String("run_id", exec.OpenAIRunID))
+			return ctx.Err()
+		case <-time.After(openAIRunPollInterval):
+		}
+	}
+	metrics.ObserveOpenAIRun(string(run.Status), time.Since(time.Unix(run.CreatedAt, 0)))
+	impl.Logger.DebugContext(ctx, "openai finished running for message processing")
 
-		////
-		//// Exit our transaction successfully.
-		////
+	// --- Get message list --- //
 
-		return exec, nil
+	// The following code will fetch the latest messages for the particular
+	// `thread_id` and return all the messages so far. Then extract most
+	// recent message and save it into our system.
+	impl.Logger.DebugContext(ctx, "fetching recent messages from openai...")
+
+	msgs, err := client.ListMessage(ctx, exec.OpenAIAssistantThreadID, nil, nil, nil, nil)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed listing message",
+			slog.Any("error", err))
+		return err
 	}
+	impl.Logger.DebugContext(ctx, "received recent messages from openai")
+	msg := msgs.Messages[0]
 
-	// Start a transaction
-	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+	////
+	//// Update database record.
+	////
+
+	err = impl.updateExecutableWithRetry(ctx, exec, func(ex *executable_s.Executable) {
+		// If the run answers a submitted question then populate the pending
+		// message contents from OpenAI, else create the message.
+		message := findPendingMessage(ex)
+		if message == nil {
+			message = &executable_s.Message{}
+			message.ID = primitive.NewObjectID()
+			ex.Messages = append(ex.Messages, message)
+		}
+		message.OpenAIMessageID = msg.ID
+		message.Content = msg.Content[0].Text.Value
+		message.CreatedAt = time.Now()
+		message.Status = executable_s.ExecutableStatusActive
+		message.FromExecutable = true
+
+		// Set the status that this executive is active and clear our checkpoint.
+		ex.Status = executable_s.ExecutableStatusActive
+		ex.OpenAIRunID = ""
+	})
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed updating executable",
+			slog.Any("executable_id", exec.ID),
 			slog.Any("error", err))
 		return err
 	}
 
+	impl.Logger.DebugContext(ctx, "updated executable with latest message")
 	return nil
 }
+
+// findPendingMessage function returns the submitted question which is still
+// waiting for an answer from OpenAI, if any.
+func findPendingMessage(exec *executable_s.Executable) *executable_s.Message {
+	for _, message := range exec.Messages {
+		if message.Status == executable_s.ExecutableStatusProcessing {
+			return message
+		}
+	}
+	return nil
+}
+
+// The number of times our background processing retries saving its changes
+// to an executable which was updated by somebody else in the meantime.
+const executableUpdateMaxAttempts = 3
+
+// updateExecutableWithRetry function applies the changes of our background
+// processing to the executable. If the executable was updated in the
+// meantime, ex: by the user, then the changes are applied again to the latest
+// version of the executable instead of overwriting the other changes.
+func (impl *ExecutableControllerImpl) updateExecutableWithRetry(ctx context.Context, exec *executable_s.Executable, apply func(ex *executable_s.Executable)) error {
+	ex := exec
+	for attempt := 1; ; attempt++ {
+		apply(ex)
+		err := impl.ExecutableStorer.UpdateByID(ctx, ex)
+		if err == nil {
+			*exec = *ex
+			return nil
+		}
+		if !errors.Is(err, versioning.ErrConflict) || attempt == executableUpdateMaxAttempts {
+			return err
+		}
+
+		impl.Logger.WarnContext(ctx, "executable was modified, retrying update",
+			slog.Any("executable_id", exec.ID),
+			slog.Int("attempt", attempt))
+		ex, err = impl.ExecutableStorer.GetByID(ctx, exec.ID)
+		if err != nil {
+			return err
+		}
+		if ex == nil {
+			return versioning.ErrConflict
+		}
+	}
+}
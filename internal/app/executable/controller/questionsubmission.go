@@ -2,7 +2,6 @@ package controller
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"time"
 
@@ -10,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -45,7 +45,7 @@ func (impl *ExecutableControllerImpl) QuestionSubmissionOperation(ctx context.Co
 	// ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
 
 	if err := impl.validateQuestionSubmissionOperationRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -69,7 +69,7 @@ func (impl *ExecutableControllerImpl) QuestionSubmissionOperation(ctx context.Co
 	// case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 	// 	break
 	// default:
-	// 	impl.Logger.Error("you do not have permission to create a client")
+	// 	impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	// }
 
@@ -79,7 +79,7 @@ func (impl *ExecutableControllerImpl) QuestionSubmissionOperation(ctx context.Co
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -94,14 +94,13 @@ func (impl *ExecutableControllerImpl) QuestionSubmissionOperation(ctx context.Co
 
 		exec, err := impl.ExecutableStorer.GetByID(sessCtx, requestData.ExecutableID)
 		if err != nil {
-			impl.Logger.Error("failed getting user",
+			impl.Logger.ErrorContext(ctx, "failed getting user",
 				slog.Any("error", err))
 			return nil, err
 		}
 		if exec == nil {
-			err := fmt.Errorf("executable does not exist for id: %v", requestData.ExecutableID.Hex())
-			impl.Logger.Error("executable does not exist", slog.Any("error", err))
-			return nil, err
+			impl.Logger.WarnContext(ctx, "executable does not exist", slog.String("executable_id", requestData.ExecutableID.Hex()))
+			return nil, httperror.NewForNotFoundWithSingleField("executable_id", "does not exist")
 		}
 
 		////
@@ -127,10 +126,11 @@ func (impl *ExecutableControllerImpl) QuestionSubmissionOperation(ctx context.Co
 		exec.Messages = append(exec.Messages, msg1)
 		exec.Messages = append(exec.Messages, msg2)
 		exec.Status = executable_s.ExecutableStatusProcessing
+		exec.ModifiedAt = time.Now()
 
 		// Save to our database.
 		if err := impl.ExecutableStorer.UpdateByID(sessCtx, exec); err != nil {
-			impl.Logger.Error("database create error",
+			impl.Logger.ErrorContext(ctx, "database create error",
 				slog.Any("error", err))
 			return nil, err
 		}
@@ -145,7 +145,7 @@ func (impl *ExecutableControllerImpl) QuestionSubmissionOperation(ctx context.Co
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -158,12 +158,10 @@ func (impl *ExecutableControllerImpl) QuestionSubmissionOperation(ctx context.Co
 	////
 
 	// Submit the following into the background of this web-application.
-	// This function will run independently of this function call.
-	go func(ex *executable_s.Executable) {
-		if err := impl.processQuestionSubmissionInBackgroundForOpenAI(ex); err != nil {
-			impl.Logger.Error("failed submitting to openai", slog.Any("error", err))
-		}
-	}(exec)
+	// This function will run independently of this function call. Please
+	// note we detach from the request cancellation but keep its values so
+	// the background logs and spans belong to this API call.
+	impl.runInBackgroundForOpenAI(ctx, "executable.question_submission.openai", exec, impl.processQuestionSubmissionInBackgroundForOpenAI, webhook_s.EventTypeExecutableQuestionAnswered)
 
 	return exec, nil
 }
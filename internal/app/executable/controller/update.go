@@ -9,10 +9,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type ExecutableUpdateRequestIDO struct {
@@ -43,7 +45,7 @@ func (impl *ExecutableControllerImpl) UpdateByID(ctx context.Context, requestDat
 	//
 
 	if err := impl.validateUpdateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -60,7 +62,7 @@ func (impl *ExecutableControllerImpl) UpdateByID(ctx context.Context, requestDat
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -74,7 +76,7 @@ func (impl *ExecutableControllerImpl) UpdateByID(ctx context.Context, requestDat
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -90,13 +92,18 @@ func (impl *ExecutableControllerImpl) UpdateByID(ctx context.Context, requestDat
 		// Lookup the executable in our database, else return a `400 Bad Request` error.
 		hh, err := impl.ExecutableStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if hh == nil {
-			impl.Logger.Warn("executable does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "executable does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, hh.Version); err != nil {
+			return nil, err
+		}
+		before := *hh
 
 		////
 		//// Update primary record.
@@ -114,7 +121,7 @@ func (impl *ExecutableControllerImpl) UpdateByID(ctx context.Context, requestDat
 		// hh.SortNumber = requestData.SortNumber
 
 		if err := impl.ExecutableStorer.UpdateByID(sessCtx, hh); err != nil {
-			impl.Logger.Error("executable update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "executable update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -124,6 +131,10 @@ func (impl *ExecutableControllerImpl) UpdateByID(ctx context.Context, requestDat
 
 		//
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeExecutable, hh.ID, &before, hh); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -134,7 +145,7 @@ func (impl *ExecutableControllerImpl) UpdateByID(ctx context.Context, requestDat
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
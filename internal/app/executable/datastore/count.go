@@ -0,0 +1,36 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CountByStatus function returns the number of executables for every status.
+func (impl ExecutableStorerImpl) CountByStatus(ctx context.Context) (map[int8]int64, error) {
+	pipeline := bson.A{
+		bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+	}
+	cursor, err := impl.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		impl.Logger.Error("database count by status error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Status int8  `bson:"_id"`
+		Count  int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database count by status error", slog.Any("error", err))
+		return nil, err
+	}
+
+	counts := make(map[int8]int64, len(results))
+	for _, r := range results {
+		counts[r.Status] = r.Count
+	}
+	return counts, nil
+}
@@ -17,6 +17,7 @@ const (
 	ExecutableStatusActive     = 1
 	ExecutableStatusProcessing = 2
 	ExecutableStatusArchived   = 3
+	ExecutableStatusFailed     = 4
 )
 
 type Executable struct {
@@ -36,9 +37,11 @@ type Executable struct {
 	ModifiedByUserID        primitive.ObjectID    `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
 	ModifiedByUserName      string                `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedFromIPAddress   string                `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version                 int64                 `bson:"version" json:"version"`
 	Directories             []*UploadFolderOption `bson:"directories" json:"directories,omitempty"`
 	OpenAIAssistantID       string                `bson:"openai_assistant_id" json:"openai_assistant_id"` // https://platform.openai.com/docs/assistants/tools/supported-files
 	OpenAIAssistantThreadID string                `bson:"openai_assistant_thread_id" json:"openai_assistant_thread_id"`
+	OpenAIRunID             string                `bson:"openai_run_id" json:"openai_run_id,omitempty"` // Checkpoint of the in-progress run so it can be resumed after a restart.
 	UserID                  primitive.ObjectID    `bson:"user_id" json:"user_id"`
 	UserName                string                `bson:"user_name" json:"user_name"`
 	UserLexicalName         string                `bson:"user_lexical_name" json:"user_lexical_name"`
@@ -120,6 +123,7 @@ type ExecutableStorer interface {
 	ListByFilter(ctx context.Context, f *ExecutablePaginationListFilter) (*ExecutablePaginationListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *ExecutablePaginationListFilter) ([]*ExecutableAsSelectOption, error)
 	ListByTenantID(ctx context.Context, tid primitive.ObjectID) (*ExecutablePaginationListResult, error)
+	CountByStatus(ctx context.Context) (map[int8]int64, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
 }
 
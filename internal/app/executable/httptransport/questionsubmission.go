@@ -47,7 +47,7 @@ func (h *Handler) QuestionSubmissionOperation(w http.ResponseWriter, r *http.Req
 
 func MarshalQuestionSubmissionOperationResponse(res *executable_s.Executable, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
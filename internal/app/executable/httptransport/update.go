@@ -8,6 +8,7 @@ import (
 	executable_c "github.com/bartmika/databoutique-backend/internal/app/executable/controller"
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func UnmarshalUpdateRequest(ctx context.Context, r *http.Request) (*executable_c.ExecutableUpdateRequestIDO, error) {
@@ -41,12 +42,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(res.Version))
 	MarshalUpdateResponse(res, w)
 }
 
 func MarshalUpdateResponse(res *executable_s.Executable, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
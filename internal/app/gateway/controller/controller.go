@@ -8,25 +8,38 @@ import (
 	"log/slog"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/bartmika/databoutique-backend/internal/adapter/cache/mongodbcache"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
 	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
 	howhear_s "github.com/bartmika/databoutique-backend/internal/app/howhear/datastore"
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
 	"github.com/bartmika/databoutique-backend/internal/provider/jwt"
 	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
+	"github.com/bartmika/databoutique-backend/internal/provider/oidc"
 	"github.com/bartmika/databoutique-backend/internal/provider/password"
+	"github.com/bartmika/databoutique-backend/internal/provider/totp"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
 )
 
 type GatewayController interface {
 	UserRegister(ctx context.Context, req *UserRegisterRequestIDO) (*gateway_s.LoginResponseIDO, error)
+	TenantRegister(ctx context.Context, req *TenantRegisterRequestIDO) (*gateway_s.LoginResponseIDO, error)
+	Verify(ctx context.Context, code string) (*VerifyResponseIDO, error)
+	VerifyResend(ctx context.Context, email string) error
 	Login(ctx context.Context, email, password string) (*gateway_s.LoginResponseIDO, error)
+	LoginTwoFactor(ctx context.Context, req *LoginTwoFactorRequestIDO) (*gateway_s.LoginResponseIDO, error)
+	LoginTwoFactorEnroll(ctx context.Context, challengeToken string) (*TwoFactorEnrollmentResponseIDO, error)
+	OIDCLoginStart(ctx context.Context, req *OIDCLoginStartRequestIDO) (*OIDCLoginStartResponseIDO, error)
+	OIDCLoginCallback(ctx context.Context, req *OIDCLoginCallbackRequestIDO) (*gateway_s.LoginResponseIDO, error)
+	ProfileOIDCLinkStart(ctx context.Context) (*OIDCLoginStartResponseIDO, error)
 	GetUserBySessionID(ctx context.Context, sessionID string) (*user_s.User, error)
+	TouchSession(ctx context.Context, sessionID string) error
 	RefreshToken(ctx context.Context, value string) (*user_s.User, string, time.Time, string, time.Time, error)
 	Logout(ctx context.Context) error
 	ForgotPassword(ctx context.Context, email string) error
@@ -34,6 +47,12 @@ type GatewayController interface {
 	Profile(ctx context.Context) (*user_s.User, error)
 	ProfileUpdate(ctx context.Context, nu *user_s.User) error
 	ProfileChangePassword(ctx context.Context, req *ProfileChangePasswordRequestIDO) error
+	ProfileTwoFactorGenerate(ctx context.Context) (*TwoFactorEnrollmentResponseIDO, error)
+	ProfileTwoFactorVerify(ctx context.Context, req *ProfileTwoFactorVerifyRequestIDO) (*TwoFactorRecoveryCodesResponseIDO, error)
+	ProfileTwoFactorDisable(ctx context.Context, req *ProfileTwoFactorDisableRequestIDO) error
+	ProfileSessionList(ctx context.Context) (*session_s.SessionListResult, error)
+	ProfileSessionRevoke(ctx context.Context, id primitive.ObjectID) error
+	ProfileSessionRevokeAll(ctx context.Context) error
 	ExecutiveVisitsTenant(ctx context.Context, req *ExecutiveVisitsTenantRequest) error
 	Dashboard(ctx context.Context) (*DashboardResponseIDO, error)
 }
@@ -44,12 +63,15 @@ type GatewayControllerImpl struct {
 	UUID                     uuid.Provider
 	JWT                      jwt.Provider
 	Password                 password.Provider
+	TOTP                     totp.Provider
+	OIDC                     oidc.Provider
 	Kmutex                   kmutex.Provider
 	DbClient                 *mongo.Client
 	Cache                    mongodbcache.Cacher
 	TemplatedEmailer         templatedemailer.TemplatedEmailer
 	UserStorer               user_s.UserStorer
 	TenantStorer             tenant_s.TenantStorer
+	SessionStorer            session_s.SessionStorer
 	HowHearAboutUsItemStorer howhear_s.HowHearAboutUsItemStorer
 }
 
@@ -59,12 +81,15 @@ func NewController(
 	uuidp uuid.Provider,
 	jwtp jwt.Provider,
 	passwordp password.Provider,
+	totpp totp.Provider,
+	oidcp oidc.Provider,
 	kmux kmutex.Provider,
 	cache mongodbcache.Cacher,
 	te templatedemailer.TemplatedEmailer,
 	client *mongo.Client,
 	usr_storer user_s.UserStorer,
 	org_storer tenant_s.TenantStorer,
+	sess_storer session_s.SessionStorer,
 	howhear_s howhear_s.HowHearAboutUsItemStorer,
 ) GatewayController {
 	// loggerp.Debug("gateway controller initialization started...") // For debugging purposes only.
@@ -75,11 +100,14 @@ func NewController(
 		JWT:                      jwtp,
 		Kmutex:                   kmux,
 		Password:                 passwordp,
+		TOTP:                     totpp,
+		OIDC:                     oidcp,
 		DbClient:                 client,
 		Cache:                    cache,
 		TemplatedEmailer:         te,
 		UserStorer:               usr_storer,
 		TenantStorer:             org_storer,
+		SessionStorer:            sess_storer,
 		HowHearAboutUsItemStorer: howhear_s,
 	}
 	// s.Logger.Debug("gateway controller initialized")
@@ -98,13 +126,13 @@ func (impl *GatewayControllerImpl) GetUserBySessionID(ctx context.Context, sessi
 		return nil, err
 	}
 	if userBytes == nil {
-		impl.Logger.Warn("record not found")
+		impl.Logger.WarnContext(ctx, "record not found")
 		return nil, errors.New("record not found")
 	}
 	var user user_s.User
 	err = json.Unmarshal(userBytes, &user)
 	if err != nil {
-		impl.Logger.Error("unmarshalling failed", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "unmarshalling failed", slog.Any("err", err))
 		return nil, err
 	}
 	return &user, nil
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+	"github.com/bartmika/databoutique-backend/internal/provider/jwt"
+	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
+	"github.com/bartmika/databoutique-backend/internal/provider/password"
+	"github.com/bartmika/databoutique-backend/internal/provider/totp"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+func newTestController(t *testing.T) *GatewayControllerImpl {
+	t.Helper()
+	conf := fakes.NewConfig()
+	return &GatewayControllerImpl{
+		Config:           conf,
+		Logger:           fakes.NewLogger(),
+		UUID:             uuid.NewProvider(),
+		JWT:              jwt.NewProvider(conf),
+		Password:         password.NewProvider(),
+		TOTP:             totp.NewProvider(),
+		Kmutex:           kmutex.NewProvider(),
+		Cache:            fakes.NewCache(),
+		UserStorer:       fakes.NewUserStorer(),
+		TenantStorer:     fakes.NewTenantStorer(),
+		SessionStorer:    fakes.NewSessionStorer(),
+		TemplatedEmailer: &fakes.TemplatedEmailer{},
+	}
+}
@@ -26,7 +26,7 @@ func (impl *GatewayControllerImpl) getActiveClientsCount(ctx context.Context, te
 	}
 	count, err := impl.UserStorer.CountByFilter(ctx, f)
 	if err != nil {
-		impl.Logger.Error("database count error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database count error", slog.Any("err", err))
 		return count, err
 	}
 	return count, nil
@@ -41,7 +41,7 @@ func (impl *GatewayControllerImpl) getActiveAssociatesCount(ctx context.Context,
 	}
 	count, err := impl.UserStorer.CountByFilter(ctx, f)
 	if err != nil {
-		impl.Logger.Error("database count error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database count error", slog.Any("err", err))
 		return count, err
 	}
 	return count, nil
@@ -63,12 +63,12 @@ func (impl *GatewayControllerImpl) Dashboard(ctx context.Context) (*DashboardRes
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	u, err := impl.UserStorer.GetByID(ctx, userID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if u == nil {
-		impl.Logger.Warn("user does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
 
 	////
@@ -77,22 +77,22 @@ func (impl *GatewayControllerImpl) Dashboard(ctx context.Context) (*DashboardRes
 
 	clientsCount, err := impl.getActiveClientsCount(ctx, tenantID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	associatesCount, err := impl.getActiveAssociatesCount(ctx, tenantID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	jobsCount, err := impl.getActiveJobsCount(ctx, tenantID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	tasksCount, err := impl.getActiveTasksCount(ctx, tenantID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"strings"
+	"time"
 
 	"log/slog"
 
@@ -16,21 +17,25 @@ func (impl *GatewayControllerImpl) ForgotPassword(ctx context.Context, email str
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	u, err := impl.UserStorer.GetByEmail(ctx, email)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return err
 	}
 	if u == nil {
-		impl.Logger.Warn("user does not exist validation error", slog.String("email", email))
+		impl.Logger.WarnContext(ctx, "user does not exist validation error", slog.String("email", email))
 		return httperror.NewForBadRequestWithSingleField("email", "does not exist")
 	}
 
-	// Generate unique token and save it to the user record.
-	u.EmailVerificationCode = impl.UUID.NewUUID()
+	// Generate unique token and save it to the user record. The token is
+	// separate from the email verification code so a signup link cannot be
+	// used to reset the password.
+	u.PasswordResetCode = impl.UUID.NewUUID()
+	u.PasswordResetExpiry = time.Now().Add(passwordResetExpiry)
+	u.ModifiedAt = time.Now()
 	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
-		impl.Logger.Warn("user update by id failed", slog.Any("error", err))
+		impl.Logger.WarnContext(ctx, "user update by id failed", slog.Any("error", err))
 		return err
 	}
 
 	// Send password reset email.
-	return impl.TemplatedEmailer.SendForgotPasswordEmail(email, u.EmailVerificationCode, u.FirstName)
+	return impl.TemplatedEmailer.SendForgotPasswordEmail(email, u.PasswordResetCode, u.FirstName)
 }
@@ -18,14 +18,14 @@ func (impl *GatewayControllerImpl) initializeAccounts(ctx context.Context) error
 		return err
 	}
 	if initialStore == nil {
-		impl.Logger.Debug("initializing accounts for first-time use...")
+		impl.Logger.DebugContext(ctx, "initializing accounts for first-time use...")
 
 		// Use the user's provided time zone or default to UTC.
 		location, _ := time.LoadLocation("UTC")
 
 		//TODO: Implement saving into encrypted field for openai key.
 
-		impl.Logger.Debug("initializing primary tenant")
+		impl.Logger.DebugContext(ctx, "initializing primary tenant")
 		tenant := &tenant_s.Tenant{
 			ID:           impl.Config.InitialAccount.AdminTenantID,
 			Name:         impl.Config.InitialAccount.AdminTenantName,
@@ -40,11 +40,11 @@ func (impl *GatewayControllerImpl) initializeAccounts(ctx context.Context) error
 			return err
 		}
 
-		impl.Logger.Debug("initializing primary executive administrator")
+		impl.Logger.DebugContext(ctx, "initializing primary executive administrator")
 
 		passwordHash, err := impl.Password.GenerateHashFromPassword(impl.Config.InitialAccount.AdminPassword)
 		if err != nil {
-			impl.Logger.Error("hashing error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "hashing error", slog.Any("error", err))
 			return err
 		}
 
@@ -68,10 +68,10 @@ func (impl *GatewayControllerImpl) initializeAccounts(ctx context.Context) error
 		}
 		err = impl.UserStorer.Create(ctx, admin)
 		if err != nil {
-			impl.Logger.Error("database create error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
 			return err
 		}
-		impl.Logger.Debug("executive user created.",
+		impl.Logger.DebugContext(ctx, "executive user created.",
 			slog.Any("_id", admin.ID),
 			slog.String("name", admin.Name),
 			slog.String("email", admin.Email))
@@ -80,7 +80,7 @@ func (impl *GatewayControllerImpl) initializeAccounts(ctx context.Context) error
 	// // FOR DEBUGGING PURPOSES ONLY.
 	// creds, err := impl.TenantStorer.GetOpenAICredentialsByID(ctx, impl.Config.InitialAccount.AdminTenantID)
 	// if err != nil {
-	// 	impl.Logger.Error("failed getting openai credentials",
+	// 	impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 	// 		slog.Any("error", err))
 	// 	return err
 	// }
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 
 	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -20,30 +21,55 @@ func (impl *GatewayControllerImpl) Login(ctx context.Context, email, password st
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	u, err := impl.UserStorer.GetByEmail(ctx, email)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if u == nil {
-		impl.Logger.Warn("user does not exist validation error")
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
 		return nil, httperror.NewForBadRequestWithSingleField("email", "does not exist")
 	}
 
 	// Verify the inputted password and hashed password match.
 	passwordMatch, _ := impl.Password.ComparePasswordAndHash(password, u.PasswordHash)
 	if passwordMatch == false {
-		impl.Logger.Warn("password check validation error")
+		impl.Logger.WarnContext(ctx, "password check validation error")
 		return nil, httperror.NewForBadRequestWithSingleField("password", "password do not match with record")
 	}
 
-	// // Enforce the verification code of the email.
-	// if u.WasEmailVerified == false {
-	// 	impl.Logger.Warn("email verification validation error", slog.Any("u", u))
-	// 	return nil, httperror.NewForBadRequestWithSingleField("email", "was not verified")
-	// }
+	// Enforce the verification code of the email if the tenant requires it.
+	if u.WasEmailVerified == false {
+		isVerificationRequired, err := impl.isEmailVerificationRequired(ctx, u)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "email verification requirement check error", slog.Any("err", err))
+			return nil, err
+		}
+		if isVerificationRequired {
+			impl.Logger.WarnContext(ctx, "email verification validation error", slog.Any("user_id", u.ID))
+			return nil, httperror.NewForBadRequestWithSingleField("email", "was not verified")
+		}
+	}
+
+	// Enforce two-factor authentication if the user enrolled or if the
+	// tenant requires it for the user's role. When enforced, we do not issue
+	// the tokens until the second step of the login was completed.
+	isOTPRequired, err := impl.isTwoFactorRequired(ctx, u)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "two-factor requirement check error", slog.Any("err", err))
+		return nil, err
+	}
+	if u.OTPEnabled || isOTPRequired {
+		return impl.startTwoFactorChallenge(ctx, u)
+	}
 
+	return impl.loginUser(ctx, u)
+}
+
+// loginUser function will start the session for the authenticated user and
+// generate the access and refresh tokens.
+func (impl *GatewayControllerImpl) loginUser(ctx context.Context, u *user_s.User) (*gateway_s.LoginResponseIDO, error) {
 	uBin, err := json.Marshal(u)
 	if err != nil {
-		impl.Logger.Error("marshalling error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "marshalling error", slog.Any("err", err))
 		return nil, err
 	}
 
@@ -56,14 +82,20 @@ func (impl *GatewayControllerImpl) Login(ctx context.Context, email, password st
 
 	err = impl.Cache.SetWithExpiry(ctx, sessionUUID, uBin, rtExpiry)
 	if err != nil {
-		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "cache set with expiry error", slog.Any("err", err))
 		return nil, err
 	}
 
-	// Generate our JWT token.
-	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, err := impl.JWT.GenerateJWTTokenPair(sessionUUID, atExpiry, rtExpiry)
+	// Generate our JWT token. Every login starts a new refresh token family.
+	tokenFamilyID := impl.UUID.NewUUID()
+	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, refreshTokenID, err := impl.JWT.GenerateJWTTokenPairForFamily(sessionUUID, tokenFamilyID, atExpiry, rtExpiry)
 	if err != nil {
-		impl.Logger.Error("jwt generate pairs error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "jwt generate pairs error", slog.Any("err", err))
+		return nil, err
+	}
+
+	// Keep a record of the device which started this session.
+	if err := impl.createSession(ctx, u, sessionUUID, tokenFamilyID, refreshTokenID, rtExpiry); err != nil {
 		return nil, err
 	}
 
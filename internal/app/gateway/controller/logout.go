@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"log/slog"
 )
@@ -11,8 +12,17 @@ func (impl *GatewayControllerImpl) Logout(ctx context.Context) error {
 	// Extract from our session the following data.
 	sessionID := ctx.Value(constants.SessionID).(string)
 
+	s, err := impl.SessionStorer.GetBySessionUUID(ctx, sessionID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "session get error", slog.Any("err", err))
+		return err
+	}
+	if s != nil {
+		return impl.revokeSession(ctx, s, session_s.SessionRevokedReasonLogout)
+	}
+
 	if err := impl.Cache.Delete(ctx, sessionID); err != nil {
-		impl.Logger.Error("cache delete error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "cache delete error", slog.Any("err", err))
 		return err
 	}
 
@@ -6,37 +6,58 @@ import (
 
 	"log/slog"
 
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
+// The duration the user has to click the link in the forgot password email.
+const passwordResetExpiry = 24 * time.Hour
+
 func (impl *GatewayControllerImpl) PasswordReset(ctx context.Context, code string, password string) error {
+	if code == "" {
+		return httperror.NewForBadRequestWithSingleField("code", "missing value")
+	}
+
+	impl.Kmutex.Lockf("password-reset-code-%s", code)
+	defer impl.Kmutex.Unlockf("password-reset-code-%s", code)
+
 	// Lookup the user in our database, else return a `400 Bad Request` error.
-	u, err := impl.UserStorer.GetByVerificationCode(ctx, code)
+	u, err := impl.UserStorer.GetByPasswordResetCode(ctx, code)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return err
 	}
 	if u == nil {
-		impl.Logger.Warn("user does not exist validation error")
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
 		return httperror.NewForBadRequestWithSingleField("code", "does not exist")
 	}
 
-	//TODO: Handle expiry dates.
+	if time.Now().After(u.PasswordResetExpiry) {
+		impl.Logger.WarnContext(ctx, "password reset code expired", slog.Any("user_id", u.ID))
+		return httperror.NewForBadRequestWithSingleField("code", "expired, please request a new password reset email")
+	}
 
 	passwordHash, err := impl.Password.GenerateHashFromPassword(password)
 	if err != nil {
-		impl.Logger.Error("hashing error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "hashing error", slog.Any("error", err))
 		return err
 	}
 
 	u.PasswordHash = passwordHash
 	u.PasswordHashAlgorithm = impl.Password.AlgorithmName()
-	u.EmailVerificationCode = "" // Remove email active code so it cannot be used agian.
-	u.EmailVerificationExpiry = time.Now()
+	u.PasswordResetCode = "" // Remove the reset code so it cannot be used again.
+	u.PasswordResetExpiry = time.Now()
 	u.ModifiedAt = time.Now()
 
 	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
-		impl.Logger.Error("update error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "update error", slog.Any("err", err))
+		return err
+	}
+
+	// For security purposes, resetting the password will log out the user
+	// from all their devices.
+	if err := impl.revokeAllSessionsByUserID(ctx, u.ID, session_s.SessionRevokedReasonPasswordChange); err != nil {
+		impl.Logger.ErrorContext(ctx, "revoke all sessions error", slog.Any("error", err))
 		return err
 	}
 
@@ -7,6 +7,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
@@ -19,12 +20,12 @@ func (impl *GatewayControllerImpl) Profile(ctx context.Context) (*user_s.User, e
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	u, err := impl.UserStorer.GetByID(ctx, userID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if u == nil {
-		impl.Logger.Warn("user does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
 	return u, nil
 }
@@ -36,21 +37,21 @@ func (impl *GatewayControllerImpl) ProfileUpdate(ctx context.Context, nu *user_s
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	ou, err := impl.UserStorer.GetByID(ctx, userID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return err
 	}
 	if ou == nil {
-		impl.Logger.Warn("user does not exist validation error")
-		return httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
+		return httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
 
 	hh, err := impl.HowHearAboutUsItemStorer.GetByID(ctx, nu.HowDidYouHearAboutUsID)
 	if err != nil {
-		impl.Logger.Error("fetching how hear error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "fetching how hear error", slog.Any("error", err))
 		return err
 	}
 	if hh == nil {
-		impl.Logger.Error("how hear does not exist error", slog.Any("tagID", nu.HowDidYouHearAboutUsID))
+		impl.Logger.ErrorContext(ctx, "how hear does not exist error", slog.Any("tagID", nu.HowDidYouHearAboutUsID))
 		return httperror.NewForBadRequestWithSingleField("tags", nu.HowDidYouHearAboutUsID.Hex()+" how hear does not exist")
 	}
 	ou.HowDidYouHearAboutUsID = hh.ID
@@ -71,6 +72,7 @@ func (impl *GatewayControllerImpl) ProfileUpdate(ctx context.Context, nu *user_s
 	ou.AddressLine1 = nu.AddressLine1
 	ou.AddressLine2 = nu.AddressLine2
 	ou.AgreePromotionsEmail = nu.AgreePromotionsEmail
+	ou.IsAnswerReadyEmailDisabled = nu.IsAnswerReadyEmailDisabled
 	ou.HasShippingAddress = nu.HasShippingAddress
 	ou.ShippingName = nu.ShippingName
 	ou.ShippingPhone = nu.ShippingPhone
@@ -82,7 +84,7 @@ func (impl *GatewayControllerImpl) ProfileUpdate(ctx context.Context, nu *user_s
 	ou.ShippingAddressLine2 = nu.ShippingAddressLine2
 
 	if err := impl.UserStorer.UpdateByID(ctx, ou); err != nil {
-		impl.Logger.Error("user update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
 		return err
 	}
 	return nil
@@ -124,34 +126,41 @@ func (impl *GatewayControllerImpl) ProfileChangePassword(ctx context.Context, re
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	u, err := impl.UserStorer.GetByID(ctx, userID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return err
 	}
 	if u == nil {
-		impl.Logger.Warn("user does not exist validation error")
-		return httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
+		return httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
 
 	if err := ValidateProfileChangePassworRequest(req); err != nil {
-		impl.Logger.Warn("user validation failed", slog.Any("err", err))
+		impl.Logger.WarnContext(ctx, "user validation failed", slog.Any("err", err))
 		return err
 	}
 
 	// Verify the inputted password and hashed password match.
 	if passwordMatch, _ := impl.Password.ComparePasswordAndHash(req.OldPassword, u.PasswordHash); passwordMatch == false {
-		impl.Logger.Warn("password check validation error")
+		impl.Logger.WarnContext(ctx, "password check validation error")
 		return httperror.NewForBadRequestWithSingleField("old_password", "old password do not match with record of existing password")
 	}
 
 	passwordHash, err := impl.Password.GenerateHashFromPassword(req.Password)
 	if err != nil {
-		impl.Logger.Error("hashing error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "hashing error", slog.Any("error", err))
 		return err
 	}
 	u.PasswordHash = passwordHash
 	u.PasswordHashAlgorithm = impl.Password.AlgorithmName()
 	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
-		impl.Logger.Error("user update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
+		return err
+	}
+
+	// For security purposes, changing the password will log out the user
+	// from all their devices.
+	if err := impl.revokeAllSessionsByUserID(ctx, u.ID, session_s.SessionRevokedReasonPasswordChange); err != nil {
+		impl.Logger.ErrorContext(ctx, "revoke all sessions error", slog.Any("error", err))
 		return err
 	}
 	return nil
@@ -3,12 +3,14 @@ package controller
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"net/http"
 	"time"
 
 	"log/slog"
 
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 func (impl *GatewayControllerImpl) RefreshToken(ctx context.Context, value string) (*user_s.User, string, time.Time, string, time.Time, error) {
@@ -16,27 +18,69 @@ func (impl *GatewayControllerImpl) RefreshToken(ctx context.Context, value strin
 	//// Extract the `sessionID` so we can process it.
 	////
 
-	sessionID, err := impl.JWT.ProcessJWTToken(value)
+	sessionID, tokenFamilyID, refreshTokenID, err := impl.JWT.ProcessJWTRefreshToken(value)
 	if err != nil {
-		impl.Logger.Warn("process jwt refresh token does not exist", slog.String("value", value))
-		err := errors.New("jwt refresh token failed")
+		impl.Logger.WarnContext(ctx, "process jwt refresh token does not exist", slog.String("value", value))
+		err := httperror.NewForUnauthorized("jwt refresh token failed")
 		return nil, "", time.Now(), "", time.Now(), err
 	}
 
+	////
+	//// Lookup the session of the token family and detect reuse.
+	////
+
+	var sess *session_s.Session
+	if tokenFamilyID != "" {
+		// Lock the token family so concurrent refreshes with the same token
+		// cannot both succeed.
+		impl.Kmutex.Lockf("token-family-%s", tokenFamilyID)
+		defer impl.Kmutex.Unlockf("token-family-%s", tokenFamilyID)
+
+		sess, err = impl.SessionStorer.GetByTokenFamilyID(ctx, tokenFamilyID)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "session get error", slog.Any("err", err))
+			return nil, "", time.Now(), "", time.Now(), err
+		}
+		if sess == nil || sess.Status == session_s.SessionStatusRevoked {
+			impl.Logger.WarnContext(ctx, "refresh token family revoked or does not exist", slog.String("token_family_id", tokenFamilyID))
+			return nil, "", time.Now(), "", time.Now(), httperror.NewForSingleField(http.StatusUnauthorized, "refresh_token", "session was revoked")
+		}
+
+		// If the refresh token is not the most recent one issued for this
+		// family then it was already rotated and somebody is replaying it.
+		// Since we cannot tell if the legitimate user or an attacker holds
+		// the most recent token, we revoke the entire family.
+		if sess.RefreshTokenID != refreshTokenID {
+			impl.Logger.WarnContext(ctx, "refresh token reuse detected, revoking token family",
+				slog.String("token_family_id", tokenFamilyID),
+				slog.Any("session_id", sess.ID),
+				slog.Any("user_id", sess.UserID))
+			if err := impl.revokeSession(ctx, sess, session_s.SessionRevokedReasonTokenReuse); err != nil {
+				return nil, "", time.Now(), "", time.Now(), err
+			}
+			return nil, "", time.Now(), "", time.Now(), httperror.NewForSingleField(http.StatusUnauthorized, "refresh_token", "session was revoked")
+		}
+		sessionID = sess.SessionUUID
+	} else {
+		// Tokens issued before token families existed will start a new
+		// family now.
+		tokenFamilyID = impl.UUID.NewUUID()
+	}
+
 	////
 	//// Lookup in our in-memory the user record for the `sessionID` or error.
 	////
 
 	uBin, err := impl.Cache.Get(ctx, sessionID)
 	if err != nil {
-		impl.Logger.Error("in-memory set error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "in-memory set error", slog.Any("err", err))
 		return nil, "", time.Now(), "", time.Now(), err
 	}
 
 	var u *user_s.User
 	err = json.Unmarshal(uBin, &u)
 	if err != nil {
-		impl.Logger.Error("unmarshal error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "unmarshal error", slog.Any("err", err))
 		return nil, "", time.Now(), "", time.Now(), err
 	}
 
@@ -53,14 +97,46 @@ func (impl *GatewayControllerImpl) RefreshToken(ctx context.Context, value strin
 
 	err = impl.Cache.SetWithExpiry(ctx, newSessionUUID, uBin, rtExpiry)
 	if err != nil {
-		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "cache set with expiry error", slog.Any("err", err))
 		return nil, "", time.Now(), "", time.Now(), err
 	}
 
 	// Generate our JWT token.
-	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, err := impl.JWT.GenerateJWTTokenPair(newSessionUUID, atExpiry, rtExpiry)
+	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, newRefreshTokenID, err := impl.JWT.GenerateJWTTokenPairForFamily(newSessionUUID, tokenFamilyID, atExpiry, rtExpiry)
 	if err != nil {
-		impl.Logger.Error("jwt generate pairs error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "jwt generate pairs error", slog.Any("err", err))
+		return nil, "", time.Now(), "", time.Now(), err
+	}
+
+	////
+	//// Rotate the session record to the new `sessionID` and end the old one.
+	////
+
+	if sess == nil {
+		sess, err = impl.SessionStorer.GetBySessionUUID(ctx, sessionID)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "session get error", slog.Any("err", err))
+			return nil, "", time.Now(), "", time.Now(), err
+		}
+	}
+	if sess != nil {
+		sess.SessionUUID = newSessionUUID
+		sess.TokenFamilyID = tokenFamilyID
+		sess.RefreshTokenID = newRefreshTokenID
+		sess.LastSeenAt = time.Now()
+		sess.ExpiresAt = time.Now().Add(rtExpiry)
+		if err := impl.SessionStorer.UpdateByID(ctx, sess); err != nil {
+			impl.Logger.ErrorContext(ctx, "session update error", slog.Any("err", err))
+			return nil, "", time.Now(), "", time.Now(), err
+		}
+	} else {
+		// Sessions started before we kept records will get one now.
+		if err := impl.createSession(ctx, u, newSessionUUID, tokenFamilyID, newRefreshTokenID, rtExpiry); err != nil {
+			return nil, "", time.Now(), "", time.Now(), err
+		}
+	}
+	if err := impl.Cache.Delete(ctx, sessionID); err != nil {
+		impl.Logger.ErrorContext(ctx, "cache delete error", slog.Any("err", err))
 		return nil, "", time.Now(), "", time.Now(), err
 	}
 
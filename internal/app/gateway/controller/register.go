@@ -89,7 +89,7 @@ func (impl *GatewayControllerImpl) UserRegister(ctx context.Context, req *UserRe
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -101,45 +101,62 @@ func (impl *GatewayControllerImpl) UserRegister(ctx context.Context, req *UserRe
 		// Lookup the user in our database, else return a `400 Bad Request` error.
 		u, err := impl.UserStorer.GetByEmail(sessCtx, req.Email)
 		if err != nil {
-			impl.Logger.Error("database error",
+			impl.Logger.ErrorContext(ctx, "database error",
 				slog.Any("err", err),
 				slog.String("Email", req.Email))
 			return nil, err
 		}
 		if u != nil {
-			impl.Logger.Warn("user already exists validation error",
+			impl.Logger.WarnContext(ctx, "user already exists validation error",
 				slog.String("Email", req.Email))
 			return nil, httperror.NewForBadRequestWithSingleField("email", "email is not unique")
 		}
 
 		// Create our user.
-		u, err = impl.createUserForRequest(sessCtx, req)
-		if err != nil {
-			return nil, err
-		}
-
-		// // Send our verification email.
-		// if err := impl.TemplatedEmailer.SendMemberVerificationEmail(u.Email, u.EmailVerificationCode, u.FirstName, u.TenantName); err != nil {
-		// 	impl.Logger.Error("failed sending verification email with error from registration",
-		// 		slog.Any("err", err),
-		// 		slog.String("Email", u.Email),
-		// 		slog.Any("UserID", u.ID))
-		// 	// Do not send error message to user nor abort the registration process.
-		// 	// Just simply log an error message and continue.
-		// }
-		return nil, nil
+		return impl.createUserForRequest(sessCtx, req)
 	}
 
 	// Start a transaction
-	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+	res, err := session.WithTransaction(ctx, transactionFunc)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
+	u := res.(*user_s.User)
+
+	// Send our verification email. We do this after the transaction
+	// committed so retries of the transaction do not send duplicate emails.
+	impl.sendRegistrationVerificationEmail(u)
+
+	// If the tenant requires verification then the user must verify before
+	// we issue any tokens.
+	isVerificationRequired, err := impl.isEmailVerificationRequired(ctx, u)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "email verification requirement check error", slog.Any("err", err))
+		return nil, err
+	}
+	if isVerificationRequired {
+		return &gateway_s.LoginResponseIDO{
+			User:                        u,
+			IsEmailVerificationRequired: true,
+		}, nil
+	}
 
 	return impl.Login(ctx, req.Email, req.Password)
 }
 
+func (impl *GatewayControllerImpl) sendRegistrationVerificationEmail(u *user_s.User) {
+	if err := impl.TemplatedEmailer.SendVerificationEmail(u.Email, u.EmailVerificationCode, u.FirstName); err != nil {
+		impl.Logger.Error("failed sending verification email with error from registration",
+			slog.Any("err", err),
+			slog.String("Email", u.Email),
+			slog.Any("UserID", u.ID))
+		// Do not send error message to user nor abort the registration process.
+		// Just simply log an error message and continue.
+	}
+}
+
 func (impl *GatewayControllerImpl) createUserForRequest(sessCtx mongo.SessionContext, req *UserRegisterRequestIDO) (*user_s.User, error) {
 	// Hash the password for security purposes.
 	passwordHash, err := impl.Password.GenerateHashFromPassword(req.Password)
@@ -171,7 +188,7 @@ func (impl *GatewayControllerImpl) createUserForRequest(sessCtx mongo.SessionCon
 	// 	req.City, req.Country, req.AddressLine1, req.AddressLine2, req.PostalCode, req.Region, // Billing
 	// )
 	// if err != nil {
-	// 	impl.Logger.Error("creating customer from payment processor error", slog.Any("error", err))
+	// 	impl.Logger.ErrorContext(ctx, "creating customer from payment processor error", slog.Any("error", err))
 	// 	return nil, err
 	// }
 
@@ -239,7 +256,7 @@ func (impl *GatewayControllerImpl) createUserForRequest(sessCtx mongo.SessionCon
 		ModifiedByUserName:      fmt.Sprintf("%s %s", req.FirstName, req.LastName),
 		WasEmailVerified:        false,
 		EmailVerificationCode:   impl.UUID.NewUUID(),
-		EmailVerificationExpiry: time.Now().Add(72 * time.Hour),
+		EmailVerificationExpiry: time.Now().Add(emailVerificationExpiry),
 		Status:                  user_s.UserStatusActive,
 		// PaymentProcessorName:       b.PaymentProcessorName, // Attach the required payment process
 		// PaymentProcessorCustomerID: *paymentProcessorCustomerID,
@@ -0,0 +1,280 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+var (
+	schemaNameRegexp        = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,62}$`)
+	schemaNameInvalidRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type TenantRegisterRequestIDO struct {
+	// Tenant related.
+	TenantName  string `json:"tenant_name"`
+	SchemaName  string `json:"schema_name"`
+	Description string `json:"description"`
+	Timezone    string `json:"timezone"`
+
+	// Management user related.
+	FirstName            string `json:"first_name"`
+	LastName             string `json:"last_name"`
+	Email                string `json:"email"`
+	EmailRepeated        string `json:"email_repeated"`
+	Password             string `json:"password"`
+	PasswordRepeated     string `json:"password_repeated"`
+	Phone                string `json:"phone,omitempty"`
+	AgreeTOS             bool   `json:"agree_tos,omitempty"`
+	AgreePromotionsEmail bool   `json:"agree_promotions_email,omitempty"`
+}
+
+// TenantRegister function creates a brand-new tenant along with its first
+// management user. The user must verify their email before they can login.
+func (impl *GatewayControllerImpl) TenantRegister(ctx context.Context, req *TenantRegisterRequestIDO) (*gateway_s.LoginResponseIDO, error) {
+	if !impl.Config.AppServer.HasTenantSelfSignup {
+		impl.Logger.WarnContext(ctx, "tenant self-signup is disabled")
+		return nil, httperror.NewForSingleField(http.StatusForbidden, "non_field_error", "registering new tenants is disabled")
+	}
+
+	// Defensive Code: For security purposes we need to remove all whitespaces from the email and lower the characters.
+	req.Email = strings.ToLower(strings.ReplaceAll(req.Email, " ", ""))
+	req.EmailRepeated = strings.ToLower(strings.ReplaceAll(req.EmailRepeated, " ", ""))
+	req.Password = strings.ReplaceAll(req.Password, " ", "")
+	req.PasswordRepeated = strings.ReplaceAll(req.PasswordRepeated, " ", "")
+	req.TenantName = strings.TrimSpace(req.TenantName)
+	req.SchemaName = strings.ToLower(strings.TrimSpace(req.SchemaName))
+	if req.SchemaName == "" {
+		req.SchemaName = strings.Trim(schemaNameInvalidRegexp.ReplaceAllString(strings.ToLower(req.TenantName), "-"), "-")
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+
+	if err := validateTenantRegisterRequest(req); err != nil {
+		return nil, err
+	}
+
+	impl.Kmutex.Lockf("REGISTRATION-WITH-EMAIL-%v", req.Email)
+	defer impl.Kmutex.Unlockf("REGISTRATION-WITH-EMAIL-%v", req.Email)
+	impl.Kmutex.Lockf("TENANT-REGISTRATION-WITH-SCHEMA-NAME-%v", req.SchemaName)
+	defer impl.Kmutex.Unlockf("TENANT-REGISTRATION-WITH-SCHEMA-NAME-%v", req.SchemaName)
+
+	////
+	//// Start the transaction.
+	////
+
+	session, err := impl.DbClient.StartSession()
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "start session error",
+			slog.Any("error", err))
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	// Define a transaction function with a series of operations
+	transactionFunc := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		// Lookup the user in our database, else return a `400 Bad Request` error.
+		u, err := impl.UserStorer.GetByEmail(sessCtx, req.Email)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database error",
+				slog.Any("err", err),
+				slog.String("Email", req.Email))
+			return nil, err
+		}
+		if u != nil {
+			impl.Logger.WarnContext(ctx, "user already exists validation error",
+				slog.String("Email", req.Email))
+			return nil, httperror.NewForBadRequestWithSingleField("email", "email is not unique")
+		}
+
+		t, err := impl.TenantStorer.GetBySchemaName(sessCtx, req.SchemaName)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database error",
+				slog.Any("err", err),
+				slog.String("SchemaName", req.SchemaName))
+			return nil, err
+		}
+		if t != nil {
+			impl.Logger.WarnContext(ctx, "tenant already exists validation error",
+				slog.String("SchemaName", req.SchemaName))
+			return nil, httperror.NewForBadRequestWithSingleField("schema_name", "schema name is not unique")
+		}
+
+		return impl.createTenantAndManagerForRequest(sessCtx, req)
+	}
+
+	// Start a transaction
+	res, err := session.WithTransaction(ctx, transactionFunc)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "session failed error",
+			slog.Any("error", err))
+		return nil, err
+	}
+	u := res.(*user_s.User)
+
+	// Send our verification email. We do this after the transaction
+	// committed so retries of the transaction do not send duplicate emails.
+	impl.sendRegistrationVerificationEmail(u)
+
+	return &gateway_s.LoginResponseIDO{
+		User:                        u,
+		IsEmailVerificationRequired: true,
+	}, nil
+}
+
+func (impl *GatewayControllerImpl) createTenantAndManagerForRequest(sessCtx mongo.SessionContext, req *TenantRegisterRequestIDO) (*user_s.User, error) {
+	// Hash the password for security purposes.
+	passwordHash, err := impl.Password.GenerateHashFromPassword(req.Password)
+	if err != nil {
+		impl.Logger.Error("hashing error", slog.Any("error", err))
+		return nil, err
+	}
+
+	ipAddress, _ := sessCtx.Value(constants.SessionIPAddress).(string)
+	userID := primitive.NewObjectID()
+	userName := fmt.Sprintf("%s %s", req.FirstName, req.LastName)
+
+	//
+	// Create our tenant.
+	//
+
+	t := &tenant_s.Tenant{
+		ID:                          primitive.NewObjectID(),
+		Uuid:                        impl.UUID.NewUUID(),
+		SchemaName:                  req.SchemaName,
+		Name:                        req.TenantName,
+		Description:                 req.Description,
+		Status:                      tenant_s.TenantActiveStatus,
+		Timezone:                    req.Timezone,
+		Email:                       req.Email,
+		Telephone:                   req.Phone,
+		CreatedAt:                   time.Now(),
+		CreatedByUserID:             userID,
+		CreatedByUserName:           userName,
+		CreatedFromIPAddress:        ipAddress,
+		ModifiedAt:                  time.Now(),
+		ModifiedByUserID:            userID,
+		ModifiedByUserName:          userName,
+		ModifiedFromIPAddress:       ipAddress,
+		Comments:                    make([]*tenant_s.TenantComment, 0),
+		IsEmailVerificationRequired: true,
+	}
+	if err := impl.TenantStorer.Create(sessCtx, t); err != nil {
+		impl.Logger.Error("database create error",
+			slog.String("schema_name", t.SchemaName),
+			slog.Any("error", err))
+		return nil, err
+	}
+
+	//
+	// Create our management user. Please do not use the executive role
+	// since executives have access to every tenant.
+	//
+
+	u := &user_s.User{
+		ID:                      userID,
+		TenantID:                t.ID,
+		TenantName:              t.Name,
+		TenantType:              tenant_s.RetailerType,
+		FirstName:               req.FirstName,
+		LastName:                req.LastName,
+		Name:                    userName,
+		LexicalName:             fmt.Sprintf("%s, %s", req.LastName, req.FirstName),
+		Email:                   req.Email,
+		Phone:                   req.Phone,
+		PasswordHash:            passwordHash,
+		PasswordHashAlgorithm:   impl.Password.AlgorithmName(),
+		Role:                    user_s.UserRoleManagement,
+		AgreeTOS:                req.AgreeTOS,
+		TOSVersion:              "January, 2024",
+		TOSAgreedOn:             time.Now(),
+		AgreePromotionsEmail:    req.AgreePromotionsEmail,
+		CreatedAt:               time.Now(),
+		CreatedByUserID:         userID,
+		CreatedByUserName:       userName,
+		CreatedFromIPAddress:    ipAddress,
+		ModifiedAt:              time.Now(),
+		ModifiedByUserID:        userID,
+		ModifiedByUserName:      userName,
+		ModifiedFromIPAddress:   ipAddress,
+		WasEmailVerified:        false,
+		EmailVerificationCode:   impl.UUID.NewUUID(),
+		EmailVerificationExpiry: time.Now().Add(emailVerificationExpiry),
+		Status:                  user_s.UserStatusActive,
+		Timezone:                req.Timezone,
+		JoinedTime:              time.Now(),
+		Coupons:                 make([]*user_s.UserClaimedCoupon, 0),
+	}
+	if err := impl.UserStorer.Create(sessCtx, u); err != nil {
+		impl.Logger.Error("database create error",
+			slog.String("user_email", u.Email),
+			slog.Any("error", err))
+		return nil, err
+	}
+	impl.Logger.Info("Tenant registered.",
+		slog.Any("tenant_id", t.ID),
+		slog.String("tenant_schema_name", t.SchemaName),
+		slog.Any("user_id", u.ID),
+		slog.String("user_email", u.Email))
+
+	return u, nil
+}
+
+func validateTenantRegisterRequest(dirtyData *TenantRegisterRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.TenantName == "" {
+		e["tenant_name"] = "missing value"
+	}
+	if !schemaNameRegexp.MatchString(dirtyData.SchemaName) {
+		e["schema_name"] = "must be 2 to 63 lowercase letters, numbers, dashes or underscores"
+	}
+	if _, err := time.LoadLocation(dirtyData.Timezone); err != nil {
+		e["timezone"] = "invalid value"
+	}
+	if dirtyData.FirstName == "" {
+		e["first_name"] = "missing value"
+	}
+	if dirtyData.LastName == "" {
+		e["last_name"] = "missing value"
+	}
+	if dirtyData.Email == "" {
+		e["email"] = "missing value"
+	}
+	if len(dirtyData.Email) > 255 {
+		e["email"] = "too long"
+	}
+	if dirtyData.Email != dirtyData.EmailRepeated {
+		e["email"] = "does not match email repeated"
+		e["email_repeated"] = "does not match email"
+	}
+	if dirtyData.Password == "" {
+		e["password"] = "missing value"
+	}
+	if dirtyData.PasswordRepeated != dirtyData.Password {
+		e["password"] = "does not match"
+		e["password_repeated"] = "does not match"
+	}
+	if dirtyData.AgreeTOS == false {
+		e["agree_tos"] = "you must agree to the terms before proceeding"
+	}
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+)
+
+func TestCreateTenantAndManagerForRequest(t *testing.T) {
+	impl := newTestController(t)
+	sessCtx := mongo.NewSessionContext(context.Background(), nil)
+
+	u, err := impl.createTenantAndManagerForRequest(sessCtx, &TenantRegisterRequestIDO{
+		TenantName: "Acme",
+		SchemaName: "acme",
+		Timezone:   "UTC",
+		FirstName:  "Alice",
+		LastName:   "Smith",
+		Email:      "alice@acme.com",
+		Password:   "secret",
+		AgreeTOS:   true,
+	})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+
+	// Self-signup must never grant the executive role since executives
+	// have access to every tenant.
+	if u.Role != user_s.UserRoleManagement {
+		t.Fatalf("expected the management role but got %d", u.Role)
+	}
+	tenant, _ := impl.TenantStorer.GetByID(sessCtx, u.TenantID)
+	if tenant == nil || tenant.SchemaName != "acme" {
+		t.Fatalf("expected the user to belong to the new tenant but got %+v", tenant)
+	}
+	if u.WasEmailVerified {
+		t.Fatal("expected the email to require verification")
+	}
+}
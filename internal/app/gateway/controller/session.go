@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// createSession function saves a record of the device which started the
+// session so the user can review and revoke their sessions.
+func (impl *GatewayControllerImpl) createSession(ctx context.Context, u *user_s.User, sessionUUID string, tokenFamilyID string, refreshTokenID string, expiry time.Duration) error {
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	userAgent, _ := ctx.Value(constants.SessionUserAgent).(string)
+
+	s := &session_s.Session{
+		ID:          primitive.NewObjectID(),
+		SessionUUID: sessionUUID,
+		UserID:      u.ID,
+		UserName:    u.Name,
+		TenantID:    u.TenantID,
+		Device:      deviceFromUserAgent(userAgent),
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		Status:      session_s.SessionStatusActive,
+		CreatedAt:   time.Now(),
+		LastSeenAt:  time.Now(),
+		ExpiresAt:   time.Now().Add(expiry),
+
+		TokenFamilyID:  tokenFamilyID,
+		RefreshTokenID: refreshTokenID,
+	}
+	if err := impl.SessionStorer.Create(ctx, s); err != nil {
+		impl.Logger.ErrorContext(ctx, "session create error", slog.Any("err", err))
+		return err
+	}
+	return nil
+}
+
+// revokeSession function ends the session by removing the user from our
+// cache so the access and refresh tokens can no longer be used.
+func (impl *GatewayControllerImpl) revokeSession(ctx context.Context, s *session_s.Session, reason string) error {
+	if err := impl.Cache.Delete(ctx, s.SessionUUID); err != nil {
+		impl.Logger.ErrorContext(ctx, "cache delete error", slog.Any("err", err))
+		return err
+	}
+	s.Status = session_s.SessionStatusRevoked
+	s.RevokedAt = time.Now()
+	s.RevokedReason = reason
+	if err := impl.SessionStorer.UpdateByID(ctx, s); err != nil {
+		impl.Logger.ErrorContext(ctx, "session update error", slog.Any("err", err))
+		return err
+	}
+	return nil
+}
+
+// revokeAllSessionsByUserID function ends every active session of the user.
+func (impl *GatewayControllerImpl) revokeAllSessionsByUserID(ctx context.Context, userID primitive.ObjectID, reason string) error {
+	res, err := impl.SessionStorer.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "session list error", slog.Any("err", err))
+		return err
+	}
+	for _, s := range res.Results {
+		if err := impl.revokeSession(ctx, s, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TouchSession function updates when the session was last seen. Please note
+// the datastore will throttle the writes.
+func (impl *GatewayControllerImpl) TouchSession(ctx context.Context, sessionID string) error {
+	return impl.SessionStorer.UpdateLastSeenBySessionUUID(ctx, sessionID, time.Now())
+}
+
+func (impl *GatewayControllerImpl) ProfileSessionList(ctx context.Context) (*session_s.SessionListResult, error) {
+	// Extract from our session the following data.
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	sessionID := ctx.Value(constants.SessionID).(string)
+
+	res, err := impl.SessionStorer.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "session list error", slog.Any("err", err))
+		return nil, err
+	}
+	for _, s := range res.Results {
+		s.IsCurrent = s.SessionUUID == sessionID
+	}
+	return res, nil
+}
+
+func (impl *GatewayControllerImpl) ProfileSessionRevoke(ctx context.Context, id primitive.ObjectID) error {
+	// Extract from our session the following data.
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+
+	s, err := impl.SessionStorer.GetByID(ctx, id)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "session get by id error", slog.Any("err", err))
+		return err
+	}
+	if s == nil || s.UserID != userID {
+		impl.Logger.WarnContext(ctx, "session does not exist validation error", slog.Any("id", id))
+		return httperror.NewForSingleField(http.StatusNotFound, "id", "does not exist")
+	}
+	if s.Status == session_s.SessionStatusRevoked {
+		return nil
+	}
+	return impl.revokeSession(ctx, s, session_s.SessionRevokedReasonUser)
+}
+
+func (impl *GatewayControllerImpl) ProfileSessionRevokeAll(ctx context.Context) error {
+	// Extract from our session the following data.
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+
+	return impl.revokeAllSessionsByUserID(ctx, userID, session_s.SessionRevokedReasonUser)
+}
+
+// deviceFromUserAgent function returns a human readable description of the
+// device based on the `User-Agent` header.
+func deviceFromUserAgent(ua string) string {
+	ua = strings.ToLower(ua)
+
+	var browser string
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "curl/"):
+		browser = "curl"
+	default:
+		browser = "Unknown browser"
+	}
+
+	var os string
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		os = "iOS"
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "mac os"):
+		os = "macOS"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	default:
+		return browser
+	}
+	return browser + " on " + os
+}
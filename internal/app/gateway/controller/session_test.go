@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+)
+
+// newSessionForUser function logs in the user and returns the new session.
+func newSessionForUser(t *testing.T, impl *GatewayControllerImpl, u *user_s.User) *session_s.Session {
+	t.Helper()
+	ss := impl.SessionStorer.(*fakes.SessionStorer)
+	existing := make(map[primitive.ObjectID]bool, len(ss.Sessions))
+	for id := range ss.Sessions {
+		existing[id] = true
+	}
+	if _, err := impl.loginUser(context.Background(), u); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	for id, s := range ss.Sessions {
+		if !existing[id] {
+			return s
+		}
+	}
+	t.Fatal("expected a session to be created")
+	return nil
+}
+
+func newSessionContext(u *user_s.User, s *session_s.Session) context.Context {
+	ctx := context.WithValue(context.Background(), constants.SessionUserID, u.ID)
+	return context.WithValue(ctx, constants.SessionID, s.SessionUUID)
+}
+
+func isSessionCached(impl *GatewayControllerImpl, s *session_s.Session) bool {
+	b, _ := impl.Cache.Get(context.Background(), s.SessionUUID)
+	return len(b) > 0
+}
+
+func TestProfileSessionList(t *testing.T) {
+	alice := &user_s.User{ID: primitive.NewObjectID(), Name: "Alice"}
+	bob := &user_s.User{ID: primitive.NewObjectID(), Name: "Bob"}
+	impl := newTestController(t)
+	laptop := newSessionForUser(t, impl, alice)
+	phone := newSessionForUser(t, impl, alice)
+	newSessionForUser(t, impl, bob)
+
+	res, err := impl.ProfileSessionList(newSessionContext(alice, laptop))
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if len(res.Results) != 2 {
+		t.Fatalf("expected only the sessions of the user but got %d", len(res.Results))
+	}
+	for _, s := range res.Results {
+		if s.UserID != alice.ID || s.IsCurrent != (s.ID == laptop.ID) {
+			t.Fatalf("unexpected session %+v", s)
+		}
+	}
+	if phone.IsCurrent {
+		t.Fatal("expected only the session of the request to be current")
+	}
+}
+
+func TestProfileSessionRevoke(t *testing.T) {
+	alice := &user_s.User{ID: primitive.NewObjectID(), Name: "Alice"}
+	bob := &user_s.User{ID: primitive.NewObjectID(), Name: "Bob"}
+	impl := newTestController(t)
+	laptop := newSessionForUser(t, impl, alice)
+	phone := newSessionForUser(t, impl, alice)
+	bobs := newSessionForUser(t, impl, bob)
+	ctx := newSessionContext(alice, laptop)
+
+	if err := impl.ProfileSessionRevoke(ctx, phone.ID); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if phone.Status != session_s.SessionStatusRevoked || phone.RevokedReason != session_s.SessionRevokedReasonUser || isSessionCached(impl, phone) {
+		t.Fatalf("expected the session to be revoked but got %+v", phone)
+	}
+	if laptop.Status != session_s.SessionStatusActive || !isSessionCached(impl, laptop) {
+		t.Fatal("expected the other session to stay active")
+	}
+
+	// Revoking a revoked session is a no-op.
+	if err := impl.ProfileSessionRevoke(ctx, phone.ID); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+
+	// Users cannot revoke the sessions of other users, nor learn they exist.
+	if err := impl.ProfileSessionRevoke(ctx, bobs.ID); err == nil {
+		t.Fatal("expected the session of another user to be rejected")
+	}
+	if err := impl.ProfileSessionRevoke(ctx, primitive.NewObjectID()); err == nil {
+		t.Fatal("expected an unknown session to be rejected")
+	}
+	if bobs.Status != session_s.SessionStatusActive || !isSessionCached(impl, bobs) {
+		t.Fatal("expected the session of another user to stay active")
+	}
+}
+
+func TestProfileSessionRevokeAll(t *testing.T) {
+	alice := &user_s.User{ID: primitive.NewObjectID(), Name: "Alice"}
+	bob := &user_s.User{ID: primitive.NewObjectID(), Name: "Bob"}
+	impl := newTestController(t)
+	laptop := newSessionForUser(t, impl, alice)
+	phone := newSessionForUser(t, impl, alice)
+	bobs := newSessionForUser(t, impl, bob)
+
+	if err := impl.ProfileSessionRevokeAll(newSessionContext(alice, laptop)); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	for _, s := range []*session_s.Session{laptop, phone} {
+		if s.Status != session_s.SessionStatusRevoked || isSessionCached(impl, s) {
+			t.Fatalf("expected the session to be revoked but got %+v", s)
+		}
+	}
+	if bobs.Status != session_s.SessionStatusActive || !isSessionCached(impl, bobs) {
+		t.Fatal("expected the session of another user to stay active")
+	}
+}
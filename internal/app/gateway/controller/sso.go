@@ -0,0 +1,414 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/oidc"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+const (
+	// The duration the user has to authenticate with the identity provider.
+	oidcStateExpiry = 10 * time.Minute
+
+	oidcStateCacheKeyPrefix = "oidc_state_"
+)
+
+// oidcState represents the state we save in the cache while the user is
+// authenticating with the identity provider.
+type oidcState struct {
+	TenantID  primitive.ObjectID `json:"tenant_id"`
+	Nonce     string             `json:"nonce"`
+	ExpiresAt time.Time          `json:"expires_at"`
+
+	// The user who started linking their account to their identity from
+	// their profile. If zero then this is a login.
+	LinkUserID primitive.ObjectID `json:"link_user_id,omitempty"`
+}
+
+type OIDCLoginStartRequestIDO struct {
+	// The schema name of the tenant the user belongs to.
+	Tenant string `json:"tenant"`
+}
+
+type OIDCLoginStartResponseIDO struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiryTime       time.Time `json:"expiry_time"`
+}
+
+type OIDCLoginCallbackRequestIDO struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+func oidcConfigForTenant(t *tenant_s.Tenant) *oidc.Config {
+	return &oidc.Config{
+		Issuer:       t.OIDC.Issuer,
+		ClientID:     t.OIDC.ClientID,
+		ClientSecret: t.OIDC.ClientSecret,
+		RedirectURL:  t.OIDC.RedirectURL,
+		Scopes:       t.OIDC.Scopes,
+	}
+}
+
+// OIDCLoginStart function is the first step of the single sign-on which
+// returns the URL of the tenant's identity provider the frontend must
+// redirect the user to.
+func (impl *GatewayControllerImpl) OIDCLoginStart(ctx context.Context, req *OIDCLoginStartRequestIDO) (*OIDCLoginStartResponseIDO, error) {
+	req.Tenant = strings.ToLower(strings.TrimSpace(req.Tenant))
+	if req.Tenant == "" {
+		return nil, httperror.NewForBadRequestWithSingleField("tenant", "missing value")
+	}
+
+	t, err := impl.TenantStorer.GetBySchemaName(ctx, req.Tenant)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if t == nil || t.OIDC == nil || !t.OIDC.IsEnabled {
+		impl.Logger.WarnContext(ctx, "tenant does not support single sign-on", slog.String("tenant", req.Tenant))
+		return nil, httperror.NewForBadRequestWithSingleField("tenant", "single sign-on is not enabled")
+	}
+	return impl.startOIDCLogin(ctx, t, primitive.NilObjectID)
+}
+
+// ProfileOIDCLinkStart function is the first step of linking the
+// authenticated user to their identity in the identity provider of their
+// tenant. The callback is the same as the single sign-on.
+func (impl *GatewayControllerImpl) ProfileOIDCLinkStart(ctx context.Context) (*OIDCLoginStartResponseIDO, error) {
+	// Extract from our session the following data.
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	tid, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+
+	t, err := impl.TenantStorer.GetByID(ctx, tid)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if t == nil || t.OIDC == nil || !t.OIDC.IsEnabled {
+		impl.Logger.WarnContext(ctx, "tenant does not support single sign-on", slog.Any("tenant_id", tid))
+		return nil, httperror.NewForBadRequestWithSingleField("tenant", "single sign-on is not enabled")
+	}
+	return impl.startOIDCLogin(ctx, t, userID)
+}
+
+func (impl *GatewayControllerImpl) startOIDCLogin(ctx context.Context, t *tenant_s.Tenant, linkUserID primitive.ObjectID) (*OIDCLoginStartResponseIDO, error) {
+	state := impl.UUID.NewUUID()
+	st := &oidcState{
+		TenantID:   t.ID,
+		Nonce:      impl.UUID.NewUUID(),
+		ExpiresAt:  time.Now().Add(oidcStateExpiry),
+		LinkUserID: linkUserID,
+	}
+
+	authURL, err := impl.OIDC.AuthCodeURL(ctx, oidcConfigForTenant(t), state, st.Nonce)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "oidc authorization url error",
+			slog.Any("tenant_id", t.ID),
+			slog.Any("err", err))
+		return nil, httperror.NewForSingleField(http.StatusBadGateway, "non_field_error", "identity provider is unavailable")
+	}
+
+	stBin, err := json.Marshal(st)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "marshalling error", slog.Any("err", err))
+		return nil, err
+	}
+	if err := impl.Cache.SetWithExpiry(ctx, oidcStateCacheKeyPrefix+state, stBin, oidcStateExpiry); err != nil {
+		impl.Logger.ErrorContext(ctx, "cache set with expiry error", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &OIDCLoginStartResponseIDO{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiryTime:       st.ExpiresAt,
+	}, nil
+}
+
+// OIDCLoginCallback function is the second step of the single sign-on which
+// exchanges the authorization code from the identity provider for our
+// tokens. Users are provisioned or linked just-in-time.
+func (impl *GatewayControllerImpl) OIDCLoginCallback(ctx context.Context, req *OIDCLoginCallbackRequestIDO) (*gateway_s.LoginResponseIDO, error) {
+	e := make(map[string]string)
+	if req.Code == "" {
+		e["code"] = "missing value"
+	}
+	if req.State == "" {
+		e["state"] = "missing value"
+	}
+	if len(e) != 0 {
+		return nil, httperror.NewForBadRequest(&e)
+	}
+
+	////
+	//// Consume the state so it cannot be replayed.
+	////
+
+	impl.Kmutex.Lockf("oidc-state-%s", req.State)
+	defer impl.Kmutex.Unlockf("oidc-state-%s", req.State)
+
+	stBin, err := impl.Cache.Get(ctx, oidcStateCacheKeyPrefix+req.State)
+	if err != nil || len(stBin) == 0 {
+		impl.Logger.WarnContext(ctx, "oidc state does not exist")
+		return nil, httperror.NewForBadRequestWithSingleField("state", "expired or does not exist")
+	}
+	if err := impl.Cache.Delete(ctx, oidcStateCacheKeyPrefix+req.State); err != nil {
+		impl.Logger.ErrorContext(ctx, "cache delete error", slog.Any("err", err))
+		return nil, err
+	}
+	var st oidcState
+	if err := json.Unmarshal(stBin, &st); err != nil {
+		impl.Logger.ErrorContext(ctx, "unmarshal error", slog.Any("err", err))
+		return nil, err
+	}
+	if time.Now().After(st.ExpiresAt) {
+		return nil, httperror.NewForBadRequestWithSingleField("state", "expired or does not exist")
+	}
+
+	t, err := impl.TenantStorer.GetByID(ctx, st.TenantID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if t == nil || t.OIDC == nil || !t.OIDC.IsEnabled {
+		impl.Logger.WarnContext(ctx, "tenant does not support single sign-on", slog.Any("tenant_id", st.TenantID))
+		return nil, httperror.NewForBadRequestWithSingleField("tenant", "single sign-on is not enabled")
+	}
+
+	////
+	//// Authenticate with the identity provider.
+	////
+
+	claims, err := impl.OIDC.Exchange(ctx, oidcConfigForTenant(t), req.Code, st.Nonce)
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "oidc exchange error",
+			slog.Any("tenant_id", t.ID),
+			slog.Any("err", err))
+		return nil, httperror.NewForSingleField(http.StatusUnauthorized, "non_field_error", "failed authenticating with identity provider")
+	}
+
+	var u *user_s.User
+	if st.LinkUserID.IsZero() {
+		u, err = impl.getOrProvisionUserForOIDCClaims(ctx, t, claims)
+	} else {
+		u, err = impl.linkUserToOIDCClaims(ctx, t, st.LinkUserID, claims)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Enforce our own two-factor authentication as the password login does
+	// since we cannot verify the identity provider enforced its own.
+	isOTPRequired, err := impl.isTwoFactorRequired(ctx, u)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "two-factor requirement check error", slog.Any("err", err))
+		return nil, err
+	}
+	if u.OTPEnabled || isOTPRequired {
+		return impl.startTwoFactorChallenge(ctx, u)
+	}
+
+	return impl.loginUser(ctx, u)
+}
+
+// getOrProvisionUserForOIDCClaims function returns the user who was linked
+// to the identity, else a new user is provisioned. Existing users are never
+// linked by email since the tenant's identity provider is not trusted to
+// prove the ownership of our accounts; they must link their identity from
+// their profile instead.
+func (impl *GatewayControllerImpl) getOrProvisionUserForOIDCClaims(ctx context.Context, t *tenant_s.Tenant, claims *oidc.Claims) (*user_s.User, error) {
+	impl.Kmutex.Lockf("oidc-subject-%s-%s", claims.Issuer, claims.Subject)
+	defer impl.Kmutex.Unlockf("oidc-subject-%s-%s", claims.Issuer, claims.Subject)
+
+	role, hasRoleMapping := oidcRoleForClaims(t.OIDC, claims)
+
+	u, err := impl.UserStorer.GetByOIDCSubject(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+
+	if u == nil {
+		email := strings.ToLower(strings.TrimSpace(claims.Email))
+		if email == "" || !claims.EmailVerified {
+			impl.Logger.WarnContext(ctx, "oidc identity is missing a verified email",
+				slog.Any("tenant_id", t.ID),
+				slog.String("subject", claims.Subject))
+			return nil, httperror.NewForSingleField(http.StatusForbidden, "email", "identity provider did not return a verified email")
+		}
+
+		u, err = impl.UserStorer.GetByEmail(ctx, email)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+			return nil, err
+		}
+		if u != nil {
+			impl.Logger.WarnContext(ctx, "oidc identity email belongs to an unlinked user",
+				slog.Any("tenant_id", t.ID),
+				slog.Any("user_id", u.ID))
+			return nil, httperror.NewForSingleField(http.StatusForbidden, "email", "already belongs to an account, please login with your password and link your identity from your profile")
+		}
+
+		if !t.OIDC.IsJITProvisioningEnabled {
+			impl.Logger.WarnContext(ctx, "oidc provisioning is disabled", slog.Any("tenant_id", t.ID))
+			return nil, httperror.NewForSingleField(http.StatusForbidden, "email", "does not exist")
+		}
+		if role == 0 {
+			impl.Logger.WarnContext(ctx, "oidc identity has no matching role", slog.Any("tenant_id", t.ID))
+			return nil, httperror.NewForSingleField(http.StatusForbidden, "role", "you are not allowed to access this tenant")
+		}
+		return impl.provisionUserForOIDCClaims(ctx, t, claims, email, role)
+	}
+
+	if u.TenantID != t.ID {
+		impl.Logger.WarnContext(ctx, "oidc identity is linked to another tenant",
+			slog.Any("tenant_id", t.ID),
+			slog.Any("user_id", u.ID))
+		return nil, httperror.NewForSingleField(http.StatusForbidden, "tenant", "you are not allowed to access this tenant")
+	}
+	if u.Status != user_s.UserStatusActive {
+		impl.Logger.WarnContext(ctx, "user is not active", slog.Any("user_id", u.ID))
+		return nil, httperror.NewForSingleField(http.StatusForbidden, "email", "account is not active")
+	}
+
+	// The identity provider is the source of truth for the roles so keep
+	// the user's role in sync on every login.
+	if hasRoleMapping && u.Role != role {
+		u.Role = role
+		u.ModifiedAt = time.Now()
+		if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+			impl.Logger.ErrorContext(ctx, "database update error", slog.Any("err", err))
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// linkUserToOIDCClaims function links the user who started the link from
+// their profile to the identity.
+func (impl *GatewayControllerImpl) linkUserToOIDCClaims(ctx context.Context, t *tenant_s.Tenant, userID primitive.ObjectID, claims *oidc.Claims) (*user_s.User, error) {
+	impl.Kmutex.Lockf("oidc-subject-%s-%s", claims.Issuer, claims.Subject)
+	defer impl.Kmutex.Unlockf("oidc-subject-%s-%s", claims.Issuer, claims.Subject)
+
+	linked, err := impl.UserStorer.GetByOIDCSubject(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if linked != nil && linked.ID != userID {
+		impl.Logger.WarnContext(ctx, "oidc identity is linked to another user",
+			slog.Any("user_id", userID),
+			slog.Any("linked_user_id", linked.ID))
+		return nil, httperror.NewForSingleField(http.StatusForbidden, "non_field_error", "identity is already linked to another account")
+	}
+
+	u, err := impl.UserStorer.GetByID(ctx, userID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if u == nil || u.TenantID != t.ID || u.Status != user_s.UserStatusActive {
+		impl.Logger.WarnContext(ctx, "user does not exist or is not active", slog.Any("user_id", userID))
+		return nil, httperror.NewForSingleField(http.StatusForbidden, "non_field_error", "account is not active")
+	}
+	if u.OIDCSubject != "" && (u.OIDCIssuer != claims.Issuer || u.OIDCSubject != claims.Subject) {
+		impl.Logger.WarnContext(ctx, "user is already linked to another oidc identity", slog.Any("user_id", u.ID))
+		return nil, httperror.NewForSingleField(http.StatusForbidden, "non_field_error", "account is already linked to another identity")
+	}
+
+	u.OIDCIssuer = claims.Issuer
+	u.OIDCSubject = claims.Subject
+	if role, hasRoleMapping := oidcRoleForClaims(t.OIDC, claims); hasRoleMapping {
+		u.Role = role
+	}
+	u.ModifiedAt = time.Now()
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "database update error", slog.Any("err", err))
+		return nil, err
+	}
+	impl.Logger.InfoContext(ctx, "User linked to identity provider.",
+		slog.Any("tenant_id", u.TenantID),
+		slog.Any("user_id", u.ID))
+	return u, nil
+}
+
+func (impl *GatewayControllerImpl) provisionUserForOIDCClaims(ctx context.Context, t *tenant_s.Tenant, claims *oidc.Claims, email string, role int8) (*user_s.User, error) {
+	firstName, lastName := claims.GivenName, claims.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(strings.TrimSpace(claims.Name), " ")
+	}
+	if firstName == "" {
+		firstName = email
+	}
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	userID := primitive.NewObjectID()
+	name := strings.TrimSpace(fmt.Sprintf("%s %s", firstName, lastName))
+	u := &user_s.User{
+		ID:                   userID,
+		TenantID:             t.ID,
+		TenantName:           t.Name,
+		FirstName:            firstName,
+		LastName:             lastName,
+		Name:                 name,
+		LexicalName:          fmt.Sprintf("%s, %s", lastName, firstName),
+		Email:                email,
+		Role:                 role,
+		WasEmailVerified:     true,
+		OIDCIssuer:           claims.Issuer,
+		OIDCSubject:          claims.Subject,
+		Status:               user_s.UserStatusActive,
+		CreatedAt:            time.Now(),
+		CreatedByUserID:      userID,
+		CreatedByUserName:    name,
+		CreatedFromIPAddress: ipAddress,
+		ModifiedAt:           time.Now(),
+		ModifiedByUserID:     userID,
+		ModifiedByUserName:   name,
+		JoinedTime:           time.Now(),
+		Timezone:             "UTC",
+		Coupons:              make([]*user_s.UserClaimedCoupon, 0),
+	}
+	if err := impl.UserStorer.Create(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "database create error",
+			slog.String("user_email", u.Email),
+			slog.Any("error", err))
+		return nil, err
+	}
+	impl.Logger.InfoContext(ctx, "User provisioned from identity provider.",
+		slog.Any("tenant_id", u.TenantID),
+		slog.Any("user_id", u.ID),
+		slog.Int("role", int(u.Role)))
+	return u, nil
+}
+
+// oidcRoleForClaims function returns the role of the first mapping which
+// matches the role claim, else the default role. The boolean is true only
+// if a mapping matched.
+func oidcRoleForClaims(cfg *tenant_s.TenantOIDCConfig, claims *oidc.Claims) (int8, bool) {
+	if cfg.RoleClaim != "" {
+		values := claims.Values(cfg.RoleClaim)
+		for _, m := range cfg.RoleMappings {
+			for _, v := range values {
+				if v == m.ClaimValue {
+					return m.Role, true
+				}
+			}
+		}
+	}
+	return cfg.DefaultRole, false
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+	"github.com/bartmika/databoutique-backend/internal/provider/oidc"
+)
+
+type fakeOIDCProvider struct {
+	oidc.Provider
+	claims *oidc.Claims
+}
+
+func (p *fakeOIDCProvider) Exchange(ctx context.Context, cfg *oidc.Config, code, nonce string) (*oidc.Claims, error) {
+	return p.claims, nil
+}
+
+func newOIDCTenant() *tenant_s.Tenant {
+	return &tenant_s.Tenant{
+		ID:   primitive.NewObjectID(),
+		Name: "Acme",
+		OIDC: &tenant_s.TenantOIDCConfig{
+			IsEnabled: true,
+			Issuer:    "https://idp.acme.com",
+			RoleClaim: "groups",
+			RoleMappings: []*tenant_s.TenantOIDCRoleMapping{
+				{ClaimValue: "managers", Role: user_s.UserRoleManagement},
+			},
+			DefaultRole: user_s.UserRoleStaff,
+		},
+	}
+}
+
+func TestGetOrProvisionUserForOIDCClaims(t *testing.T) {
+	ctx := context.Background()
+	tenant := newOIDCTenant()
+	claims := &oidc.Claims{
+		Issuer:        tenant.OIDC.Issuer,
+		Subject:       "alice",
+		Email:         "Alice@Acme.com",
+		EmailVerified: true,
+		Name:          "Alice Smith",
+		Raw:           map[string]interface{}{"groups": []interface{}{"staff", "managers"}},
+	}
+
+	// Unknown users are rejected unless the tenant provisions them.
+	impl := newTestController(t)
+	if _, err := impl.getOrProvisionUserForOIDCClaims(ctx, tenant, claims); err == nil {
+		t.Fatal("expected an unknown user to be rejected")
+	}
+
+	tenant.OIDC.IsJITProvisioningEnabled = true
+	u, err := impl.getOrProvisionUserForOIDCClaims(ctx, tenant, claims)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if u.Email != "alice@acme.com" || u.FirstName != "Alice" || u.LastName != "Smith" || u.TenantID != tenant.ID || u.Role != user_s.UserRoleManagement || u.OIDCSubject != "alice" {
+		t.Fatalf("unexpected provisioned user %+v", u)
+	}
+
+	// The next login returns the same user and syncs the role.
+	claims.Raw = map[string]interface{}{"groups": "managers"}
+	again, err := impl.getOrProvisionUserForOIDCClaims(ctx, tenant, claims)
+	if err != nil || again.ID != u.ID {
+		t.Fatalf("expected the provisioned user but got %+v %v", again, err)
+	}
+
+	// The identity cannot be used to log into another tenant.
+	if _, err := impl.getOrProvisionUserForOIDCClaims(ctx, newOIDCTenant(), claims); err == nil {
+		t.Fatal("expected the identity of another tenant to be rejected")
+	}
+
+	// Unverified emails are neither linked nor provisioned.
+	if _, err := impl.getOrProvisionUserForOIDCClaims(ctx, tenant, &oidc.Claims{Issuer: tenant.OIDC.Issuer, Subject: "mallory", Email: "mallory@acme.com"}); err == nil {
+		t.Fatal("expected an unverified email to be rejected")
+	}
+}
+
+func TestGetOrProvisionUserForOIDCClaimsRejectsExistingUser(t *testing.T) {
+	ctx := context.Background()
+	tenant := newOIDCTenant()
+	tenant.OIDC.IsJITProvisioningEnabled = true
+	bob := &user_s.User{ID: primitive.NewObjectID(), TenantID: tenant.ID, Email: "bob@acme.com", Role: user_s.UserRoleStaff, Status: user_s.UserStatusActive}
+	impl := newTestController(t)
+	impl.UserStorer = fakes.NewUserStorer(bob)
+
+	// A verified email is not enough to take over an existing account.
+	if _, err := impl.getOrProvisionUserForOIDCClaims(ctx, tenant, &oidc.Claims{Issuer: tenant.OIDC.Issuer, Subject: "bob", Email: "bob@acme.com", EmailVerified: true}); err == nil {
+		t.Fatal("expected the existing user to be rejected")
+	}
+	if bob.OIDCSubject != "" {
+		t.Fatal("expected the existing user to stay unlinked")
+	}
+}
+
+func TestLinkUserToOIDCClaims(t *testing.T) {
+	ctx := context.Background()
+	tenant := newOIDCTenant()
+	bob := &user_s.User{ID: primitive.NewObjectID(), TenantID: tenant.ID, Email: "bob@acme.com", Role: user_s.UserRoleStaff, Status: user_s.UserStatusActive}
+	carol := &user_s.User{ID: primitive.NewObjectID(), TenantID: tenant.ID, Email: "carol@acme.com", Role: user_s.UserRoleStaff, Status: user_s.UserStatusActive}
+	eve := &user_s.User{ID: primitive.NewObjectID(), TenantID: primitive.NewObjectID(), Email: "eve@other.com", Role: user_s.UserRoleStaff, Status: user_s.UserStatusActive}
+	impl := newTestController(t)
+	impl.UserStorer = fakes.NewUserStorer(bob, carol, eve)
+	impl.TenantStorer = fakes.NewTenantStorer(tenant)
+
+	// The link is started from the profile and finished by the callback.
+	impl.OIDC = &fakeOIDCProvider{claims: &oidc.Claims{Issuer: tenant.OIDC.Issuer, Subject: "bob"}}
+	st, _ := json.Marshal(&oidcState{TenantID: tenant.ID, Nonce: "nonce", LinkUserID: bob.ID, ExpiresAt: time.Now().Add(time.Minute)})
+	impl.Cache.SetWithExpiry(ctx, oidcStateCacheKeyPrefix+"state", st, time.Minute)
+	if _, err := impl.OIDCLoginCallback(ctx, &OIDCLoginCallbackRequestIDO{Code: "code", State: "state"}); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if bob.OIDCIssuer != tenant.OIDC.Issuer || bob.OIDCSubject != "bob" || bob.Role != user_s.UserRoleStaff {
+		t.Fatalf("expected the user to be linked but got %+v", bob)
+	}
+
+	// The identity cannot be linked to a second account.
+	if _, err := impl.linkUserToOIDCClaims(ctx, tenant, carol.ID, &oidc.Claims{Issuer: tenant.OIDC.Issuer, Subject: "bob"}); err == nil {
+		t.Fatal("expected an identity linked to another user to be rejected")
+	}
+
+	// A linked user cannot be linked to another identity.
+	if _, err := impl.linkUserToOIDCClaims(ctx, tenant, bob.ID, &oidc.Claims{Issuer: tenant.OIDC.Issuer, Subject: "bob2"}); err == nil {
+		t.Fatal("expected a linked user to be rejected")
+	}
+
+	// Users of other tenants are never linked.
+	if _, err := impl.linkUserToOIDCClaims(ctx, tenant, eve.ID, &oidc.Claims{Issuer: tenant.OIDC.Issuer, Subject: "eve"}); err == nil {
+		t.Fatal("expected the user of another tenant to be rejected")
+	}
+	if eve.OIDCSubject != "" {
+		t.Fatal("expected the user of another tenant to stay unlinked")
+	}
+}
+
+func TestOIDCLoginCallbackRequiresTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	tenant := newOIDCTenant()
+	tenant.TwoFactorRequiredRoles = []int8{user_s.UserRoleManagement}
+	manager := &user_s.User{ID: primitive.NewObjectID(), TenantID: tenant.ID, Email: "alice@acme.com", Role: user_s.UserRoleManagement, Status: user_s.UserStatusActive, OIDCIssuer: tenant.OIDC.Issuer, OIDCSubject: "alice"}
+	staff := &user_s.User{ID: primitive.NewObjectID(), TenantID: tenant.ID, Email: "bob@acme.com", Role: user_s.UserRoleStaff, Status: user_s.UserStatusActive, OIDCIssuer: tenant.OIDC.Issuer, OIDCSubject: "bob", OTPEnabled: true}
+
+	impl := newTestController(t)
+	impl.UserStorer = fakes.NewUserStorer(manager, staff)
+	impl.TenantStorer = fakes.NewTenantStorer(tenant)
+
+	for _, u := range []*user_s.User{manager, staff} {
+		st, _ := json.Marshal(&oidcState{TenantID: tenant.ID, Nonce: "nonce", ExpiresAt: time.Now().Add(time.Minute)})
+		impl.Cache.SetWithExpiry(ctx, oidcStateCacheKeyPrefix+"state", st, time.Minute)
+		impl.OIDC = &fakeOIDCProvider{claims: &oidc.Claims{Issuer: u.OIDCIssuer, Subject: u.OIDCSubject}}
+
+		// The tokens are not issued until the second step of the login.
+		res, err := impl.OIDCLoginCallback(ctx, &OIDCLoginCallbackRequestIDO{Code: "code", State: "state"})
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		if !res.IsOTPRequired || res.OTPChallengeToken == "" || res.AccessToken != "" {
+			t.Fatalf("expected a two-factor challenge for %s but got %+v", u.Email, res)
+		}
+	}
+}
@@ -26,7 +26,7 @@ func (impl *GatewayControllerImpl) ExecutiveVisitsTenant(ctx context.Context, re
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	if userRole != user_s.UserRoleExecutive {
-		impl.Logger.Error("not executive error", slog.Int("role", int(userRole)))
+		impl.Logger.ErrorContext(ctx, "not executive error", slog.Int("role", int(userRole)))
 		return errors.New("user is not executive")
 	}
 
@@ -36,14 +36,14 @@ func (impl *GatewayControllerImpl) ExecutiveVisitsTenant(ctx context.Context, re
 
 	uBin, err := impl.Cache.Get(ctx, sessionID)
 	if err != nil {
-		impl.Logger.Error("in-memory set error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "in-memory set error", slog.Any("err", err))
 		return err
 	}
 
 	var u *user_s.User
 	err = json.Unmarshal(uBin, &u)
 	if err != nil {
-		impl.Logger.Error("unmarshal error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "unmarshal error", slog.Any("err", err))
 		return err
 	}
 
@@ -62,7 +62,7 @@ func (impl *GatewayControllerImpl) ExecutiveVisitsTenant(ctx context.Context, re
 
 	// Save the session.
 	if err := impl.Cache.SetWithExpiry(ctx, newSessionUUID, uBin, expiry); err != nil {
-		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "cache set with expiry error", slog.Any("err", err))
 		return err
 	}
 
@@ -0,0 +1,383 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+const (
+	// The duration the user has to complete the second step of the login.
+	otpChallengeExpiry = 5 * time.Minute
+
+	// The number of invalid codes allowed per challenge before the user
+	// must login again with their password.
+	otpChallengeMaxAttempts = 5
+
+	// The number of single-use recovery codes given to the user.
+	otpRecoveryCodeCount = 10
+
+	otpChallengeCacheKeyPrefix = "otp_challenge_"
+)
+
+// otpChallenge represents the state we save in the cache between the first
+// and second step of the login.
+type otpChallenge struct {
+	UserID    primitive.ObjectID `json:"user_id"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Attempts  int                `json:"attempts"`
+}
+
+type TwoFactorEnrollmentResponseIDO struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorRecoveryCodesResponseIDO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type LoginTwoFactorRequestIDO struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+type ProfileTwoFactorVerifyRequestIDO struct {
+	Code string `json:"code"`
+}
+
+type ProfileTwoFactorDisableRequestIDO struct {
+	Password string `json:"password"`
+}
+
+// isTwoFactorRequired function returns true if the tenant of the user
+// enforces two-factor authentication for the user's role.
+func (impl *GatewayControllerImpl) isTwoFactorRequired(ctx context.Context, u *user_s.User) (bool, error) {
+	t, err := impl.TenantStorer.GetByID(ctx, u.TenantID)
+	if err != nil {
+		return false, err
+	}
+	if t == nil {
+		return false, nil
+	}
+	for _, role := range t.TwoFactorRequiredRoles {
+		if role == u.Role {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// startTwoFactorChallenge function saves a short-lived challenge for the
+// user and returns the challenge token instead of the access and refresh
+// tokens.
+func (impl *GatewayControllerImpl) startTwoFactorChallenge(ctx context.Context, u *user_s.User) (*gateway_s.LoginResponseIDO, error) {
+	expiresAt := time.Now().Add(otpChallengeExpiry)
+	token := impl.UUID.NewUUID()
+
+	chBin, err := json.Marshal(&otpChallenge{UserID: u.ID, ExpiresAt: expiresAt})
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "marshalling error", slog.Any("err", err))
+		return nil, err
+	}
+	if err := impl.Cache.SetWithExpiry(ctx, otpChallengeCacheKeyPrefix+token, chBin, otpChallengeExpiry); err != nil {
+		impl.Logger.ErrorContext(ctx, "cache set with expiry error", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &gateway_s.LoginResponseIDO{
+		IsOTPRequired:           true,
+		IsOTPEnrollmentRequired: !u.OTPEnabled,
+		OTPChallengeToken:       token,
+		OTPChallengeExpiryTime:  expiresAt,
+	}, nil
+}
+
+// getTwoFactorChallengeUser function looks up the challenge in the cache and
+// returns the user who started the challenge.
+func (impl *GatewayControllerImpl) getTwoFactorChallengeUser(ctx context.Context, token string) (*otpChallenge, *user_s.User, error) {
+	chBin, err := impl.Cache.Get(ctx, otpChallengeCacheKeyPrefix+token)
+	if err != nil || len(chBin) == 0 {
+		impl.Logger.WarnContext(ctx, "otp challenge does not exist")
+		return nil, nil, httperror.NewForBadRequestWithSingleField("challenge_token", "expired or does not exist")
+	}
+	var ch otpChallenge
+	if err := json.Unmarshal(chBin, &ch); err != nil {
+		impl.Logger.ErrorContext(ctx, "unmarshal error", slog.Any("err", err))
+		return nil, nil, err
+	}
+	if time.Now().After(ch.ExpiresAt) {
+		return nil, nil, httperror.NewForBadRequestWithSingleField("challenge_token", "expired or does not exist")
+	}
+
+	u, err := impl.UserStorer.GetByID(ctx, ch.UserID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, nil, err
+	}
+	if u == nil {
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
+		return nil, nil, httperror.NewForBadRequestWithSingleField("challenge_token", "expired or does not exist")
+	}
+	return &ch, u, nil
+}
+
+// LoginTwoFactorEnroll function generates the TOTP secret for a user who is
+// required to use two-factor authentication but has not enrolled yet.
+func (impl *GatewayControllerImpl) LoginTwoFactorEnroll(ctx context.Context, challengeToken string) (*TwoFactorEnrollmentResponseIDO, error) {
+	_, u, err := impl.getTwoFactorChallengeUser(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if u.OTPEnabled {
+		return nil, httperror.NewForBadRequestWithSingleField("non_field_error", "two-factor authentication already enabled")
+	}
+	return impl.generateTwoFactorSecret(ctx, u)
+}
+
+// LoginTwoFactor function is the second step of the login which exchanges
+// the challenge token and a TOTP or recovery code for our tokens.
+func (impl *GatewayControllerImpl) LoginTwoFactor(ctx context.Context, req *LoginTwoFactorRequestIDO) (*gateway_s.LoginResponseIDO, error) {
+	e := make(map[string]string)
+	if req.ChallengeToken == "" {
+		e["challenge_token"] = "missing value"
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		e["code"] = "missing value"
+	}
+	if len(e) != 0 {
+		return nil, httperror.NewForBadRequest(&e)
+	}
+
+	// Lock this challenge to prevent concurrent guesses from by-passing the
+	// maximum number of attempts.
+	impl.Kmutex.Lockf("otp-challenge-%s", req.ChallengeToken)
+	defer impl.Kmutex.Unlockf("otp-challenge-%s", req.ChallengeToken)
+
+	ch, u, err := impl.getTwoFactorChallengeUser(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	////
+	//// Verify the code.
+	////
+
+	var isValid bool
+	var recoveryCodes []string
+	wasEnrolled := u.OTPEnabled
+	if req.RecoveryCode != "" && wasEnrolled {
+		isValid = impl.useRecoveryCode(u, req.RecoveryCode)
+	} else if u.OTPSecret != "" {
+		isValid = impl.useTOTPCode(u, req.Code)
+	}
+
+	if !isValid {
+		ch.Attempts++
+		if ch.Attempts >= otpChallengeMaxAttempts {
+			impl.Logger.WarnContext(ctx, "otp challenge exceeded maximum attempts", slog.Any("user_id", u.ID))
+			if err := impl.Cache.Delete(ctx, otpChallengeCacheKeyPrefix+req.ChallengeToken); err != nil {
+				impl.Logger.ErrorContext(ctx, "cache delete error", slog.Any("err", err))
+			}
+			return nil, httperror.NewForBadRequestWithSingleField("challenge_token", "too many attempts, please login again")
+		}
+		chBin, err := json.Marshal(ch)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "marshalling error", slog.Any("err", err))
+			return nil, err
+		}
+		if err := impl.Cache.SetWithExpiry(ctx, otpChallengeCacheKeyPrefix+req.ChallengeToken, chBin, time.Until(ch.ExpiresAt)); err != nil {
+			impl.Logger.ErrorContext(ctx, "cache set with expiry error", slog.Any("err", err))
+			return nil, err
+		}
+		return nil, httperror.NewForBadRequestWithSingleField("code", "invalid code")
+	}
+
+	////
+	//// Finish enrollment if this was the user's first code.
+	////
+
+	if !wasEnrolled {
+		recoveryCodes, err = impl.enableTwoFactor(u)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
+		return nil, err
+	}
+
+	// The challenge is single-use.
+	if err := impl.Cache.Delete(ctx, otpChallengeCacheKeyPrefix+req.ChallengeToken); err != nil {
+		impl.Logger.ErrorContext(ctx, "cache delete error", slog.Any("err", err))
+		return nil, err
+	}
+
+	res, err := impl.loginUser(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	res.OTPRecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+// ProfileTwoFactorGenerate function generates a new TOTP secret for the
+// authenticated user. The secret is not active until it has been verified.
+func (impl *GatewayControllerImpl) ProfileTwoFactorGenerate(ctx context.Context) (*TwoFactorEnrollmentResponseIDO, error) {
+	u, err := impl.Profile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u.OTPEnabled {
+		return nil, httperror.NewForBadRequestWithSingleField("non_field_error", "two-factor authentication already enabled")
+	}
+	return impl.generateTwoFactorSecret(ctx, u)
+}
+
+// ProfileTwoFactorVerify function confirms the user successfully setup their
+// authenticator app and enables two-factor authentication for the account.
+func (impl *GatewayControllerImpl) ProfileTwoFactorVerify(ctx context.Context, req *ProfileTwoFactorVerifyRequestIDO) (*TwoFactorRecoveryCodesResponseIDO, error) {
+	if req.Code == "" {
+		return nil, httperror.NewForBadRequestWithSingleField("code", "missing value")
+	}
+
+	u, err := impl.Profile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u.OTPEnabled {
+		return nil, httperror.NewForBadRequestWithSingleField("non_field_error", "two-factor authentication already enabled")
+	}
+	if u.OTPSecret == "" {
+		return nil, httperror.NewForBadRequestWithSingleField("non_field_error", "two-factor authentication was not generated")
+	}
+	if !impl.useTOTPCode(u, req.Code) {
+		return nil, httperror.NewForBadRequestWithSingleField("code", "invalid code")
+	}
+
+	recoveryCodes, err := impl.enableTwoFactor(u)
+	if err != nil {
+		return nil, err
+	}
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &TwoFactorRecoveryCodesResponseIDO{RecoveryCodes: recoveryCodes}, nil
+}
+
+// ProfileTwoFactorDisable function turns off two-factor authentication for
+// the authenticated user unless their tenant enforces it for their role.
+func (impl *GatewayControllerImpl) ProfileTwoFactorDisable(ctx context.Context, req *ProfileTwoFactorDisableRequestIDO) error {
+	if req.Password == "" {
+		return httperror.NewForBadRequestWithSingleField("password", "missing value")
+	}
+
+	u, err := impl.Profile(ctx)
+	if err != nil {
+		return err
+	}
+	if passwordMatch, _ := impl.Password.ComparePasswordAndHash(req.Password, u.PasswordHash); passwordMatch == false {
+		impl.Logger.WarnContext(ctx, "password check validation error")
+		return httperror.NewForBadRequestWithSingleField("password", "password do not match with record")
+	}
+
+	isRequired, err := impl.isTwoFactorRequired(ctx, u)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "two-factor requirement check error", slog.Any("err", err))
+		return err
+	}
+	if isRequired {
+		return httperror.NewForForbiddenWithSingleField("message", "your organization requires two-factor authentication for your role")
+	}
+
+	u.OTPEnabled = false
+	u.OTPVerified = false
+	u.OTPSecret = ""
+	u.OTPRecoveryCodeHashes = []string{}
+	u.ModifiedAt = time.Now()
+	u.ModifiedByUserID = u.ID
+	u.ModifiedByUserName = u.Name
+	u.ModifiedFromIPAddress, _ = ctx.Value(constants.SessionIPAddress).(string)
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl *GatewayControllerImpl) generateTwoFactorSecret(ctx context.Context, u *user_s.User) (*TwoFactorEnrollmentResponseIDO, error) {
+	secret, err := impl.TOTP.GenerateSecret()
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "generate otp secret error", slog.Any("err", err))
+		return nil, err
+	}
+	u.OTPSecret = secret
+	u.OTPVerified = false
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &TwoFactorEnrollmentResponseIDO{
+		Secret:          secret,
+		ProvisioningURI: impl.TOTP.GenerateProvisioningURI(secret, impl.Config.AppServer.DomainName, u.Email),
+	}, nil
+}
+
+// enableTwoFactor function marks two-factor authentication as enabled on
+// the user and returns the plain-text recovery codes. Only the hashes of
+// the recovery codes are saved.
+func (impl *GatewayControllerImpl) enableTwoFactor(u *user_s.User) ([]string, error) {
+	codes, err := impl.TOTP.GenerateRecoveryCodes(otpRecoveryCodeCount)
+	if err != nil {
+		impl.Logger.Error("generate recovery codes error", slog.Any("err", err))
+		return nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hash, err := impl.Password.GenerateHashFromPassword(code)
+		if err != nil {
+			impl.Logger.Error("hashing error", slog.Any("error", err))
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	u.OTPEnabled = true
+	u.OTPVerified = true
+	u.OTPRecoveryCodeHashes = hashes
+	return codes, nil
+}
+
+// useTOTPCode function returns true if the code is valid and was not used
+// before and remembers its time-step so it cannot be replayed while it is
+// still valid.
+func (impl *GatewayControllerImpl) useTOTPCode(u *user_s.User, code string) bool {
+	step, ok := impl.TOTP.ValidateCode(u.OTPSecret, code, u.OTPLastUsedStep)
+	if !ok {
+		return false
+	}
+	u.OTPLastUsedStep = step
+	return true
+}
+
+// useRecoveryCode function returns true if the code matches one of the
+// user's recovery codes and removes it so it cannot be used again.
+func (impl *GatewayControllerImpl) useRecoveryCode(u *user_s.User, code string) bool {
+	for i, hash := range u.OTPRecoveryCodeHashes {
+		if match, _ := impl.Password.ComparePasswordAndHash(code, hash); match {
+			u.OTPRecoveryCodeHashes = append(u.OTPRecoveryCodeHashes[:i], u.OTPRecoveryCodeHashes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
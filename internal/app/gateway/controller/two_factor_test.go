@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+	"github.com/bartmika/databoutique-backend/internal/provider/totp"
+)
+
+// fakeTOTP accepts a single code so the tests do not depend on the clock.
+// The code belongs to `step` which the tests advance to simulate time.
+type fakeTOTP struct {
+	totp.Provider
+	step int64
+}
+
+func (p *fakeTOTP) ValidateCode(secret, code string, afterStep int64) (int64, bool) {
+	return p.step, code == "123456" && p.step > afterStep
+}
+
+func newTwoFactorTestController(t *testing.T, u *user_s.User) *GatewayControllerImpl {
+	t.Helper()
+	impl := newTestController(t)
+	impl.TOTP = &fakeTOTP{Provider: totp.NewProvider(), step: 1}
+	impl.UserStorer = fakes.NewUserStorer(u)
+	impl.TenantStorer = fakes.NewTenantStorer()
+	return impl
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	u := &user_s.User{ID: primitive.NewObjectID(), Email: "alice@acme.com", OTPEnabled: true, OTPSecret: "SECRET"}
+	impl := newTwoFactorTestController(t, u)
+
+	ch, err := impl.startTwoFactorChallenge(ctx, u)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !ch.IsOTPRequired || ch.IsOTPEnrollmentRequired || ch.AccessToken != "" {
+		t.Fatalf("unexpected challenge %+v", ch)
+	}
+
+	if _, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "000000"}); err == nil {
+		t.Fatal("expected an invalid code to be rejected")
+	}
+	res, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "123456"})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if res.AccessToken == "" || res.RefreshToken == "" || res.OTPRecoveryCodes != nil {
+		t.Fatalf("expected the tokens but got %+v", res)
+	}
+
+	// The challenge is single-use.
+	if _, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "123456"}); err == nil {
+		t.Fatal("expected a used challenge to be rejected")
+	}
+}
+
+func TestLoginTwoFactorExpiredChallenge(t *testing.T) {
+	ctx := context.Background()
+	u := &user_s.User{ID: primitive.NewObjectID(), OTPEnabled: true, OTPSecret: "SECRET"}
+	impl := newTwoFactorTestController(t, u)
+
+	// The expiry is enforced even if the cache still has the challenge.
+	chBin, _ := json.Marshal(&otpChallenge{UserID: u.ID, ExpiresAt: time.Now().Add(-time.Second)})
+	impl.Cache.SetWithExpiry(ctx, otpChallengeCacheKeyPrefix+"expired", chBin, otpChallengeExpiry)
+	if _, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: "expired", Code: "123456"}); err == nil {
+		t.Fatal("expected an expired challenge to be rejected")
+	}
+	if _, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: "unknown", Code: "123456"}); err == nil {
+		t.Fatal("expected an unknown challenge to be rejected")
+	}
+}
+
+func TestLoginTwoFactorMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	u := &user_s.User{ID: primitive.NewObjectID(), OTPEnabled: true, OTPSecret: "SECRET"}
+	impl := newTwoFactorTestController(t, u)
+
+	ch, err := impl.startTwoFactorChallenge(ctx, u)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	for i := 0; i < otpChallengeMaxAttempts; i++ {
+		if _, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "000000"}); err == nil {
+			t.Fatal("expected an invalid code to be rejected")
+		}
+	}
+
+	// The challenge is discarded so the user must login again.
+	if _, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "123456"}); err == nil {
+		t.Fatal("expected the challenge to be discarded after too many attempts")
+	}
+}
+
+func TestLoginTwoFactorEnrollmentAndRecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	u := &user_s.User{ID: primitive.NewObjectID(), Email: "alice@acme.com"}
+	impl := newTwoFactorTestController(t, u)
+
+	ch, err := impl.startTwoFactorChallenge(ctx, u)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !ch.IsOTPEnrollmentRequired {
+		t.Fatalf("expected the enrollment to be required but got %+v", ch)
+	}
+	if _, err := impl.LoginTwoFactorEnroll(ctx, ch.OTPChallengeToken); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	res, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "123456"})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !u.OTPEnabled || len(res.OTPRecoveryCodes) != otpRecoveryCodeCount {
+		t.Fatalf("expected the enrollment to be completed but got %+v", res)
+	}
+
+	// The recovery codes are single-use.
+	code := res.OTPRecoveryCodes[0]
+	for i, wantErr := range []bool{false, true} {
+		ch, err := impl.startTwoFactorChallenge(ctx, u)
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		_, err = impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, RecoveryCode: code})
+		if (err != nil) != wantErr {
+			t.Fatalf("unexpected result of use %d of the recovery code: %v", i+1, err)
+		}
+	}
+}
+
+func TestTwoFactorCodeReplay(t *testing.T) {
+	ctx := context.Background()
+	u := &user_s.User{ID: primitive.NewObjectID(), Email: "alice@acme.com", OTPSecret: "SECRET"}
+	impl := newTwoFactorTestController(t, u)
+	p := impl.TOTP.(*fakeTOTP)
+
+	// The code which enabled two-factor authentication cannot be used to
+	// login while it is still valid.
+	profileCtx := context.WithValue(ctx, constants.SessionUserID, u.ID)
+	if _, err := impl.ProfileTwoFactorVerify(profileCtx, &ProfileTwoFactorVerifyRequestIDO{Code: "123456"}); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if u.OTPLastUsedStep != p.step {
+		t.Fatalf("expected the step %d to be saved but got %d", p.step, u.OTPLastUsedStep)
+	}
+	ch, err := impl.startTwoFactorChallenge(ctx, u)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if _, err := impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "123456"}); err == nil {
+		t.Fatal("expected the replayed code to be rejected")
+	}
+
+	// The code of the next step is accepted once.
+	p.step++
+	for i, wantErr := range []bool{false, true} {
+		ch, err := impl.startTwoFactorChallenge(ctx, u)
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		_, err = impl.LoginTwoFactor(ctx, &LoginTwoFactorRequestIDO{ChallengeToken: ch.OTPChallengeToken, Code: "123456"})
+		if (err != nil) != wantErr {
+			t.Fatalf("unexpected result of use %d of the code: %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+const (
+	// The duration the user has to click the link in the verification email.
+	emailVerificationExpiry = 72 * time.Hour
+
+	// The minimum duration between sending verification emails to the user.
+	emailVerificationResendInterval = time.Minute
+)
+
+type VerifyRequestIDO struct {
+	Code string `json:"code"`
+}
+
+type VerifyResponseIDO struct {
+	Message  string `json:"message"`
+	UserRole int8   `json:"user_role"`
+}
+
+type VerifyResendRequestIDO struct {
+	Email string `json:"email"`
+}
+
+// isEmailVerificationRequired function returns true if the tenant of the
+// user requires users to verify their email before they can login.
+func (impl *GatewayControllerImpl) isEmailVerificationRequired(ctx context.Context, u *user_s.User) (bool, error) {
+	t, err := impl.TenantStorer.GetByID(ctx, u.TenantID)
+	if err != nil {
+		return false, err
+	}
+	if t == nil {
+		return false, nil
+	}
+	return t.IsEmailVerificationRequired, nil
+}
+
+// sendVerificationEmail function generates a new verification code for the
+// user and emails the verification link to the user.
+func (impl *GatewayControllerImpl) sendVerificationEmail(ctx context.Context, u *user_s.User) error {
+	u.EmailVerificationCode = impl.UUID.NewUUID()
+	u.EmailVerificationExpiry = time.Now().Add(emailVerificationExpiry)
+	u.ModifiedAt = time.Now()
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "user update by id failed", slog.Any("error", err))
+		return err
+	}
+	return impl.TemplatedEmailer.SendVerificationEmail(u.Email, u.EmailVerificationCode, u.FirstName)
+}
+
+func (impl *GatewayControllerImpl) Verify(ctx context.Context, code string) (*VerifyResponseIDO, error) {
+	if code == "" {
+		return nil, httperror.NewForBadRequestWithSingleField("code", "missing value")
+	}
+
+	impl.Kmutex.Lockf("verify-email-code-%s", code)
+	defer impl.Kmutex.Unlockf("verify-email-code-%s", code)
+
+	// Lookup the user in our database, else return a `400 Bad Request` error.
+	u, err := impl.UserStorer.GetByVerificationCode(ctx, code)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if u == nil {
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
+		return nil, httperror.NewForBadRequestWithSingleField("code", "does not exist")
+	}
+
+	res := &VerifyResponseIDO{
+		Message:  "Thank you for verifying your email, you may log in now.",
+		UserRole: u.Role,
+	}
+
+	// Do nothing if the user clicked the link more then once.
+	if u.WasEmailVerified {
+		return res, nil
+	}
+	if time.Now().After(u.EmailVerificationExpiry) {
+		impl.Logger.WarnContext(ctx, "email verification code expired", slog.Any("user_id", u.ID))
+		return nil, httperror.NewForBadRequestWithSingleField("code", "expired, please request a new verification email")
+	}
+
+	u.WasEmailVerified = true
+	u.EmailVerificationCode = "" // Remove email active code so it cannot be used agian.
+	u.EmailVerificationExpiry = time.Now()
+	u.ModifiedAt = time.Now()
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.ErrorContext(ctx, "update error", slog.Any("err", err))
+		return nil, err
+	}
+	impl.Logger.InfoContext(ctx, "User verified email.",
+		slog.Any("tenant_id", u.TenantID),
+		slog.Any("user_id", u.ID))
+
+	return res, nil
+}
+
+func (impl *GatewayControllerImpl) VerifyResend(ctx context.Context, email string) error {
+	// Defensive Code: For security purposes we need to remove all whitespaces from the email and lower the characters.
+	email = strings.ToLower(strings.ReplaceAll(email, " ", ""))
+	if email == "" {
+		return httperror.NewForBadRequestWithSingleField("email", "missing value")
+	}
+
+	impl.Kmutex.Lockf("verify-email-resend-%s", email)
+	defer impl.Kmutex.Unlockf("verify-email-resend-%s", email)
+
+	// Lookup the user in our database. For security purposes we respond the
+	// same whether or not the email exists so the accounts cannot be
+	// enumerated.
+	u, err := impl.UserStorer.GetByEmail(ctx, email)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return err
+	}
+	if u == nil {
+		impl.Logger.WarnContext(ctx, "user does not exist validation error", slog.String("email", email))
+		return nil
+	}
+	if u.WasEmailVerified {
+		impl.Logger.WarnContext(ctx, "user email was already verified", slog.Any("user_id", u.ID))
+		return nil
+	}
+
+	// Throttle the emails; we know when the last email was sent based on
+	// the expiry of the verification code.
+	if time.Until(u.EmailVerificationExpiry) > emailVerificationExpiry-emailVerificationResendInterval {
+		impl.Logger.WarnContext(ctx, "verification email resend throttled", slog.Any("user_id", u.ID))
+		return nil
+	}
+
+	return impl.sendVerificationEmail(ctx, u)
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+)
+
+func TestVerifyAndPasswordResetCodesAreSeparate(t *testing.T) {
+	ctx := context.Background()
+	bob := &user_s.User{ID: primitive.NewObjectID(), Email: "bob@acme.com", Status: user_s.UserStatusActive}
+	impl := newTestController(t)
+	impl.UserStorer = fakes.NewUserStorer(bob)
+	impl.TenantStorer = fakes.NewTenantStorer()
+	te := impl.TemplatedEmailer.(*fakes.TemplatedEmailer)
+
+	if err := impl.sendVerificationEmail(ctx, bob); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	verificationCode := te.Tokens[0]
+
+	// The signup code cannot be used to reset the password.
+	if err := impl.PasswordReset(ctx, verificationCode, "new-password"); err == nil {
+		t.Fatal("expected the verification code to be rejected")
+	}
+
+	if err := impl.ForgotPassword(ctx, bob.Email); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	resetCode := te.Tokens[1]
+
+	// The reset code cannot be used to verify the email.
+	if _, err := impl.Verify(ctx, resetCode); err == nil {
+		t.Fatal("expected the reset code to be rejected")
+	}
+
+	// Verifying the email keeps the reset code working and vice versa.
+	if _, err := impl.Verify(ctx, verificationCode); err != nil || !bob.WasEmailVerified {
+		t.Fatalf("expected the email to be verified but got %v", err)
+	}
+	if err := impl.PasswordReset(ctx, resetCode, "new-password"); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if bob.PasswordResetCode != "" {
+		t.Fatal("expected the reset code to be removed")
+	}
+	if err := impl.PasswordReset(ctx, resetCode, "another-password"); err == nil {
+		t.Fatal("expected the used reset code to be rejected")
+	}
+}
+
+func TestPasswordResetExpired(t *testing.T) {
+	ctx := context.Background()
+	bob := &user_s.User{ID: primitive.NewObjectID(), Email: "bob@acme.com", PasswordResetCode: "code", PasswordResetExpiry: time.Now().Add(-time.Minute)}
+	impl := newTestController(t)
+	impl.UserStorer = fakes.NewUserStorer(bob)
+
+	if err := impl.PasswordReset(ctx, "code", "new-password"); err == nil {
+		t.Fatal("expected the expired reset code to be rejected")
+	}
+}
+
+func TestVerifyResendDoesNotEnumerateAccounts(t *testing.T) {
+	ctx := context.Background()
+	bob := &user_s.User{ID: primitive.NewObjectID(), Email: "bob@acme.com", WasEmailVerified: true}
+	carol := &user_s.User{ID: primitive.NewObjectID(), Email: "carol@acme.com"}
+	impl := newTestController(t)
+	impl.UserStorer = fakes.NewUserStorer(bob, carol)
+	te := impl.TemplatedEmailer.(*fakes.TemplatedEmailer)
+
+	for _, email := range []string{"nobody@acme.com", "bob@acme.com", "carol@acme.com", "carol@acme.com"} {
+		if err := impl.VerifyResend(ctx, email); err != nil {
+			t.Fatalf("expected the same response for %s but got %v", email, err)
+		}
+	}
+
+	// Only the unverified account was emailed and the resend was throttled.
+	if len(te.Tokens) != 1 || carol.EmailVerificationCode != te.Tokens[0] {
+		t.Fatalf("expected a single verification email but got %v", te.Tokens)
+	}
+}
@@ -55,4 +55,21 @@ type LoginResponseIDO struct {
 	AccessTokenExpiryTime  time.Time    `json:"access_token_expiry_time"`
 	RefreshToken           string       `json:"refresh_token"`
 	RefreshTokenExpiryTime time.Time    `json:"refresh_token_expiry_time"`
+
+	// The following fields are only set when the user must complete the
+	// second step of the login with two-factor authentication. In this case
+	// no tokens will be issued until `/api/v1/login/2fa` was called with
+	// the challenge token and a valid code.
+	IsOTPRequired           bool      `json:"is_otp_required,omitempty"`
+	IsOTPEnrollmentRequired bool      `json:"is_otp_enrollment_required,omitempty"`
+	OTPChallengeToken       string    `json:"otp_challenge_token,omitempty"`
+	OTPChallengeExpiryTime  time.Time `json:"otp_challenge_expiry_time,omitempty"`
+
+	// The recovery codes are only returned once after the user finished
+	// enrolling in two-factor authentication during login.
+	OTPRecoveryCodes []string `json:"otp_recovery_codes,omitempty"`
+
+	// If true then the user registered but must verify their email before
+	// any tokens will be issued.
+	IsEmailVerificationRequired bool `json:"is_email_verification_required,omitempty"`
 }
@@ -20,7 +20,7 @@ func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
 
 func MarshalDashboardResponse(responseData *way_c.DashboardResponseIDO, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&responseData); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
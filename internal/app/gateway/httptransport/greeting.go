@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 type GreetingRequest struct {
@@ -17,7 +19,7 @@ type GreetingResponse struct {
 func (h *Handler) Greet(w http.ResponseWriter, r *http.Request) {
 	var requestData GreetingRequest
 	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 
@@ -25,6 +27,6 @@ func (h *Handler) Greet(w http.ResponseWriter, r *http.Request) {
 		Message: fmt.Sprintf("greetings %s", requestData.Name),
 	}
 	if err := json.NewEncoder(w).Encode(&responseData); err != nil { // [2]
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 	}
 }
@@ -77,7 +77,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 func MarshalLoginResponse(responseData *gateway_s.LoginResponseIDO, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&responseData); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
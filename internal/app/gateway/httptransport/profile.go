@@ -24,7 +24,7 @@ func (h *Handler) Profile(w http.ResponseWriter, r *http.Request) {
 
 func MarshalProfileResponse(responseData *user_s.User, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&responseData); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -44,6 +44,7 @@ type ProfileUpdateRequestIDO struct {
 	IsHowDidYouHearAboutUsOther bool               `bson:"is_how_did_you_hear_about_us_other" json:"is_how_did_you_hear_about_us_other,omitempty"`
 	HowDidYouHearAboutUsOther   string             `bson:"how_did_you_hear_about_us_other" json:"how_did_you_hear_about_us_other,omitempty"`
 	AgreePromotionsEmail        bool               `bson:"agree_promotions_email,omitempty" json:"agree_promotions_email,omitempty"`
+	IsAnswerReadyEmailDisabled  bool               `bson:"is_answer_ready_email_disabled" json:"is_answer_ready_email_disabled"`
 	HasShippingAddress          bool               `bson:"has_shipping_address" json:"has_shipping_address,omitempty"`
 	ShippingName                string             `bson:"shipping_name" json:"shipping_name,omitempty"`
 	ShippingPhone               string             `bson:"shipping_phone" json:"shipping_phone,omitempty"`
@@ -93,6 +94,7 @@ func UnmarshalProfileUpdateRequest(ctx context.Context, r *http.Request) (*user_
 		IsHowDidYouHearAboutUsOther: requestData.IsHowDidYouHearAboutUsOther,
 		HowDidYouHearAboutUsOther:   requestData.HowDidYouHearAboutUsOther,
 		AgreePromotionsEmail:        requestData.AgreePromotionsEmail,
+		IsAnswerReadyEmailDisabled:  requestData.IsAnswerReadyEmailDisabled,
 		HasShippingAddress:          requestData.HasShippingAddress,
 		ShippingName:                requestData.ShippingName,
 		ShippingPhone:               requestData.ShippingPhone,
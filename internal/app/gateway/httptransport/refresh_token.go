@@ -3,11 +3,11 @@ package httptransport
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"time"
 
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 type RefreshTokenRequestIDO struct {
@@ -23,7 +23,7 @@ type RefreshTokenResponseIDO struct {
 	RefreshTokenExpiryDate time.Time `json:"refresh_token_expiry_date"`
 }
 
-func UnmarshalRefreshTokenRequest(ctx context.Context, r *http.Request) (*RefreshTokenRequestIDO, error, int) {
+func UnmarshalRefreshTokenRequest(ctx context.Context, r *http.Request) (*RefreshTokenRequestIDO, error) {
 	// Initialize our array which will store all the results from the remote server.
 	var requestData RefreshTokenRequestIDO
 
@@ -33,19 +33,17 @@ func UnmarshalRefreshTokenRequest(ctx context.Context, r *http.Request) (*Refres
 	// to send a `400 Bad Request` errror message back to the client,
 	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
 	if err != nil {
-		return nil, err, http.StatusBadRequest
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
 	}
 
 	// Perform our validation and return validation error on any issues detected.
-	isValid, errStr := ValidateRefreshTokenRequest(&requestData)
-	if isValid == false {
-		return nil, errors.New(errStr), http.StatusBadRequest
+	if err = ValidateRefreshTokenRequest(&requestData); err != nil {
+		return nil, err
 	}
-
-	return &requestData, nil, http.StatusOK
+	return &requestData, nil
 }
 
-func ValidateRefreshTokenRequest(dirtyData *RefreshTokenRequestIDO) (bool, string) {
+func ValidateRefreshTokenRequest(dirtyData *RefreshTokenRequestIDO) error {
 	e := make(map[string]string)
 
 	if dirtyData.Value == "" {
@@ -53,31 +51,28 @@ func ValidateRefreshTokenRequest(dirtyData *RefreshTokenRequestIDO) (bool, strin
 	}
 
 	if len(e) != 0 {
-		b, err := json.Marshal(e)
-		if err != nil { // Defensive code
-			return false, err.Error()
-		}
-		return false, string(b)
+		return httperror.NewForBadRequest(&e)
 	}
-	return true, ""
+	return nil
 }
 
 func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	requestData, err, errStatusCode := UnmarshalRefreshTokenRequest(ctx, r)
+	requestData, err := UnmarshalRefreshTokenRequest(ctx, r)
 	if err != nil {
-		http.Error(w, err.Error(), errStatusCode)
+		httperror.ResponseError(w, err)
 		return
 	}
 
+	// Revoked sessions and reused refresh tokens return an `401 Unauthorized`.
 	user, accessToken, accessTokenExpiryDate, refreshToken, refreshTokenExpiryDate, err := h.Controller.RefreshToken(ctx, requestData.Value)
-	if user == nil {
-		http.Error(w, "{'non_field_error':'user does not exist'}", http.StatusNotFound)
+	if err != nil {
+		httperror.ResponseError(w, err)
 		return
 	}
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusOK)
+	if user == nil {
+		httperror.ResponseError(w, httperror.NewForNotFoundWithSingleField("non_field_error", "user does not exist"))
 		return
 	}
 
@@ -93,7 +88,7 @@ func MarshalRefreshTokenResponse(accessToken string, accessTokenExpiryDate time.
 		RefreshTokenExpiryDate: refreshTokenExpiryDate,
 	}
 	if err := json.NewEncoder(w).Encode(&responseData); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -72,3 +72,21 @@ func (h *Handler) UserRegister(w http.ResponseWriter, r *http.Request) {
 	}
 	MarshalLoginResponse(res, w)
 }
+
+func (h *Handler) TenantRegister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData gateway_c.TenantRegisterRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	res, err := h.Controller.TenantRegister(ctx, &requestData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalLoginResponse(res, w)
+}
@@ -0,0 +1,55 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) ProfileSessionList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.Controller.ProfileSessionList(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalProfileSessionListResponse(res, w)
+}
+
+func MarshalProfileSessionListResponse(res *session_s.SessionListResult, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func (h *Handler) ProfileSessionRevoke(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	if err := h.Controller.ProfileSessionRevoke(ctx, objectID); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ProfileSessionRevokeAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.Controller.ProfileSessionRevokeAll(ctx); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	gateway_c "github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) OIDCLoginStart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData gateway_c.OIDCLoginStartRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	res, err := h.Controller.OIDCLoginStart(ctx, &requestData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func (h *Handler) ProfileOIDCLinkStart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.Controller.ProfileOIDCLinkStart(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func (h *Handler) OIDCLoginCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData gateway_c.OIDCLoginCallbackRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	res, err := h.Controller.OIDCLoginCallback(ctx, &requestData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalLoginResponse(res, w)
+}
@@ -0,0 +1,121 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	gateway_c "github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func UnmarshalLoginTwoFactorRequest(ctx context.Context, r *http.Request) (*gateway_c.LoginTwoFactorRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData gateway_c.LoginTwoFactorRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+	return &requestData, nil
+}
+
+func (h *Handler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalLoginTwoFactorRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.LoginTwoFactor(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalLoginResponse(res, w)
+}
+
+func (h *Handler) LoginTwoFactorEnroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalLoginTwoFactorRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	if data.ChallengeToken == "" {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("challenge_token", "missing value"))
+		return
+	}
+
+	res, err := h.Controller.LoginTwoFactorEnroll(ctx, data.ChallengeToken)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalTwoFactorEnrollmentResponse(res, w)
+}
+
+func (h *Handler) ProfileTwoFactorGenerate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.Controller.ProfileTwoFactorGenerate(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalTwoFactorEnrollmentResponse(res, w)
+}
+
+func MarshalTwoFactorEnrollmentResponse(responseData *gateway_c.TwoFactorEnrollmentResponseIDO, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&responseData); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func (h *Handler) ProfileTwoFactorVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData gateway_c.ProfileTwoFactorVerifyRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	res, err := h.Controller.ProfileTwoFactorVerify(ctx, &requestData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func (h *Handler) ProfileTwoFactorDisable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData gateway_c.ProfileTwoFactorDisableRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	if err := h.Controller.ProfileTwoFactorDisable(ctx, &requestData); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	// Get the request
+	h.Profile(w, r)
+}
@@ -0,0 +1,47 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	gateway_c "github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData gateway_c.VerifyRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	res, err := h.Controller.Verify(ctx, requestData.Code)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func (h *Handler) VerifyResend(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData gateway_c.VerifyResendRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	if err := h.Controller.VerifyResend(ctx, requestData.Email); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
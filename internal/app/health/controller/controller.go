@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthController Interface for the liveness and readiness checks.
+type HealthController interface {
+	// Liveness function returns if this process is running. It must not
+	// check any dependencies so an outage of a dependency does not restart
+	// every instance of our application.
+	Liveness(ctx context.Context) *HealthResponseIDO
+	// Readiness function checks every dependency required to serve the API
+	// and returns a breakdown of the status and latency of each. The errors
+	// are only logged since this check is public.
+	Readiness(ctx context.Context) *HealthResponseIDO
+	// OpenAI function verifies OpenAI is reachable with the credentials of
+	// every active tenant. The result is cached since every check of a tenant
+	// is a request to OpenAI billed to the tenant.
+	OpenAI(ctx context.Context) (*DependencyResult, error)
+}
+
+// HealthResponseIDO is the result of a health check.
+type HealthResponseIDO struct {
+	Status string                       `json:"status"`
+	Checks map[string]*DependencyResult `json:"checks,omitempty"`
+}
+
+// DependencyResult is the result of checking a single dependency.
+type DependencyResult struct {
+	Status    string               `json:"status"`
+	LatencyMS int64                `json:"latency_ms"`
+	Error     string               `json:"error,omitempty"`
+	Tenants   []*TenantCheckResult `json:"tenants,omitempty"`
+}
+
+// TenantCheckResult is the result of checking a dependency for a tenant.
+type TenantCheckResult struct {
+	TenantID  string `json:"tenant_id"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type HealthControllerImpl struct {
+	Config       *config.Conf
+	Logger       *slog.Logger
+	S3           s3_storage.S3Storager
+	DbClient     *mongo.Client
+	TenantStorer tenant_s.TenantStorer
+
+	openAIMu        sync.Mutex
+	openAIResult    *DependencyResult
+	openAICheckedAt time.Time
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	s3 s3_storage.S3Storager,
+	client *mongo.Client,
+	t_storer tenant_s.TenantStorer,
+) HealthController {
+	s := &HealthControllerImpl{
+		Config:       appCfg,
+		Logger:       loggerp,
+		S3:           s3,
+		DbClient:     client,
+		TenantStorer: t_storer,
+	}
+	s.Logger.Debug("health controller initialization started...")
+	s.Logger.Debug("health controller initialized")
+	return s
+}
+
+// checkTimeout is the maximum time a single dependency check can take.
+const checkTimeout = 3 * time.Second
+
+// check function runs the dependency check with a timeout and measures its
+// latency.
+func check(ctx context.Context, fn func(ctx context.Context) error) *DependencyResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	startAt := time.Now()
+	err := fn(ctx)
+	res := &DependencyResult{
+		Status:    HealthStatusOK,
+		LatencyMS: time.Since(startAt).Milliseconds(),
+	}
+	if err != nil {
+		res.Status = HealthStatusUnavailable
+		res.Error = err.Error()
+	}
+	return res
+}
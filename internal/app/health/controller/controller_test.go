@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+func TestCheck(t *testing.T) {
+	res := check(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+	if res.Status != HealthStatusOK || res.Error != "" {
+		t.Fatalf("expected an ok result but got %+v", res)
+	}
+
+	res = check(context.Background(), func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	if res.Status != HealthStatusUnavailable || res.Error != "connection refused" {
+		t.Fatalf("expected an unavailable result but got %+v", res)
+	}
+
+	res = check(context.Background(), func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("expected a deadline")
+		}
+		return nil
+	})
+	if res.Status != HealthStatusOK {
+		t.Fatalf("expected the check to run with a deadline but got %+v", res)
+	}
+}
+
+func TestOpenAI(t *testing.T) {
+	impl := &HealthControllerImpl{Config: &config.Conf{}}
+	if _, err := impl.OpenAI(context.Background()); err == nil {
+		t.Fatal("expected the disabled check to be rejected")
+	}
+
+	// The cached result is returned without checking OpenAI again since
+	// every check is billed to our tenants.
+	impl.Config.AppServer.HasOpenAIHealthCheck = true
+	cached := &DependencyResult{Status: HealthStatusOK}
+	impl.openAIResult = cached
+	impl.openAICheckedAt = time.Now()
+	res, err := impl.OpenAI(context.Background())
+	if err != nil || res != cached {
+		t.Fatalf("expected the cached result but got %+v %v", res, err)
+	}
+}
@@ -0,0 +1,9 @@
+package controller
+
+import (
+	"context"
+)
+
+func (impl *HealthControllerImpl) Liveness(ctx context.Context) *HealthResponseIDO {
+	return &HealthResponseIDO{Status: HealthStatusOK}
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+const (
+	// maxOpenAITenantChecks is the maximum number of tenants to check the
+	// OpenAI reachability for in a single check.
+	maxOpenAITenantChecks = 100
+
+	// openAICheckCacheTTL is how long the result of the OpenAI check is
+	// reused before OpenAI is checked again.
+	openAICheckCacheTTL = 5 * time.Minute
+)
+
+func (impl *HealthControllerImpl) Readiness(ctx context.Context) *HealthResponseIDO {
+	checks := map[string]func(ctx context.Context) *DependencyResult{
+		"mongodb": func(ctx context.Context) *DependencyResult {
+			return check(ctx, impl.checkMongoDB)
+		},
+		"mongodb_transactions": func(ctx context.Context) *DependencyResult {
+			return check(ctx, impl.checkMongoDBTransactions)
+		},
+		"s3": func(ctx context.Context) *DependencyResult {
+			return check(ctx, impl.checkS3)
+		},
+	}
+
+	// Run every check concurrently so our latency is the slowest check.
+	res := &HealthResponseIDO{
+		Status: HealthStatusOK,
+		Checks: make(map[string]*DependencyResult, len(checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, fn := range checks {
+		wg.Add(1)
+		go func(name string, fn func(ctx context.Context) *DependencyResult) {
+			defer wg.Done()
+			result := fn(ctx)
+			mu.Lock()
+			res.Checks[name] = result
+			mu.Unlock()
+		}(name, fn)
+	}
+	wg.Wait()
+
+	for name, result := range res.Checks {
+		if result.Status != HealthStatusOK {
+			impl.Logger.WarnContext(ctx, "readiness check failed",
+				slog.String("dependency", name),
+				slog.String("error", result.Error))
+			res.Status = HealthStatusUnavailable
+		}
+		// DEVELOPERS NOTE: Do not expose the details of our infrastructure
+		// to the public, ex: the name of our bucket.
+		result.Error = ""
+	}
+	return res
+}
+
+func (impl *HealthControllerImpl) checkMongoDB(ctx context.Context) error {
+	return impl.DbClient.Ping(ctx, readpref.Primary())
+}
+
+// checkMongoDBTransactions function verifies our database is a replica set or
+// a sharded cluster as transactions are not supported by standalone servers.
+func (impl *HealthControllerImpl) checkMongoDBTransactions(ctx context.Context) error {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := impl.DbClient.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return err
+	}
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return errors.New("transactions are not supported by a standalone server")
+	}
+	return nil
+}
+
+func (impl *HealthControllerImpl) checkS3(ctx context.Context) error {
+	exists, err := impl.S3.BucketExists(ctx, impl.Config.AWS.BucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", impl.Config.AWS.BucketName)
+	}
+	return nil
+}
+
+func (impl *HealthControllerImpl) OpenAI(ctx context.Context) (*DependencyResult, error) {
+	if !impl.Config.AppServer.HasOpenAIHealthCheck {
+		return nil, httperror.NewForNotFoundWithSingleField("message", "openai health check is disabled")
+	}
+
+	// DEVELOPERS NOTE: The lock is held during the check so concurrent
+	// requests wait for and share a single check.
+	impl.openAIMu.Lock()
+	defer impl.openAIMu.Unlock()
+	if impl.openAIResult == nil || time.Since(impl.openAICheckedAt) > openAICheckCacheTTL {
+		impl.openAIResult = impl.checkOpenAI(ctx)
+		impl.openAICheckedAt = time.Now()
+	}
+	return impl.openAIResult, nil
+}
+
+// checkOpenAI function verifies OpenAI is reachable with the credentials of
+// every active tenant.
+func (impl *HealthControllerImpl) checkOpenAI(ctx context.Context) *DependencyResult {
+	var tenants []*tenant_s.Tenant
+	res := check(ctx, func(ctx context.Context) error {
+		tl, err := impl.TenantStorer.ListByFilter(ctx, &tenant_s.TenantListFilter{
+			PageSize:  maxOpenAITenantChecks,
+			SortField: "_id",
+			SortOrder: 1,
+			Status:    tenant_s.TenantActiveStatus,
+		})
+		if err != nil {
+			return err
+		}
+		tenants = tl.Results
+		return nil
+	})
+	if res.Status != HealthStatusOK {
+		return res
+	}
+
+	res.Tenants = make([]*TenantCheckResult, len(tenants))
+	var wg sync.WaitGroup
+	for i, t := range tenants {
+		wg.Add(1)
+		go func(i int, t *tenant_s.Tenant) {
+			defer wg.Done()
+			r := check(ctx, func(ctx context.Context) error {
+				return impl.checkOpenAIForTenant(ctx, t)
+			})
+			res.Tenants[i] = &TenantCheckResult{
+				TenantID:  t.ID.Hex(),
+				Status:    r.Status,
+				LatencyMS: r.LatencyMS,
+				Error:     r.Error,
+			}
+		}(i, t)
+	}
+	wg.Wait()
+
+	for _, r := range res.Tenants {
+		if r.LatencyMS > res.LatencyMS {
+			res.LatencyMS = r.LatencyMS
+		}
+		if r.Status != HealthStatusOK {
+			res.Status = HealthStatusUnavailable
+		}
+	}
+	return res
+}
+
+func (impl *HealthControllerImpl) checkOpenAIForTenant(ctx context.Context, t *tenant_s.Tenant) error {
+	creds, err := impl.TenantStorer.GetOpenAICredentialsByID(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	if creds == nil || creds.APIKey == "" {
+		return errors.New("openai credentials are not set")
+	}
+	_, err = metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey).ListModels(ctx)
+	return err
+}
@@ -0,0 +1,21 @@
+package httptransport
+
+import (
+	"log/slog"
+
+	health_c "github.com/bartmika/databoutique-backend/internal/app/health/controller"
+)
+
+// Handler Creates http request handler
+type Handler struct {
+	Logger     *slog.Logger
+	Controller health_c.HealthController
+}
+
+// NewHandler Constructor
+func NewHandler(loggerp *slog.Logger, c health_c.HealthController) *Handler {
+	return &Handler{
+		Logger:     loggerp,
+		Controller: c,
+	}
+}
@@ -0,0 +1,49 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	health_c "github.com/bartmika/databoutique-backend/internal/app/health/controller"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// Liveness returns if the server is running. Developers note, to see result you can run in your terminal `curl http://localhost:8000/api/v1/health/live`.
+func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res := h.Controller.Liveness(ctx)
+	MarshalHealthResponse(res, w)
+}
+
+// Readiness returns if the server and its dependencies are ready to serve
+// the API. Developers note, to see result you can run in your terminal `curl http://localhost:8000/api/v1/health/ready`.
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res := h.Controller.Readiness(ctx)
+	MarshalHealthResponse(res, w)
+}
+
+// OpenAI returns if OpenAI is reachable with the credentials of every active
+// tenant. Only our executives can see this breakdown.
+func (h *Handler) OpenAI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res, err := h.Controller.OpenAI(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func MarshalHealthResponse(res *health_c.HealthResponseIDO, w http.ResponseWriter) {
+	if res.Status != health_c.HealthStatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
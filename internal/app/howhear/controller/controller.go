@@ -9,6 +9,7 @@ import (
 
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	howhear_s "github.com/bartmika/databoutique-backend/internal/app/howhear/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
@@ -40,6 +41,7 @@ type HowHearAboutUsItemControllerImpl struct {
 	UserStorer               user_s.UserStorer
 	HowHearAboutUsItemStorer howhear_s.HowHearAboutUsItemStorer
 	TemplatedEmailer         templatedemailer.TemplatedEmailer
+	AuditEvent               auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -53,6 +55,7 @@ func NewController(
 	client *mongo.Client,
 	usr_storer user_s.UserStorer,
 	howhear_s howhear_s.HowHearAboutUsItemStorer,
+	auditevent auditevent_c.AuditEventController,
 ) HowHearAboutUsItemController {
 	s := &HowHearAboutUsItemControllerImpl{
 		Config:                   appCfg,
@@ -65,6 +68,7 @@ func NewController(
 		DbClient:                 client,
 		UserStorer:               usr_storer,
 		HowHearAboutUsItemStorer: howhear_s,
+		AuditEvent:               auditevent,
 	}
 	s.Logger.Debug("howhear controller initialization started...")
 	s.Logger.Debug("howhear controller initialized")
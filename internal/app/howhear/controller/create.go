@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	howhear_s "github.com/bartmika/databoutique-backend/internal/app/howhear/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
@@ -66,7 +67,7 @@ func (impl *HowHearAboutUsItemControllerImpl) Create(ctx context.Context, reques
 	//
 
 	if err := impl.validateCreateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -74,7 +75,7 @@ func (impl *HowHearAboutUsItemControllerImpl) Create(ctx context.Context, reques
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -84,7 +85,7 @@ func (impl *HowHearAboutUsItemControllerImpl) Create(ctx context.Context, reques
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -117,7 +118,11 @@ func (impl *HowHearAboutUsItemControllerImpl) Create(ctx context.Context, reques
 
 		// Save to our database.
 		if err := impl.HowHearAboutUsItemStorer.Create(sessCtx, hh); err != nil {
-			impl.Logger.Error("database create error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+			return nil, err
+		}
+
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeHowHearAboutUsItem, hh.ID, nil, hh); err != nil {
 			return nil, err
 		}
 
@@ -131,7 +136,7 @@ func (impl *HowHearAboutUsItemControllerImpl) Create(ctx context.Context, reques
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -19,7 +19,7 @@ func (c *HowHearAboutUsItemControllerImpl) ListByFilter(ctx context.Context, f *
 	// Apply filtering based on ownership and role.
 	f.TenantID = tenantID // Manditory
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -38,7 +38,7 @@ func (c *HowHearAboutUsItemControllerImpl) ListByFilter(ctx context.Context, f *
 
 	m, err := c.HowHearAboutUsItemStorer.ListByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -51,7 +51,7 @@ func (c *HowHearAboutUsItemControllerImpl) ListByFilter(ctx context.Context, f *
 // 	// Apply filtering based on ownership and role.
 // 	f.TenantID = tenantID // Manditory
 //
-// 	c.Logger.Debug("listing using filter options:",
+// 	c.Logger.DebugContext(ctx, "listing using filter options:",
 // 		slog.Any("Cursor", f.Cursor),
 // 		slog.Int64("PageSize", f.PageSize),
 // 		slog.String("SortField", f.SortField),
@@ -61,7 +61,7 @@ func (c *HowHearAboutUsItemControllerImpl) ListByFilter(ctx context.Context, f *
 //
 // 	m, err := c.HowHearAboutUsItemStorer.LiteListByFilter(ctx, f)
 // 	if err != nil {
-// 		c.Logger.Error("database list by filter error", slog.Any("error", err))
+// 		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 // 		return nil, err
 // 	}
 // 	return m, err
@@ -74,7 +74,7 @@ func (c *HowHearAboutUsItemControllerImpl) ListAsSelectOptionByFilter(ctx contex
 	// Apply filtering based on ownership and role.
 	f.TenantID = tenantID // Manditory
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -85,7 +85,7 @@ func (c *HowHearAboutUsItemControllerImpl) ListAsSelectOptionByFilter(ctx contex
 	// Filtering the database.
 	m, err := c.HowHearAboutUsItemStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -101,7 +101,7 @@ func (c *HowHearAboutUsItemControllerImpl) PublicListAsSelectOptionByFilter(ctx
 	// 	}
 	// }
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -112,7 +112,7 @@ func (c *HowHearAboutUsItemControllerImpl) PublicListAsSelectOptionByFilter(ctx
 	// Filtering the database.
 	m, err := c.HowHearAboutUsItemStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	howhear_s "github.com/bartmika/databoutique-backend/internal/app/howhear/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
@@ -46,7 +47,7 @@ func (impl *HowHearAboutUsItemControllerImpl) UpdateByID(ctx context.Context, re
 	//
 
 	if err := impl.validateUpdateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -63,7 +64,7 @@ func (impl *HowHearAboutUsItemControllerImpl) UpdateByID(ctx context.Context, re
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -73,7 +74,7 @@ func (impl *HowHearAboutUsItemControllerImpl) UpdateByID(ctx context.Context, re
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -89,13 +90,14 @@ func (impl *HowHearAboutUsItemControllerImpl) UpdateByID(ctx context.Context, re
 		// Lookup the howhear in our database, else return a `400 Bad Request` error.
 		hh, err := impl.HowHearAboutUsItemStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if hh == nil {
-			impl.Logger.Warn("howhear does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "howhear does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		before := *hh
 
 		////
 		//// Update primary record.
@@ -116,7 +118,7 @@ func (impl *HowHearAboutUsItemControllerImpl) UpdateByID(ctx context.Context, re
 		hh.IsForStaff = requestData.IsForStaff
 
 		if err := impl.HowHearAboutUsItemStorer.UpdateByID(sessCtx, hh); err != nil {
-			impl.Logger.Error("howhear update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "howhear update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -126,6 +128,10 @@ func (impl *HowHearAboutUsItemControllerImpl) UpdateByID(ctx context.Context, re
 
 		//
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeHowHearAboutUsItem, hh.ID, &before, hh); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -136,7 +142,7 @@ func (impl *HowHearAboutUsItemControllerImpl) UpdateByID(ctx context.Context, re
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
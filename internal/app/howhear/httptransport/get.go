@@ -30,7 +30,7 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request, id string) {
 
 func MarshalDetailResponse(res *howhear_s.HowHearAboutUsItem, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -41,7 +41,7 @@ func (h *Handler) ListAsSelectOptions(w http.ResponseWriter, r *http.Request) {
 
 func MarshalListAsSelectOptionResponse(res []*howhear_s.HowHearAboutUsItemAsSelectOption, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -46,7 +46,7 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 
 func MarshalUpdateResponse(res *howhear_s.HowHearAboutUsItem, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	idempotencykey_s "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// The duration after which a key still processing is considered abandoned,
+// ex: the server crashed, so a retry may take it over.
+const idempotencyKeyLockTimeout = 10 * time.Minute
+
+func (impl *IdempotencyKeyControllerImpl) Begin(ctx context.Context, scope string, key string, method string, path string, requestHash string) (*idempotencykey_s.IdempotencyKey, error) {
+	// The second attempt is only made if we deleted an expired or abandoned
+	// key and must reserve it again.
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		m := &idempotencykey_s.IdempotencyKey{
+			ID:          primitive.NewObjectID(),
+			Scope:       scope,
+			Key:         key,
+			Method:      method,
+			Path:        path,
+			RequestHash: requestHash,
+			Status:      idempotencykey_s.IdempotencyKeyStatusProcessing,
+			CreatedAt:   now,
+			ModifiedAt:  now,
+			ExpiresAt:   now.Add(impl.Config.AppServer.IdempotencyKeyWindow),
+		}
+		err := impl.IdempotencyKeyStorer.Create(ctx, m)
+		if err == nil {
+			return m, nil
+		}
+		if !errors.Is(err, idempotencykey_s.ErrIdempotencyKeyExists) {
+			impl.Logger.ErrorContext(ctx, "failed reserving idempotency key", slog.Any("error", err))
+			return nil, err
+		}
+
+		existing, err := impl.IdempotencyKeyStorer.GetByScopeAndKey(ctx, scope, key)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "failed getting idempotency key", slog.Any("error", err))
+			return nil, err
+		}
+		if existing == nil { // Deleted since our insert.
+			continue
+		}
+
+		// Our database deletes the expired keys periodically so we must
+		// ignore the keys which expired in the meantime.
+		isExpired := now.After(existing.ExpiresAt)
+		isAbandoned := existing.Status == idempotencykey_s.IdempotencyKeyStatusProcessing && now.Sub(existing.ModifiedAt) > idempotencyKeyLockTimeout
+		if isExpired || isAbandoned {
+			if err := impl.IdempotencyKeyStorer.DeleteByID(ctx, existing.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if existing.RequestHash != requestHash {
+			impl.Logger.WarnContext(ctx, "idempotency key reused with a different request",
+				slog.String("scope", scope),
+				slog.String("key", key))
+			return nil, httperror.NewWithCode(http.StatusUnprocessableEntity, httperror.CodeIdempotencyKeyReused, "idempotency key was already used with a different request")
+		}
+		if existing.Status == idempotencykey_s.IdempotencyKeyStatusProcessing {
+			return nil, httperror.NewWithCode(http.StatusConflict, httperror.CodeConflict, "a request with this idempotency key is still being processed")
+		}
+		return existing, nil
+	}
+	return nil, httperror.NewWithCode(http.StatusConflict, httperror.CodeConflict, "a request with this idempotency key is still being processed")
+}
+
+func (impl *IdempotencyKeyControllerImpl) Complete(ctx context.Context, m *idempotencykey_s.IdempotencyKey, statusCode int, header http.Header, body []byte) error {
+	m.Status = idempotencykey_s.IdempotencyKeyStatusCompleted
+	m.ResponseStatusCode = statusCode
+	m.ResponseHeader = header
+	m.ResponseBody = body
+	m.ModifiedAt = time.Now()
+	if err := impl.IdempotencyKeyStorer.UpdateByID(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed saving idempotency key response", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl *IdempotencyKeyControllerImpl) Release(ctx context.Context, m *idempotencykey_s.IdempotencyKey) error {
+	if err := impl.IdempotencyKeyStorer.DeleteByID(ctx, m.ID); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed releasing idempotency key", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
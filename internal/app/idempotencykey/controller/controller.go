@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	idempotencykey_s "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// IdempotencyKeyController Interface for the idempotency key business logic
+// controller.
+type IdempotencyKeyController interface {
+	// Begin function reserves the key for the request. If the key was used
+	// before by the same request then the completed record is returned so
+	// the caller replays the response instead of running the request again.
+	Begin(ctx context.Context, scope string, key string, method string, path string, requestHash string) (*idempotencykey_s.IdempotencyKey, error)
+
+	// Complete function saves the response of the reserved key.
+	Complete(ctx context.Context, m *idempotencykey_s.IdempotencyKey, statusCode int, header http.Header, body []byte) error
+
+	// Release function deletes the reserved key so the request may be
+	// retried, ex: after an internal error.
+	Release(ctx context.Context, m *idempotencykey_s.IdempotencyKey) error
+}
+
+type IdempotencyKeyControllerImpl struct {
+	Config               *config.Conf
+	Logger               *slog.Logger
+	IdempotencyKeyStorer idempotencykey_s.IdempotencyKeyStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	idempotencykey_storer idempotencykey_s.IdempotencyKeyStorer,
+) IdempotencyKeyController {
+	s := &IdempotencyKeyControllerImpl{
+		Config:               appCfg,
+		Logger:               loggerp,
+		IdempotencyKeyStorer: idempotencykey_storer,
+	}
+	s.Logger.Debug("idempotency key controller initialization started...")
+	s.Logger.Debug("idempotency key controller initialized")
+	return s
+}
@@ -0,0 +1,27 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (impl IdempotencyKeyStorerImpl) Create(ctx context.Context, m *IdempotencyKey) error {
+	if m.ID == primitive.NilObjectID {
+		m.ID = primitive.NewObjectID()
+		impl.Logger.Warn("database insert idempotency key not included id value, created id now.", slog.Any("id", m.ID))
+	}
+
+	// Our unique index on the scope and key guarantees only one of the
+	// concurrent requests with the same key is processed.
+	if _, err := impl.Collection.InsertOne(ctx, m); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrIdempotencyKeyExists
+		}
+		impl.Logger.Error("database insert error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
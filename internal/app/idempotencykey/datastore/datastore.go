@@ -0,0 +1,88 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	IdempotencyKeyStatusProcessing = 1
+	IdempotencyKeyStatusCompleted  = 2
+)
+
+// ErrIdempotencyKeyExists is returned by `Create` if the key was already
+// used within the same scope.
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+// IdempotencyKey represents an `Idempotency-Key` header sent by a client
+// along with the response we returned so retries of the same request can be
+// answered without running the request again.
+type IdempotencyKey struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+
+	// Scope is who sent the key, ex: `user:<id>` or `ip:<address>`, so
+	// different clients using the same key do not conflict.
+	Scope string `bson:"scope" json:"scope"`
+	Key   string `bson:"key" json:"key"`
+
+	Method      string `bson:"method" json:"method"`
+	Path        string `bson:"path" json:"path"`
+	RequestHash string `bson:"request_hash" json:"request_hash"`
+	Status      int8   `bson:"status" json:"status"`
+
+	ResponseStatusCode int                 `bson:"response_status_code" json:"response_status_code"`
+	ResponseHeader     map[string][]string `bson:"response_header" json:"response_header"`
+	ResponseBody       []byte              `bson:"response_body" json:"response_body"`
+
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	ModifiedAt time.Time `bson:"modified_at" json:"modified_at"`
+
+	// ExpiresAt is when the database automatically deletes the record.
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// IdempotencyKeyStorer Interface for idempotency keys.
+type IdempotencyKeyStorer interface {
+	Create(ctx context.Context, m *IdempotencyKey) error
+	GetByScopeAndKey(ctx context.Context, scope string, key string) (*IdempotencyKey, error)
+	UpdateByID(ctx context.Context, m *IdempotencyKey) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+type IdempotencyKeyStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) IdempotencyKeyStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("idempotency_keys")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "scope", Value: 1}, {Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &IdempotencyKeyStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,25 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (impl IdempotencyKeyStorerImpl) GetByScopeAndKey(ctx context.Context, scope string, key string) (*IdempotencyKey, error) {
+	filter := bson.D{{"scope", scope}, {"key", key}}
+
+	var result IdempotencyKey
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by scope and key error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
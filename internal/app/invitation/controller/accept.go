@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// InvitationDetailResponseIDO is the publicly visible details of the
+// invitation which are shown to the invitee before they accept.
+type InvitationDetailResponseIDO struct {
+	Email      string    `json:"email"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	TenantName string    `json:"tenant_name"`
+	Role       int8      `json:"role"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type InvitationAcceptRequestIDO struct {
+	Token                string `json:"token"`
+	FirstName            string `json:"first_name"`
+	LastName             string `json:"last_name"`
+	Password             string `json:"password"`
+	PasswordRepeated     string `json:"password_repeated"`
+	Phone                string `json:"phone,omitempty"`
+	AgreeTOS             bool   `json:"agree_tos,omitempty"`
+	AgreePromotionsEmail bool   `json:"agree_promotions_email,omitempty"`
+}
+
+// getPendingByToken function returns the invitation for the token or an
+// error if the token is invalid, expired or the invitation is not pending.
+func (impl *InvitationControllerImpl) getPendingByToken(ctx context.Context, token string) (*invitation_s.Invitation, error) {
+	id, nonce, err := impl.parseToken(token)
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "invalid invitation token", slog.Any("error", err))
+		return nil, httperror.NewForBadRequestWithSingleField("token", "invitation is invalid or expired")
+	}
+
+	m, err := impl.InvitationStorer.GetByID(ctx, id)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if m == nil || m.Nonce != nonce || m.IsExpired() {
+		return nil, httperror.NewForBadRequestWithSingleField("token", "invitation is invalid or expired")
+	}
+	if m.Status != invitation_s.InvitationStatusPending {
+		return nil, httperror.NewForBadRequestWithSingleField("token", "invitation is no longer pending")
+	}
+	return m, nil
+}
+
+func (impl *InvitationControllerImpl) GetByToken(ctx context.Context, token string) (*InvitationDetailResponseIDO, error) {
+	m, err := impl.getPendingByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &InvitationDetailResponseIDO{
+		Email:      m.Email,
+		FirstName:  m.FirstName,
+		LastName:   m.LastName,
+		TenantName: m.TenantName,
+		Role:       m.Role,
+		ExpiresAt:  m.ExpiresAt,
+	}, nil
+}
+
+func validateAcceptRequest(dirtyData *InvitationAcceptRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.Token == "" {
+		e["token"] = "missing value"
+	}
+	if dirtyData.FirstName == "" {
+		e["first_name"] = "missing value"
+	}
+	if dirtyData.LastName == "" {
+		e["last_name"] = "missing value"
+	}
+	if dirtyData.Password == "" {
+		e["password"] = "missing value"
+	}
+	if dirtyData.PasswordRepeated != dirtyData.Password {
+		e["password"] = "does not match"
+		e["password_repeated"] = "does not match"
+	}
+	if dirtyData.AgreeTOS == false {
+		e["agree_tos"] = "you must agree to the terms before proceeding"
+	}
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+// Accept function creates the user account for the invitee with the role
+// assigned in the invitation. The email is considered verified because the
+// invitee received the link by email.
+func (impl *InvitationControllerImpl) Accept(ctx context.Context, req *InvitationAcceptRequestIDO) (*user_s.User, error) {
+	req.Password = strings.ReplaceAll(req.Password, " ", "")
+	req.PasswordRepeated = strings.ReplaceAll(req.PasswordRepeated, " ", "")
+	req.FirstName = strings.TrimSpace(req.FirstName)
+	req.LastName = strings.TrimSpace(req.LastName)
+
+	if err := validateAcceptRequest(req); err != nil {
+		return nil, err
+	}
+	id, _, err := impl.parseToken(req.Token)
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "invalid invitation token", slog.Any("error", err))
+		return nil, httperror.NewForBadRequestWithSingleField("token", "invitation is invalid or expired")
+	}
+
+	impl.Kmutex.Lockf("invitation-%s", id.Hex())
+	defer impl.Kmutex.Unlockf("invitation-%s", id.Hex())
+
+	////
+	//// Start the transaction.
+	////
+
+	session, err := impl.DbClient.StartSession()
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "start session error",
+			slog.Any("error", err))
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	// Define a transaction function with a series of operations
+	transactionFunc := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		m, err := impl.getPendingByToken(sessCtx, req.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		// Lookup the user in our database, else return a `400 Bad Request` error.
+		u, err := impl.UserStorer.GetByEmail(sessCtx, m.Email)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+			return nil, err
+		}
+		if u != nil {
+			impl.Logger.WarnContext(ctx, "user already exists validation error", slog.String("email", m.Email))
+			return nil, httperror.NewForBadRequestWithSingleField("email", "email is not unique")
+		}
+
+		// Lookup the tenant in our database, else return a `400 Bad Request` error.
+		t, err := impl.TenantStorer.GetByID(sessCtx, m.TenantID)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+			return nil, err
+		}
+		if t == nil || t.Status != tenant_s.TenantActiveStatus {
+			impl.Logger.WarnContext(ctx, "tenant does not exist validation error")
+			return nil, httperror.NewForBadRequestWithSingleField("token", "organization is no longer active")
+		}
+
+		return impl.createUserForInvitation(sessCtx, m, t, req)
+	}
+
+	// Start a transaction
+	res, err := session.WithTransaction(ctx, transactionFunc)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "session failed error",
+			slog.Any("error", err))
+		return nil, err
+	}
+	return res.(*user_s.User), nil
+}
+
+func (impl *InvitationControllerImpl) createUserForInvitation(sessCtx mongo.SessionContext, m *invitation_s.Invitation, t *tenant_s.Tenant, req *InvitationAcceptRequestIDO) (*user_s.User, error) {
+	// Hash the password for security purposes.
+	passwordHash, err := impl.Password.GenerateHashFromPassword(req.Password)
+	if err != nil {
+		impl.Logger.Error("hashing error", slog.Any("error", err))
+		return nil, err
+	}
+
+	ipAddress, _ := sessCtx.Value(constants.SessionIPAddress).(string)
+	userID := primitive.NewObjectID()
+	userName := fmt.Sprintf("%s %s", req.FirstName, req.LastName)
+
+	u := &user_s.User{
+		ID:                    userID,
+		TenantID:              t.ID,
+		TenantName:            t.Name,
+		TenantType:            tenant_s.RetailerType,
+		FirstName:             req.FirstName,
+		LastName:              req.LastName,
+		Name:                  userName,
+		LexicalName:           fmt.Sprintf("%s, %s", req.LastName, req.FirstName),
+		Email:                 m.Email,
+		Phone:                 req.Phone,
+		PasswordHash:          passwordHash,
+		PasswordHashAlgorithm: impl.Password.AlgorithmName(),
+		Role:                  m.Role,
+		AgreeTOS:              req.AgreeTOS,
+		TOSVersion:            "January, 2024",
+		TOSAgreedOn:           time.Now(),
+		AgreePromotionsEmail:  req.AgreePromotionsEmail,
+		CreatedAt:             time.Now(),
+		CreatedByUserID:       m.CreatedByUserID,
+		CreatedByUserName:     m.CreatedByUserName,
+		CreatedFromIPAddress:  ipAddress,
+		ModifiedAt:            time.Now(),
+		ModifiedByUserID:      userID,
+		ModifiedByUserName:    userName,
+		ModifiedFromIPAddress: ipAddress,
+		WasEmailVerified:      true,
+		Status:                user_s.UserStatusActive,
+		Timezone:              t.Timezone,
+		JoinedTime:            time.Now(),
+		Coupons:               make([]*user_s.UserClaimedCoupon, 0),
+	}
+	if err := impl.UserStorer.Create(sessCtx, u); err != nil {
+		impl.Logger.Error("database create error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeUser, u.ID, nil, u); err != nil {
+		return nil, err
+	}
+
+	before := *m
+	m.Status = invitation_s.InvitationStatusAccepted
+	m.AcceptedAt = time.Now()
+	m.AcceptedUserID = u.ID
+	m.ModifiedAt = time.Now()
+	m.ModifiedByUserID = u.ID
+	m.ModifiedByUserName = u.Name
+	m.ModifiedFromIPAddress = ipAddress
+	if err := impl.InvitationStorer.UpdateByID(sessCtx, m); err != nil {
+		impl.Logger.Error("database update error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeInvitation, m.ID, &before, m); err != nil {
+		return nil, err
+	}
+	impl.Logger.Info("Invitation accepted.",
+		slog.Any("tenant_id", u.TenantID),
+		slog.Any("invitation_id", m.ID),
+		slog.Any("user_id", u.ID))
+
+	return u, nil
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
+	"github.com/bartmika/databoutique-backend/internal/provider/password"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+// InvitationController Interface for invitation business logic controller.
+type InvitationController interface {
+	Create(ctx context.Context, requestData *InvitationCreateRequestIDO) (*invitation_s.Invitation, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*invitation_s.Invitation, error)
+	ListPending(ctx context.Context) (*invitation_s.InvitationListResult, error)
+	ResendByID(ctx context.Context, id primitive.ObjectID) (*invitation_s.Invitation, error)
+	RevokeByID(ctx context.Context, id primitive.ObjectID) error
+	GetByToken(ctx context.Context, token string) (*InvitationDetailResponseIDO, error)
+	Accept(ctx context.Context, requestData *InvitationAcceptRequestIDO) (*user_s.User, error)
+}
+
+type InvitationControllerImpl struct {
+	Config           *config.Conf
+	Logger           *slog.Logger
+	UUID             uuid.Provider
+	Password         password.Provider
+	Kmutex           kmutex.Provider
+	DbClient         *mongo.Client
+	TemplatedEmailer templatedemailer.TemplatedEmailer
+	UserStorer       user_s.UserStorer
+	TenantStorer     tenant_s.TenantStorer
+	InvitationStorer invitation_s.InvitationStorer
+	AuditEvent       auditevent_c.AuditEventController
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	uuidp uuid.Provider,
+	passwordp password.Provider,
+	kmux kmutex.Provider,
+	client *mongo.Client,
+	te templatedemailer.TemplatedEmailer,
+	usr_storer user_s.UserStorer,
+	tenant_storer tenant_s.TenantStorer,
+	invitation_storer invitation_s.InvitationStorer,
+	auditevent auditevent_c.AuditEventController,
+) InvitationController {
+	s := &InvitationControllerImpl{
+		Config:           appCfg,
+		Logger:           loggerp,
+		UUID:             uuidp,
+		Password:         passwordp,
+		Kmutex:           kmux,
+		DbClient:         client,
+		TemplatedEmailer: te,
+		UserStorer:       usr_storer,
+		TenantStorer:     tenant_storer,
+		InvitationStorer: invitation_storer,
+		AuditEvent:       auditevent,
+	}
+	s.Logger.Debug("invitation controller initialization started...")
+	s.Logger.Debug("invitation controller initialized")
+	return s
+}
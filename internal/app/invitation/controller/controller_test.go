@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
+	"github.com/bartmika/databoutique-backend/internal/provider/password"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+// The fakes below keep their records in memory. The methods not overridden
+// panic since the embedded interfaces are nil.
+
+type fakeInvitationStorer struct {
+	invitation_s.InvitationStorer
+	invitations map[primitive.ObjectID]*invitation_s.Invitation
+}
+
+func (s *fakeInvitationStorer) Create(ctx context.Context, m *invitation_s.Invitation) error {
+	s.invitations[m.ID] = m
+	return nil
+}
+
+func (s *fakeInvitationStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*invitation_s.Invitation, error) {
+	return s.invitations[id], nil
+}
+
+func (s *fakeInvitationStorer) GetPendingByTenantIDAndEmail(ctx context.Context, tid primitive.ObjectID, email string) (*invitation_s.Invitation, error) {
+	for _, m := range s.invitations {
+		if m.TenantID == tid && m.Email == email && m.Status == invitation_s.InvitationStatusPending {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeInvitationStorer) UpdateByID(ctx context.Context, m *invitation_s.Invitation) error {
+	s.invitations[m.ID] = m
+	return nil
+}
+
+func newTestController() (*InvitationControllerImpl, *fakeInvitationStorer, *fakes.TemplatedEmailer) {
+	is := &fakeInvitationStorer{invitations: make(map[primitive.ObjectID]*invitation_s.Invitation)}
+	te := &fakes.TemplatedEmailer{}
+	impl := &InvitationControllerImpl{
+		Config:           fakes.NewConfig(),
+		Logger:           fakes.NewLogger(),
+		UUID:             uuid.NewProvider(),
+		Password:         password.NewProvider(),
+		Kmutex:           kmutex.NewProvider(),
+		TemplatedEmailer: te,
+		UserStorer:       fakes.NewUserStorer(),
+		InvitationStorer: is,
+		AuditEvent:       &fakes.AuditEvent{},
+	}
+	return impl, is, te
+}
+
+func newAdministratorContext(tid primitive.ObjectID) context.Context {
+	ctx := context.WithValue(context.Background(), constants.SessionUserTenantID, tid)
+	ctx = context.WithValue(ctx, constants.SessionUserTenantName, "Acme")
+	ctx = context.WithValue(ctx, constants.SessionUserID, primitive.NewObjectID())
+	ctx = context.WithValue(ctx, constants.SessionUserRole, int8(user_s.UserRoleManagement))
+	return ctx
+}
+
+func TestToken(t *testing.T) {
+	impl, _, _ := newTestController()
+	m := &invitation_s.Invitation{ID: primitive.NewObjectID(), Nonce: "nonce", ExpiresAt: time.Now().Add(time.Hour)}
+
+	token := impl.generateToken(m)
+	id, nonce, err := impl.parseToken(token)
+	if err != nil || id != m.ID || nonce != "nonce" {
+		t.Fatalf("expected the token to be valid but got %v %v %v", id, nonce, err)
+	}
+
+	// The expiry and nonce are covered by the signature.
+	parts := strings.Split(token, ".")
+	for _, tampered := range []string{
+		strings.Join([]string{parts[0], "other", parts[2], parts[3]}, "."),
+		strings.Join([]string{parts[0], parts[1], "9999999999", parts[3]}, "."),
+		strings.Join(parts[:3], "."),
+		token + "x",
+	} {
+		if _, _, err := impl.parseToken(tampered); err != ErrInvalidInvitationToken {
+			t.Fatalf("expected tampered token %q to be invalid but got %v", tampered, err)
+		}
+	}
+
+	// Tokens signed with another secret are rejected.
+	other, _, _ := newTestController()
+	other.Config.AppServer.HMACSecret = []byte("other-secret")
+	if _, _, err := other.parseToken(token); err != ErrInvalidInvitationToken {
+		t.Fatalf("expected token of another secret to be invalid but got %v", err)
+	}
+
+	// Expired tokens are rejected.
+	m.ExpiresAt = time.Now().Add(-time.Second)
+	if _, _, err := impl.parseToken(impl.generateToken(m)); err != ErrInvalidInvitationToken {
+		t.Fatalf("expected expired token to be invalid but got %v", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	impl, is, te := newTestController()
+	tenantA, tenantB := primitive.NewObjectID(), primitive.NewObjectID()
+
+	m, err := impl.Create(newAdministratorContext(tenantA), &InvitationCreateRequestIDO{Email: "Bob@Example.com", FirstName: "Bob", Role: user_s.UserRoleStaff})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if m.Email != "bob@example.com" || m.TenantID != tenantA || m.IsSendFailed || len(te.Tokens) != 1 {
+		t.Fatalf("unexpected invitation %+v", m)
+	}
+
+	// Inviting the same email again in the same tenant is rejected.
+	if _, err := impl.Create(newAdministratorContext(tenantA), &InvitationCreateRequestIDO{Email: "bob@example.com", Role: user_s.UserRoleStaff}); err == nil {
+		t.Fatal("expected a duplicate invitation to be rejected")
+	}
+
+	// Other tenants neither see nor revoke the invitation.
+	if _, err := impl.Create(newAdministratorContext(tenantB), &InvitationCreateRequestIDO{Email: "bob@example.com", Role: user_s.UserRoleStaff}); err != nil {
+		t.Fatalf("expected another tenant to invite the same email but got %v", err)
+	}
+	if is.invitations[m.ID].Status != invitation_s.InvitationStatusPending {
+		t.Fatal("expected the invitation of the other tenant to stay pending")
+	}
+
+	// Managers cannot invite executives.
+	if _, err := impl.Create(newAdministratorContext(tenantA), &InvitationCreateRequestIDO{Email: "eve@example.com", Role: user_s.UserRoleExecutive}); err == nil {
+		t.Fatal("expected a more privileged role to be rejected")
+	}
+}
+
+func TestCreateWhenSendFails(t *testing.T) {
+	impl, is, te := newTestController()
+	te.Err = errors.New("mailgun unavailable")
+	ctx := newAdministratorContext(primitive.NewObjectID())
+
+	m, err := impl.Create(ctx, &InvitationCreateRequestIDO{Email: "bob@example.com", Role: user_s.UserRoleStaff})
+	if err != nil {
+		t.Fatalf("expected the invitation to be kept but got %v", err)
+	}
+	if stored := is.invitations[m.ID]; stored == nil || !stored.IsSendFailed || stored.SentCount != 0 {
+		t.Fatalf("expected the send failure to be recorded but got %+v", stored)
+	}
+
+	// The administrator may resend right away once the issue is resolved.
+	te.Err = nil
+	m, err = impl.ResendByID(ctx, m.ID)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if m.IsSendFailed || m.SentCount != 1 || len(te.Tokens) != 1 {
+		t.Fatalf("expected the invitation to be sent but got %+v", m)
+	}
+}
+
+func TestAccept(t *testing.T) {
+	impl, is, te := newTestController()
+	tenant := &tenant_s.Tenant{ID: primitive.NewObjectID(), Name: "Acme", Status: tenant_s.TenantActiveStatus, Timezone: "UTC"}
+
+	m, err := impl.Create(newAdministratorContext(tenant.ID), &InvitationCreateRequestIDO{Email: "bob@example.com", Role: user_s.UserRoleStaff})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	token := te.Tokens[0]
+	if res, err := impl.GetByToken(context.Background(), token); err != nil || res.Email != "bob@example.com" {
+		t.Fatalf("expected the invitation details but got %+v %v", res, err)
+	}
+
+	sessCtx := mongo.NewSessionContext(context.Background(), nil)
+	pending, err := impl.getPendingByToken(sessCtx, token)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	u, err := impl.createUserForInvitation(sessCtx, pending, tenant, &InvitationAcceptRequestIDO{FirstName: "Bob", LastName: "Smith", Password: "secret", AgreeTOS: true})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if u.Role != user_s.UserRoleStaff || u.TenantID != tenant.ID || !u.WasEmailVerified {
+		t.Fatalf("unexpected user %+v", u)
+	}
+	if stored := is.invitations[m.ID]; stored.Status != invitation_s.InvitationStatusAccepted || stored.AcceptedUserID != u.ID {
+		t.Fatalf("expected the invitation to be accepted but got %+v", stored)
+	}
+
+	// The token cannot be used twice.
+	if _, err := impl.getPendingByToken(sessCtx, token); err == nil {
+		t.Fatal("expected an accepted invitation to be rejected")
+	}
+}
+
+func TestGetPendingByTokenAfterResend(t *testing.T) {
+	impl, is, te := newTestController()
+	ctx := newAdministratorContext(primitive.NewObjectID())
+
+	m, err := impl.Create(ctx, &InvitationCreateRequestIDO{Email: "bob@example.com", Role: user_s.UserRoleStaff})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	is.invitations[m.ID].LastSentAt = time.Now().Add(-invitationResendInterval)
+	if _, err := impl.ResendByID(ctx, m.ID); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+
+	// Resending replaces the nonce which invalidates the previous link.
+	if _, err := impl.getPendingByToken(ctx, te.Tokens[0]); err == nil {
+		t.Fatal("expected the previous link to be invalid")
+	}
+	if _, err := impl.getPendingByToken(ctx, te.Tokens[1]); err != nil {
+		t.Fatalf("expected the new link to be valid but got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// The duration the invitee has to accept the invitation.
+const invitationExpiry = 7 * 24 * time.Hour
+
+type InvitationCreateRequestIDO struct {
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Role      int8   `json:"role"`
+}
+
+func (impl *InvitationControllerImpl) validateCreateRequest(ctx context.Context, dirtyData *InvitationCreateRequestIDO) error {
+	userRole, _ := ctx.Value(constants.SessionUserRole).(int8)
+
+	e := make(map[string]string)
+
+	if dirtyData.Email == "" {
+		e["email"] = "missing value"
+	}
+	if len(dirtyData.Email) > 255 {
+		e["email"] = "too long"
+	}
+	if dirtyData.Role < user_s.UserRoleExecutive || dirtyData.Role > user_s.UserRoleCustomer {
+		e["role"] = "invalid value"
+	} else if dirtyData.Role < userRole {
+		// Defensive Code: Administrators cannot grant a role with more
+		// privileges then their own.
+		e["role"] = "you cannot invite users with more privileges then your own"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (impl *InvitationControllerImpl) Create(ctx context.Context, requestData *InvitationCreateRequestIDO) (*invitation_s.Invitation, error) {
+	//
+	// Get variables from our user authenticated session.
+	//
+
+	tid, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+	tenantName, _ := ctx.Value(constants.SessionUserTenantName).(string)
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	if err := impl.checkPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	//
+	// Perform our validation and return validation error on any issues detected.
+	//
+
+	// Defensive Code: For security purposes we need to remove all whitespaces from the email and lower the characters.
+	requestData.Email = strings.ToLower(strings.ReplaceAll(requestData.Email, " ", ""))
+
+	if err := impl.validateCreateRequest(ctx, requestData); err != nil {
+		impl.Logger.WarnContext(ctx, "validation error", slog.Any("error", err))
+		return nil, err
+	}
+
+	impl.Kmutex.Lockf("INVITATION-WITH-EMAIL-%v", requestData.Email)
+	defer impl.Kmutex.Unlockf("INVITATION-WITH-EMAIL-%v", requestData.Email)
+
+	u, err := impl.UserStorer.GetByEmail(ctx, requestData.Email)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if u != nil {
+		impl.Logger.WarnContext(ctx, "user already exists validation error", slog.String("email", requestData.Email))
+		return nil, httperror.NewForBadRequestWithSingleField("email", "a user with this email already exists")
+	}
+	// Defensive Code: Only look at the invitations of our tenant so we do not
+	// leak nor modify the invitations sent by other tenants.
+	pending, err := impl.InvitationStorer.GetPendingByTenantIDAndEmail(ctx, tid, requestData.Email)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+		return nil, err
+	}
+	if pending != nil && !pending.IsExpired() {
+		impl.Logger.WarnContext(ctx, "pending invitation already exists validation error", slog.String("email", requestData.Email))
+		return nil, httperror.NewForBadRequestWithSingleField("email", "a pending invitation for this email already exists")
+	}
+
+	m := &invitation_s.Invitation{
+		ID:                    primitive.NewObjectID(),
+		TenantID:              tid,
+		TenantName:            tenantName,
+		Email:                 requestData.Email,
+		FirstName:             requestData.FirstName,
+		LastName:              requestData.LastName,
+		Role:                  requestData.Role,
+		Nonce:                 impl.UUID.NewUUID(),
+		Status:                invitation_s.InvitationStatusPending,
+		ExpiresAt:             time.Now().Add(invitationExpiry),
+		SentCount:             1,
+		LastSentAt:            time.Now(),
+		CreatedAt:             time.Now(),
+		CreatedByUserID:       userID,
+		CreatedByUserName:     userName,
+		CreatedFromIPAddress:  ipAddress,
+		ModifiedAt:            time.Now(),
+		ModifiedByUserID:      userID,
+		ModifiedByUserName:    userName,
+		ModifiedFromIPAddress: ipAddress,
+	}
+	if err := impl.InvitationStorer.Create(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeInvitation, m.ID, nil, m); err != nil {
+		return nil, err
+	}
+
+	// Expired invitations for the same email are no longer needed.
+	if pending != nil {
+		pending.Status = invitation_s.InvitationStatusRevoked
+		pending.RevokedAt = time.Now()
+		pending.ModifiedAt = time.Now()
+		if err := impl.InvitationStorer.UpdateByID(ctx, pending); err != nil {
+			impl.Logger.ErrorContext(ctx, "database update error", slog.Any("error", err))
+			return nil, err
+		}
+	}
+
+	// The invitation is kept if the email could not be sent so the
+	// administrator can resend it once the issue is resolved.
+	if err := impl.TemplatedEmailer.SendUserInvitationEmail(m.Email, m.FirstName, m.TenantName, userName, impl.generateToken(m), m.ExpiresAt); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed sending invitation email", slog.Any("error", err))
+		if markErr := impl.markSendFailed(ctx, m); markErr != nil {
+			return nil, markErr
+		}
+	}
+	impl.Logger.InfoContext(ctx, "Invitation created.",
+		slog.Any("tenant_id", m.TenantID),
+		slog.Any("invitation_id", m.ID))
+
+	return m, nil
+}
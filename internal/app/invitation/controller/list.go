@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+)
+
+func (impl *InvitationControllerImpl) ListPending(ctx context.Context) (*invitation_s.InvitationListResult, error) {
+	tid, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+
+	if err := impl.checkPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	res, err := impl.InvitationStorer.ListPendingByTenantID(ctx, tid)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database list error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
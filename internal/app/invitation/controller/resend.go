@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// The minimum duration between sending invitation emails.
+const invitationResendInterval = time.Minute
+
+func (impl *InvitationControllerImpl) ResendByID(ctx context.Context, id primitive.ObjectID) (*invitation_s.Invitation, error) {
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	impl.Kmutex.Lockf("invitation-%s", id.Hex())
+	defer impl.Kmutex.Unlockf("invitation-%s", id.Hex())
+
+	m, err := impl.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Status != invitation_s.InvitationStatusPending {
+		return nil, httperror.NewForBadRequestWithSingleField("status", "invitation is no longer pending")
+	}
+	if !m.IsSendFailed && time.Since(m.LastSentAt) < invitationResendInterval {
+		impl.Logger.WarnContext(ctx, "invitation resend throttled", slog.Any("invitation_id", m.ID))
+		return nil, httperror.NewForSingleField(http.StatusTooManyRequests, "message", "please wait a minute before resending the invitation")
+	}
+
+	// Replacing the nonce invalidates the previously sent links.
+	m.Nonce = impl.UUID.NewUUID()
+	m.ExpiresAt = time.Now().Add(invitationExpiry)
+	m.SentCount++
+	m.LastSentAt = time.Now()
+	m.IsSendFailed = false
+	m.ModifiedAt = time.Now()
+	m.ModifiedByUserID = userID
+	m.ModifiedByUserName = userName
+	m.ModifiedFromIPAddress = ipAddress
+	if err := impl.InvitationStorer.UpdateByID(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database update error", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := impl.TemplatedEmailer.SendUserInvitationEmail(m.Email, m.FirstName, m.TenantName, userName, impl.generateToken(m), m.ExpiresAt); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed sending invitation email", slog.Any("error", err))
+		if markErr := impl.markSendFailed(ctx, m); markErr != nil {
+			return nil, markErr
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// markSendFailed function records the invitation email could not be sent so
+// the administrator sees it and may resend it without waiting.
+func (impl *InvitationControllerImpl) markSendFailed(ctx context.Context, m *invitation_s.Invitation) error {
+	m.IsSendFailed = true
+	m.SentCount--
+	if err := impl.InvitationStorer.UpdateByID(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database update error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
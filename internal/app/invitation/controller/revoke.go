@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (impl *InvitationControllerImpl) RevokeByID(ctx context.Context, id primitive.ObjectID) error {
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	impl.Kmutex.Lockf("invitation-%s", id.Hex())
+	defer impl.Kmutex.Unlockf("invitation-%s", id.Hex())
+
+	m, err := impl.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if m.Status != invitation_s.InvitationStatusPending {
+		return httperror.NewForBadRequestWithSingleField("status", "invitation is no longer pending")
+	}
+	before := *m
+
+	m.Status = invitation_s.InvitationStatusRevoked
+	m.RevokedAt = time.Now()
+	m.ModifiedAt = time.Now()
+	m.ModifiedByUserID = userID
+	m.ModifiedByUserName = userName
+	m.ModifiedFromIPAddress = ipAddress
+	if err := impl.InvitationStorer.UpdateByID(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database update error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeInvitation, m.ID, &before, m); err != nil {
+		return err
+	}
+	impl.Logger.InfoContext(ctx, "Invitation revoked.",
+		slog.Any("tenant_id", m.TenantID),
+		slog.Any("invitation_id", m.ID))
+	return nil
+}
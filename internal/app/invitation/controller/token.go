@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+)
+
+var ErrInvalidInvitationToken = errors.New("invalid invitation token")
+
+// generateToken function returns the token used in the invitation link. The
+// format is `<id>.<nonce>.<expiry>.<signature>` where the signature is an
+// HMAC so the token cannot be forged nor have its expiry modified. Please
+// note the nonce is replaced on resend which invalidates older links.
+func (impl *InvitationControllerImpl) generateToken(m *invitation_s.Invitation) string {
+	payload := fmt.Sprintf("%s.%s.%d", m.ID.Hex(), m.Nonce, m.ExpiresAt.Unix())
+	return payload + "." + impl.sign(payload)
+}
+
+// parseToken function verifies the signature and expiry of the token and
+// returns the invitation ID and nonce.
+func (impl *InvitationControllerImpl) parseToken(token string) (primitive.ObjectID, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return primitive.NilObjectID, "", ErrInvalidInvitationToken
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(impl.sign(payload)), []byte(parts[3])) {
+		return primitive.NilObjectID, "", ErrInvalidInvitationToken
+	}
+
+	id, err := primitive.ObjectIDFromHex(parts[0])
+	if err != nil {
+		return primitive.NilObjectID, "", ErrInvalidInvitationToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return primitive.NilObjectID, "", ErrInvalidInvitationToken
+	}
+	if time.Now().After(time.Unix(expiresAt, 0)) {
+		return primitive.NilObjectID, "", ErrInvalidInvitationToken
+	}
+	return id, parts[1], nil
+}
+
+func (impl *InvitationControllerImpl) sign(payload string) string {
+	mac := hmac.New(sha256.New, impl.Config.AppServer.HMACSecret)
+	mac.Write([]byte("invitation:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,93 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	InvitationStatusPending  = 1
+	InvitationStatusAccepted = 2
+	InvitationStatusRevoked  = 3
+)
+
+type Invitation struct {
+	ID                    primitive.ObjectID `bson:"_id" json:"id"`
+	TenantID              primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	TenantName            string             `bson:"tenant_name" json:"tenant_name"`
+	Email                 string             `bson:"email" json:"email"`
+	FirstName             string             `bson:"first_name" json:"first_name"`
+	LastName              string             `bson:"last_name" json:"last_name"`
+	Role                  int8               `bson:"role" json:"role"`
+	Nonce                 string             `bson:"nonce" json:"-"`
+	Status                int8               `bson:"status" json:"status"`
+	ExpiresAt             time.Time          `bson:"expires_at" json:"expires_at"`
+	SentCount             int                `bson:"sent_count" json:"sent_count"`
+	LastSentAt            time.Time          `bson:"last_sent_at" json:"last_sent_at"`
+	IsSendFailed          bool               `bson:"is_send_failed" json:"is_send_failed"`
+	AcceptedAt            time.Time          `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	AcceptedUserID        primitive.ObjectID `bson:"accepted_user_id,omitempty" json:"accepted_user_id,omitempty"`
+	RevokedAt             time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt             time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID       primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id,omitempty"`
+	CreatedByUserName     string             `bson:"created_by_user_name" json:"created_by_user_name"`
+	CreatedFromIPAddress  string             `bson:"created_from_ip_address" json:"created_from_ip_address"`
+	ModifiedAt            time.Time          `bson:"modified_at" json:"modified_at"`
+	ModifiedByUserID      primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
+	ModifiedByUserName    string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
+	ModifiedFromIPAddress string             `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+}
+
+// IsExpired function returns true if the invitation link can no longer be used.
+func (m *Invitation) IsExpired() bool {
+	return time.Now().After(m.ExpiresAt)
+}
+
+type InvitationListResult struct {
+	Results []*Invitation `json:"results"`
+}
+
+// InvitationStorer Interface for invitations.
+type InvitationStorer interface {
+	Create(ctx context.Context, m *Invitation) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Invitation, error)
+	GetPendingByTenantIDAndEmail(ctx context.Context, tid primitive.ObjectID, email string) (*Invitation, error)
+	UpdateByID(ctx context.Context, m *Invitation) error
+	ListPendingByTenantID(ctx context.Context, tid primitive.ObjectID) (*InvitationListResult, error)
+}
+
+type InvitationStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) InvitationStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("invitations")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "email", Value: 1}}},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &InvitationStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (impl InvitationStorerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*Invitation, error) {
+	filter := bson.D{{"_id", id}}
+
+	var result Invitation
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (impl InvitationStorerImpl) GetPendingByTenantIDAndEmail(ctx context.Context, tid primitive.ObjectID, email string) (*Invitation, error) {
+	filter := bson.D{{"tenant_id", tid}, {"email", email}, {"status", InvitationStatusPending}}
+
+	var result Invitation
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get pending by tenant id and email error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,29 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (impl InvitationStorerImpl) ListPendingByTenantID(ctx context.Context, tid primitive.ObjectID) (*InvitationListResult, error) {
+	filter := bson.M{"tenant_id": tid, "status": InvitationStatusPending}
+	opts := options.Find().SetSort(bson.D{{"created_at", -1}})
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list pending by tenant id error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Invitation{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database decode error", slog.Any("error", err))
+		return nil, err
+	}
+	return &InvitationListResult{Results: results}, nil
+}
@@ -0,0 +1,45 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	invitation_c "github.com/bartmika/databoutique-backend/internal/app/invitation/controller"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) GetByToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.Controller.GetByToken(ctx, r.URL.Query().Get("q"))
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+func (h *Handler) Accept(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData invitation_c.InvitationAcceptRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	res, err := h.Controller.Accept(ctx, &requestData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
@@ -0,0 +1,44 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	invitation_c "github.com/bartmika/databoutique-backend/internal/app/invitation/controller"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func UnmarshalCreateRequest(ctx context.Context, r *http.Request) (*invitation_c.InvitationCreateRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData invitation_c.InvitationCreateRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+	return &requestData, nil
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalCreateRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.Create(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	MarshalDetailResponse(res, w)
+}
@@ -0,0 +1,27 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) ResendByID(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.ResendByID(ctx, objectID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalDetailResponse(res, w)
+}
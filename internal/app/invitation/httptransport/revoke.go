@@ -0,0 +1,25 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func (h *Handler) RevokeByID(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	if err := h.Controller.RevokeByID(ctx, objectID); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log/slog"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
@@ -17,18 +18,22 @@ func (impl *ProgramControllerImpl) ArchiveByID(ctx context.Context, id primitive
 	// Lookup the program in our database, else return a `400 Bad Request` error.
 	ou, err := impl.ProgramStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if ou == nil {
-		impl.Logger.Warn("program does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "program does not exist validation error")
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
+	before := *ou
 
 	ou.Status = program_s.ProgramStatusArchived
 
 	if err := impl.ProgramStorer.UpdateByID(ctx, ou); err != nil {
-		impl.Logger.Error("program update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "program update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeProgram, ou.ID, &before, ou); err != nil {
 		return nil, err
 	}
 	return ou, nil
@@ -9,6 +9,7 @@ import (
 
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
@@ -16,6 +17,7 @@ import (
 	uploadfile_ds "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
 	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
 	"github.com/bartmika/databoutique-backend/internal/provider/password"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
@@ -40,6 +42,7 @@ type ProgramControllerImpl struct {
 	S3                    s3_storage.S3Storager
 	Password              password.Provider
 	Kmutex                kmutex.Provider
+	Background            background.Provider
 	DbClient              *mongo.Client
 	TenantStorer          tenant_s.TenantStorer
 	UserStorer            user_s.UserStorer
@@ -48,6 +51,7 @@ type ProgramControllerImpl struct {
 	ProgramStorer         program_s.ProgramStorer
 	ExecutableStorer      executable_s.ExecutableStorer
 	TemplatedEmailer      templatedemailer.TemplatedEmailer
+	AuditEvent            auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -57,6 +61,7 @@ func NewController(
 	s3 s3_storage.S3Storager,
 	passwordp password.Provider,
 	kmux kmutex.Provider,
+	bg background.Provider,
 	temailer templatedemailer.TemplatedEmailer,
 	client *mongo.Client,
 	t_storer tenant_s.TenantStorer,
@@ -65,6 +70,7 @@ func NewController(
 	uploadfile_storer uploadfile_ds.UploadFileStorer,
 	program_s program_s.ProgramStorer,
 	executable_s executable_s.ExecutableStorer,
+	auditevent auditevent_c.AuditEventController,
 ) ProgramController {
 	s := &ProgramControllerImpl{
 		Config:                appCfg,
@@ -73,6 +79,7 @@ func NewController(
 		S3:                    s3,
 		Password:              passwordp,
 		Kmutex:                kmux,
+		Background:            bg,
 		TemplatedEmailer:      temailer,
 		DbClient:              client,
 		TenantStorer:          t_storer,
@@ -81,6 +88,7 @@ func NewController(
 		UploadFileStorer:      uploadfile_storer,
 		ProgramStorer:         program_s,
 		ExecutableStorer:      executable_s,
+		AuditEvent:            auditevent,
 	}
 	s.Logger.Debug("program controller initialization started...")
 	s.Logger.Debug("program controller initialized")
@@ -8,9 +8,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -79,7 +82,7 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 	//
 
 	if err := impl.validateCreateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -87,7 +90,7 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -97,7 +100,7 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -112,7 +115,7 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 
 		uploadFolders, err := impl.UploadDirectoryStorer.ListByIDs(sessCtx, requestData.UploadDirectoryIDs)
 		if err != nil {
-			impl.Logger.Error("failed getting folders",
+			impl.Logger.ErrorContext(ctx, "failed getting folders",
 				slog.Any("upload_directory_ids", requestData.UploadDirectoryIDs),
 				slog.Any("error", err))
 			return nil, err
@@ -160,7 +163,7 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 				}
 				dirfiles, err := impl.UploadFileStorer.ListByUploadDirectoryID(ctx, folder.ID)
 				if err != nil {
-					impl.Logger.Error("failed getting files within folder",
+					impl.Logger.ErrorContext(ctx, "failed getting files within folder",
 						slog.Any("upload_directory_id", folder.ID),
 						slog.Any("error", err))
 					return nil, err
@@ -181,7 +184,11 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 
 		// Save to our database.
 		if err := impl.ProgramStorer.Create(sessCtx, prog); err != nil {
-			impl.Logger.Error("database create error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+			return nil, err
+		}
+
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeProgram, prog.ID, nil, prog); err != nil {
 			return nil, err
 		}
 
@@ -195,7 +202,7 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -208,14 +215,24 @@ func (impl *ProgramControllerImpl) Create(ctx context.Context, requestData *Prog
 	////
 
 	// Submit the following into the background of this web-application.
-	// This function will run independently of this function call.
-	go func(p *program_s.Program) {
-		if p.BusinessFunction == program_s.ProgramBusinessFunctionAdmintorDocumentReview {
-			if err := impl.createProgramInBackgroundForOpenAI(p); err != nil {
-				impl.Logger.Error("failed submitting to openai", slog.Any("error", err))
+	// This function will run independently of this function call. Please
+	// note we detach from the request cancellation but keep its values so
+	// the background logs and spans belong to this API call.
+	err = impl.Background.Go(ctx, "program.create.openai", func(jobCtx context.Context) {
+		bgCtx, span := tracing.Start(jobCtx, "program.create.openai")
+		defer span.End()
+		metrics.IncBackgroundJob("program_openai")
+		defer metrics.DecBackgroundJob("program_openai")
+		if prog.BusinessFunction == program_s.ProgramBusinessFunctionAdmintorDocumentReview {
+			if err := impl.createProgramInBackgroundForOpenAI(bgCtx, prog); err != nil {
+				impl.Logger.ErrorContext(bgCtx, "failed submitting to openai", slog.Any("error", err))
+				span.RecordError(err)
 			}
 		}
-	}(prog)
+	})
+	if err != nil {
+		impl.Logger.WarnContext(ctx, "openai job not started", slog.Any("error", err))
+	}
 
 	return prog, nil
 }
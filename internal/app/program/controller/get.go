@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 func (impl *ProgramControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*program_s.Program, error) {
@@ -16,8 +17,11 @@ func (impl *ProgramControllerImpl) GetByID(ctx context.Context, id primitive.Obj
 	// Retrieve from our database the record for the specific id.
 	m, err := impl.ProgramStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
 	return m, err
 }
@@ -11,10 +11,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 )
 
-func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *program_s.Program) error {
-	ctx := context.Background()
+func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(ctx context.Context, prog *program_s.Program) error {
 
 	// Lock this program until OpenAI finishes executing.
 	impl.Kmutex.Lockf("openai_program_%s", prog.ID.Hex())
@@ -26,7 +26,7 @@ func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *prog
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return err
 	}
@@ -41,7 +41,7 @@ func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *prog
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, prog.TenantID)
 		if err != nil {
-			impl.Logger.Error("failed getting openai credentials",
+			impl.Logger.ErrorContext(ctx, "failed getting openai credentials",
 				slog.Any("program_id", prog.ID),
 				slog.Any("error", err))
 			return nil, err
@@ -50,9 +50,9 @@ func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *prog
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("openai initializing...")
-		client := openai.NewOrgClient(creds.APIKey, creds.OrgKey)
-		impl.Logger.Debug("openai initialized")
+		impl.Logger.DebugContext(ctx, "openai initializing...")
+		client := metrics.NewOpenAIOrgClient(creds.APIKey, creds.OrgKey)
+		impl.Logger.DebugContext(ctx, "openai initialized")
 
 		// Get all the files which we will pre-train the LLM.
 		fileIDs := prog.GetOpenAIFileIDs()
@@ -61,11 +61,11 @@ func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *prog
 		//// Create assistant.
 		////
 
-		impl.Logger.Debug("beginning to create assistant...",
+		impl.Logger.DebugContext(ctx, "beginning to create assistant...",
 			slog.Any("program_id", prog.ID))
 
 		pname := fmt.Sprintf("program_%s", prog.ID.Hex())
-		assistant, err := client.CreateAssistant(context.Background(), openai.AssistantRequest{
+		assistant, err := client.CreateAssistant(ctx, openai.AssistantRequest{
 			Name:         &pname,
 			Model:        prog.Model,
 			Instructions: &prog.Instructions,
@@ -73,7 +73,7 @@ func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *prog
 			FileIDs:      fileIDs,
 		})
 		if err != nil {
-			impl.Logger.Error("failed creating assistant",
+			impl.Logger.ErrorContext(ctx, "failed creating assistant",
 				slog.Any("program_id", prog.ID),
 				slog.Any("name", assistant.Name),
 				slog.Any("model", assistant.Model),
@@ -84,24 +84,37 @@ func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *prog
 			return nil, err
 		}
 		if isStructEmpty(assistant) {
-			impl.Logger.Error("no openai assistant returned",
+			impl.Logger.ErrorContext(ctx, "no openai assistant returned",
 				slog.Any("program_id", prog.ID),
 				slog.Any("assistant", assistant))
 			return "", errors.New("no openai file returned")
 		}
 
-		prog.OpenAIAssistantID = assistant.ID
-
-		impl.Logger.Debug("finished creating assistant",
+		impl.Logger.DebugContext(ctx, "finished creating assistant",
 			slog.Any("program_id", prog.ID),
 			slog.Any("assistant_id", assistant.ID))
 
+		// Reload the program as it may have been updated while we were
+		// waiting on OpenAI, our update would fail otherwise.
+		latest, err := impl.ProgramStorer.GetByID(sessCtx, prog.ID)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "failed getting program",
+				slog.Any("program_id", prog.ID),
+				slog.Any("error", err))
+			return nil, err
+		}
+		if latest == nil {
+			return nil, fmt.Errorf("program was deleted: %v", prog.ID.Hex())
+		}
+		*prog = *latest
+		prog.OpenAIAssistantID = assistant.ID
+
 		// Set the status that this executive is active.
 		prog.Status = program_s.ProgramStatusActive
 
 		// Update the program.
 		if err := impl.ProgramStorer.UpdateByID(sessCtx, prog); err != nil {
-			impl.Logger.Error("failed updating program",
+			impl.Logger.ErrorContext(ctx, "failed updating program",
 				slog.Any("program_id", prog.ID),
 				slog.Any("error", err))
 			return nil, err
@@ -116,7 +129,7 @@ func (impl *ProgramControllerImpl) createProgramInBackgroundForOpenAI(prog *prog
 
 	// Start a transaction
 	if _, err := session.WithTransaction(ctx, transactionFunc); err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return err
 	}
@@ -9,10 +9,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type ProgramUpdateRequestIDO struct {
@@ -58,7 +60,7 @@ func (impl *ProgramControllerImpl) UpdateByID(ctx context.Context, requestData *
 	//
 
 	if err := impl.validateUpdateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -78,7 +80,7 @@ func (impl *ProgramControllerImpl) UpdateByID(ctx context.Context, requestData *
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -88,7 +90,7 @@ func (impl *ProgramControllerImpl) UpdateByID(ctx context.Context, requestData *
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -104,13 +106,18 @@ func (impl *ProgramControllerImpl) UpdateByID(ctx context.Context, requestData *
 		// Lookup the program in our database, else return a `400 Bad Request` error.
 		prog, err := impl.ProgramStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if prog == nil {
-			impl.Logger.Warn("program does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "program does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, prog.Version); err != nil {
+			return nil, err
+		}
+		before := *prog
 
 		////
 		//// Update primary record.
@@ -131,7 +138,7 @@ func (impl *ProgramControllerImpl) UpdateByID(ctx context.Context, requestData *
 		prog.BusinessFunction = requestData.BusinessFunction
 
 		if err := impl.ProgramStorer.UpdateByID(sessCtx, prog); err != nil {
-			impl.Logger.Error("program update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "program update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -141,6 +148,10 @@ func (impl *ProgramControllerImpl) UpdateByID(ctx context.Context, requestData *
 
 		//
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeProgram, prog.ID, &before, prog); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -151,7 +162,7 @@ func (impl *ProgramControllerImpl) UpdateByID(ctx context.Context, requestData *
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
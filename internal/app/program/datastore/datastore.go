@@ -48,6 +48,7 @@ type Program struct {
 	ModifiedByUserID      primitive.ObjectID    `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
 	ModifiedByUserName    string                `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedFromIPAddress string                `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version               int64                 `bson:"version" json:"version"`
 }
 
 type UploadFolderOption struct {
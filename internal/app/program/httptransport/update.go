@@ -8,6 +8,7 @@ import (
 	program_c "github.com/bartmika/databoutique-backend/internal/app/program/controller"
 	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func UnmarshalUpdateRequest(ctx context.Context, r *http.Request) (*program_c.ProgramUpdateRequestIDO, error) {
@@ -41,12 +42,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(res.Version))
 	MarshalUpdateResponse(res, w)
 }
 
 func MarshalUpdateResponse(res *program_s.Program, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -9,6 +9,7 @@ import (
 
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	programcategory_s "github.com/bartmika/databoutique-backend/internal/app/programcategory/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
@@ -40,6 +41,7 @@ type ProgramCategoryControllerImpl struct {
 	UserStorer               user_s.UserStorer
 	ProgramCategoryStorer programcategory_s.ProgramCategoryStorer
 	TemplatedEmailer         templatedemailer.TemplatedEmailer
+	AuditEvent               auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -53,6 +55,7 @@ func NewController(
 	client *mongo.Client,
 	usr_storer user_s.UserStorer,
 	programcategory_s programcategory_s.ProgramCategoryStorer,
+	auditevent auditevent_c.AuditEventController,
 ) ProgramCategoryController {
 	s := &ProgramCategoryControllerImpl{
 		Config:                   appCfg,
@@ -65,6 +68,7 @@ func NewController(
 		DbClient:                 client,
 		UserStorer:               usr_storer,
 		ProgramCategoryStorer: programcategory_s,
+		AuditEvent:               auditevent,
 	}
 	s.Logger.Debug("programcategory controller initialization started...")
 	s.Logger.Debug("programcategory controller initialized")
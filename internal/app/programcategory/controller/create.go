@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	programcategory_s "github.com/bartmika/databoutique-backend/internal/app/programcategory/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
@@ -67,7 +68,7 @@ func (impl *ProgramCategoryControllerImpl) Create(ctx context.Context, requestDa
 	//
 
 	if err := impl.validateCreateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -75,7 +76,7 @@ func (impl *ProgramCategoryControllerImpl) Create(ctx context.Context, requestDa
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -85,7 +86,7 @@ func (impl *ProgramCategoryControllerImpl) Create(ctx context.Context, requestDa
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -116,7 +117,11 @@ func (impl *ProgramCategoryControllerImpl) Create(ctx context.Context, requestDa
 
 		// Save to our database.
 		if err := impl.ProgramCategoryStorer.Create(sessCtx, hh); err != nil {
-			impl.Logger.Error("database create error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+			return nil, err
+		}
+
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeProgramCategory, hh.ID, nil, hh); err != nil {
 			return nil, err
 		}
 
@@ -130,7 +135,7 @@ func (impl *ProgramCategoryControllerImpl) Create(ctx context.Context, requestDa
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
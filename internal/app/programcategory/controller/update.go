@@ -9,10 +9,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	programcategory_s "github.com/bartmika/databoutique-backend/internal/app/programcategory/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type ProgramCategoryUpdateRequestIDO struct {
@@ -44,7 +46,7 @@ func (impl *ProgramCategoryControllerImpl) UpdateByID(ctx context.Context, reque
 	//
 
 	if err := impl.validateUpdateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -61,7 +63,7 @@ func (impl *ProgramCategoryControllerImpl) UpdateByID(ctx context.Context, reque
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -71,7 +73,7 @@ func (impl *ProgramCategoryControllerImpl) UpdateByID(ctx context.Context, reque
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -87,13 +89,18 @@ func (impl *ProgramCategoryControllerImpl) UpdateByID(ctx context.Context, reque
 		// Lookup the programcategory in our database, else return a `400 Bad Request` error.
 		hh, err := impl.ProgramCategoryStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if hh == nil {
-			impl.Logger.Warn("programcategory does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "programcategory does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, hh.Version); err != nil {
+			return nil, err
+		}
+		before := *hh
 
 		////
 		//// Update primary record.
@@ -112,7 +119,7 @@ func (impl *ProgramCategoryControllerImpl) UpdateByID(ctx context.Context, reque
 		hh.SortNumber = requestData.SortNumber
 
 		if err := impl.ProgramCategoryStorer.UpdateByID(sessCtx, hh); err != nil {
-			impl.Logger.Error("programcategory update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "programcategory update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -122,6 +129,10 @@ func (impl *ProgramCategoryControllerImpl) UpdateByID(ctx context.Context, reque
 
 		//
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeProgramCategory, hh.ID, &before, hh); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -132,7 +143,7 @@ func (impl *ProgramCategoryControllerImpl) UpdateByID(ctx context.Context, reque
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
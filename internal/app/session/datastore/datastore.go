@@ -0,0 +1,101 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	SessionStatusActive  = 1
+	SessionStatusRevoked = 2
+
+	SessionRevokedReasonLogout         = "logout"
+	SessionRevokedReasonUser           = "revoked_by_user"
+	SessionRevokedReasonPasswordChange = "password_change"
+	SessionRevokedReasonTokenReuse     = "refresh_token_reuse"
+)
+
+// Session represents a logged in device of a user. Every session is tied
+// to the session UUID which is used as the key in our cache to store the
+// authenticated user.
+type Session struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	SessionUUID string             `bson:"session_uuid" json:"-"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	UserName    string             `bson:"user_name" json:"user_name"`
+	TenantID    primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	Device      string             `bson:"device" json:"device"`
+	IPAddress   string             `bson:"ip_address" json:"ip_address"`
+	UserAgent   string             `bson:"user_agent" json:"user_agent"`
+	Status      int8               `bson:"status" json:"status"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	LastSeenAt  time.Time          `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt   time.Time          `bson:"expires_at" json:"expires_at"`
+	RevokedAt   time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+
+	// Every refresh of the session rotates the refresh token. All refresh
+	// tokens of the session share the same token family and only the most
+	// recent one is valid. If an older refresh token is used again then
+	// we assume the token was stolen and revoke the session.
+	TokenFamilyID  string `bson:"token_family_id" json:"-"`
+	RefreshTokenID string `bson:"refresh_token_id" json:"-"`
+	RevokedReason  string `bson:"revoked_reason,omitempty" json:"revoked_reason,omitempty"`
+
+	// IsCurrent is computed per request to let the user know which session
+	// belongs to the device they are currently using.
+	IsCurrent bool `bson:"-" json:"is_current"`
+}
+
+type SessionListResult struct {
+	Results []*Session `json:"results"`
+}
+
+// SessionStorer Interface for session.
+type SessionStorer interface {
+	Create(ctx context.Context, m *Session) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Session, error)
+	GetBySessionUUID(ctx context.Context, sessionUUID string) (*Session, error)
+	GetByTokenFamilyID(ctx context.Context, familyID string) (*Session, error)
+	UpdateByID(ctx context.Context, m *Session) error
+	UpdateLastSeenBySessionUUID(ctx context.Context, sessionUUID string, lastSeenAt time.Time) error
+	ListActiveByUserID(ctx context.Context, userID primitive.ObjectID) (*SessionListResult, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+type SessionStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) SessionStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("sessions")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "session_uuid", Value: 1}}},
+		{Keys: bson.D{{Key: "token_family_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &SessionStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (impl SessionStorerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*Session, error) {
+	filter := bson.D{{"_id", id}}
+
+	var result Session
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (impl SessionStorerImpl) GetBySessionUUID(ctx context.Context, sessionUUID string) (*Session, error) {
+	filter := bson.D{{"session_uuid", sessionUUID}}
+
+	var result Session
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by session uuid error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (impl SessionStorerImpl) GetByTokenFamilyID(ctx context.Context, familyID string) (*Session, error) {
+	filter := bson.D{{"token_family_id", familyID}}
+
+	var result Session
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by token family id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
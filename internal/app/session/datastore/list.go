@@ -0,0 +1,34 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (impl SessionStorerImpl) ListActiveByUserID(ctx context.Context, userID primitive.ObjectID) (*SessionListResult, error) {
+	filter := bson.M{
+		"user_id":    userID,
+		"status":     SessionStatusActive,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	opts := options.Find().SetSort(bson.D{{"last_seen_at", -1}})
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list by user id error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Session{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database decode error", slog.Any("error", err))
+		return nil, err
+	}
+	return &SessionListResult{Results: results}, nil
+}
@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (impl SessionStorerImpl) UpdateByID(ctx context.Context, m *Session) error {
+	filter := bson.D{{"_id", m.ID}}
+
+	update := bson.M{ // DEVELOPERS NOTE: https://stackoverflow.com/a/60946010
+		"$set": m,
+	}
+
+	// execute the UpdateOne() function to update the first matching document
+	_, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLastSeenBySessionUUID function will update the last seen time of
+// the session only if it was not updated in the past minute. We do this so
+// our API calls do not perform a write for every request.
+func (impl SessionStorerImpl) UpdateLastSeenBySessionUUID(ctx context.Context, sessionUUID string, lastSeenAt time.Time) error {
+	filter := bson.M{
+		"session_uuid": sessionUUID,
+		"last_seen_at": bson.M{"$lt": lastSeenAt.Add(-1 * time.Minute)},
+	}
+	update := bson.M{
+		"$set": bson.M{"last_seen_at": lastSeenAt},
+	}
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update last seen error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -7,8 +7,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
-	mg "github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer"
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	domain "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	org_d "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
@@ -22,6 +23,7 @@ type TenantController interface {
 	Create(ctx context.Context, m *domain.Tenant) (*domain.Tenant, error)
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Tenant, error)
 	UpdateByID(ctx context.Context, m *domain.Tenant) (*domain.Tenant, error)
+	UpdateOIDCByID(ctx context.Context, id primitive.ObjectID, cfg *domain.TenantOIDCConfig) (*domain.Tenant, error)
 	ListByFilter(ctx context.Context, f *domain.TenantListFilter) (*domain.TenantListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *domain.TenantListFilter) ([]*domain.TenantAsSelectOption, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
@@ -34,9 +36,10 @@ type TenantControllerImpl struct {
 	UUID         uuid.Provider
 	Kmutex       kmutex.Provider
 	S3           s3_storage.S3Storager
-	Emailer      mg.Emailer
+	Emailer      emailer.Emailer
 	DbClient     *mongo.Client
 	TenantStorer tenant_s.TenantStorer
+	AuditEvent   auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -45,9 +48,10 @@ func NewController(
 	uuidp uuid.Provider,
 	kmux kmutex.Provider,
 	s3 s3_storage.S3Storager,
-	emailer mg.Emailer,
+	emailerp emailer.Emailer,
 	client *mongo.Client,
 	org_storer tenant_s.TenantStorer,
+	auditevent auditevent_c.AuditEventController,
 ) TenantController {
 	s := &TenantControllerImpl{
 		Config:       appCfg,
@@ -55,9 +59,10 @@ func NewController(
 		UUID:         uuidp,
 		Kmutex:       kmux,
 		S3:           s3,
-		Emailer:      emailer,
+		Emailer:      emailerp,
 		DbClient:     client,
 		TenantStorer: org_storer,
+		AuditEvent:   auditevent,
 	}
 	s.Logger.Debug("Tenant controller initialization started...")
 	s.Logger.Debug("Tenant controller initialized")
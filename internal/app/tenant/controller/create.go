@@ -8,6 +8,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	s_d "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
@@ -22,7 +23,7 @@ func (c *TenantControllerImpl) Create(ctx context.Context, m *s_d.Tenant) (*s_d.
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		c.Logger.Error("authenticated user is not staff role error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
@@ -43,7 +44,10 @@ func (c *TenantControllerImpl) Create(ctx context.Context, m *s_d.Tenant) (*s_d.
 	// Save to our database.
 	err := c.TenantStorer.Create(ctx, m)
 	if err != nil {
-		c.Logger.Error("database create error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := c.AuditEvent.Record(ctx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeTenant, m.ID, nil, m); err != nil {
 		return nil, err
 	}
 
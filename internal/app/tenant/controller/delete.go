@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log/slog"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	org_d "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
@@ -19,7 +20,7 @@ func (impl *TenantControllerImpl) DeleteByID(ctx context.Context, id primitive.O
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		impl.Logger.Error("authenticated user is not staff role error",
+		impl.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
@@ -27,24 +28,28 @@ func (impl *TenantControllerImpl) DeleteByID(ctx context.Context, id primitive.O
 
 	// Update the database.
 	tenant, err := impl.GetByID(ctx, id)
-	tenant.Status = org_d.TenantArchivedStatus
 	if err != nil {
-		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return err
 	}
 	if tenant == nil {
-		impl.Logger.Error("database returns nothing from get by id")
+		impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 		return err
 	}
+	before := *tenant
+	tenant.Status = org_d.TenantArchivedStatus
 	// Security: Prevent deletion of root user(s).
 	if userRole == org_d.RootType {
-		impl.Logger.Warn("root tenant cannot be deleted error")
+		impl.Logger.WarnContext(ctx, "root tenant cannot be deleted error")
 		return httperror.NewForForbiddenWithSingleField("role", "root tenant cannot be deleted")
 	}
 
 	// Save to the database the modified tenant.
 	if err := impl.TenantStorer.UpdateByID(ctx, tenant); err != nil {
-		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeTenant, tenant.ID, &before, tenant); err != nil {
 		return err
 	}
 	return nil
@@ -18,7 +18,7 @@ func (c *TenantControllerImpl) GetByID(ctx context.Context, id primitive.ObjectI
 
 	// If user is not administrator nor belongs to the Tenant then error.
 	if userRole != user_d.UserRoleExecutive && id != userTenantID {
-		c.Logger.Error("authenticated user is not staff role nor belongs to the Tenant error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role nor belongs to the Tenant error",
 			slog.Any("userRole", userRole),
 			slog.Any("userTenantID", userTenantID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this Tenant")
@@ -27,8 +27,11 @@ func (c *TenantControllerImpl) GetByID(ctx context.Context, id primitive.ObjectI
 	// Retrieve from our database the record for the specific id.
 	m, err := c.TenantStorer.GetByID(ctx, id)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
 	return m, err
 }
@@ -18,14 +18,14 @@ func (c *TenantControllerImpl) ListByFilter(ctx context.Context, f *domain.Tenan
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		c.Logger.Error("authenticated user is not staff role error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
 	}
 
-	c.Logger.Debug("fetching Tenants now...", slog.Any("userID", userID))
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "fetching Tenants now...", slog.Any("userID", userID))
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("TenantID", f.TenantID),
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
@@ -38,10 +38,10 @@ func (c *TenantControllerImpl) ListByFilter(ctx context.Context, f *domain.Tenan
 
 	m, err := c.TenantStorer.ListByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
-	c.Logger.Debug("fetched Tenants", slog.Any("m", m))
+	c.Logger.DebugContext(ctx, "fetched Tenants", slog.Any("m", m))
 	return m, err
 }
 
@@ -52,19 +52,19 @@ func (c *TenantControllerImpl) ListAsSelectOptionByFilter(ctx context.Context, f
 
 	// Apply protection based on ownership and role.
 	if userRole != user_d.UserRoleExecutive {
-		c.Logger.Error("authenticated user is not staff role error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
 	}
 
-	c.Logger.Debug("fetching Tenants now...", slog.Any("userID", userID))
+	c.Logger.DebugContext(ctx, "fetching Tenants now...", slog.Any("userID", userID))
 
 	m, err := c.TenantStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
-	c.Logger.Debug("fetched Tenants", slog.Any("m", m))
+	c.Logger.DebugContext(ctx, "fetched Tenants", slog.Any("m", m))
 	return m, err
 }
@@ -16,13 +16,13 @@ func (c *TenantControllerImpl) CreateComment(ctx context.Context, TenantID primi
 	// Fetch the original customer.
 	s, err := c.TenantStorer.GetByID(ctx, TenantID)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
 	if s == nil {
-		c.Logger.Error("Tenant does not exist error",
+		c.Logger.ErrorContext(ctx, "Tenant does not exist error",
 			slog.Any("Tenant_id", TenantID))
-		return nil, httperror.NewForBadRequestWithSingleField("message", "Tenant does not exist")
+		return nil, httperror.NewForNotFoundWithSingleField("message", "Tenant does not exist")
 	}
 
 	// Create our comment.
@@ -45,7 +45,7 @@ func (c *TenantControllerImpl) CreateComment(ctx context.Context, TenantID primi
 
 	// Save to the database the modified customer.
 	if err := c.TenantStorer.UpdateByID(ctx, s); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
 		return nil, err
 	}
 
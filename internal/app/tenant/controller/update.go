@@ -8,10 +8,12 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	domain "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func validateUpdateRequest(dirtyData *domain.Tenant) error {
@@ -41,14 +43,19 @@ func (c *TenantControllerImpl) UpdateByID(ctx context.Context, ns *domain.Tenant
 	// Fetch the original Tenant.
 	os, err := c.TenantStorer.GetByID(ctx, ns.ID)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
 	if os == nil {
-		c.Logger.Error("Tenant does not exist error",
+		c.Logger.ErrorContext(ctx, "Tenant does not exist error",
 			slog.Any("Tenant_id", ns.ID))
-		return nil, httperror.NewForBadRequestWithSingleField("message", "Tenant does not exist")
+		return nil, httperror.NewForNotFoundWithSingleField("message", "Tenant does not exist")
 	}
+	// Return `412 precondition failed` if the record was modified since the client retrieved it.
+	if err := versioning.CheckExpectedVersion(ctx, os.Version); err != nil {
+		return nil, err
+	}
+	before := *os
 
 	// Extract from our session the following data.
 	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
@@ -58,7 +65,7 @@ func (c *TenantControllerImpl) UpdateByID(ctx context.Context, ns *domain.Tenant
 
 	// If user is not administrator nor belongs to the Tenant then error.
 	if userRole != user_d.UserRoleExecutive && os.ID != userTenantID {
-		c.Logger.Error("authenticated user is not staff role nor belongs to the Tenant error",
+		c.Logger.ErrorContext(ctx, "authenticated user is not staff role nor belongs to the Tenant error",
 			slog.Any("userRole", userRole),
 			slog.Any("userTenantID", userTenantID))
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this Tenant")
@@ -72,9 +79,23 @@ func (c *TenantControllerImpl) UpdateByID(ctx context.Context, ns *domain.Tenant
 	os.Name = ns.Name
 	os.Description = ns.Description
 
+	// DEVELOPERS NOTE: The two-factor and email verification policies of the
+	// tenant may only be changed by our executives and the tenant's
+	// management; the fields are ignored for everyone else.
+	if userRole == user_d.UserRoleExecutive || userRole == user_d.UserRoleManagement {
+		os.TwoFactorRequiredRoles = ns.TwoFactorRequiredRoles
+		os.IsEmailVerificationRequired = ns.IsEmailVerificationRequired
+	}
+
+	// DEVELOPERS NOTE: The single sign-on configuration is only changed by
+	// our administrators through `UpdateOIDCByID` so it is ignored here.
+
 	// Save to the database the modified Tenant.
 	if err := c.TenantStorer.UpdateByID(ctx, os); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := c.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeTenant, os.ID, &before, os); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	domain "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_d "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+func validateOIDCConfig(cfg *domain.TenantOIDCConfig) error {
+	e := make(map[string]string)
+
+	if cfg.IsEnabled {
+		if cfg.Issuer == "" {
+			e["issuer"] = "missing value"
+		}
+		if cfg.ClientID == "" {
+			e["client_id"] = "missing value"
+		}
+		if cfg.RedirectURL == "" {
+			e["redirect_url"] = "missing value"
+		}
+		// Executives have access to every tenant so they must never be
+		// granted by a tenant's identity provider.
+		if cfg.DefaultRole != 0 && (cfg.DefaultRole < user_d.UserRoleManagement || cfg.DefaultRole > user_d.UserRoleCustomer) {
+			e["default_role"] = "invalid value"
+		}
+		for _, m := range cfg.RoleMappings {
+			if m.ClaimValue == "" || m.Role < user_d.UserRoleManagement || m.Role > user_d.UserRoleCustomer {
+				e["role_mappings"] = "invalid value"
+			}
+		}
+	}
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+// UpdateOIDCByID function replaces the single sign-on configuration of the
+// tenant. The identity provider decides who can login and with which role so
+// only our executives and the tenant's management are allowed to change it.
+func (c *TenantControllerImpl) UpdateOIDCByID(ctx context.Context, id primitive.ObjectID, cfg *domain.TenantOIDCConfig) (*domain.Tenant, error) {
+	// Extract from our session the following data.
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userTenantID, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+	userRole, _ := ctx.Value(constants.SessionUserRole).(int8)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+
+	switch {
+	case userRole == user_d.UserRoleExecutive:
+	case userRole == user_d.UserRoleManagement && id == userTenantID:
+	default:
+		c.Logger.WarnContext(ctx, "you do not have permission to configure single sign-on",
+			slog.Any("userRole", userRole),
+			slog.Any("userTenantID", userTenantID))
+		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to configure single sign-on")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	if err := validateOIDCConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	os, err := c.TenantStorer.GetByID(ctx, id)
+	if err != nil {
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if os == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("message", "Tenant does not exist")
+	}
+	// Return `412 precondition failed` if the record was modified since the client retrieved it.
+	if err := versioning.CheckExpectedVersion(ctx, os.Version); err != nil {
+		return nil, err
+	}
+	before := *os
+
+	// The client secret is write-only so if it was not provided then we keep
+	// the secret we already have on record.
+	if cfg.ClientSecret == "" && os.OIDC != nil {
+		cfg.ClientSecret = os.OIDC.ClientSecret
+	}
+	os.OIDC = cfg
+	os.ModifiedAt = time.Now()
+	os.ModifiedByUserID = userID
+	os.ModifiedByUserName = userName
+
+	if err := c.TenantStorer.UpdateByID(ctx, os); err != nil {
+		c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := c.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeTenant, os.ID, &before, os); err != nil {
+		return nil, err
+	}
+	return os, nil
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/fakes"
+)
+
+func newSessionContext(tenantID primitive.ObjectID, role int8) context.Context {
+	ctx := context.WithValue(context.Background(), constants.SessionUserID, primitive.NewObjectID())
+	ctx = context.WithValue(ctx, constants.SessionUserTenantID, tenantID)
+	ctx = context.WithValue(ctx, constants.SessionUserRole, role)
+	return context.WithValue(ctx, constants.SessionUserName, "Test User")
+}
+
+func TestUpdateByIDPolicies(t *testing.T) {
+	tid := primitive.NewObjectID()
+	impl := &TenantControllerImpl{
+		Logger:       fakes.NewLogger(),
+		TenantStorer: fakes.NewTenantStorer(&tenant_s.Tenant{ID: tid, Name: "Acme", Description: "Acme", TwoFactorRequiredRoles: []int8{user_s.UserRoleManagement}, IsEmailVerificationRequired: true}),
+		AuditEvent:   &fakes.AuditEvent{},
+	}
+
+	// Other members of the tenant cannot change the policies.
+	res, err := impl.UpdateByID(newSessionContext(tid, user_s.UserRoleStaff), &tenant_s.Tenant{ID: tid, Name: "Acme", Description: "Acme"})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if len(res.TwoFactorRequiredRoles) != 1 || !res.IsEmailVerificationRequired {
+		t.Fatalf("expected the policies to be kept but got %v %v", res.TwoFactorRequiredRoles, res.IsEmailVerificationRequired)
+	}
+
+	// The tenant's management can.
+	res, err = impl.UpdateByID(newSessionContext(tid, user_s.UserRoleManagement), &tenant_s.Tenant{ID: tid, Name: "Acme", Description: "Acme", TwoFactorRequiredRoles: []int8{user_s.UserRoleManagement, user_s.UserRoleStaff}})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if len(res.TwoFactorRequiredRoles) != 2 || res.IsEmailVerificationRequired {
+		t.Fatalf("expected the policies to be changed but got %v %v", res.TwoFactorRequiredRoles, res.IsEmailVerificationRequired)
+	}
+}
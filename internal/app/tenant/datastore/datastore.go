@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"time"
 
@@ -41,6 +42,7 @@ type Tenant struct {
 	ModifiedByUserID        primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
 	ModifiedByUserName      string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedFromIPAddress   string             `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version                 int64              `bson:"version" json:"version"`
 	AddressCountry          string             `bson:"address_country" json:"address_country"`
 	AddressRegion           string             `bson:"address_region" json:"address_region"`
 	AddressLocality         string             `bson:"address_locality" json:"address_locality"`
@@ -66,6 +68,62 @@ type Tenant struct {
 	Comments                []*TenantComment   `bson:"comments" json:"comments"`
 	OpenAIAPIKey            string             `bson:"openai_api_key" json:"openai_api_key"`
 	OpenAIOrgKey            string             `bson:"openai_org_key" json:"openai_org_key"`
+
+	// The user roles which must complete two-factor authentication before
+	// a session is granted. Example: `[1, 2]` enforces executives and
+	// management to use an authenticator app.
+	TwoFactorRequiredRoles []int8 `bson:"two_factor_required_roles" json:"two_factor_required_roles"`
+
+	// If true then users must verify their email before they can login.
+	IsEmailVerificationRequired bool `bson:"is_email_verification_required" json:"is_email_verification_required"`
+
+	// The OpenID Connect settings used for single sign-on with the tenant's
+	// own identity provider.
+	OIDC *TenantOIDCConfig `bson:"oidc" json:"oidc"`
+}
+
+type TenantOIDCConfig struct {
+	IsEnabled    bool     `bson:"is_enabled" json:"is_enabled"`
+	Issuer       string   `bson:"issuer" json:"issuer"`
+	ClientID     string   `bson:"client_id" json:"client_id"`
+	ClientSecret string   `bson:"client_secret" json:"client_secret"`
+	RedirectURL  string   `bson:"redirect_url" json:"redirect_url"`
+	Scopes       []string `bson:"scopes" json:"scopes"`
+
+	// If true then users who authenticated with the identity provider but
+	// do not have an account yet will get one created on their first login.
+	IsJITProvisioningEnabled bool `bson:"is_jit_provisioning_enabled" json:"is_jit_provisioning_enabled"`
+
+	// The claim in the `id_token` which contains the user's roles or groups
+	// in the identity provider. Example: `groups`.
+	RoleClaim string `bson:"role_claim" json:"role_claim"`
+
+	// The mapping from claim values to our user roles. The first matching
+	// mapping wins so list the most privileged roles first.
+	RoleMappings []*TenantOIDCRoleMapping `bson:"role_mappings" json:"role_mappings"`
+
+	// The role given to provisioned users if no mapping matched. If zero
+	// then users without a matching mapping are not allowed to log in.
+	DefaultRole int8 `bson:"default_role" json:"default_role"`
+}
+
+// MarshalJSON function hides the client secret from the API responses since
+// the secret is write-only.
+func (c TenantOIDCConfig) MarshalJSON() ([]byte, error) {
+	type alias TenantOIDCConfig
+	return json.Marshal(&struct {
+		alias
+		ClientSecret    string `json:"client_secret"`
+		HasClientSecret bool   `json:"has_client_secret"`
+	}{
+		alias:           alias(c),
+		HasClientSecret: c.ClientSecret != "",
+	})
+}
+
+type TenantOIDCRoleMapping struct {
+	ClaimValue string `bson:"claim_value" json:"claim_value"`
+	Role       int8   `bson:"role" json:"role"`
 }
 
 type TenantComment struct {
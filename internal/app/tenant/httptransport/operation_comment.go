@@ -76,7 +76,7 @@ func (h *Handler) OperationCreateComment(w http.ResponseWriter, r *http.Request)
 
 func MarshalOperationCreateCommentResponse(res *sub_s.Tenant, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 	sub_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -47,12 +48,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(org.Version))
 	MarshalUpdateResponse(org, w)
 }
 
 func MarshalUpdateResponse(res *sub_s.Tenant, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -0,0 +1,38 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sub_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+func (h *Handler) UpdateOIDCByID(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	var requestData sub_s.TenantOIDCConfig
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+
+	tid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	org, err := h.Controller.UpdateOIDCByID(ctx, tid, &requestData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", versioning.ETag(org.Version))
+	MarshalUpdateResponse(org, w)
+}
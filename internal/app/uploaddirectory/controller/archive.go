@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log/slog"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
@@ -17,18 +18,22 @@ func (impl *UploadDirectoryControllerImpl) ArchiveByID(ctx context.Context, id p
 	// Lookup the uploaddirectory in our database, else return a `400 Bad Request` error.
 	ou, err := impl.UploadDirectoryStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if ou == nil {
-		impl.Logger.Warn("uploaddirectory does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "uploaddirectory does not exist validation error")
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
+	before := *ou
 
 	ou.Status = uploaddirectory_s.UploadDirectoryStatusArchived
 
 	if err := impl.UploadDirectoryStorer.UpdateByID(ctx, ou); err != nil {
-		impl.Logger.Error("uploaddirectory update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "uploaddirectory update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeUploadDirectory, ou.ID, &before, ou); err != nil {
 		return nil, err
 	}
 	return ou, nil
@@ -9,6 +9,7 @@ import (
 
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
@@ -40,6 +41,7 @@ type UploadDirectoryControllerImpl struct {
 	UserStorer               user_s.UserStorer
 	UploadDirectoryStorer uploaddirectory_s.UploadDirectoryStorer
 	TemplatedEmailer         templatedemailer.TemplatedEmailer
+	AuditEvent               auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -53,6 +55,7 @@ func NewController(
 	client *mongo.Client,
 	usr_storer user_s.UserStorer,
 	uploaddirectory_s uploaddirectory_s.UploadDirectoryStorer,
+	auditevent auditevent_c.AuditEventController,
 ) UploadDirectoryController {
 	s := &UploadDirectoryControllerImpl{
 		Config:                   appCfg,
@@ -65,6 +68,7 @@ func NewController(
 		DbClient:                 client,
 		UserStorer:               usr_storer,
 		UploadDirectoryStorer: uploaddirectory_s,
+		AuditEvent:               auditevent,
 	}
 	s.Logger.Debug("uploaddirectory controller initialization started...")
 	s.Logger.Debug("uploaddirectory controller initialized")
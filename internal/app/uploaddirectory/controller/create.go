@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
@@ -61,7 +62,7 @@ func (impl *UploadDirectoryControllerImpl) Create(ctx context.Context, requestDa
 	//
 
 	if err := impl.validateCreateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -69,7 +70,7 @@ func (impl *UploadDirectoryControllerImpl) Create(ctx context.Context, requestDa
 	// case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 	// 	break
 	// default:
-	// 	impl.Logger.Error("you do not have permission to create a client")
+	// 	impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	// }
 
@@ -79,7 +80,7 @@ func (impl *UploadDirectoryControllerImpl) Create(ctx context.Context, requestDa
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -113,7 +114,11 @@ func (impl *UploadDirectoryControllerImpl) Create(ctx context.Context, requestDa
 
 		// Save to our database.
 		if err := impl.UploadDirectoryStorer.Create(sessCtx, ud); err != nil {
-			impl.Logger.Error("database create error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+			return nil, err
+		}
+
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeUploadDirectory, ud.ID, nil, ud); err != nil {
 			return nil, err
 		}
 
@@ -127,7 +132,7 @@ func (impl *UploadDirectoryControllerImpl) Create(ctx context.Context, requestDa
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -5,23 +5,28 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log/slog"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 )
 
 func (impl *UploadDirectoryControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	// STEP 1: Lookup the record or error.
 	uploaddirectory, err := impl.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return err
 	}
 	if uploaddirectory == nil {
-		impl.Logger.Error("database returns nothing from get by id")
+		impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 		return err
 	}
 
 	// STEP 2: Delete from database.
 	if err := impl.UploadDirectoryStorer.DeleteByID(ctx, id); err != nil {
-		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeUploadDirectory, uploaddirectory.ID, uploaddirectory, nil); err != nil {
 		return err
 	}
 	return nil
@@ -21,7 +21,7 @@ func (c *UploadDirectoryControllerImpl) ListByFilter(ctx context.Context, f *t_s
 	f.TenantID = tenantID // Manditory
 	f.UserID = userID
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -40,7 +40,7 @@ func (c *UploadDirectoryControllerImpl) ListByFilter(ctx context.Context, f *t_s
 
 	m, err := c.UploadDirectoryStorer.ListByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -53,7 +53,7 @@ func (c *UploadDirectoryControllerImpl) ListByFilter(ctx context.Context, f *t_s
 // 	// Apply filtering based on ownership and role.
 // 	f.TenantID = tenantID // Manditory
 //
-// 	c.Logger.Debug("listing using filter options:",
+// 	c.Logger.DebugContext(ctx, "listing using filter options:",
 // 		slog.Any("Cursor", f.Cursor),
 // 		slog.Int64("PageSize", f.PageSize),
 // 		slog.String("SortField", f.SortField),
@@ -63,7 +63,7 @@ func (c *UploadDirectoryControllerImpl) ListByFilter(ctx context.Context, f *t_s
 //
 // 	m, err := c.UploadDirectoryStorer.LiteListByFilter(ctx, f)
 // 	if err != nil {
-// 		c.Logger.Error("database list by filter error", slog.Any("error", err))
+// 		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 // 		return nil, err
 // 	}
 // 	return m, err
@@ -76,7 +76,7 @@ func (c *UploadDirectoryControllerImpl) ListAsSelectOptionByFilter(ctx context.C
 	// Apply filtering based on ownership and role.
 	f.TenantID = tenantID // Manditory
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -87,7 +87,7 @@ func (c *UploadDirectoryControllerImpl) ListAsSelectOptionByFilter(ctx context.C
 	// Filtering the database.
 	m, err := c.UploadDirectoryStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -103,7 +103,7 @@ func (c *UploadDirectoryControllerImpl) PublicListAsSelectOptionByFilter(ctx con
 	// 	}
 	// }
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
 		slog.String("SortField", f.SortField),
@@ -114,7 +114,7 @@ func (c *UploadDirectoryControllerImpl) PublicListAsSelectOptionByFilter(ctx con
 	// Filtering the database.
 	m, err := c.UploadDirectoryStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -9,10 +9,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
 	u_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type UploadDirectoryUpdateRequestIDO struct {
@@ -41,7 +43,7 @@ func (impl *UploadDirectoryControllerImpl) UpdateByID(ctx context.Context, reque
 	//
 
 	if err := impl.validateUpdateRequest(ctx, requestData); err != nil {
-		impl.Logger.Error("validation error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "validation error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -59,7 +61,7 @@ func (impl *UploadDirectoryControllerImpl) UpdateByID(ctx context.Context, reque
 	case u_s.UserRoleExecutive, u_s.UserRoleManagement, u_s.UserRoleFrontlineStaff:
 		break
 	default:
-		impl.Logger.Error("you do not have permission to create a client")
+		impl.Logger.ErrorContext(ctx, "you do not have permission to create a client")
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission to create a client")
 	}
 
@@ -69,7 +71,7 @@ func (impl *UploadDirectoryControllerImpl) UpdateByID(ctx context.Context, reque
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -85,13 +87,18 @@ func (impl *UploadDirectoryControllerImpl) UpdateByID(ctx context.Context, reque
 		// Lookup the uploaddirectory in our database, else return a `400 Bad Request` error.
 		ud, err := impl.UploadDirectoryStorer.GetByID(sessCtx, requestData.ID)
 		if err != nil {
-			impl.Logger.Error("database error", slog.Any("err", err))
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 			return nil, err
 		}
 		if ud == nil {
-			impl.Logger.Warn("uploaddirectory does not exist validation error")
-			return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+			impl.Logger.WarnContext(ctx, "uploaddirectory does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, ud.Version); err != nil {
+			return nil, err
+		}
+		before := *ud
 
 		////
 		//// Update primary record.
@@ -113,7 +120,7 @@ func (impl *UploadDirectoryControllerImpl) UpdateByID(ctx context.Context, reque
 		ud.SortNumber = requestData.SortNumber
 
 		if err := impl.UploadDirectoryStorer.UpdateByID(sessCtx, ud); err != nil {
-			impl.Logger.Error("uploaddirectory update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "uploaddirectory update by id error", slog.Any("error", err))
 			return nil, err
 		}
 
@@ -123,6 +130,10 @@ func (impl *UploadDirectoryControllerImpl) UpdateByID(ctx context.Context, reque
 
 		//
 
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeUploadDirectory, ud.ID, &before, ud); err != nil {
+			return nil, err
+		}
+
 		////
 		//// Exit our transaction successfully.
 		////
@@ -133,7 +144,7 @@ func (impl *UploadDirectoryControllerImpl) UpdateByID(ctx context.Context, reque
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
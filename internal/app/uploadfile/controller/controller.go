@@ -7,12 +7,14 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
-	mg "github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer"
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
 	uploadfile_ds "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	webhook_c "github.com/bartmika/databoutique-backend/internal/app/webhook/controller"
 	"github.com/bartmika/databoutique-backend/internal/config"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
 )
@@ -33,12 +35,14 @@ type UploadFileControllerImpl struct {
 	Logger                *slog.Logger
 	UUID                  uuid.Provider
 	S3                    s3_storage.S3Storager
-	Emailer               mg.Emailer
+	Emailer               emailer.Emailer
 	DbClient              *mongo.Client
 	TenantStorer          tenant_s.TenantStorer
 	UploadFileStorer      uploadfile_ds.UploadFileStorer
 	UploadDirectoryStorer uploaddirectory_s.UploadDirectoryStorer
 	UserStorer            user_s.UserStorer
+	Webhook               webhook_c.WebhookController
+	AuditEvent            auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -47,23 +51,27 @@ func NewController(
 	uuidp uuid.Provider,
 	s3 s3_storage.S3Storager,
 	client *mongo.Client,
-	emailer mg.Emailer,
+	emailerp emailer.Emailer,
 	t_storer tenant_s.TenantStorer,
 	uploaddirectory_s uploaddirectory_s.UploadDirectoryStorer,
 	org_storer uploadfile_ds.UploadFileStorer,
 	usr_storer user_s.UserStorer,
+	webhook webhook_c.WebhookController,
+	auditevent auditevent_c.AuditEventController,
 ) UploadFileController {
 	s := &UploadFileControllerImpl{
 		Config:                appCfg,
 		Logger:                loggerp,
 		UUID:                  uuidp,
 		S3:                    s3,
-		Emailer:               emailer,
+		Emailer:               emailerp,
 		DbClient:              client,
 		TenantStorer:          t_storer,
 		UploadDirectoryStorer: uploaddirectory_s,
 		UploadFileStorer:      org_storer,
 		UserStorer:            usr_storer,
+		Webhook:               webhook,
+		AuditEvent:            auditevent,
 	}
 	s.Logger.Debug("uploadfile controller initialization started...")
 	s.Logger.Debug("uploadfile controller initialized")
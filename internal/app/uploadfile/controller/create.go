@@ -13,8 +13,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	a_d "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
@@ -22,12 +25,12 @@ import (
 // https://freedium.cfd/https://story.tomasen.org/openais-assistant-api-in-go-a-practical-guide-4b9e7243ebff
 
 type UploadFileCreateRequestIDO struct {
-	Name              string
-	Description       string
-	FileName          string
-	FileType          string
-	File              multipart.File
-	UploadDirectoryID primitive.ObjectID
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	FileName          string             `json:"-"`
+	FileType          string             `json:"-"`
+	File              multipart.File     `json:"file"`
+	UploadDirectoryID primitive.ObjectID `json:"upload_directory_id"`
 }
 
 func validateCreateRequest(dirtyData *UploadFileCreateRequestIDO) error {
@@ -69,7 +72,7 @@ func (impl *UploadFileControllerImpl) Create(ctx context.Context, req *UploadFil
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -80,18 +83,18 @@ func (impl *UploadFileControllerImpl) Create(ctx context.Context, req *UploadFil
 
 		uploadDirectory, err := impl.UploadDirectoryStorer.GetByID(sessCtx, req.UploadDirectoryID)
 		if err != nil {
-			impl.Logger.Error("failed getting upload directory",
+			impl.Logger.ErrorContext(ctx, "failed getting upload directory",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.String("upload_directory_id", req.UploadDirectoryID.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if uploadDirectory == nil {
-			return nil, errors.New("upload directory does not exist")
+			return nil, httperror.NewForBadRequestWithSingleField("upload_directory_id", "does not exist")
 		}
 
 		// For debugging purposes only.
-		impl.Logger.Debug("pre-upload meta",
+		impl.Logger.DebugContext(ctx, "pre-upload meta",
 			slog.String("FileName", req.FileName),
 			slog.String("FileType", req.FileType),
 			slog.String("Name", req.Name),
@@ -104,7 +107,7 @@ func (impl *UploadFileControllerImpl) Create(ctx context.Context, req *UploadFil
 
 		creds, err := impl.TenantStorer.GetOpenAICredentialsByID(sessCtx, tenantID)
 		if err != nil {
-			impl.Logger.Error("failed file upload to openai",
+			impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
@@ -113,20 +116,20 @@ func (impl *UploadFileControllerImpl) Create(ctx context.Context, req *UploadFil
 			return nil, errors.New("no openai credentials returned")
 		}
 
-		impl.Logger.Debug("beginning openai file upload...", slog.String("tenant_id", tenantID.Hex()))
+		impl.Logger.DebugContext(ctx, "beginning openai file upload...", slog.String("tenant_id", tenantID.Hex()))
 		fileID, err := impl.uploadContentFromMulipart(context.Background(), req.FileName, req.File, creds.APIKey, creds.OrgKey)
 		if err != nil {
-			impl.Logger.Error("failed file upload to openai",
+			impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if fileID == "" {
-			impl.Logger.Debug("no openai `file_id` returned",
+			impl.Logger.DebugContext(ctx, "no openai `file_id` returned",
 				slog.String("tenant_id", tenantID.Hex()))
 			return nil, errors.New("failed file upload to openai as no `file_id` was returned")
 		}
-		impl.Logger.Debug("finished file upload to openai",
+		impl.Logger.DebugContext(ctx, "finished file upload to openai",
 			slog.String("tenant_id", tenantID.Hex()),
 			slog.Any("file_id", fileID))
 
@@ -155,23 +158,34 @@ func (impl *UploadFileControllerImpl) Create(ctx context.Context, req *UploadFil
 			UserLexicalName:     userLexicalName,
 		}
 		if err := impl.UploadFileStorer.Create(sessCtx, res); err != nil {
-			impl.Logger.Error("upload file create error",
+			impl.Logger.ErrorContext(ctx, "upload file create error",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeUploadFile, res.ID, nil, res); err != nil {
+			return nil, err
+		}
 		return res, nil
 	}
 
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
+	uf := result.(*a_d.UploadFile)
+
+	// Notify the tenant's webhooks; a failure here must not fail the upload.
+	if err := impl.Webhook.Publish(ctx, uf.TenantID, webhook_s.EventTypeUploadFileProcessed, uf); err != nil {
+		impl.Logger.ErrorContext(ctx, "failed publishing webhook event",
+			slog.Any("upload_file_id", uf.ID),
+			slog.Any("error", err))
+	}
 
-	return result.(*a_d.UploadFile), nil
+	return uf, nil
 }
 
 func isStructEmpty(s interface{}) bool {
@@ -181,28 +195,28 @@ func isStructEmpty(s interface{}) bool {
 }
 
 func (impl *UploadFileControllerImpl) uploadContentFromMulipart(ctx context.Context, filename string, file multipart.File, apikey string, orgKey string) (string, error) {
-	impl.Logger.Debug("openai initializing...")
-	client := openai.NewOrgClient(apikey, orgKey)
-	impl.Logger.Debug("openai initialized")
+	impl.Logger.DebugContext(ctx, "openai initializing...")
+	client := metrics.NewOpenAIOrgClient(apikey, orgKey)
+	impl.Logger.DebugContext(ctx, "openai initialized")
 
 	// Read the contents of the file into a byte slice
 	fileBytes, err := ioutil.ReadAll(file)
 	if err != nil {
-		impl.Logger.Error("failed converting multipart file into file bytes", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "failed converting multipart file into file bytes", slog.Any("error", err))
 		return "", err
 	}
 
-	openAIFile, err := client.CreateFileBytes(context.Background(), openai.FileBytesRequest{
+	openAIFile, err := client.CreateFileBytes(context.WithoutCancel(ctx), openai.FileBytesRequest{
 		Name:    filename,
 		Bytes:   fileBytes,
 		Purpose: openai.PurposeAssistants,
 	})
 	if err != nil {
-		impl.Logger.Error("failed uploaded openai file", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "failed uploaded openai file", slog.Any("error", err))
 		return "", err
 	}
 	if isStructEmpty(openAIFile) {
-		impl.Logger.Error("no openai file returned")
+		impl.Logger.ErrorContext(ctx, "no openai file returned")
 		return "", errors.New("no openai file returned")
 	}
 
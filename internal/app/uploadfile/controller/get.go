@@ -6,6 +6,7 @@ import (
 	"time"
 
 	domain "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -16,7 +17,7 @@ func (c *UploadFileControllerImpl) GetByID(ctx context.Context, id primitive.Obj
 	//
 	// If user is not administrator nor belongs to the uploadfile then error.
 	// if userRole != user_d.UserRoleRoot && id != userUploadFileID {
-	// 	c.Logger.Error("authenticated user is not staff role nor belongs to the uploadfile error",
+	// 	c.Logger.ErrorContext(ctx, "authenticated user is not staff role nor belongs to the uploadfile error",
 	// 		slog.Any("userRole", userRole),
 	// 		slog.Any("userUploadFileID", userUploadFileID))
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this uploadfile")
@@ -25,15 +26,18 @@ func (c *UploadFileControllerImpl) GetByID(ctx context.Context, id primitive.Obj
 	// Retrieve from our database the record for the specific id.
 	m, err := c.UploadFileStorer.GetByID(ctx, id)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
 
 	// Generate the URL if it exists.
 	if m.ObjectKey != "" {
 		fileURL, err := c.S3.GetPresignedURL(ctx, m.ObjectKey, 5*time.Minute)
 		if err != nil {
-			c.Logger.Error("s3 failed get presigned url error", slog.Any("error", err))
+			c.Logger.ErrorContext(ctx, "s3 failed get presigned url error", slog.Any("error", err))
 			return nil, err
 		}
 		m.ObjectURL = fileURL
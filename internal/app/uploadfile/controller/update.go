@@ -10,19 +10,21 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	a_d "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type UploadFileUpdateRequestIDO struct {
-	ID                primitive.ObjectID
-	Name              string
-	Description       string
-	FileName          string
-	FileType          string
-	File              multipart.File
-	UploadDirectoryID primitive.ObjectID
+	ID                primitive.ObjectID `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	FileName          string             `json:"-"`
+	FileType          string             `json:"-"`
+	File              multipart.File     `json:"file"`
+	UploadDirectoryID primitive.ObjectID `json:"upload_directory_id"`
 }
 
 func validateUpdateRequest(dirtyData *UploadFileUpdateRequestIDO) error {
@@ -54,16 +56,21 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 	// Fetch the original uploadfile.
 	os, err := impl.UploadFileStorer.GetByID(ctx, req.ID)
 	if err != nil {
-		impl.Logger.Error("database get by id error",
+		impl.Logger.ErrorContext(ctx, "database get by id error",
 			slog.Any("error", err),
 			slog.Any("uploadfile_id", req.ID))
 		return nil, err
 	}
 	if os == nil {
-		impl.Logger.Error("uploadfile does not exist error",
+		impl.Logger.ErrorContext(ctx, "uploadfile does not exist error",
 			slog.Any("uploadfile_id", req.ID))
-		return nil, httperror.NewForBadRequestWithSingleField("message", "uploadfile does not exist")
+		return nil, httperror.NewForNotFoundWithSingleField("message", "uploadfile does not exist")
 	}
+	// Return `412 precondition failed` if the record was modified since the client retrieved it.
+	if err := versioning.CheckExpectedVersion(ctx, os.Version); err != nil {
+		return nil, err
+	}
+	before := *os
 
 	// Extract from our session the following data.
 	tenantID, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
@@ -76,7 +83,7 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 
 	// // If user is not administrator nor belongs to the uploadfile then error.
 	// if userRole != user_d.UserRoleExecutive {
-	// 	impl.Logger.Error("authenticated user is not staff role nor belongs to the uploadfile error",
+	// 	impl.Logger.ErrorContext(ctx, "authenticated user is not staff role nor belongs to the uploadfile error",
 	// 		slog.Any("userRole", userRole),
 	// 		slog.Any("userTenantID", userTenantID))
 	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this uploadfile")
@@ -88,7 +95,7 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 
 	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("start session error",
+		impl.Logger.ErrorContext(ctx, "start session error",
 			slog.Any("error", err))
 		return nil, err
 	}
@@ -99,20 +106,20 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 
 		uploadDirectory, err := impl.UploadDirectoryStorer.GetByID(sessCtx, req.UploadDirectoryID)
 		if err != nil {
-			impl.Logger.Error("failed getting upload directory",
+			impl.Logger.ErrorContext(ctx, "failed getting upload directory",
 				slog.String("tenant_id", tenantID.Hex()),
 				slog.String("upload_directory_id", req.UploadDirectoryID.Hex()),
 				slog.Any("error", err))
 			return nil, err
 		}
 		if uploadDirectory == nil {
-			return nil, errors.New("upload directory does not exist")
+			return nil, httperror.NewForBadRequestWithSingleField("upload_directory_id", "does not exist")
 		}
 
 		// Update the file if the user uploaded a new file.
 		if req.File != nil {
 			// For debugging purposes only.
-			impl.Logger.Debug("pre-upload meta",
+			impl.Logger.DebugContext(ctx, "pre-upload meta",
 				slog.String("FileName", req.FileName),
 				slog.String("FileType", req.FileType),
 				slog.String("Name", req.Name),
@@ -125,7 +132,7 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 
 			creds, err := impl.TenantStorer.GetOpenAICredentialsByID(ctx, userTenantID)
 			if err != nil {
-				impl.Logger.Error("failed file upload to openai",
+				impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 					slog.String("tenant_id", userTenantID.Hex()),
 					slog.Any("error", err))
 				return nil, err
@@ -134,20 +141,20 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 				return nil, errors.New("no openai credentials returned")
 			}
 
-			impl.Logger.Debug("beginning openai file upload...", slog.String("tenant_id", userTenantID.Hex()))
+			impl.Logger.DebugContext(ctx, "beginning openai file upload...", slog.String("tenant_id", userTenantID.Hex()))
 			fileID, err := impl.uploadContentFromMulipart(context.Background(), req.FileName, req.File, creds.APIKey, creds.OrgKey)
 			if err != nil {
-				impl.Logger.Error("failed file upload to openai",
+				impl.Logger.ErrorContext(ctx, "failed file upload to openai",
 					slog.String("tenant_id", userTenantID.Hex()),
 					slog.Any("error", err))
 				return nil, err
 			}
 			if fileID == "" {
-				impl.Logger.Debug("no openai `file_id` returned",
+				impl.Logger.DebugContext(ctx, "no openai `file_id` returned",
 					slog.String("tenant_id", userTenantID.Hex()))
 				return nil, errors.New("failed file upload to openai as no `file_id` was returned")
 			}
-			impl.Logger.Debug("finished file upload to openai",
+			impl.Logger.DebugContext(ctx, "finished file upload to openai",
 				slog.String("tenant_id", userTenantID.Hex()),
 				slog.Any("file_id", fileID))
 		}
@@ -166,7 +173,10 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 
 		// Save to the database the modified uploadfile.
 		if err := impl.UploadFileStorer.UpdateByID(ctx, os); err != nil {
-			impl.Logger.Error("database update by id error", slog.Any("error", err))
+			impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeUploadFile, os.ID, &before, os); err != nil {
 			return nil, err
 		}
 
@@ -183,7 +193,7 @@ func (impl *UploadFileControllerImpl) UpdateByID(ctx context.Context, req *Uploa
 	// Start a transaction
 	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("session failed error",
+		impl.Logger.ErrorContext(ctx, "session failed error",
 			slog.Any("error", err))
 		return nil, err
 	}
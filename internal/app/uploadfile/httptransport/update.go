@@ -10,6 +10,7 @@ import (
 	sub_c "github.com/bartmika/databoutique-backend/internal/app/uploadfile/controller"
 	sub_s "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -88,12 +89,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, idStr strin
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(uploadfile.Version))
 	MarshalUpdateResponse(uploadfile, w)
 }
 
 func MarshalUpdateResponse(res *sub_s.UploadFile, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log/slog"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
@@ -24,17 +25,18 @@ func (impl *UserControllerImpl) ArchiveByID(ctx context.Context, id primitive.Ob
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	ou, err := impl.UserStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if ou == nil {
-		impl.Logger.Warn("user does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+		impl.Logger.WarnContext(ctx, "user does not exist validation error")
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
 	}
+	before := *ou
 
 	// Security: Prevent deletion of root user(s).
 	if ou.Role == user_s.UserRoleExecutive {
-		impl.Logger.Warn("root user(s) cannot be deleted error")
+		impl.Logger.WarnContext(ctx, "root user(s) cannot be deleted error")
 		return nil, httperror.NewForForbiddenWithSingleField("role", "root user(s) cannot be deleted")
 	}
 
@@ -42,7 +44,10 @@ func (impl *UserControllerImpl) ArchiveByID(ctx context.Context, id primitive.Ob
 	ou.Status = user_s.UserStatusArchived
 
 	if err := impl.UserStorer.UpdateByID(ctx, ou); err != nil {
-		impl.Logger.Error("user update by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionArchive, auditevent_s.AuditEventResourceTypeUser, ou.ID, &before, ou); err != nil {
 		return nil, err
 	}
 	return ou, nil
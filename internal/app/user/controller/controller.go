@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
 	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
@@ -40,6 +41,7 @@ type UserControllerImpl struct {
 	TenantStorer     tenant_s.TenantStorer
 	UserStorer       user_s.UserStorer
 	TemplatedEmailer templatedemailer.TemplatedEmailer
+	AuditEvent       auditevent_c.AuditEventController
 }
 
 func NewController(
@@ -52,6 +54,7 @@ func NewController(
 	org_storer tenant_s.TenantStorer,
 	usr_storer user_s.UserStorer,
 	temailer templatedemailer.TemplatedEmailer,
+	auditevent auditevent_c.AuditEventController,
 ) UserController {
 	s := &UserControllerImpl{
 		Config:           appCfg,
@@ -63,6 +66,7 @@ func NewController(
 		TenantStorer:     org_storer,
 		UserStorer:       usr_storer,
 		TemplatedEmailer: temailer,
+		AuditEvent:       auditevent,
 	}
 	s.Logger.Debug("user controller initialization started...")
 
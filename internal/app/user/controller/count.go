@@ -23,7 +23,7 @@ func (c *UserControllerImpl) CountByFilter(ctx context.Context, f *user_s.UserLi
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
 	}
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("TenantID", f.TenantID),
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
@@ -37,7 +37,7 @@ func (c *UserControllerImpl) CountByFilter(ctx context.Context, f *user_s.UserLi
 	// Filtering the database.
 	m, err := c.UserStorer.CountByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return &UserCountResult{Count: m}, err
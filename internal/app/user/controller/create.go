@@ -10,6 +10,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
@@ -107,22 +108,22 @@ func (impl *UserControllerImpl) Create(ctx context.Context, requestData *UserCre
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	u, err := impl.UserStorer.GetByEmail(ctx, m.Email)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if u != nil {
-		impl.Logger.Warn("user already exists validation error")
+		impl.Logger.WarnContext(ctx, "user already exists validation error")
 		return nil, httperror.NewForBadRequestWithSingleField("email", "email is not unique")
 	}
 
 	// Lookup the tenant in our database, else return a `400 Bad Request` error.
 	o, err := impl.TenantStorer.GetByID(ctx, m.TenantID)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
 		return nil, err
 	}
 	if o == nil {
-		impl.Logger.Warn("tenant does not exist exists validation error")
+		impl.Logger.WarnContext(ctx, "tenant does not exist exists validation error")
 		return nil, httperror.NewForBadRequestWithSingleField("tenant_id", "tenant does not exist")
 	}
 
@@ -148,7 +149,7 @@ func (impl *UserControllerImpl) Create(ctx context.Context, requestData *UserCre
 	// Hash our password with the temporary password and attach to account.
 	temporaryPasswordHash, err := impl.Password.GenerateHashFromPassword(temporaryPassword)
 	if err != nil {
-		impl.Logger.Error("hashing error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "hashing error", slog.Any("error", err))
 		return nil, err
 	}
 	m.PasswordHashAlgorithm = impl.Password.AlgorithmName()
@@ -156,13 +157,16 @@ func (impl *UserControllerImpl) Create(ctx context.Context, requestData *UserCre
 
 	// Save to our database.
 	if err := impl.UserStorer.Create(ctx, m); err != nil {
-		impl.Logger.Error("database create error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeUser, m.ID, nil, m); err != nil {
 		return nil, err
 	}
 
 	// Send email to user of the new password.
 	if err := impl.TemplatedEmailer.SendNewUserTemporaryPasswordEmail(m.Email, m.FirstName, temporaryPassword); err != nil {
-		impl.Logger.Error("failed sending verification email with error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "failed sending verification email with error", slog.Any("err", err))
 		return nil, err
 	}
 
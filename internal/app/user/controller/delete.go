@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
@@ -22,23 +23,26 @@ func (impl *UserControllerImpl) DeleteByID(ctx context.Context, id primitive.Obj
 	// STEP 1: Lookup the record or error.
 	user, err := impl.UserStorer.GetByID(ctx, id)
 	if err != nil {
-		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return err
 	}
 	if user == nil {
-		impl.Logger.Error("database returns nothing from get by id")
+		impl.Logger.ErrorContext(ctx, "database returns nothing from get by id")
 		return err
 	}
 
 	// Security: Prevent deletion of root user(s).
 	if user.Role == user_s.UserRoleExecutive {
-		impl.Logger.Warn("root user(s) cannot be deleted error")
+		impl.Logger.WarnContext(ctx, "root user(s) cannot be deleted error")
 		return httperror.NewForForbiddenWithSingleField("role", "root user(s) cannot be deleted")
 	}
 
 	// STEP 2: Delete from database.
 	if err := impl.UserStorer.DeleteByID(ctx, id); err != nil {
-		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeUser, user.ID, user, nil); err != nil {
 		return err
 	}
 	return nil
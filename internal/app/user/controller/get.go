@@ -23,9 +23,12 @@ func (c *UserControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID)
 	// Retrieve from our database the record for the specific id.
 	m, err := c.UserStorer.GetByID(ctx, id)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
 	return m, err
 }
 
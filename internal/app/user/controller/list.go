@@ -19,7 +19,7 @@ func (c *UserControllerImpl) ListByFilter(ctx context.Context, f *user_s.UserLis
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
 	}
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("TenantID", f.TenantID),
 		slog.Any("Cursor", f.Cursor),
 		slog.Int64("PageSize", f.PageSize),
@@ -33,7 +33,7 @@ func (c *UserControllerImpl) ListByFilter(ctx context.Context, f *user_s.UserLis
 	// Filtering the database.
 	m, err := c.UserStorer.ListByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
@@ -48,14 +48,14 @@ func (c *UserControllerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
 	}
 
-	c.Logger.Debug("listing using filter options:",
+	c.Logger.DebugContext(ctx, "listing using filter options:",
 		slog.Any("TenantID", f.TenantID),
 		slog.Any("Role", f.Role))
 
 	// Filtering the database.
 	m, err := c.UserStorer.ListAsSelectOptionByFilter(ctx, f)
 	if err != nil {
-		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database list by filter error", slog.Any("error", err))
 		return nil, err
 	}
 	return m, err
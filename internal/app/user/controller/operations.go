@@ -16,13 +16,13 @@ func (c *UserControllerImpl) CreateComment(ctx context.Context, customerID primi
 	// Fetch the original customer.
 	s, err := c.UserStorer.GetByID(ctx, customerID)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
 		return nil, err
 	}
 	if s == nil {
-		c.Logger.Error("user does not exist error",
+		c.Logger.ErrorContext(ctx, "user does not exist error",
 			slog.Any("userid", customerID))
-		return nil, httperror.NewForBadRequestWithSingleField("message", "user does not exist")
+		return nil, httperror.NewForNotFoundWithSingleField("message", "user does not exist")
 	}
 
 	// Create our comment.
@@ -45,7 +45,7 @@ func (c *UserControllerImpl) CreateComment(ctx context.Context, customerID primi
 
 	// Save to the database the modified customer.
 	if err := c.UserStorer.UpdateByID(ctx, s); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+		c.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
 		return nil, err
 	}
 
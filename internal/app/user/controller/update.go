@@ -7,10 +7,13 @@ import (
 	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
 	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 type UserUpdateRequestIDO struct {
@@ -103,59 +106,92 @@ func (impl *UserControllerImpl) UpdateByID(ctx context.Context, requestData *Use
 		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
 	}
 
-	// Lookup the user in our database, else return a `400 Bad Request` error.
-	ou, err := impl.UserStorer.GetByID(ctx, nu.ID)
+	////
+	//// Start the transaction.
+	////
+
+	session, err := impl.DbClient.StartSession()
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "start session error",
+			slog.Any("error", err))
 		return nil, err
 	}
-	if ou == nil {
-		impl.Logger.Warn("user does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+	defer session.EndSession(ctx)
+
+	// Define a transaction function with a series of operations
+	transactionFunc := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		// Lookup the user in our database, else return a `400 Bad Request` error.
+		ou, err := impl.UserStorer.GetByID(sessCtx, nu.ID)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+			return nil, err
+		}
+		if ou == nil {
+			impl.Logger.WarnContext(ctx, "user does not exist validation error")
+			return nil, httperror.NewForNotFoundWithSingleField("id", "does not exist")
+		}
+		// Return `412 precondition failed` if the record was modified since the client retrieved it.
+		if err := versioning.CheckExpectedVersion(sessCtx, ou.Version); err != nil {
+			return nil, err
+		}
+		before := *ou
+
+		// Lookup the tenant in our database, else return a `400 Bad Request` error.
+		o, err := impl.TenantStorer.GetByID(sessCtx, nu.TenantID)
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "database error", slog.Any("err", err))
+			return nil, err
+		}
+		if o == nil {
+			impl.Logger.WarnContext(ctx, "tenant does not exist exists validation error")
+			return nil, httperror.NewForBadRequestWithSingleField("tenant_id", "tenant does not exist")
+		}
+
+		ou.TenantID = o.ID
+		ou.FirstName = nu.FirstName
+		ou.LastName = nu.LastName
+		ou.Name = fmt.Sprintf("%s %s", nu.FirstName, nu.LastName)
+		ou.LexicalName = fmt.Sprintf("%s, %s", nu.LastName, nu.FirstName)
+		ou.Email = nu.Email
+		ou.Phone = nu.Phone
+		ou.Country = nu.Country
+		ou.Region = nu.Region
+		ou.City = nu.City
+		ou.PostalCode = nu.PostalCode
+		ou.AddressLine1 = nu.AddressLine1
+		ou.AddressLine2 = nu.AddressLine2
+		// ou.HowDidYouHearAboutUs = nu.HowDidYouHearAboutUs
+		// ou.HowDidYouHearAboutUsOther = nu.HowDidYouHearAboutUsOther
+		ou.AgreePromotionsEmail = nu.AgreePromotionsEmail
+		ou.ModifiedByUserID = userID
+		ou.ModifiedByUserName = userName
+		ou.HasShippingAddress = nu.HasShippingAddress
+		ou.ShippingName = nu.ShippingName
+		ou.ShippingPhone = nu.ShippingPhone
+		ou.ShippingCountry = nu.ShippingCountry
+		ou.ShippingRegion = nu.ShippingRegion
+		ou.ShippingCity = nu.ShippingCity
+		ou.ShippingPostalCode = nu.ShippingPostalCode
+		ou.ShippingAddressLine1 = nu.ShippingAddressLine1
+		ou.ShippingAddressLine2 = nu.ShippingAddressLine2
+
+		if err := impl.UserStorer.UpdateByID(sessCtx, ou); err != nil {
+			impl.Logger.ErrorContext(ctx, "user update by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if err := impl.AuditEvent.Record(sessCtx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeUser, ou.ID, &before, ou); err != nil {
+			return nil, err
+		}
+		return ou, nil
 	}
 
-	// Lookup the tenant in our database, else return a `400 Bad Request` error.
-	o, err := impl.TenantStorer.GetByID(ctx, nu.TenantID)
+	// Start a transaction
+	result, err := session.WithTransaction(ctx, transactionFunc)
 	if err != nil {
-		impl.Logger.Error("database error", slog.Any("err", err))
+		impl.Logger.ErrorContext(ctx, "session failed error",
+			slog.Any("error", err))
 		return nil, err
 	}
-	if o == nil {
-		impl.Logger.Warn("tenant does not exist exists validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("tenant_id", "tenant does not exist")
-	}
 
-	ou.TenantID = o.ID
-	ou.FirstName = nu.FirstName
-	ou.LastName = nu.LastName
-	ou.Name = fmt.Sprintf("%s %s", nu.FirstName, nu.LastName)
-	ou.LexicalName = fmt.Sprintf("%s, %s", nu.LastName, nu.FirstName)
-	ou.Email = nu.Email
-	ou.Phone = nu.Phone
-	ou.Country = nu.Country
-	ou.Region = nu.Region
-	ou.City = nu.City
-	ou.PostalCode = nu.PostalCode
-	ou.AddressLine1 = nu.AddressLine1
-	ou.AddressLine2 = nu.AddressLine2
-	// ou.HowDidYouHearAboutUs = nu.HowDidYouHearAboutUs
-	// ou.HowDidYouHearAboutUsOther = nu.HowDidYouHearAboutUsOther
-	ou.AgreePromotionsEmail = nu.AgreePromotionsEmail
-	ou.ModifiedByUserID = userID
-	ou.ModifiedByUserName = userName
-	ou.HasShippingAddress = nu.HasShippingAddress
-	ou.ShippingName = nu.ShippingName
-	ou.ShippingPhone = nu.ShippingPhone
-	ou.ShippingCountry = nu.ShippingCountry
-	ou.ShippingRegion = nu.ShippingRegion
-	ou.ShippingCity = nu.ShippingCity
-	ou.ShippingPostalCode = nu.ShippingPostalCode
-	ou.ShippingAddressLine1 = nu.ShippingAddressLine1
-	ou.ShippingAddressLine2 = nu.ShippingAddressLine2
-
-	if err := impl.UserStorer.UpdateByID(ctx, ou); err != nil {
-		impl.Logger.Error("user update by id error", slog.Any("error", err))
-		return nil, err
-	}
-	return ou, nil
+	return result.(*user_s.User), nil
 }
@@ -44,8 +44,17 @@ type User struct {
 	Role                        int8               `bson:"role" json:"role"`
 	HasStaffRole                bool               `bson:"has_staff_role" json:"has_staff_role"`
 	WasEmailVerified            bool               `bson:"was_email_verified" json:"was_email_verified"`
-	EmailVerificationCode       string             `bson:"email_verification_code,omitempty" json:"email_verification_code,omitempty"`
+	EmailVerificationCode       string             `bson:"email_verification_code" json:"-"`
 	EmailVerificationExpiry     time.Time          `bson:"email_verification_expiry,omitempty" json:"email_verification_expiry,omitempty"`
+	PasswordResetCode           string             `bson:"password_reset_code" json:"-"`
+	PasswordResetExpiry         time.Time          `bson:"password_reset_expiry,omitempty" json:"-"`
+	OTPEnabled                  bool               `bson:"otp_enabled" json:"otp_enabled"`
+	OTPVerified                 bool               `bson:"otp_verified" json:"otp_verified"`
+	OTPSecret                   string             `bson:"otp_secret" json:"-"`
+	OTPLastUsedStep             int64              `bson:"otp_last_used_step" json:"-"`
+	OTPRecoveryCodeHashes       []string           `bson:"otp_recovery_code_hashes" json:"-"`
+	OIDCIssuer                  string             `bson:"oidc_issuer" json:"oidc_issuer,omitempty"`
+	OIDCSubject                 string             `bson:"oidc_subject" json:"oidc_subject,omitempty"`
 	Phone                       string             `bson:"phone" json:"phone,omitempty"`
 	Country                     string             `bson:"country" json:"country,omitempty"`
 	Region                      string             `bson:"region" json:"region,omitempty"`
@@ -74,6 +83,7 @@ type User struct {
 	PrivacyText                 string             `bson:"privacy_text" json:"privacy_text,omitempty"`
 	PrivacyAgreedOn             time.Time          `bson:"privacy_agreed_on" json:"privacy_agreed_on,omitempty"`
 	AgreePromotionsEmail        bool               `bson:"agree_promotions_email" json:"agree_promotions_email,omitempty"`
+	IsAnswerReadyEmailDisabled  bool               `bson:"is_answer_ready_email_disabled" json:"is_answer_ready_email_disabled"` // If true then do not email the user when an executable finishes processing.
 	AgreeWaiver                 bool               `bson:"agree_waiver" json:"agree_waiver,omitempty"`
 	WaiverText                  string             `bson:"waiver_text" json:"waiver_text,omitempty"`
 	WaiverAgreedOn              time.Time          `bson:"waiver_agreed_on" json:"waiver_agreed_on,omitempty"`
@@ -85,6 +95,7 @@ type User struct {
 	ModifiedByUserID            primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
 	ModifiedByUserName          string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedFromIPAddress       string             `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version                     int64              `bson:"version" json:"version"`
 	Status                      int8               `bson:"status" json:"status"`
 	Comments                    []*UserComment     `bson:"comments" json:"comments"`
 	Salt                        string             `bson:"salt" json:"salt,omitempty"`
@@ -190,6 +201,8 @@ type UserStorer interface {
 	GetByPublicID(ctx context.Context, oldID uint64) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByVerificationCode(ctx context.Context, verificationCode string) (*User, error)
+	GetByPasswordResetCode(ctx context.Context, passwordResetCode string) (*User, error)
+	GetByOIDCSubject(ctx context.Context, issuer string, subject string) (*User, error)
 	GetLatestByTenantID(ctx context.Context, tenantID primitive.ObjectID) (*User, error)
 	CheckIfExistsByEmail(ctx context.Context, email string) (bool, error)
 	UpdateByID(ctx context.Context, m *User) error
@@ -223,6 +236,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Us
 		{Keys: bson.D{{Key: "joined_time", Value: 1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
 		{Keys: bson.D{{Key: "type", Value: 1}}},
+		{Keys: bson.D{{Key: "oidc_issuer", Value: 1}, {Key: "oidc_subject", Value: 1}}},
 		{Keys: bson.D{
 			{"name", "text"},
 			{"lexical_name", "text"},
@@ -75,6 +75,38 @@ func (impl UserStorerImpl) GetByVerificationCode(ctx context.Context, verificati
 	return &result, nil
 }
 
+func (impl UserStorerImpl) GetByPasswordResetCode(ctx context.Context, passwordResetCode string) (*User, error) {
+	filter := bson.D{{"password_reset_code", passwordResetCode}}
+
+	var result User
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by password reset code error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (impl UserStorerImpl) GetByOIDCSubject(ctx context.Context, issuer string, subject string) (*User, error) {
+	filter := bson.D{{"oidc_issuer", issuer}, {"oidc_subject", subject}}
+
+	var result User
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get by oidc subject error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
 func (impl UserStorerImpl) GetLatestByTenantID(ctx context.Context, tenantID primitive.ObjectID) (*User, error) {
 	filter := bson.D{{"tenant_id", tenantID}}
 	opts := options.Find().SetSort(bson.D{{"public_id", -1}}).SetLimit(1)
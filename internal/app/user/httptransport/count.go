@@ -113,7 +113,7 @@ func (h *Handler) Count(w http.ResponseWriter, r *http.Request) {
 
 func MarshalCountResponse(res *sub_c.UserCountResult, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
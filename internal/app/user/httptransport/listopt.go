@@ -67,7 +67,7 @@ func (h *Handler) ListAsSelectOptions(w http.ResponseWriter, r *http.Request) {
 
 func MarshalListAsSelectOptionResponse(res []*sub_s.UserAsSelectOption, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
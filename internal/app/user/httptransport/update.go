@@ -11,6 +11,7 @@ import (
 	usr_c "github.com/bartmika/databoutique-backend/internal/app/user/controller"
 	usr_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
 func UnmarshalUpdateRequest(ctx context.Context, r *http.Request) (*usr_c.UserUpdateRequestIDO, error) {
@@ -134,12 +135,13 @@ func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
+	w.Header().Set("ETag", versioning.ETag(customer.Version))
 	MarshalUpdateResponse(customer, w)
 }
 
 func MarshalUpdateResponse(res *usr_s.User, w http.ResponseWriter) {
 	if err := json.NewEncoder(w).Encode(&res); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperror.ResponseError(w, err)
 		return
 	}
 }
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
+	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+)
+
+// WebhookController Interface for webhook business logic controller.
+type WebhookController interface {
+	Create(ctx context.Context, requestData *WebhookCreateRequestIDO) (*WebhookCreateResponseIDO, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*webhook_s.Webhook, error)
+	UpdateByID(ctx context.Context, requestData *WebhookUpdateRequestIDO) (*webhook_s.Webhook, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	List(ctx context.Context) (*webhook_s.WebhookListResult, error)
+	ListDeliveriesByWebhookID(ctx context.Context, id primitive.ObjectID) (*webhook_s.WebhookDeliveryListResult, error)
+	SendTestEventByID(ctx context.Context, id primitive.ObjectID) (*webhook_s.WebhookDelivery, error)
+
+	// Publish function queues the event for delivery to every webhook of
+	// the tenant which subscribed to the event type.
+	Publish(ctx context.Context, tenantID primitive.ObjectID, eventType string, data interface{}) error
+
+	// RunDeliveryWorker function sends the queued deliveries until the
+	// context is cancelled.
+	RunDeliveryWorker(ctx context.Context)
+}
+
+type WebhookControllerImpl struct {
+	Config                *config.Conf
+	Logger                *slog.Logger
+	UUID                  uuid.Provider
+	Kmutex                kmutex.Provider
+	DbClient              *mongo.Client
+	HTTPClient            *http.Client
+	WebhookStorer         webhook_s.WebhookStorer
+	WebhookDeliveryStorer webhook_s.WebhookDeliveryStorer
+	AuditEvent            auditevent_c.AuditEventController
+}
+
+// newHTTPClient function returns the client used to send the webhooks. The
+// endpoints are configured by our tenants so when `isPrivateNetworkBlocked`
+// is true we refuse to connect to our internal network.
+func newHTTPClient(isPrivateNetworkBlocked bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if isPrivateNetworkBlocked {
+		// DEVELOPERS NOTE: We check the resolved address when connecting
+		// so a hostname cannot resolve to an internal address.
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return fmt.Errorf("connecting to %s is not allowed", host)
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		// Do not follow redirects as the endpoint must be the URL the
+		// tenant configured.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	uuidp uuid.Provider,
+	kmux kmutex.Provider,
+	client *mongo.Client,
+	webhook_storer webhook_s.WebhookStorer,
+	delivery_storer webhook_s.WebhookDeliveryStorer,
+	auditevent auditevent_c.AuditEventController,
+) WebhookController {
+	s := &WebhookControllerImpl{
+		Config:                appCfg,
+		Logger:                loggerp,
+		UUID:                  uuidp,
+		Kmutex:                kmux,
+		DbClient:              client,
+		HTTPClient:            newHTTPClient(!appCfg.AppServer.HasDebugging),
+		WebhookStorer:         webhook_storer,
+		WebhookDeliveryStorer: delivery_storer,
+		AuditEvent:            auditevent,
+	}
+	s.Logger.Debug("webhook controller initialization started...")
+	s.Logger.Debug("webhook controller initialized")
+	return s
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+type WebhookCreateRequestIDO struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+}
+
+// WebhookCreateResponseIDO includes the signing secret which is only ever
+// returned once upon creation.
+type WebhookCreateResponseIDO struct {
+	Webhook *webhook_s.Webhook `json:"webhook"`
+	Secret  string             `json:"secret"`
+}
+
+// generateSecret function returns a random secret used to sign the payloads
+// sent to the webhook.
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (impl *WebhookControllerImpl) validateURL(rawURL string, e map[string]string) {
+	if rawURL == "" {
+		e["url"] = "missing value"
+		return
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		e["url"] = "invalid value"
+		return
+	}
+	// Plain-text endpoints are only allowed while developing locally.
+	if u.Scheme != "https" && !(u.Scheme == "http" && impl.Config.AppServer.HasDebugging) {
+		e["url"] = "must use https"
+	}
+}
+
+func validateEventTypes(eventTypes []string, e map[string]string) {
+	if len(eventTypes) == 0 {
+		e["event_types"] = "missing value"
+		return
+	}
+	for _, eventType := range eventTypes {
+		isValid := false
+		for _, et := range webhook_s.EventTypes {
+			if et == eventType {
+				isValid = true
+				break
+			}
+		}
+		if !isValid {
+			e["event_types"] = fmt.Sprintf("invalid event type: %s", eventType)
+			return
+		}
+	}
+}
+
+func (impl *WebhookControllerImpl) validateCreateRequest(dirtyData *WebhookCreateRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.Name == "" {
+		e["name"] = "missing value"
+	}
+	impl.validateURL(dirtyData.URL, e)
+	validateEventTypes(dirtyData.EventTypes, e)
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (impl *WebhookControllerImpl) Create(ctx context.Context, requestData *WebhookCreateRequestIDO) (*WebhookCreateResponseIDO, error) {
+	//
+	// Get variables from our user authenticated session.
+	//
+
+	tid, _ := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID)
+	tenantName, _ := ctx.Value(constants.SessionUserTenantName).(string)
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	if err := impl.checkPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	//
+	// Perform our validation and return validation error on any issues detected.
+	//
+
+	if err := impl.validateCreateRequest(requestData); err != nil {
+		impl.Logger.WarnContext(ctx, "validation error", slog.Any("error", err))
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "generate webhook secret error", slog.Any("error", err))
+		return nil, err
+	}
+
+	m := &webhook_s.Webhook{
+		ID:                    primitive.NewObjectID(),
+		TenantID:              tid,
+		TenantName:            tenantName,
+		Name:                  requestData.Name,
+		Description:           requestData.Description,
+		URL:                   requestData.URL,
+		Secret:                secret,
+		EventTypes:            requestData.EventTypes,
+		Status:                webhook_s.WebhookStatusActive,
+		CreatedAt:             time.Now(),
+		CreatedByUserID:       userID,
+		CreatedByUserName:     userName,
+		CreatedFromIPAddress:  ipAddress,
+		ModifiedAt:            time.Now(),
+		ModifiedByUserID:      userID,
+		ModifiedByUserName:    userName,
+		ModifiedFromIPAddress: ipAddress,
+	}
+	if err := impl.WebhookStorer.Create(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionCreate, auditevent_s.AuditEventResourceTypeWebhook, m.ID, nil, m); err != nil {
+		return nil, err
+	}
+	impl.Logger.InfoContext(ctx, "Webhook created.",
+		slog.Any("tenant_id", m.TenantID),
+		slog.Any("webhook_id", m.ID))
+
+	return &WebhookCreateResponseIDO{
+		Webhook: m,
+		Secret:  secret,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+)
+
+func (impl *WebhookControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	// STEP 1: Lookup the record or error.
+	m, err := impl.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// STEP 2: Delete from database along with the delivery logs.
+	if err := impl.WebhookStorer.DeleteByID(ctx, id); err != nil {
+		impl.Logger.ErrorContext(ctx, "database delete by id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.WebhookDeliveryStorer.DeleteByWebhookID(ctx, id); err != nil {
+		impl.Logger.ErrorContext(ctx, "database delete by webhook id error", slog.Any("error", err))
+		return err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionDelete, auditevent_s.AuditEventResourceTypeWebhook, m.ID, m, nil); err != nil {
+		return err
+	}
+	return nil
+}
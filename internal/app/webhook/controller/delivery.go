@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+const (
+	// The maximum number of times we attempt to send a delivery; with our
+	// backoff the last attempt happens roughly two hours after the first.
+	maxDeliveryAttempts = 8
+
+	// The delay before the first retry which doubles with every attempt.
+	deliveryRetryDelay = 30 * time.Second
+
+	// The duration a claimed delivery is hidden from the other workers.
+	deliveryLease = time.Minute
+
+	// The interval the worker checks for deliveries which are due.
+	deliveryPollInterval = 5 * time.Second
+
+	// The maximum number of bytes of the response we save to the log.
+	maxResponseBodyLength = 1024
+)
+
+// Sign function returns the value of the `X-Webhook-Signature` header. The
+// signature is the HMAC-SHA256 of `<timestamp>.<payload>` using the secret
+// of the webhook so endpoints can verify the payload came from us and
+// reject old payloads which are replayed.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+// retryDelay function returns the exponential backoff after the attempt.
+func retryDelay(attemptCount int) time.Duration {
+	return deliveryRetryDelay * time.Duration(1<<uint(attemptCount-1))
+}
+
+func (impl *WebhookControllerImpl) RunDeliveryWorker(ctx context.Context) {
+	impl.Logger.InfoContext(ctx, "webhook delivery worker started")
+
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+	for {
+		impl.deliverDue(ctx)
+		impl.reportQueueDepth(ctx)
+
+		select {
+		case <-ctx.Done():
+			impl.Logger.InfoContext(ctx, "webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue function sends every delivery which is due.
+func (impl *WebhookControllerImpl) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		d, err := impl.WebhookDeliveryStorer.ClaimNextDue(ctx, time.Now(), time.Now().Add(deliveryLease))
+		if err != nil {
+			impl.Logger.ErrorContext(ctx, "failed claiming webhook delivery", slog.Any("error", err))
+			return
+		}
+		if d == nil {
+			return
+		}
+		impl.deliver(ctx, d, maxDeliveryAttempts)
+	}
+}
+
+// reportQueueDepth function records the number of pending deliveries for our
+// metrics.
+func (impl *WebhookControllerImpl) reportQueueDepth(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+	count, err := impl.WebhookDeliveryStorer.CountPending(ctx)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "failed counting pending webhook deliveries", slog.Any("error", err))
+		return
+	}
+	metrics.SetBackgroundJobQueueDepth("webhook_delivery", count)
+}
+
+// deliver function sends the delivery and saves the result of the attempt.
+// If the attempt fails then the delivery is retried with backoff until the
+// maximum attempts is reached.
+func (impl *WebhookControllerImpl) deliver(ctx context.Context, d *webhook_s.WebhookDelivery, maxAttempts int) {
+	attempt := &webhook_s.WebhookDeliveryAttempt{AttemptedAt: time.Now()}
+
+	w, err := impl.WebhookStorer.GetByID(ctx, d.WebhookID)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database get by id error", slog.Any("error", err))
+		return // The delivery is retried when the lease expires.
+	}
+	if w == nil || w.Status != webhook_s.WebhookStatusActive {
+		attempt.Error = "webhook was deleted or disabled"
+		maxAttempts = 0 // Do not retry.
+	} else {
+		impl.send(ctx, w, d, attempt)
+	}
+
+	d.Attempts = append(d.Attempts, attempt)
+	d.AttemptCount++
+	d.ModifiedAt = time.Now()
+	switch {
+	case attempt.Error == "":
+		d.Status = webhook_s.WebhookDeliveryStatusSucceeded
+	case d.AttemptCount >= maxAttempts:
+		d.Status = webhook_s.WebhookDeliveryStatusFailed
+	default:
+		d.NextAttemptAt = time.Now().Add(retryDelay(d.AttemptCount))
+	}
+	if err := impl.WebhookDeliveryStorer.UpdateByID(ctx, d); err != nil {
+		impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return
+	}
+
+	impl.Logger.DebugContext(ctx, "webhook delivery attempted",
+		slog.Any("webhook_id", d.WebhookID),
+		slog.Any("delivery_id", d.ID),
+		slog.String("event_type", d.EventType),
+		slog.Int("attempt_count", d.AttemptCount),
+		slog.Int("status_code", attempt.ResponseStatusCode),
+		slog.String("error", attempt.Error))
+}
+
+// send function posts the payload to the webhook endpoint and records the
+// response in the attempt.
+func (impl *WebhookControllerImpl) send(ctx context.Context, w *webhook_s.Webhook, d *webhook_s.WebhookDelivery, attempt *webhook_s.WebhookDeliveryAttempt) {
+	startedAt := time.Now()
+	defer func() {
+		attempt.DurationInMS = time.Since(startedAt).Milliseconds()
+	}()
+
+	payload := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, strings.NewReader(d.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DataBoutique-Webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", d.ID.Hex())
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Signature", Sign(w.Secret, time.Now(), payload))
+
+	resp, err := impl.HTTPClient.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyLength))
+	attempt.ResponseStatusCode = resp.StatusCode
+	attempt.ResponseBody = string(body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("endpoint responded with status code %d", resp.StatusCode)
+	}
+}
+
+func (impl *WebhookControllerImpl) ListDeliveriesByWebhookID(ctx context.Context, id primitive.ObjectID) (*webhook_s.WebhookDeliveryListResult, error) {
+	// Lookup the record or error.
+	if _, err := impl.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	res, err := impl.WebhookDeliveryStorer.ListByWebhookID(ctx, id, 100)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database list error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
+
+// SendTestEventByID function sends a test event to the webhook immediately
+// and returns the result. Test events are not retried.
+func (impl *WebhookControllerImpl) SendTestEventByID(ctx context.Context, id primitive.ObjectID) (*webhook_s.WebhookDelivery, error) {
+	w, err := impl.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if w.Status != webhook_s.WebhookStatusActive {
+		return nil, httperror.NewForBadRequestWithSingleField("status", "webhook is disabled")
+	}
+
+	payload, err := impl.newEventPayload(w.TenantID, webhook_s.EventTypeTest, map[string]string{
+		"message": "This is a test event.",
+	})
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "marshal event error", slog.Any("error", err))
+		return nil, err
+	}
+
+	d := &webhook_s.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		TenantID:  w.TenantID,
+		WebhookID: w.ID,
+		EventType: webhook_s.EventTypeTest,
+		Payload:   payload,
+		Status:    webhook_s.WebhookDeliveryStatusPending,
+		// Hide from the worker as we are sending it now.
+		NextAttemptAt: time.Now().Add(deliveryLease),
+		Attempts:      make([]*webhook_s.WebhookDeliveryAttempt, 0),
+		CreatedAt:     time.Now(),
+		ModifiedAt:    time.Now(),
+	}
+	if err := impl.WebhookDeliveryStorer.Create(ctx, d); err != nil {
+		impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+		return nil, err
+	}
+	impl.deliver(ctx, d, 1)
+	return d, nil
+}
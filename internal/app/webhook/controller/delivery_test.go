@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	payload := []byte(`{"type":"webhook.test"}`)
+
+	mac := hmac.New(sha256.New, []byte("whsec_secret"))
+	mac.Write([]byte("1700000000." + string(payload)))
+	want := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("whsec_secret", ts, payload); got != want {
+		t.Fatalf("expected %q but got %q", want, got)
+	}
+	if Sign("whsec_other", ts, payload) == want {
+		t.Fatal("expected a different signature for a different secret")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{
+		1: 30 * time.Second,
+		2: 60 * time.Second,
+		3: 120 * time.Second,
+	} {
+		if got := retryDelay(attempt); got != want {
+			t.Fatalf("attempt %d: expected %v but got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestHTTPClientBlocksPrivateNetwork(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	if _, err := newHTTPClient(true).Get(srv.URL); err == nil {
+		t.Fatal("expected an error when connecting to a loopback address")
+	}
+
+	res, err := newHTTPClient(false).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	res.Body.Close()
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+)
+
+// Event is the payload sent to the webhook endpoints.
+type Event struct {
+	ID        string             `json:"id"`
+	Type      string             `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	TenantID  primitive.ObjectID `json:"tenant_id"`
+	Data      interface{}        `json:"data"`
+}
+
+func (impl *WebhookControllerImpl) newEventPayload(tenantID primitive.ObjectID, eventType string, data interface{}) (string, error) {
+	payload, err := json.Marshal(&Event{
+		ID:        impl.UUID.NewUUID(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		TenantID:  tenantID,
+		Data:      data,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
+
+func (impl *WebhookControllerImpl) Publish(ctx context.Context, tenantID primitive.ObjectID, eventType string, data interface{}) error {
+	webhooks, err := impl.WebhookStorer.ListActiveByTenantIDAndEventType(ctx, tenantID, eventType)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "database list error", slog.Any("error", err))
+		return err
+	}
+	if len(webhooks.Results) == 0 {
+		return nil
+	}
+
+	// Every webhook receives the same event so the endpoints can detect
+	// duplicates using the event ID.
+	payload, err := impl.newEventPayload(tenantID, eventType, data)
+	if err != nil {
+		impl.Logger.ErrorContext(ctx, "marshal event error", slog.Any("error", err))
+		return err
+	}
+
+	for _, w := range webhooks.Results {
+		d := &webhook_s.WebhookDelivery{
+			ID:            primitive.NewObjectID(),
+			TenantID:      tenantID,
+			WebhookID:     w.ID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        webhook_s.WebhookDeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+			Attempts:      make([]*webhook_s.WebhookDeliveryAttempt, 0),
+			CreatedAt:     time.Now(),
+			ModifiedAt:    time.Now(),
+		}
+		if err := impl.WebhookDeliveryStorer.Create(ctx, d); err != nil {
+			impl.Logger.ErrorContext(ctx, "database create error", slog.Any("error", err))
+			return err
+		}
+	}
+	impl.Logger.DebugContext(ctx, "webhook event published",
+		slog.Any("tenant_id", tenantID),
+		slog.String("event_type", eventType),
+		slog.Int("webhooks", len(webhooks.Results)))
+	return nil
+}
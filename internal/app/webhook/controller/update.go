@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+type WebhookUpdateRequestIDO struct {
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	URL         string             `json:"url"`
+	EventTypes  []string           `json:"event_types"`
+	Status      int8               `json:"status"`
+}
+
+func (impl *WebhookControllerImpl) validateUpdateRequest(dirtyData *WebhookUpdateRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.ID.IsZero() {
+		e["id"] = "missing value"
+	}
+	if dirtyData.Name == "" {
+		e["name"] = "missing value"
+	}
+	impl.validateURL(dirtyData.URL, e)
+	validateEventTypes(dirtyData.EventTypes, e)
+	if dirtyData.Status != webhook_s.WebhookStatusActive && dirtyData.Status != webhook_s.WebhookStatusDisabled {
+		e["status"] = "invalid value"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (impl *WebhookControllerImpl) UpdateByID(ctx context.Context, requestData *WebhookUpdateRequestIDO) (*webhook_s.Webhook, error) {
+	//
+	// Get variables from our user authenticated session.
+	//
+
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+
+	if err := impl.validateUpdateRequest(requestData); err != nil {
+		impl.Logger.WarnContext(ctx, "validation error", slog.Any("error", err))
+		return nil, err
+	}
+
+	m, err := impl.GetByID(ctx, requestData.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Return `412 precondition failed` if the record was modified since the client retrieved it.
+	if err := versioning.CheckExpectedVersion(ctx, m.Version); err != nil {
+		return nil, err
+	}
+	before := *m
+
+	m.Name = requestData.Name
+	m.Description = requestData.Description
+	m.URL = requestData.URL
+	m.EventTypes = requestData.EventTypes
+	m.Status = requestData.Status
+	m.ModifiedAt = time.Now()
+	m.ModifiedByUserID = userID
+	m.ModifiedByUserName = userName
+	m.ModifiedFromIPAddress = ipAddress
+
+	if err := impl.WebhookStorer.UpdateByID(ctx, m); err != nil {
+		impl.Logger.ErrorContext(ctx, "database update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := impl.AuditEvent.Record(ctx, auditevent_s.AuditEventActionUpdate, auditevent_s.AuditEventResourceTypeWebhook, m.ID, &before, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,110 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	WebhookStatusActive   = 1
+	WebhookStatusDisabled = 2
+)
+
+const (
+	EventTypeExecutableCompleted        = "executable.completed"
+	EventTypeExecutableFailed           = "executable.failed"
+	EventTypeExecutableQuestionAnswered = "executable.question_answered"
+	EventTypeUploadFileProcessed        = "upload_file.processed"
+
+	// EventTypeTest is only sent by the "send test event" endpoint and
+	// cannot be subscribed to.
+	EventTypeTest = "webhook.test"
+)
+
+// EventTypes are the event types which webhooks can subscribe to.
+var EventTypes = []string{
+	EventTypeExecutableCompleted,
+	EventTypeExecutableFailed,
+	EventTypeExecutableQuestionAnswered,
+	EventTypeUploadFileProcessed,
+}
+
+type Webhook struct {
+	ID                    primitive.ObjectID `bson:"_id" json:"id"`
+	TenantID              primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	TenantName            string             `bson:"tenant_name" json:"tenant_name"`
+	Name                  string             `bson:"name" json:"name"`
+	Description           string             `bson:"description" json:"description"`
+	URL                   string             `bson:"url" json:"url"`
+	Secret                string             `bson:"secret" json:"-"`
+	EventTypes            []string           `bson:"event_types" json:"event_types"`
+	Status                int8               `bson:"status" json:"status"`
+	CreatedAt             time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID       primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id,omitempty"`
+	CreatedByUserName     string             `bson:"created_by_user_name" json:"created_by_user_name"`
+	CreatedFromIPAddress  string             `bson:"created_from_ip_address" json:"created_from_ip_address"`
+	ModifiedAt            time.Time          `bson:"modified_at" json:"modified_at"`
+	ModifiedByUserID      primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id,omitempty"`
+	ModifiedByUserName    string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
+	ModifiedFromIPAddress string             `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
+	Version               int64              `bson:"version" json:"version"`
+}
+
+// IsSubscribedTo function returns true if the webhook subscribed to the event type.
+func (m *Webhook) IsSubscribedTo(eventType string) bool {
+	for _, et := range m.EventTypes {
+		if et == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookListResult struct {
+	Results []*Webhook `json:"results"`
+}
+
+// WebhookStorer Interface for webhooks.
+type WebhookStorer interface {
+	Create(ctx context.Context, m *Webhook) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Webhook, error)
+	UpdateByID(ctx context.Context, m *Webhook) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	ListByTenantID(ctx context.Context, tid primitive.ObjectID) (*WebhookListResult, error)
+	ListActiveByTenantIDAndEventType(ctx context.Context, tid primitive.ObjectID, eventType string) (*WebhookListResult, error)
+}
+
+type WebhookStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) WebhookStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("webhooks")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "event_types", Value: 1}}},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &WebhookStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
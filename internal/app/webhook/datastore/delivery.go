@@ -0,0 +1,181 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	c "github.com/bartmika/databoutique-backend/internal/config"
+)
+
+const (
+	WebhookDeliveryStatusPending   = 1
+	WebhookDeliveryStatusSucceeded = 2
+	WebhookDeliveryStatusFailed    = 3
+)
+
+// The duration we keep the delivery logs before they are automatically
+// deleted by the database.
+const webhookDeliveryRetention = 30 * 24 * time.Hour
+
+type WebhookDelivery struct {
+	ID            primitive.ObjectID        `bson:"_id" json:"id"`
+	TenantID      primitive.ObjectID        `bson:"tenant_id" json:"tenant_id"`
+	WebhookID     primitive.ObjectID        `bson:"webhook_id" json:"webhook_id"`
+	EventType     string                    `bson:"event_type" json:"event_type"`
+	Payload       string                    `bson:"payload" json:"payload"`
+	Status        int8                      `bson:"status" json:"status"`
+	AttemptCount  int                       `bson:"attempt_count" json:"attempt_count"`
+	NextAttemptAt time.Time                 `bson:"next_attempt_at" json:"next_attempt_at"`
+	Attempts      []*WebhookDeliveryAttempt `bson:"attempts" json:"attempts"`
+	CreatedAt     time.Time                 `bson:"created_at" json:"created_at"`
+	ModifiedAt    time.Time                 `bson:"modified_at" json:"modified_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	AttemptedAt        time.Time `bson:"attempted_at" json:"attempted_at"`
+	ResponseStatusCode int       `bson:"response_status_code" json:"response_status_code"`
+	ResponseBody       string    `bson:"response_body" json:"response_body"`
+	Error              string    `bson:"error" json:"error,omitempty"`
+	DurationInMS       int64     `bson:"duration_in_ms" json:"duration_in_ms"`
+}
+
+type WebhookDeliveryListResult struct {
+	Results []*WebhookDelivery `json:"results"`
+}
+
+// WebhookDeliveryStorer Interface for webhook delivery logs.
+type WebhookDeliveryStorer interface {
+	Create(ctx context.Context, m *WebhookDelivery) error
+	UpdateByID(ctx context.Context, m *WebhookDelivery) error
+	ListByWebhookID(ctx context.Context, wid primitive.ObjectID, limit int64) (*WebhookDeliveryListResult, error)
+	DeleteByWebhookID(ctx context.Context, wid primitive.ObjectID) error
+	ClaimNextDue(ctx context.Context, now time.Time, leaseUntil time.Time) (*WebhookDelivery, error)
+	CountPending(ctx context.Context) (int64, error)
+}
+
+type WebhookDeliveryStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDeliveryDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) WebhookDeliveryStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("webhook_deliveries")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(webhookDeliveryRetention.Seconds()))},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &WebhookDeliveryStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
+
+func (impl WebhookDeliveryStorerImpl) Create(ctx context.Context, m *WebhookDelivery) error {
+	if m.ID == primitive.NilObjectID {
+		m.ID = primitive.NewObjectID()
+		impl.Logger.Warn("database insert webhook delivery not included id value, created id now.", slog.Any("id", m.ID))
+	}
+	if _, err := impl.Collection.InsertOne(ctx, m); err != nil {
+		impl.Logger.Error("database insert error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl WebhookDeliveryStorerImpl) UpdateByID(ctx context.Context, m *WebhookDelivery) error {
+	filter := bson.D{{"_id", m.ID}}
+
+	update := bson.M{ // DEVELOPERS NOTE: https://stackoverflow.com/a/60946010
+		"$set": m,
+	}
+
+	// execute the UpdateOne() function to update the first matching document
+	_, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl WebhookDeliveryStorerImpl) ListByWebhookID(ctx context.Context, wid primitive.ObjectID, limit int64) (*WebhookDeliveryListResult, error) {
+	filter := bson.M{"webhook_id": wid}
+	opts := options.Find().SetSort(bson.D{{"created_at", -1}}).SetLimit(limit)
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list by webhook id error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*WebhookDelivery{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database decode error", slog.Any("error", err))
+		return nil, err
+	}
+	return &WebhookDeliveryListResult{Results: results}, nil
+}
+
+func (impl WebhookDeliveryStorerImpl) DeleteByWebhookID(ctx context.Context, wid primitive.ObjectID) error {
+	if _, err := impl.Collection.DeleteMany(ctx, bson.M{"webhook_id": wid}); err != nil {
+		impl.Logger.Error("database delete by webhook id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// ClaimNextDue function returns the next pending delivery which is due and
+// pushes back its next attempt time until the lease expires. We do this
+// atomically so multiple instances of our application do not send the same
+// delivery; if the instance crashes the delivery is retried after the lease.
+func (impl WebhookDeliveryStorerImpl) ClaimNextDue(ctx context.Context, now time.Time, leaseUntil time.Time) (*WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          WebhookDeliveryStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{"next_attempt_at", 1}}).
+		SetReturnDocument(options.After)
+
+	var result WebhookDelivery
+	err := impl.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		impl.Logger.Error("database claim next due error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CountPending function returns the number of deliveries waiting to be sent.
+func (impl WebhookDeliveryStorerImpl) CountPending(ctx context.Context) (int64, error) {
+	count, err := impl.Collection.CountDocuments(ctx, bson.M{"status": WebhookDeliveryStatusPending})
+	if err != nil {
+		impl.Logger.Error("database count pending error", slog.Any("error", err))
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,40 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (impl WebhookStorerImpl) ListByTenantID(ctx context.Context, tid primitive.ObjectID) (*WebhookListResult, error) {
+	return impl.list(ctx, bson.M{"tenant_id": tid})
+}
+
+func (impl WebhookStorerImpl) ListActiveByTenantIDAndEventType(ctx context.Context, tid primitive.ObjectID, eventType string) (*WebhookListResult, error) {
+	return impl.list(ctx, bson.M{
+		"tenant_id":   tid,
+		"status":      WebhookStatusActive,
+		"event_types": eventType,
+	})
+}
+
+func (impl WebhookStorerImpl) list(ctx context.Context, filter bson.M) (*WebhookListResult, error) {
+	opts := options.Find().SetSort(bson.D{{"created_at", -1}})
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Webhook{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database decode error", slog.Any("error", err))
+		return nil, err
+	}
+	return &WebhookListResult{Results: results}, nil
+}
@@ -0,0 +1,54 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	webhook_c "github.com/bartmika/databoutique-backend/internal/app/webhook/controller"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+func UnmarshalUpdateRequest(ctx context.Context, r *http.Request) (*webhook_c.WebhookUpdateRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData webhook_c.WebhookUpdateRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	return &requestData, nil
+}
+
+func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	data, err := UnmarshalUpdateRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	data.ID, err = primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.UpdateByID(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", versioning.ETag(res.Version))
+	MarshalDetailResponse(res, w)
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"log"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -11,10 +14,14 @@ import (
 type Conf struct {
 	InitialAccount initialAccountConf
 	AppServer      serverConf
+	HTTP           httpConfig
 	DB             dbConfig
 	AWS            awsConfig
-	Emailer        mailgunConfig
+	Emailer        emailerConfig
 	PDFBuilder     pdfBuilderConfig
+	Log            logConfig
+	Tracing        tracingConfig
+	RateLimit      rateLimitConfig
 }
 
 type initialAccountConf struct {
@@ -32,6 +39,92 @@ type serverConf struct {
 	HMACSecret   []byte
 	HasDebugging bool
 	DomainName   string
+
+	// If true then anyone can register a new tenant along with its first
+	// management user who only has access to the new tenant.
+	HasTenantSelfSignup bool
+
+	// The bearer token the `/metrics` endpoint requires. It is required
+	// unless we are debugging.
+	MetricsToken string
+
+	// If true then our executives can verify OpenAI is reachable with the
+	// credentials of every active tenant.
+	HasOpenAIHealthCheck bool
+
+	// How long the responses of requests sent with an `Idempotency-Key`
+	// header are kept so the retries of the request can be replayed.
+	IdempotencyKeyWindow time.Duration
+}
+
+type httpConfig struct {
+	// The origins allowed to call our API from a browser, ex:
+	// `https://databoutique.ca`, or `*` to allow any origin.
+	CORSAllowedOrigins []string
+
+	// The methods allowed to be sent by the allowed origins.
+	CORSAllowedMethods []string
+
+	// How long browsers must only connect to our API over HTTPS. Zero
+	// disables the `Strict-Transport-Security` header which is the default
+	// while debugging locally.
+	HSTSMaxAge time.Duration
+
+	// The maximum size in bytes of the body of our JSON requests.
+	MaxBodySize int64
+
+	// The maximum size in bytes of the body of our file upload requests.
+	MaxUploadBodySize int64
+
+	// The paths of the PEM encoded certificate and private key. If set then
+	// our server terminates TLS itself and serves HTTP/2, which is useful
+	// when deployed without a reverse proxy. The files are reloaded once
+	// they change so renewed certificates do not require a restart.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// How long a client may take to send the headers of a request.
+	ReadHeaderTimeout time.Duration
+
+	// How long a keep-alive connection is kept open between requests.
+	IdleTimeout time.Duration
+
+	// The IP ranges of our reverse proxies, ex: `172.16.0.0/12`. The
+	// `X-Forwarded-For` and `X-Real-Ip` headers are only read from requests
+	// sent by these proxies since any client may set them.
+	TrustedProxies []*net.IPNet
+}
+
+type rateLimitConfig struct {
+	// The backend keeping our token buckets, either `memory` which is local
+	// to this instance or `mongodb` which is shared by all our replicas.
+	Backend string
+
+	// The limits of each class of routes which are applied to every IP
+	// address and user, see the `RateLimitClass` of our routes.
+	Auth        RateLimit
+	AIOperation RateLimit
+	Read        RateLimit
+	Write       RateLimit
+
+	// The limits of a tenant are the limits of a user multiplied by this
+	// value since the tenant is shared by all its users.
+	TenantMultiplier int
+
+	// How long a token bucket is kept after its last request. It must be
+	// longer than the period of every limit.
+	IdleTimeout time.Duration
+
+	// The maximum number of token buckets kept by the `memory` backend so
+	// requests from many addresses cannot exhaust our memory.
+	MaxBuckets int
+}
+
+// RateLimit is a token bucket which allows a burst of up to `Requests`
+// requests and refills at the rate of `Requests` per `Period`.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
 }
 
 type dbConfig struct {
@@ -47,11 +140,44 @@ type awsConfig struct {
 	BucketName string
 }
 
-type mailgunConfig struct {
-	APIKey      string
-	Domain      string
-	APIBase     string
+type emailerConfig struct {
+	// The email transport to use, one of `mailgun`, `smtp`, `filedrop` or
+	// `memory`. Defaults to `mailgun` if the Mailgun API key was set, else
+	// `filedrop` while debugging so local development does not require an
+	// email service. Production must set one of them.
+	Backend     string
 	SenderEmail string
+
+	// Mailgun related.
+	APIKey  string
+	Domain  string
+	APIBase string
+
+	// SMTP related.
+	SMTPHost           string
+	SMTPPort           string
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPHasImplicitTLS bool
+
+	// File-drop related.
+	FileDropDirectoryPath string
+}
+
+type logConfig struct {
+	// The log output format, one of `text` or `json`.
+	Format string
+
+	// The minimum level to log, one of `debug`, `info`, `warn` or `error`.
+	// Defaults to `debug` when debugging is enabled, else `info`.
+	Level string
+}
+
+type tracingConfig struct {
+	// The URL of the OTLP/HTTP collector, ex: `http://localhost:4318`. If
+	// empty then tracing is disabled.
+	OTLPEndpoint string
+	ServiceName  string
 }
 
 type pdfBuilderConfig struct {
@@ -73,6 +199,75 @@ func New() *Conf {
 	c.AppServer.HMACSecret = []byte(getEnv("DATABOUTIQUE_BACKEND_HMAC_SECRET", true))
 	c.AppServer.HasDebugging = getEnvBool("DATABOUTIQUE_BACKEND_HAS_DEBUGGING", true, true)
 	c.AppServer.DomainName = getEnv("DATABOUTIQUE_BACKEND_DOMAIN_NAME", true)
+	c.AppServer.HasTenantSelfSignup = getEnvBool("DATABOUTIQUE_BACKEND_HAS_TENANT_SELF_SIGNUP", false, false)
+	c.AppServer.MetricsToken = getEnv("DATABOUTIQUE_BACKEND_METRICS_TOKEN", false)
+	if c.AppServer.MetricsToken == "" && !c.AppServer.HasDebugging {
+		log.Fatal("Environment variable not found: DATABOUTIQUE_BACKEND_METRICS_TOKEN is required when not debugging")
+	}
+	c.AppServer.HasOpenAIHealthCheck = getEnvBool("DATABOUTIQUE_BACKEND_HAS_OPENAI_HEALTH_CHECK", false, false)
+	c.AppServer.IdempotencyKeyWindow = getEnvDuration("DATABOUTIQUE_BACKEND_IDEMPOTENCY_KEY_WINDOW", 24*time.Hour)
+
+	c.Log.Format = getEnvString("DATABOUTIQUE_BACKEND_LOG_FORMAT", "text")
+	switch c.Log.Format {
+	case "text", "json":
+	default:
+		log.Fatalf("Invalid value for environment variable DATABOUTIQUE_BACKEND_LOG_FORMAT: %s", c.Log.Format)
+	}
+	c.Log.Level = getEnv("DATABOUTIQUE_BACKEND_LOG_LEVEL", false)
+	if c.Log.Level == "" {
+		c.Log.Level = "info"
+		if c.AppServer.HasDebugging {
+			c.Log.Level = "debug"
+		}
+	}
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		log.Fatalf("Invalid value for environment variable DATABOUTIQUE_BACKEND_LOG_LEVEL: %s", c.Log.Level)
+	}
+
+	// Browsers of any origin may call our API while debugging locally else
+	// only our web frontend is allowed.
+	if c.AppServer.HasDebugging {
+		c.HTTP.CORSAllowedOrigins = getEnvList("DATABOUTIQUE_BACKEND_CORS_ALLOWED_ORIGINS", []string{"*"})
+		c.HTTP.HSTSMaxAge = getEnvDuration("DATABOUTIQUE_BACKEND_HSTS_MAX_AGE", 0)
+	} else {
+		c.HTTP.CORSAllowedOrigins = getEnvList("DATABOUTIQUE_BACKEND_CORS_ALLOWED_ORIGINS", []string{"https://" + c.AppServer.DomainName})
+		c.HTTP.HSTSMaxAge = getEnvDuration("DATABOUTIQUE_BACKEND_HSTS_MAX_AGE", 365*24*time.Hour)
+	}
+	c.HTTP.CORSAllowedMethods = getEnvList("DATABOUTIQUE_BACKEND_CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
+	c.HTTP.MaxBodySize = int64(getEnvInt("DATABOUTIQUE_BACKEND_MAX_BODY_SIZE", 1<<20))               // 1MB
+	c.HTTP.MaxUploadBodySize = int64(getEnvInt("DATABOUTIQUE_BACKEND_MAX_UPLOAD_BODY_SIZE", 64<<20)) // 64MB
+	c.HTTP.TLSCertFile = getEnv("DATABOUTIQUE_BACKEND_TLS_CERT_FILE", false)
+	c.HTTP.TLSKeyFile = getEnv("DATABOUTIQUE_BACKEND_TLS_KEY_FILE", false)
+	if (c.HTTP.TLSCertFile == "") != (c.HTTP.TLSKeyFile == "") {
+		log.Fatal("Environment variables not found: DATABOUTIQUE_BACKEND_TLS_CERT_FILE and DATABOUTIQUE_BACKEND_TLS_KEY_FILE must be set together")
+	}
+	c.HTTP.ReadHeaderTimeout = getEnvDuration("DATABOUTIQUE_BACKEND_READ_HEADER_TIMEOUT", 10*time.Second)
+	c.HTTP.IdleTimeout = getEnvDuration("DATABOUTIQUE_BACKEND_IDLE_TIMEOUT", 2*time.Minute)
+	c.HTTP.TrustedProxies = getEnvIPNetList("DATABOUTIQUE_BACKEND_TRUSTED_PROXIES")
+
+	c.RateLimit.Backend = getEnvString("DATABOUTIQUE_BACKEND_RATE_LIMIT_BACKEND", "memory")
+	switch c.RateLimit.Backend {
+	case "memory", "mongodb":
+	default:
+		log.Fatalf("Invalid value for environment variable DATABOUTIQUE_BACKEND_RATE_LIMIT_BACKEND: %s", c.RateLimit.Backend)
+	}
+	c.RateLimit.Auth = getEnvRateLimit("DATABOUTIQUE_BACKEND_RATE_LIMIT_AUTH", RateLimit{Requests: 10, Period: time.Minute})
+	c.RateLimit.AIOperation = getEnvRateLimit("DATABOUTIQUE_BACKEND_RATE_LIMIT_AI_OPERATION", RateLimit{Requests: 20, Period: time.Minute})
+	c.RateLimit.Read = getEnvRateLimit("DATABOUTIQUE_BACKEND_RATE_LIMIT_READ", RateLimit{Requests: 300, Period: time.Minute})
+	c.RateLimit.Write = getEnvRateLimit("DATABOUTIQUE_BACKEND_RATE_LIMIT_WRITE", RateLimit{Requests: 60, Period: time.Minute})
+	c.RateLimit.TenantMultiplier = getEnvInt("DATABOUTIQUE_BACKEND_RATE_LIMIT_TENANT_MULTIPLIER", 10)
+	c.RateLimit.IdleTimeout = getEnvDuration("DATABOUTIQUE_BACKEND_RATE_LIMIT_IDLE_TIMEOUT", 10*time.Minute)
+	c.RateLimit.MaxBuckets = getEnvInt("DATABOUTIQUE_BACKEND_RATE_LIMIT_MAX_BUCKETS", 100000)
+	for _, limit := range []RateLimit{c.RateLimit.Auth, c.RateLimit.AIOperation, c.RateLimit.Read, c.RateLimit.Write} {
+		if limit.Period > c.RateLimit.IdleTimeout {
+			log.Fatal("Invalid value for environment variable DATABOUTIQUE_BACKEND_RATE_LIMIT_IDLE_TIMEOUT: must be longer than the period of every rate limit")
+		}
+	}
+
+	c.Tracing.OTLPEndpoint = getEnv("DATABOUTIQUE_BACKEND_OTEL_EXPORTER_OTLP_ENDPOINT", false)
+	c.Tracing.ServiceName = getEnvString("DATABOUTIQUE_BACKEND_OTEL_SERVICE_NAME", "databoutique-backend")
 
 	c.DB.URI = getEnv("DATABOUTIQUE_BACKEND_DB_URI", true)
 	c.DB.Name = getEnv("DATABOUTIQUE_BACKEND_DB_NAME", true)
@@ -83,10 +278,44 @@ func New() *Conf {
 	c.AWS.Region = getEnv("DATABOUTIQUE_BACKEND_AWS_REGION", true)
 	c.AWS.BucketName = getEnv("DATABOUTIQUE_BACKEND_AWS_BUCKET_NAME", true)
 
-	c.Emailer.APIKey = getEnv("DATABOUTIQUE_BACKEND_MAILGUN_API_KEY", true)
-	c.Emailer.Domain = getEnv("DATABOUTIQUE_BACKEND_MAILGUN_DOMAIN", true)
-	c.Emailer.APIBase = getEnv("DATABOUTIQUE_BACKEND_MAILGUN_API_BASE", true)
-	c.Emailer.SenderEmail = getEnv("DATABOUTIQUE_BACKEND_MAILGUN_SENDER_EMAIL", true)
+	c.Emailer.APIKey = getEnv("DATABOUTIQUE_BACKEND_MAILGUN_API_KEY", false)
+	c.Emailer.Domain = getEnv("DATABOUTIQUE_BACKEND_MAILGUN_DOMAIN", false)
+	c.Emailer.APIBase = getEnv("DATABOUTIQUE_BACKEND_MAILGUN_API_BASE", false)
+	c.Emailer.SMTPHost = getEnv("DATABOUTIQUE_BACKEND_SMTP_HOST", false)
+	c.Emailer.SMTPPort = getEnvString("DATABOUTIQUE_BACKEND_SMTP_PORT", "587")
+	c.Emailer.SMTPUsername = getEnv("DATABOUTIQUE_BACKEND_SMTP_USERNAME", false)
+	c.Emailer.SMTPPassword = getEnv("DATABOUTIQUE_BACKEND_SMTP_PASSWORD", false)
+	c.Emailer.SMTPHasImplicitTLS = getEnvBool("DATABOUTIQUE_BACKEND_SMTP_HAS_IMPLICIT_TLS", false, false)
+	c.Emailer.FileDropDirectoryPath = getEnvString("DATABOUTIQUE_BACKEND_EMAILER_FILE_DROP_DIRECTORY_PATH", "./emails")
+	c.Emailer.SenderEmail = getEnv("DATABOUTIQUE_BACKEND_EMAILER_SENDER_EMAIL", false)
+	if c.Emailer.SenderEmail == "" {
+		// Backwards compatibility with the original environment variable.
+		c.Emailer.SenderEmail = getEnvString("DATABOUTIQUE_BACKEND_MAILGUN_SENDER_EMAIL", "no-reply@"+c.AppServer.DomainName)
+	}
+	c.Emailer.Backend = getEnv("DATABOUTIQUE_BACKEND_EMAILER_BACKEND", false)
+	if c.Emailer.Backend == "" {
+		switch {
+		case c.Emailer.APIKey != "":
+			c.Emailer.Backend = "mailgun"
+		case c.AppServer.HasDebugging:
+			c.Emailer.Backend = "filedrop"
+		default:
+			log.Fatal("Environment variable not found: DATABOUTIQUE_BACKEND_EMAILER_BACKEND or DATABOUTIQUE_BACKEND_MAILGUN_API_KEY is required when not debugging")
+		}
+	}
+	switch c.Emailer.Backend {
+	case "mailgun":
+		if c.Emailer.APIKey == "" || c.Emailer.Domain == "" || c.Emailer.APIBase == "" {
+			log.Fatal("Environment variables not found: DATABOUTIQUE_BACKEND_MAILGUN_API_KEY, DATABOUTIQUE_BACKEND_MAILGUN_DOMAIN and DATABOUTIQUE_BACKEND_MAILGUN_API_BASE are required by the mailgun emailer")
+		}
+	case "smtp":
+		if c.Emailer.SMTPHost == "" {
+			log.Fatal("Environment variable not found: DATABOUTIQUE_BACKEND_SMTP_HOST is required by the smtp emailer")
+		}
+	case "filedrop", "memory":
+	default:
+		log.Fatalf("Invalid value for environment variable DATABOUTIQUE_BACKEND_EMAILER_BACKEND: %s", c.Emailer.Backend)
+	}
 
 	c.PDFBuilder.DataDirectoryPath = getEnv("DATABOUTIQUE_BACKEND_PDF_BUILDER_DATA_DIRECTORY_PATH", true)
 	c.PDFBuilder.AssociateInvoiceTemplatePath = getEnv("DATABOUTIQUE_BACKEND_PDF_BUILDER_ASSOCIATE_INVOICE_PATH", true)
@@ -102,6 +331,16 @@ func getEnv(key string, required bool) string {
 	return value
 }
 
+// getEnvString function returns the value of the optional environment
+// variable or the default value if it was not set.
+func getEnvString(key string, defaultValue string) string {
+	value := getEnv(key, false)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
 func getEnvBool(key string, required bool, defaultValue bool) bool {
 	valueStr := getEnv(key, required)
 	if valueStr == "" {
@@ -114,6 +353,92 @@ func getEnvBool(key string, required bool, defaultValue bool) bool {
 	return value
 }
 
+// getEnvDuration function returns the optional duration environment variable,
+// ex: `24h`, or the default value if it was not set.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, false)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil || value <= 0 {
+		log.Fatalf("Invalid duration value for environment variable %s", key)
+	}
+	return value
+}
+
+// getEnvInt function returns the optional positive integer environment
+// variable or the default value if it was not set.
+func getEnvInt(key string, defaultValue int) int {
+	valueStr := getEnv(key, false)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value <= 0 {
+		log.Fatalf("Invalid integer value for environment variable %s", key)
+	}
+	return value
+}
+
+// getEnvList function returns the optional comma-separated environment
+// variable, ex: `GET,POST`, or the default value if it was not set.
+func getEnvList(key string, defaultValue []string) []string {
+	valueStr := getEnv(key, false)
+	if valueStr == "" {
+		return defaultValue
+	}
+	var values []string
+	for _, value := range strings.Split(valueStr, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvIPNetList function returns the optional comma-separated list of IP
+// ranges, ex: `10.0.0.0/8,192.168.1.10`, where a single IP address is a range
+// of one address.
+func getEnvIPNetList(key string) []*net.IPNet {
+	var ipNets []*net.IPNet
+	for _, value := range getEnvList(key, nil) {
+		if !strings.Contains(value, "/") {
+			if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			log.Fatalf("Invalid IP range value for environment variable %s: %s", key, value)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets
+}
+
+// getEnvRateLimit function returns the optional rate limit environment
+// variable in the `<requests>/<period>` format, ex: `10/1m`, or the default
+// value if it was not set.
+func getEnvRateLimit(key string, defaultValue RateLimit) RateLimit {
+	valueStr := getEnv(key, false)
+	if valueStr == "" {
+		return defaultValue
+	}
+	requestsStr, periodStr, ok := strings.Cut(valueStr, "/")
+	requests, err := strconv.Atoi(requestsStr)
+	if !ok || err != nil || requests <= 0 {
+		log.Fatalf("Invalid rate limit value for environment variable %s", key)
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period < time.Millisecond {
+		log.Fatalf("Invalid rate limit value for environment variable %s", key)
+	}
+	return RateLimit{Requests: requests, Period: period}
+}
+
 func getObjectIDEnv(key string, required bool) primitive.ObjectID {
 	value := os.Getenv(key)
 	if required && value == "" {
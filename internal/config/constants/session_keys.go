@@ -7,6 +7,7 @@ const (
 	SessionSkipAuthorization
 	SessionID
 	SessionIPAddress
+	SessionUserAgent
 	SessionUser
 	SessionUserCompanyName
 	SessionUserRole
@@ -20,4 +21,6 @@ const (
 	SessionUserLastName
 	SessionUserTenantID
 	SessionUserTenantName
+	SessionAPIKey
+	SessionRequestID
 )
@@ -1,34 +1,47 @@
 package httptransport
 
 import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
+	"log"
 	"log/slog"
 	"net/http"
 
-	"github.com/rs/cors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
+	apikey "github.com/bartmika/databoutique-backend/internal/app/apikey/httptransport"
 	assistant "github.com/bartmika/databoutique-backend/internal/app/assistant/httptransport"
 	assistantfile "github.com/bartmika/databoutique-backend/internal/app/assistantfile/httptransport"
 	assistantmessage "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/httptransport"
 	assistantthread "github.com/bartmika/databoutique-backend/internal/app/assistantthread/httptransport"
 	attachment "github.com/bartmika/databoutique-backend/internal/app/attachment/httptransport"
+	auditevent "github.com/bartmika/databoutique-backend/internal/app/auditevent/httptransport"
 
 	executable "github.com/bartmika/databoutique-backend/internal/app/executable/httptransport"
 	gateway "github.com/bartmika/databoutique-backend/internal/app/gateway/httptransport"
+	health "github.com/bartmika/databoutique-backend/internal/app/health/httptransport"
 	howhear "github.com/bartmika/databoutique-backend/internal/app/howhear/httptransport"
+	invitation "github.com/bartmika/databoutique-backend/internal/app/invitation/httptransport"
 	program "github.com/bartmika/databoutique-backend/internal/app/program/httptransport"
 	programcategory "github.com/bartmika/databoutique-backend/internal/app/programcategory/httptransport"
 	tenant "github.com/bartmika/databoutique-backend/internal/app/tenant/httptransport"
 	uploaddirectory "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/httptransport"
 	uploadfile "github.com/bartmika/databoutique-backend/internal/app/uploadfile/httptransport"
 	user "github.com/bartmika/databoutique-backend/internal/app/user/httptransport"
+	webhook "github.com/bartmika/databoutique-backend/internal/app/webhook/httptransport"
 	"github.com/bartmika/databoutique-backend/internal/config"
 	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/middleware"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/openapi"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 type InputPortServer interface {
 	Run()
-	Shutdown()
+	Shutdown(ctx context.Context) error
 }
 
 type httpTransportInputPort struct {
@@ -36,6 +49,8 @@ type httpTransportInputPort struct {
 	Logger           *slog.Logger
 	Server           *http.Server
 	Middleware       middleware.Middleware
+	Router           *router.Router
+	OpenAPI          *openapi.Document
 	Tenant           *tenant.Handler
 	Gateway          *gateway.Handler
 	User             *user.Handler
@@ -50,6 +65,11 @@ type httpTransportInputPort struct {
 	UploadFile       *uploadfile.Handler
 	Program          *program.Handler
 	Executable       *executable.Handler
+	APIKey           *apikey.Handler
+	Invitation       *invitation.Handler
+	Webhook          *webhook.Handler
+	AuditEvent       *auditevent.Handler
+	Health           *health.Handler
 }
 
 func NewInputPort(
@@ -70,20 +90,49 @@ func NewInputPort(
 	upfile *uploadfile.Handler,
 	prog *program.Handler,
 	exec *executable.Handler,
+	apik *apikey.Handler,
+	inv *invitation.Handler,
+	wh *webhook.Handler,
+	ae *auditevent.Handler,
+	hlth *health.Handler,
 ) InputPortServer {
 	// Initialize the ServeMux.
 	mux := http.NewServeMux()
 
-	// cors.Default() setup the middleware with default options being
-	// all origins accepted with simple methods (GET, POST). See
-	// documentation via `https://github.com/rs/cors` for more options.
-	handler := cors.AllowAll().Handler(mux)
+	// Only the origins and methods of our configuration are allowed to call
+	// our API from a browser. See documentation via
+	// `https://github.com/rs/cors` for more options.
+	handler := newCORS(configp).Handler(mux)
+	handler = securityHeaders(configp, handler)
+
+	// Start a trace span for every API call, the span is renamed to the
+	// matched route by our middleware once the request was routed.
+	handler = otelhttp.NewHandler(handler, "http.request",
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/metrics"
+		}),
+	)
 
 	// Bind the HTTP server to the assigned address and port.
 	addr := fmt.Sprintf("%s:%s", configp.AppServer.IP, configp.AppServer.Port)
 	srv := &http.Server{
-		Addr:    addr,
-		Handler: handler,
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: configp.HTTP.ReadHeaderTimeout,
+		IdleTimeout:       configp.HTTP.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(loggerp.Handler(), slog.LevelWarn),
+	}
+
+	// Terminate TLS ourselves if a certificate was configured, ex: on-prem
+	// deployments without a reverse proxy.
+	if configp.HTTP.TLSCertFile != "" {
+		certs, err := newCertificateReloader(loggerp, configp.HTTP.TLSCertFile, configp.HTTP.TLSKeyFile)
+		if err != nil {
+			// It is important that we crash the app on startup to meet the
+			// requirements of `google/wire` framework.
+			log.Fatalf("failed loading TLS certificate: %v", err)
+		}
+		srv.TLSConfig = certs.TLSConfig()
 	}
 
 	// Create our HTTP server controller.
@@ -105,18 +154,40 @@ func NewInputPort(
 		UploadFile:       upfile,
 		Program:          prog,
 		Executable:       exec,
+		APIKey:           apik,
+		Invitation:       inv,
+		Webhook:          wh,
+		AuditEvent:       ae,
+		Health:           hlth,
 		Server:           srv,
 	}
+	p.Router = router.New(p.routes())
+	p.OpenAPI = openapi.Generate(openapi.Info{Title: "Data Boutique API", Version: "v1.0"}, p.Router.Routes())
 
 	// Attach the HTTP server controller to the ServerMux.
-	mux.HandleFunc("/", mid.Attach(p.HandleRequests))
+	mux.HandleFunc("/", mid.Attach(p.Router, p.HandleRequests))
+
+	// Expose our Prometheus metrics outside of our API middleware so scrapes
+	// are not rate limited nor counted as API calls.
+	mux.HandleFunc("/metrics", p.Metrics)
+
+	// Serve the Swagger UI outside of our API middleware since it returns
+	// HTML and static assets instead of JSON.
+	mux.Handle("/api/v1/docs/", http.StripPrefix("/api/v1/docs", openapi.SwaggerUIHandler("/api/v1/openapi.json")))
 
 	return p
 }
 
 func (port *httpTransportInputPort) Run() {
-	port.Logger.Info("HTTP server running")
-	if err := port.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	var err error
+	if port.Server.TLSConfig != nil {
+		port.Logger.Info("HTTPS server running", slog.String("addr", port.Server.Addr))
+		err = port.Server.ListenAndServeTLS("", "") // Note: Our certificates are provided by the TLS configuration.
+	} else {
+		port.Logger.Info("HTTP server running", slog.String("addr", port.Server.Addr))
+		err = port.Server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		port.Logger.Error("listen failed", slog.Any("error", err))
 
 		// DEVELOPERS NOTE: We terminate app here b/c dependency injection not allowed to fail, so fail here at startup of app.
@@ -124,254 +195,55 @@ func (port *httpTransportInputPort) Run() {
 	}
 }
 
-func (port *httpTransportInputPort) Shutdown() {
+// Shutdown function stops accepting new connections and waits for the
+// in-flight requests to finish or for the context to expire.
+func (port *httpTransportInputPort) Shutdown(ctx context.Context) error {
+	port.Logger.Info("HTTP server shutting down...")
+	if err := port.Server.Shutdown(ctx); err != nil {
+		port.Logger.Error("HTTP server did not drain in-flight requests", slog.Any("error", err))
+		return err
+	}
 	port.Logger.Info("HTTP server shutdown")
+	return nil
 }
 
+// Metrics function exposes our Prometheus metrics. The scraper must provide
+// our metrics token as a bearer token; only while debugging the metrics are
+// public if no token was configured.
+func (port *httpTransportInputPort) Metrics(w http.ResponseWriter, r *http.Request) {
+	if !isMetricsRequestAuthorized(port.Config, r) {
+		httperror.ResponseError(w, httperror.NewForUnauthorized("unauthorized"))
+		return
+	}
+	metrics.Handler().ServeHTTP(w, r)
+}
+
+func isMetricsRequestAuthorized(cfg *config.Conf, r *http.Request) bool {
+	token := cfg.AppServer.MetricsToken
+	if token == "" {
+		return cfg.AppServer.HasDebugging
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+// OpenAPISpecification function returns the OpenAPI 3 document generated from
+// our route table.
+func (port *httpTransportInputPort) OpenAPISpecification(w http.ResponseWriter, r *http.Request) {
+	if err := json.NewEncoder(w).Encode(port.OpenAPI); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+}
+
+// HandleRequests function calls the handler of the route matched by our
+// router middleware.
 func (port *httpTransportInputPort) HandleRequests(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	// Get our URL paths which are slash-seperated.
-	ctx := r.Context()
-	p := ctx.Value("url_split").([]string)
-	n := len(p)
+	route := router.RouteFromContext(r.Context())
 	port.Logger.Debug("Handling request",
-		slog.Int("n", n),
 		slog.String("m", r.Method),
-		slog.Any("p", p),
+		slog.String("route", route.Pattern),
 	)
-
-	switch {
-	// --- GATEWAY & PROFILE --- //
-	case n == 3 && p[1] == "v1" && p[2] == "health-check" && r.Method == http.MethodGet:
-		port.Gateway.HealthCheck(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "version" && r.Method == http.MethodGet:
-		port.Gateway.Version(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "greeting" && r.Method == http.MethodPost:
-		port.Gateway.Greet(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "login" && r.Method == http.MethodPost:
-		port.Gateway.Login(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "register" && r.Method == http.MethodPost:
-		port.Gateway.UserRegister(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "refresh-token" && r.Method == http.MethodPost:
-		port.Gateway.RefreshToken(w, r)
-	// case n == 3 && p[1] == "v1" && p[2] == "verify" && r.Method == http.MethodPost:
-	// 	port.Gateway.Verify(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "logout" && r.Method == http.MethodPost:
-		port.Gateway.Logout(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "profile" && r.Method == http.MethodGet:
-		port.Gateway.Profile(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "profile" && r.Method == http.MethodPut:
-		port.Gateway.ProfileUpdate(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "profile" && p[3] == "change-password" && r.Method == http.MethodPut:
-		port.Gateway.ProfileChangePassword(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "forgot-password" && r.Method == http.MethodPost:
-		port.Gateway.ForgotPassword(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "password-reset" && r.Method == http.MethodPost:
-		port.Gateway.PasswordReset(w, r)
-		// case n == 3 && p[1] == "v1" && p[2] == "profile" && r.Method == http.MethodGet:
-	case n == 3 && p[1] == "v1" && p[2] == "executive-visit-tenant" && r.Method == http.MethodPost:
-		port.Gateway.ExecutiveVisitsTenant(w, r)
-
-	// // --- DASHBOARD --- //
-	// case n == 3 && p[1] == "v1" && p[2] == "dashboard" && r.Method == http.MethodGet:
-	// 	port.Dashboard.Dashboard(w, r)
-	// 	// ...
-
-	// --- ORGANIZATION --- //
-	case n == 3 && p[1] == "v1" && p[2] == "tenants" && r.Method == http.MethodGet:
-		port.Tenant.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "tenants" && r.Method == http.MethodPost:
-		port.Tenant.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "tenant" && r.Method == http.MethodGet:
-		port.Tenant.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "tenant" && r.Method == http.MethodPut:
-		port.Tenant.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "tenant" && r.Method == http.MethodDelete:
-		port.Tenant.DeleteByID(w, r, p[3])
-	case n == 5 && p[1] == "v1" && p[2] == "tenants" && p[3] == "operation" && p[4] == "create-comment" && r.Method == http.MethodPost:
-		port.Tenant.OperationCreateComment(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "tenants" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.Tenant.ListAsSelectOptionByFilter(w, r)
-
-	// --- UPLOAD DIRECTORY --- //
-	case n == 3 && p[1] == "v1" && p[2] == "upload-directories" && r.Method == http.MethodGet:
-		port.UploadDirectory.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "upload-directories" && r.Method == http.MethodPost:
-		port.UploadDirectory.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "upload-directory" && r.Method == http.MethodGet:
-		port.UploadDirectory.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "upload-directory" && r.Method == http.MethodPut:
-		port.UploadDirectory.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "upload-directory" && r.Method == http.MethodDelete:
-		port.UploadDirectory.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "upload-directories" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.UploadDirectory.ListAsSelectOptionByFilter(w, r)
-
-	// --- UPLOAD FILE --- //
-	case n == 3 && p[1] == "v1" && p[2] == "upload-files" && r.Method == http.MethodGet:
-		port.UploadFile.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "upload-files" && r.Method == http.MethodPost:
-		port.UploadFile.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "upload-file" && r.Method == http.MethodGet:
-		port.UploadFile.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "upload-file" && r.Method == http.MethodPut:
-		port.UploadFile.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "upload-file" && r.Method == http.MethodDelete:
-		port.UploadFile.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "upload-files" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.UploadFile.ListAsSelectOptionByFilter(w, r)
-
-	// --- PROGRAM CATEGORY --- //
-	case n == 3 && p[1] == "v1" && p[2] == "program-categories" && r.Method == http.MethodGet:
-		port.ProgramCategory.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "program-categories" && r.Method == http.MethodPost:
-		port.ProgramCategory.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "program-category" && r.Method == http.MethodGet:
-		port.ProgramCategory.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "program-category" && r.Method == http.MethodPut:
-		port.ProgramCategory.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "program-category" && r.Method == http.MethodDelete:
-		port.ProgramCategory.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "program-categories" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.ProgramCategory.ListAsSelectOptionByFilter(w, r)
-
-	// --- PROGRAM --- //
-	case n == 3 && p[1] == "v1" && p[2] == "programs" && r.Method == http.MethodGet:
-		port.Program.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "programs" && r.Method == http.MethodPost:
-		port.Program.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "program" && r.Method == http.MethodGet:
-		port.Program.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "program" && r.Method == http.MethodPut:
-		port.Program.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "program" && r.Method == http.MethodDelete:
-		port.Program.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "programs" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.Program.ListAsSelectOptionByFilter(w, r)
-
-	// --- EXECUTABLE --- //
-	case n == 3 && p[1] == "v1" && p[2] == "executables" && r.Method == http.MethodGet:
-		port.Executable.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "executables" && r.Method == http.MethodPost:
-		port.Executable.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "executable" && r.Method == http.MethodGet:
-		port.Executable.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "executable" && r.Method == http.MethodPut:
-		port.Executable.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "executable" && r.Method == http.MethodDelete:
-		port.Executable.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "executables" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.Executable.ListAsSelectOptionByFilter(w, r)
-	case n == 5 && p[1] == "v1" && p[2] == "executables" && p[3] == "operations" && p[4] == "question-submission" && r.Method == http.MethodPost:
-		port.Executable.QuestionSubmissionOperation(w, r)
-
-	// --- ASSISTANT FILE --- //
-	case n == 3 && p[1] == "v1" && p[2] == "assistant-files" && r.Method == http.MethodGet:
-		port.AssistantFile.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "assistant-files" && r.Method == http.MethodPost:
-		port.AssistantFile.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-file" && r.Method == http.MethodGet:
-		port.AssistantFile.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-file" && r.Method == http.MethodPut:
-		port.AssistantFile.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-file" && r.Method == http.MethodDelete:
-		port.AssistantFile.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-files" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.AssistantFile.ListAsSelectOptionByFilter(w, r)
-
-	// --- ASSISTANT --- //
-	case n == 3 && p[1] == "v1" && p[2] == "assistants" && r.Method == http.MethodGet:
-		port.Assistant.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "assistants" && r.Method == http.MethodPost:
-		port.Assistant.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "assistant" && r.Method == http.MethodGet:
-		port.Assistant.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant" && r.Method == http.MethodPut:
-		port.Assistant.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant" && r.Method == http.MethodDelete:
-		port.Assistant.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistants" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.Assistant.ListAsSelectOptionByFilter(w, r)
-
-	// --- ASSISTANT THREAD --- //
-	case n == 3 && p[1] == "v1" && p[2] == "assistant-threads" && r.Method == http.MethodGet:
-		port.AssistantThread.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "assistant-threads" && r.Method == http.MethodPost:
-		port.AssistantThread.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-thread" && r.Method == http.MethodGet:
-		port.AssistantThread.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-thread" && r.Method == http.MethodPut:
-		port.AssistantThread.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-thread" && r.Method == http.MethodDelete:
-		port.AssistantThread.DeleteByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-threads" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.AssistantThread.ListAsSelectOptionByFilter(w, r)
-
-	// --- ASSISTANT MESSAGE --- //
-	case n == 3 && p[1] == "v1" && p[2] == "assistant-messages" && r.Method == http.MethodGet:
-		port.AssistantMessage.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "assistant-messages" && r.Method == http.MethodPost:
-		port.AssistantMessage.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-message" && r.Method == http.MethodGet:
-		port.AssistantMessage.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-message" && r.Method == http.MethodPut:
-		port.AssistantMessage.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "assistant-message" && r.Method == http.MethodDelete:
-		port.AssistantMessage.DeleteByID(w, r, p[3])
-	// case n == 4 && p[1] == "v1" && p[2] == "assistant-messages" && p[3] == "select-options" && r.Method == http.MethodGet:
-	// 	port.AssistantMessage.ListAsSelectOptionByFilter(w, r)
-
-	// // --- HOW HEAR --- //
-	// case n == 3 && p[1] == "v1" && p[2] == "how-hear-about-us-items" && r.Method == http.MethodGet:
-	// 	port.HowHear.List(w, r)
-	// case n == 3 && p[1] == "v1" && p[2] == "how-hear-about-us-items" && r.Method == http.MethodPost:
-	// 	port.HowHear.Create(w, r)
-	// case n == 4 && p[1] == "v1" && p[2] == "how-hear-about-us-item" && r.Method == http.MethodGet:
-	// 	port.HowHear.GetByID(w, r, p[3])
-	// case n == 4 && p[1] == "v1" && p[2] == "how-hear-about-us-item" && r.Method == http.MethodPut:
-	// 	port.HowHear.UpdateByID(w, r, p[3])
-	// case n == 4 && p[1] == "v1" && p[2] == "how-hear-about-us-item" && r.Method == http.MethodDelete:
-	// 	port.HowHear.DeleteByID(w, r, p[3])
-	// // case n == 5 && p[1] == "v1" && p[2] == "users" && p[3] == "operation" && p[4] == "create-comment" && r.Method == http.MethodPost:
-	// // 	port.Tag.OperationCreateComment(w, r)
-	// case n == 4 && p[1] == "v1" && p[2] == "how-hear-about-us-items" && p[3] == "select-options" && r.Method == http.MethodGet:
-	// 	port.HowHear.ListAsSelectOptions(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "select-options" && p[3] == "how-hear-about-us-items" && r.Method == http.MethodGet:
-		port.HowHear.PublicListAsSelectOptions(w, r)
-
-	// --- USERS --- //
-	case n == 3 && p[1] == "v1" && p[2] == "users" && r.Method == http.MethodGet:
-		port.User.List(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "users" && p[3] == "count" && r.Method == http.MethodGet:
-		port.User.Count(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "users" && r.Method == http.MethodPost:
-		port.User.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "user" && r.Method == http.MethodGet:
-		port.User.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "user" && r.Method == http.MethodPut:
-		port.User.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "user" && r.Method == http.MethodDelete:
-		port.User.DeleteByID(w, r, p[3])
-	case n == 5 && p[1] == "v1" && p[2] == "users" && p[3] == "operation" && p[4] == "create-comment" && r.Method == http.MethodPost:
-		port.User.OperationCreateComment(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "users" && p[3] == "select-options" && r.Method == http.MethodGet:
-		port.User.ListAsSelectOptions(w, r)
-
-	// --- ATTACHMENTS --- //
-	case n == 3 && p[1] == "v1" && p[2] == "attachments" && r.Method == http.MethodGet:
-		port.Attachment.List(w, r)
-	case n == 3 && p[1] == "v1" && p[2] == "attachments" && r.Method == http.MethodPost:
-		port.Attachment.Create(w, r)
-	case n == 4 && p[1] == "v1" && p[2] == "attachment" && r.Method == http.MethodGet:
-		port.Attachment.GetByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "attachment" && r.Method == http.MethodPut:
-		port.Attachment.UpdateByID(w, r, p[3])
-	case n == 4 && p[1] == "v1" && p[2] == "attachment" && r.Method == http.MethodDelete:
-		port.Attachment.DeleteByID(w, r, p[3])
-
-	// --- CATCH ALL: D.N.E. ---
-	default:
-		http.NotFound(w, r)
-	}
+	route.Handler(w, r)
 }
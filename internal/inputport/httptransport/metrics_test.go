@@ -0,0 +1,37 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+func TestIsMetricsRequestAuthorized(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		token         string
+		hasDebugging  bool
+		authorization string
+		want          bool
+	}{
+		{"no token in production", "", false, "", false},
+		{"no token while debugging", "", true, "", true},
+		{"missing token", "secret", true, "", false},
+		{"wrong token", "secret", false, "Bearer other", false},
+		{"wrong scheme", "secret", false, "secret", false},
+		{"valid token", "secret", false, "Bearer secret", true},
+	} {
+		cfg := &config.Conf{}
+		cfg.AppServer.MetricsToken = tc.token
+		cfg.AppServer.HasDebugging = tc.hasDebugging
+		r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if tc.authorization != "" {
+			r.Header.Set("Authorization", tc.authorization)
+		}
+		if got := isMetricsRequestAuthorized(cfg, r); got != tc.want {
+			t.Errorf("%s: expected %v but got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_c "github.com/bartmika/databoutique-backend/internal/app/apikey/controller"
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+)
+
+// fakeAPIKeyController accepts a single key. The methods not overridden
+// panic since the embedded interface is nil.
+type fakeAPIKeyController struct {
+	apikey_c.APIKeyController
+	rawKey string
+	key    *apikey_s.APIKey
+	user   *user_s.User
+}
+
+func (c *fakeAPIKeyController) Authenticate(ctx context.Context, rawKey string) (*apikey_s.APIKey, *user_s.User, error) {
+	if rawKey != c.rawKey {
+		return nil, nil, apikey_c.ErrInvalidAPIKey
+	}
+	return c.key, c.user, nil
+}
+
+func TestRequiredAPIKeyScope(t *testing.T) {
+	programs := &router.Route{APIKeyResource: "programs"}
+	for _, tc := range []struct {
+		route  *router.Route
+		method string
+		want   string
+	}{
+		{programs, http.MethodGet, "programs:read"},
+		{programs, http.MethodHead, "programs:read"},
+		{programs, http.MethodOptions, "programs:read"},
+		{programs, http.MethodPost, "programs:write"},
+		{programs, http.MethodPut, "programs:write"},
+		{programs, http.MethodDelete, "programs:write"},
+		{&router.Route{}, http.MethodGet, ""},
+		{nil, http.MethodGet, ""},
+	} {
+		if got := requiredAPIKeyScope(tc.route, tc.method); got != tc.want {
+			t.Errorf("requiredAPIKeyScope(%+v, %s) = %q, want %q", tc.route, tc.method, got, tc.want)
+		}
+	}
+}
+
+func TestProcessAPIKey(t *testing.T) {
+	u := &user_s.User{ID: primitive.NewObjectID(), TenantID: primitive.NewObjectID(), Role: user_s.UserRoleManagement}
+	mid := &middleware{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		APIKeyController: &fakeAPIKeyController{
+			rawKey: "dbk_abc_secret",
+			key:    &apikey_s.APIKey{Prefix: "abc", Scopes: []string{"programs:read"}},
+			user:   u,
+		},
+	}
+
+	var session context.Context
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		session = r.Context()
+		w.WriteHeader(http.StatusOK)
+	}
+	send := func(route *router.Route, method string, rawKey string) int {
+		session = nil
+		r := httptest.NewRequest(method, route.Pattern, nil)
+		w := httptest.NewRecorder()
+		mid.processAPIKey(fn, w, r.WithContext(router.WithMatch(r.Context(), route, nil)), rawKey)
+		return w.Code
+	}
+
+	programs := &router.Route{Pattern: "/api/v1/programs", APIKeyResource: "programs"}
+	if code := send(programs, http.MethodGet, " dbk_abc_secret "); code != http.StatusOK {
+		t.Fatalf("expected the key to be allowed but got %d", code)
+	}
+	if id, _ := session.Value(constants.SessionUserID).(primitive.ObjectID); id != u.ID {
+		t.Fatalf("expected the request to act on behalf of the owner but got %v", id)
+	}
+	if tid, _ := session.Value(constants.SessionUserTenantID).(primitive.ObjectID); tid != u.TenantID {
+		t.Fatalf("expected the tenant of the key but got %v", tid)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		route  *router.Route
+		method string
+		rawKey string
+		want   int
+	}{
+		{"invalid key", programs, http.MethodGet, "dbk_abc_other", http.StatusUnauthorized},
+		{"missing write scope", programs, http.MethodPost, "dbk_abc_secret", http.StatusForbidden},
+		{"other resource", &router.Route{Pattern: "/api/v1/users", APIKeyResource: "users"}, http.MethodGet, "dbk_abc_secret", http.StatusForbidden},
+		{"route without api keys", &router.Route{Pattern: "/api/v1/api-keys"}, http.MethodGet, "dbk_abc_secret", http.StatusForbidden},
+	} {
+		if code := send(tc.route, tc.method, tc.rawKey); code != tc.want || session != nil {
+			t.Errorf("%s: expected %d but got %d", tc.name, tc.want, code)
+		}
+	}
+}
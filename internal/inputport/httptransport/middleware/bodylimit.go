@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// BodyLimitMiddleware returns `413 payload too large` if the request body is
+// larger than the limit of the matched route. Our file upload routes are
+// allowed larger bodies than our JSON routes.
+func (mid *middleware) BodyLimitMiddleware(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route := router.RouteFromContext(r.Context())
+		if route == nil {
+			fn(w, r) // Flow to the next middleware.
+			return
+		}
+
+		limit := route.MaxBodySize
+		if limit == 0 {
+			limit = mid.Config.HTTP.MaxBodySize
+			if route.IsMultipart {
+				limit = mid.Config.HTTP.MaxUploadBodySize
+			}
+		}
+
+		// Reject early if the client told us the size of the body else the
+		// body is cut off once the limit was read.
+		if r.ContentLength > limit {
+			httperror.ResponseError(w, httperror.NewWithCode(http.StatusRequestEntityTooLarge, httperror.CodePayloadTooLarge, fmt.Sprintf("the request body must not be larger than %d bytes", limit)))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+		fn(w, r) // Flow to the next middleware.
+	}
+}
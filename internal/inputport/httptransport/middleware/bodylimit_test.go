@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	cfg := &config.Conf{}
+	cfg.HTTP.MaxBodySize = 10
+	cfg.HTTP.MaxUploadBodySize = 20
+	mid := &middleware{Config: cfg}
+	fn := mid.BodyLimitMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			httperror.ResponseError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		route      *router.Route
+		body       string
+		chunked    bool
+		wantStatus int
+	}{
+		{name: "json", route: &router.Route{}, body: strings.Repeat("a", 10), wantStatus: http.StatusOK},
+		{name: "json too large", route: &router.Route{}, body: strings.Repeat("a", 11), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "json too large without length", route: &router.Route{}, body: strings.Repeat("a", 11), chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "upload", route: &router.Route{IsMultipart: true}, body: strings.Repeat("a", 20), wantStatus: http.StatusOK},
+		{name: "upload too large", route: &router.Route{IsMultipart: true}, body: strings.Repeat("a", 21), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "route", route: &router.Route{MaxBodySize: 5}, body: strings.Repeat("a", 6), wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/programs", strings.NewReader(tt.body))
+			if tt.chunked {
+				r.ContentLength = -1
+			}
+			r = r.WithContext(router.WithMatch(r.Context(), tt.route, nil))
+			w := httptest.NewRecorder()
+			fn(w, r)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d but got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	idempotencykey_s "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// The maximum length of the `Idempotency-Key` header.
+const idempotencyKeyMaxLength = 255
+
+// IdempotencyMiddleware makes the `POST` requests sent with an
+// `Idempotency-Key` header safe to retry. The first request with the key is
+// processed and its response saved; the following requests with the same key
+// and body get the saved response replayed instead of, ex: creating a
+// duplicate executable. Responses with a server error are not saved so the
+// request may be retried.
+//
+// Only the routes which opt in with `IsIdempotent` are supported; the other
+// routes, ex: login or creating a webhook, may respond with secrets like
+// tokens which must never be saved.
+func (mid *middleware) IdempotencyMiddleware(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		key := r.Header.Get("Idempotency-Key")
+		if r.Method != http.MethodPost || key == "" || !isIdempotencySupported(router.RouteFromContext(ctx)) {
+			fn(w, r) // Flow to the next middleware.
+			return
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("Idempotency-Key", "too long"))
+			return
+		}
+
+		// Read the body so we can detect if the key is reused for a
+		// different request and then restore it for our handlers.
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			mid.Logger.WarnContext(ctx, "failed reading request body", slog.Any("err", err))
+			if maxBytes := (*http.MaxBytesError)(nil); errors.As(err, &maxBytes) {
+				httperror.ResponseError(w, err)
+				return
+			}
+			httperror.ResponseError(w, httperror.NewWithCode(http.StatusBadRequest, httperror.CodeBadRequest, "failed reading request body"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		m, err := mid.IdempotencyKeyController.Begin(ctx, idempotencyScope(ctx), key, r.Method, r.URL.Path, requestHash(r, body))
+		if err != nil {
+			httperror.ResponseError(w, err)
+			return
+		}
+		if m.Status == idempotencykey_s.IdempotencyKeyStatusCompleted {
+			mid.Logger.InfoContext(ctx, "replaying idempotent response", slog.String("path", r.URL.Path))
+			replayResponse(w, m)
+			return
+		}
+
+		rw := &recordingResponseWriter{ResponseWriter: w}
+		fn(rw, r) // Flow to the next middleware.
+
+		// Save the response even if the client went away, that is exactly
+		// when the client will retry.
+		ctx = context.WithoutCancel(ctx)
+		if rw.status == 0 { // Nothing was written.
+			rw.status = http.StatusOK
+			rw.header = w.Header().Clone()
+		}
+		if rw.status >= http.StatusInternalServerError {
+			_ = mid.IdempotencyKeyController.Release(ctx, m)
+			return
+		}
+		header := rw.header.Clone()
+		header.Del("X-Request-ID")
+		_ = mid.IdempotencyKeyController.Complete(ctx, m, rw.status, header, rw.body.Bytes())
+	}
+}
+
+// isIdempotencySupported function returns true if the responses of the route
+// may be saved. New routes are not supported unless they opt in.
+func isIdempotencySupported(route *router.Route) bool {
+	if route == nil {
+		return false
+	}
+	return route.IsIdempotent && !route.IsPublic && route.RateLimitClass != router.RateLimitClassAuth && !route.HasSecretResponse
+}
+
+// idempotencyScope function returns who sent the request so different users
+// sending the same key do not conflict.
+func idempotencyScope(ctx context.Context) string {
+	if userID, ok := ctx.Value(constants.SessionUserID).(primitive.ObjectID); ok && !userID.IsZero() {
+		return "user:" + userID.Hex()
+	}
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	return "ip:" + ipAddress
+}
+
+// requestHash function returns the fingerprint of the request which must
+// match for the saved response to be replayed.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replayResponse(w http.ResponseWriter, m *idempotencykey_s.IdempotencyKey) {
+	for k, v := range m.ResponseHeader {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(m.ResponseStatusCode)
+	w.Write(m.ResponseBody)
+}
+
+// recordingResponseWriter records the response written by our handlers while
+// writing it to the client.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows `http.ResponseController` to reach the original writer.
+func (w *recordingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	idempotencykey_c "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/controller"
+	idempotencykey_s "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/datastore"
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+)
+
+type fakeIdempotencyKeyStorer struct {
+	mu   sync.Mutex
+	keys map[string]*idempotencykey_s.IdempotencyKey
+}
+
+func (s *fakeIdempotencyKeyStorer) Create(ctx context.Context, m *idempotencykey_s.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[m.Scope+m.Key]; ok {
+		return idempotencykey_s.ErrIdempotencyKeyExists
+	}
+	c := *m
+	s.keys[m.Scope+m.Key] = &c
+	return nil
+}
+
+func (s *fakeIdempotencyKeyStorer) GetByScopeAndKey(ctx context.Context, scope string, key string) (*idempotencykey_s.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.keys[scope+key]
+	if !ok {
+		return nil, nil
+	}
+	c := *m
+	return &c, nil
+}
+
+func (s *fakeIdempotencyKeyStorer) UpdateByID(ctx context.Context, m *idempotencykey_s.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := *m
+	s.keys[m.Scope+m.Key] = &c
+	return nil
+}
+
+func (s *fakeIdempotencyKeyStorer) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, m := range s.keys {
+		if m.ID == id {
+			delete(s.keys, k)
+		}
+	}
+	return nil
+}
+
+func newIdempotencyTestMiddleware() (*middleware, *fakeIdempotencyKeyStorer) {
+	cfg := &config.Conf{}
+	cfg.AppServer.IdempotencyKeyWindow = time.Hour
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	storer := &fakeIdempotencyKeyStorer{keys: map[string]*idempotencykey_s.IdempotencyKey{}}
+	return &middleware{
+		Logger:                   logger,
+		IdempotencyKeyController: idempotencykey_c.NewController(cfg, logger, storer),
+	}, storer
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	mid, _ := newIdempotencyTestMiddleware()
+	userID := primitive.NewObjectID()
+
+	var calls int
+	status := http.StatusCreated
+	fn := mid.IdempotencyMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"call":%d,"body":%q}`, calls, body)
+	})
+	send := func(key string, body string, userID primitive.ObjectID) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/executables", strings.NewReader(body))
+		r.Header.Set("Idempotency-Key", key)
+		ctx := router.WithMatch(r.Context(), &router.Route{Method: http.MethodPost, Pattern: "/api/v1/executables", IsIdempotent: true}, nil)
+		r = r.WithContext(context.WithValue(ctx, constants.SessionUserID, userID))
+		w := httptest.NewRecorder()
+		fn(w, r)
+		return w
+	}
+
+	first := send("key-1", `{"name":"a"}`, userID)
+	if first.Code != http.StatusCreated || calls != 1 {
+		t.Fatalf("expected the request to be processed but got %d after %d calls", first.Code, calls)
+	}
+
+	// Retrying the request replays the original response.
+	retry := send("key-1", `{"name":"a"}`, userID)
+	if calls != 1 || retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("expected replayed response but got %d %s after %d calls", retry.Code, retry.Body.String(), calls)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" || retry.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected replayed headers but got %v", retry.Header())
+	}
+
+	// Reusing the key for a different request is rejected.
+	if w := send("key-1", `{"name":"b"}`, userID); w.Code != http.StatusUnprocessableEntity || calls != 1 {
+		t.Fatalf("expected 422 but got %d after %d calls", w.Code, calls)
+	}
+
+	// Keys are scoped to the user.
+	if w := send("key-1", `{"name":"a"}`, primitive.NewObjectID()); w.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("expected the request of another user to be processed but got %d after %d calls", w.Code, calls)
+	}
+
+	// Server errors are not saved so the request may be retried.
+	status = http.StatusInternalServerError
+	send("key-2", `{}`, userID)
+	status = http.StatusCreated
+	if w := send("key-2", `{}`, userID); w.Code != http.StatusCreated || calls != 4 {
+		t.Fatalf("expected the failed request to be processed again but got %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddlewareInProgress(t *testing.T) {
+	mid, storer := newIdempotencyTestMiddleware()
+	userID := primitive.NewObjectID()
+
+	fn := mid.IdempotencyMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	send := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/programs", strings.NewReader(`{}`))
+		r.Header.Set("Idempotency-Key", "key-1")
+		ctx := router.WithMatch(r.Context(), &router.Route{Method: http.MethodPost, Pattern: "/api/v1/programs", IsIdempotent: true}, nil)
+		r = r.WithContext(context.WithValue(ctx, constants.SessionUserID, userID))
+		w := httptest.NewRecorder()
+		fn(w, r)
+		return w
+	}
+
+	// Simulate a concurrent request which is still being processed.
+	ctx := context.Background()
+	m, err := mid.IdempotencyKeyController.Begin(ctx, "user:"+userID.Hex(), "key-1", http.MethodPost, "/api/v1/programs", "")
+	if err != nil {
+		t.Fatalf("failed reserving key: %v", err)
+	}
+	m.RequestHash = requestHash(httptest.NewRequest(http.MethodPost, "/api/v1/programs", nil), []byte(`{}`))
+	storer.UpdateByID(ctx, m)
+
+	if w := send(); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 but got %d", w.Code)
+	}
+
+	// Abandoned requests, ex: the server crashed, may be taken over.
+	m.ModifiedAt = time.Now().Add(-time.Hour)
+	storer.UpdateByID(ctx, m)
+	if w := send(); w.Code != http.StatusCreated {
+		t.Fatalf("expected abandoned key to be taken over but got %d", w.Code)
+	}
+}
+
+func TestIdempotencyMiddlewareSkipsSecretResponses(t *testing.T) {
+	mid, storer := newIdempotencyTestMiddleware()
+
+	fn := mid.IdempotencyMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"access_token":"secret"}`)
+	})
+	for _, route := range []*router.Route{
+		{Method: http.MethodPost, Pattern: "/api/v1/login", IsPublic: true, RateLimitClass: router.RateLimitClassAuth},
+		{Method: http.MethodPost, Pattern: "/api/v1/refresh-token", IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/api/v1/profile/2fa/verify", RateLimitClass: router.RateLimitClassAuth},
+		{Method: http.MethodPost, Pattern: "/api/v1/api-keys", HasSecretResponse: true},
+		{Method: http.MethodPost, Pattern: "/api/v1/webhooks"}, // Routes must opt in.
+		nil, // Unmatched routes.
+	} {
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/unknown", strings.NewReader(`{}`))
+			r.Header.Set("Idempotency-Key", "key-1")
+			ctx := router.WithMatch(r.Context(), route, nil)
+			ctx = context.WithValue(ctx, constants.SessionUserID, primitive.NewObjectID())
+			w := httptest.NewRecorder()
+			fn(w, r.WithContext(ctx))
+			if w.Header().Get("Idempotent-Replayed") != "" {
+				t.Fatalf("expected %+v not to be replayed", route)
+			}
+		}
+	}
+	if len(storer.keys) != 0 {
+		t.Fatalf("expected no responses to be saved but got %d", len(storer.keys))
+	}
+}
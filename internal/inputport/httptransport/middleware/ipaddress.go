@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// requestIPAddress function returns the IP address of the client which sent
+// the request. The `X-Forwarded-For` and `X-Real-Ip` headers are only read if
+// the request was sent by one of our trusted proxies, in which case the
+// right-most address not belonging to our proxies is the client since the
+// addresses on its left may have been set by the client itself.
+func requestIPAddress(r *http.Request, trustedProxies []*net.IPNet) string {
+	remoteIPAddress := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remoteIPAddress); err == nil {
+		remoteIPAddress = host
+	}
+	if !isTrustedProxy(remoteIPAddress, trustedProxies) {
+		return remoteIPAddress
+	}
+
+	// Walk the hops from our proxy back to the client.
+	var hops []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	ipAddress := remoteIPAddress
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break // Do not trust anything left of an invalid address.
+		}
+		ipAddress = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	if len(hops) > 0 {
+		return ipAddress
+	}
+
+	if realIPAddress := strings.TrimSpace(r.Header.Get("X-Real-Ip")); net.ParseIP(realIPAddress) != nil {
+		return realIPAddress
+	}
+	return remoteIPAddress
+}
+
+func isTrustedProxy(ipAddress string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
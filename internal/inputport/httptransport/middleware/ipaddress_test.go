@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIPAddress(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	trustedProxies := []*net.IPNet{proxies}
+
+	for name, tc := range map[string]struct {
+		remoteAddr    string
+		forwardedFor  []string
+		realIPAddress string
+		want          string
+	}{
+		"direct client": {
+			remoteAddr: "203.0.113.7:4000",
+			want:       "203.0.113.7",
+		},
+		"spoofed headers from an untrusted client": {
+			remoteAddr:    "203.0.113.7:4000",
+			forwardedFor:  []string{"198.51.100.1"},
+			realIPAddress: "198.51.100.2",
+			want:          "203.0.113.7",
+		},
+		"client behind our proxy": {
+			remoteAddr:   "10.0.0.2:4000",
+			forwardedFor: []string{"203.0.113.7"},
+			want:         "203.0.113.7",
+		},
+		"spoofed hop prepended by the client behind our proxies": {
+			remoteAddr:   "10.0.0.2:4000",
+			forwardedFor: []string{"198.51.100.1, 203.0.113.7", "10.0.0.3"},
+			want:         "203.0.113.7",
+		},
+		"invalid hop": {
+			remoteAddr:   "10.0.0.2:4000",
+			forwardedFor: []string{"203.0.113.7, garbage, 10.0.0.3"},
+			want:         "10.0.0.3",
+		},
+		"real ip header from our proxy": {
+			remoteAddr:    "10.0.0.2:4000",
+			realIPAddress: "203.0.113.7",
+			want:          "203.0.113.7",
+		},
+	} {
+		r := httptest.NewRequest("POST", "/api/v1/login", nil)
+		r.RemoteAddr = tc.remoteAddr
+		for _, value := range tc.forwardedFor {
+			r.Header.Add("X-Forwarded-For", value)
+		}
+		if tc.realIPAddress != "" {
+			r.Header.Set("X-Real-Ip", tc.realIPAddress)
+		}
+		if got := requestIPAddress(r, trustedProxies); got != tc.want {
+			t.Fatalf("%s: expected %q but got %q", name, tc.want, got)
+		}
+	}
+}
@@ -2,25 +2,32 @@ package middleware
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
-	"net"
 	"net/http"
 	"strings"
-	"sync"
 
-	"go.uber.org/ratelimit"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/bartmika/databoutique-backend/internal/adapter/ratelimiter"
+	apikey_c "github.com/bartmika/databoutique-backend/internal/app/apikey/controller"
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
 	gateway_c "github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
+	idempotencykey_c "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/controller"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	"github.com/bartmika/databoutique-backend/internal/config"
 	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
 	"github.com/bartmika/databoutique-backend/internal/provider/jwt"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 	"github.com/bartmika/databoutique-backend/internal/provider/time"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
 )
 
 type Middleware interface {
-	Attach(fn http.HandlerFunc) http.HandlerFunc
+	Attach(rt *router.Router, fn http.HandlerFunc) http.HandlerFunc
 }
 
 type middleware struct {
@@ -30,6 +37,10 @@ type middleware struct {
 	JWT               jwt.Provider
 	UUID              uuid.Provider
 	GatewayController gateway_c.GatewayController
+	APIKeyController  apikey_c.APIKeyController
+
+	IdempotencyKeyController idempotencykey_c.IdempotencyKeyController
+	RateLimiter              ratelimiter.Limiter
 }
 
 func NewMiddleware(
@@ -39,29 +50,43 @@ func NewMiddleware(
 	timep time.Provider,
 	jwtp jwt.Provider,
 	gatewayController gateway_c.GatewayController,
+	apiKeyController apikey_c.APIKeyController,
+	idempotencyKeyController idempotencykey_c.IdempotencyKeyController,
+	rateLimiter ratelimiter.Limiter,
 ) Middleware {
 	return &middleware{
+		Config:            configp,
 		Logger:            loggerp,
 		UUID:              uuidp,
 		Time:              timep,
 		JWT:               jwtp,
 		GatewayController: gatewayController,
+		APIKeyController:  apiKeyController,
+
+		IdempotencyKeyController: idempotencyKeyController,
+		RateLimiter:              rateLimiter,
 	}
 }
 
 // Attach function attaches to HTTP router to apply for every API call.
-func (mid *middleware) Attach(fn http.HandlerFunc) http.HandlerFunc {
+func (mid *middleware) Attach(rt *router.Router, fn http.HandlerFunc) http.HandlerFunc {
 	// Attach our middleware handlers here. Please note that all our middleware
 	// will start from the bottom and proceed upwards.
-	// Ex: `URLProcessorMiddleware` will be executed first and
-	//     `PostJWTProcessorMiddleware` will be executed last.
+	// Ex: `RouterMiddleware` will be executed first and
+	//     `IfMatchMiddleware` will be executed last.
+	fn = mid.IfMatchMiddleware(fn)       // Note: Must be below `ProtectedURLsMiddleware` so unauthorized requests are rejected first.
+	fn = mid.IdempotencyMiddleware(fn)   // Note: Must be below `ProtectedURLsMiddleware` so the keys are scoped to the user.
+	fn = mid.UserRateLimitMiddleware(fn) // Note: Must be below `ProtectedURLsMiddleware` so the user and tenant are known.
 	fn = mid.ProtectedURLsMiddleware(fn)
-	fn = mid.IPAddressMiddleware(fn)
 	fn = mid.PostJWTProcessorMiddleware(fn) // Note: Must be above `JWTProcessorMiddleware`.
 	fn = mid.JWTProcessorMiddleware(fn)     // Note: Must be above `PreJWTProcessorMiddleware`.
-	fn = mid.PreJWTProcessorMiddleware(fn)  // Note: Must be above `URLProcessorMiddleware`.
-	fn = mid.URLProcessorMiddleware(fn)
-	fn = mid.RateLimitMiddleware(fn)
+	fn = mid.PreJWTProcessorMiddleware(fn)  // Note: Must be above `RouterMiddleware`.
+	fn = mid.RateLimitMiddleware(fn)        // Note: Must be above `JWTProcessorMiddleware` so brute force attempts are rejected before checking credentials.
+	fn = mid.IPAddressMiddleware(fn)        // Note: Must be above `JWTProcessorMiddleware` so API keys can track the IP address.
+	fn = mid.BodyLimitMiddleware(fn)        // Note: Must be above `IdempotencyMiddleware` since it reads the body.
+	fn = mid.RouterMiddleware(rt, fn)
+	fn = mid.MetricsMiddleware(fn)
+	fn = mid.RequestIDMiddleware(fn) // Note: Must be last so every log record of this request can be correlated.
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Flow to the next middleware.
@@ -69,57 +94,127 @@ func (mid *middleware) Attach(fn http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-func (mid *middleware) RateLimitMiddleware(fn http.HandlerFunc) http.HandlerFunc {
-	// Special thanks: https://ubogdan.com/2021/09/ip-based-rate-limit-middleware-using-go.uber.org/ratelimit/
-	var lmap sync.Map
-
+// RequestIDMiddleware assigns a request ID to every API call, saves it to the
+// context so our logger can attach it to every log record (even from our
+// background jobs) and echoes it back in the `X-Request-ID` response header.
+// If the caller provided a well-formed request ID then we will reuse it.
+func (mid *middleware) RequestIDMiddleware(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Open our program's context based on the request and save the
-		// slash-seperated array from our URL path.
+		requestID := r.Header.Get("X-Request-ID")
+		if !isValidRequestID(requestID) {
+			requestID = mid.UUID.NewUUID()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request_id", requestID))
+
 		ctx := r.Context()
+		ctx = context.WithValue(ctx, constants.SessionRequestID, requestID)
 
-		host, _, err := net.SplitHostPort(r.RemoteAddr)
-		if err != nil {
-			mid.Logger.Error("invalid RemoteAddr", slog.Any("err", err))
-			http.Error(w, fmt.Sprintf("invalid RemoteAddr: %s", err), http.StatusInternalServerError)
-			return
-		}
+		sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		startAt := mid.Time.Now()
 
-		lif, ok := lmap.Load(host)
-		if !ok {
-			lif = ratelimit.New(50) // per second.
-		}
+		// Flow to the next middleware.
+		fn(sw, r.WithContext(ctx))
 
-		lm, ok := lif.(ratelimit.Limiter)
-		if !ok {
-			mid.Logger.Error("internal middleware error: typecast failed")
-			http.Error(w, "internal middleware error: typecast failed", http.StatusInternalServerError)
-			return
-		}
+		mid.Logger.InfoContext(ctx, "request completed",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", sw.status),
+			slog.Duration("duration", mid.Time.Now().Sub(startAt)))
+	}
+}
+
+// MetricsMiddleware records the count and latency of every API call. The
+// route label is set by our router once the request matched an endpoint.
+func (mid *middleware) MetricsMiddleware(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := metrics.WithRoute(r.Context())
 
-		lm.Take()
-		lmap.Store(host, lm)
+		sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		startAt := mid.Time.Now()
 
 		// Flow to the next middleware.
-		fn(w, r.WithContext(ctx))
+		fn(sw, r.WithContext(ctx))
+
+		route := metrics.RouteFromContext(ctx)
+		method := metricsMethod(r.Method)
+		metrics.ObserveHTTPRequest(route, method, sw.status, mid.Time.Now().Sub(startAt))
+
+		// Name the trace span of this request after the matched route.
+		span := trace.SpanFromContext(ctx)
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
 	}
 }
 
-// URLProcessorMiddleware Middleware will split the full URL path into slash-sperated parts and save to
-// the context to flow downstream in the app for this particular request.
-func (mid *middleware) URLProcessorMiddleware(fn http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Split path into slash-separated parts, for example, path "/foo/bar"
-		// gives p==["foo", "bar"] and path "/" gives p==[""]. Our API starts with
-		// "/api", as a result we will start the array slice at "1".
-		p := strings.Split(r.URL.Path, "/")[1:]
+// metricsMethod returns the method of the request as a metrics label. The
+// methods outside the standard set are reported as `OTHER` since the clients
+// can send any method and every label value creates a new time series.
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "OTHER"
+	}
+}
 
-		// log.Println(p) // For debugging purposes only.
+// isValidRequestID returns true if the request ID provided by the caller is
+// short and only contains characters safe to write into our logs.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
 
-		// Open our program's context based on the request and save the
-		// slash-seperated array from our URL path.
+// statusResponseWriter records the status code written by our handlers.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap allows `http.ResponseController` to reach the original writer.
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// RouterMiddleware matches the request against our route table and saves the
+// matched route to the context to flow downstream in the app for this
+// particular request. Unknown paths return `404 not found` and known paths
+// with an unsupported method return `405 method not allowed`.
+func (mid *middleware) RouterMiddleware(rt *router.Router, fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		ctx = context.WithValue(ctx, "url_split", p)
+
+		route, params, allowed := rt.Match(r.Method, r.URL.Path)
+		if route == nil {
+			if len(allowed) == 0 {
+				httperror.ResponseError(w, httperror.NewForSingleField(http.StatusNotFound, "message", "endpoint does not exist"))
+				return
+			}
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			httperror.ResponseError(w, httperror.NewForSingleField(http.StatusMethodNotAllowed, "message", "method not allowed"))
+			return
+		}
+
+		// Label our metrics and traces with the route instead of the path.
+		metrics.SetRoute(ctx, route.Pattern)
+
+		ctx = router.WithMatch(ctx, route, params)
 
 		// Flow to the next middleware.
 		fn(w, r.WithContext(ctx))
@@ -134,46 +229,11 @@ func (mid *middleware) PreJWTProcessorMiddleware(fn http.HandlerFunc) http.Handl
 		// slash-seperated array from our URL path.
 		ctx := r.Context()
 
-		// The following code will lookup the URL path in a whitelist and
-		// if the visited path matches then we will skip URL protection.
-		// We do this because a majority of API endpoints are protected
-		// by authorization.
-
-		urlSplit := ctx.Value("url_split").([]string)
-		skipPath := map[string]bool{
-			"health-check":    true,
-			"version":         true,
-			"greeting":        true,
-			"login":           true,
-			"refresh-token":   true,
-			"register":        true,
-			"verify":          true,
-			"forgot-password": true,
-			"password-reset":  true,
-			"select-options":  true,
-		}
-
-		// DEVELOPERS NOTE:
-		// If the URL cannot be split into the size then do not skip authorization.
-		if len(urlSplit) < 3 {
-			// mid.Logger.Warn("Skipping authorization | len less then 3")
-			ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, false)
-			fn(w, r.WithContext(ctx)) // Flow to the next middleware.
-			return
-		}
-
-		// Skip authorization if the URL matches the whitelist else we need to
-		// run authorization check.
-		if skipPath[urlSplit[2]] {
-			// mid.Logger.Warn("Skipping authorization | skipPath found")
-			ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, true)
-		} else {
-			// For debugging purposes only.
-			// log.Println("PreJWTProcessorMiddleware | Protected URL detected")
-			// log.Println("PreJWTProcessorMiddleware | urlSplit:", urlSplit)
-			// log.Println("PreJWTProcessorMiddleware | urlSplit[2]:", urlSplit[2])
-			ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, false)
-		}
+		// Skip authorization if the matched route is public else we need to
+		// run authorization check. We do this because a majority of API
+		// endpoints are protected by authorization.
+		route := router.RouteFromContext(ctx)
+		ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, route != nil && route.IsPublic)
 
 		// Flow to the next middleware.
 		fn(w, r.WithContext(ctx))
@@ -202,11 +262,18 @@ func (mid *middleware) JWTProcessorMiddleware(fn http.HandlerFunc) http.HandlerF
 		// step!
 		if reqToken != "" && strings.Contains(reqToken, "undefined") == false {
 
+			// Machine-to-machine integrations authenticate with an API key
+			// instead of a JWT token.
+			if strings.HasPrefix(reqToken, "Api-Key ") {
+				mid.processAPIKey(fn, w, r.WithContext(ctx), strings.TrimPrefix(reqToken, "Api-Key "))
+				return
+			}
+
 			// Special thanks to "poise" via https://stackoverflow.com/a/44700761
 			splitToken := strings.Split(reqToken, "JWT ")
 			if len(splitToken) < 2 {
 				mid.Logger.Warn("not properly formatted authorization header")
-				http.Error(w, "not properly formatted authorization header", http.StatusBadRequest)
+				httperror.ResponseError(w, httperror.NewWithCode(http.StatusBadRequest, httperror.CodeBadRequest, "not properly formatted authorization header"))
 				return
 			}
 
@@ -226,40 +293,11 @@ func (mid *middleware) JWTProcessorMiddleware(fn http.HandlerFunc) http.HandlerF
 				return
 			}
 
-			// The following code will lookup the URL path in a whitelist and
-			// if the visited path matches then we will skip any token errors.
-			// We do this because a majority of API endpoints are protected
-			// by authorization.
-
-			urlSplit := ctx.Value("url_split").([]string)
-			skipPath := map[string]bool{
-				"health-check":    true,
-				"version":         true,
-				"greeting":        true,
-				"login":           true,
-				"refresh-token":   true,
-				"register":        true,
-				"verify":          true,
-				"forgot-password": true,
-				"password-reset":  true,
-				"select-options":  true,
-			}
-
 			// DEVELOPERS NOTE:
-			// If the URL cannot be split into the size we want then skip running
-			// this middleware.
-			if len(urlSplit) >= 3 {
-				if skipPath[urlSplit[2]] {
-					mid.Logger.Warn("Skipping expired or error token")
-				} else {
-					// For debugging purposes only.
-					// log.Println("JWTProcessorMiddleware | ProcessJWT | err", err, "for reqToken:", reqToken)
-					// log.Println("JWTProcessorMiddleware | ProcessJWT | urlSplit:", urlSplit)
-					// log.Println("JWTProcessorMiddleware | ProcessJWT | urlSplit[2]:", urlSplit[2])
-					http.Error(w, err.Error(), http.StatusUnauthorized)
-					return
-				}
-			}
+			// Public routes skip this middleware so the token of a protected
+			// route is invalid or expired.
+			httperror.ResponseError(w, httperror.NewForUnauthorized(err.Error()))
+			return
 		}
 
 		// Flow to the next middleware without anything done.
@@ -280,6 +318,12 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 			return
 		}
 
+		// Requests authorized with an API key have the user saved already.
+		if _, ok := ctx.Value(constants.SessionAPIKey).(*apikey_s.APIKey); ok {
+			fn(w, r.WithContext(ctx))
+			return
+		}
+
 		// Get our authorization information.
 		isAuthorized, ok := ctx.Value(constants.SessionIsAuthorized).(bool)
 		if ok && isAuthorized {
@@ -289,7 +333,7 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 			user, err := mid.GatewayController.GetUserBySessionID(ctx, sessionID) //TODO: IMPLEMENT.
 			if err != nil {
 				mid.Logger.Warn("GetUserBySessionID error", slog.Any("err", err))
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				httperror.ResponseError(w, err)
 				return
 			}
 
@@ -297,7 +341,7 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 			// user needs to login or use the refresh token.
 			if user == nil {
 				mid.Logger.Warn("Session expired - please log in again")
-				http.Error(w, "attempting to access a protected endpoint", http.StatusUnauthorized)
+				httperror.ResponseError(w, httperror.NewForUnauthorized("attempting to access a protected endpoint"))
 				return
 			}
 
@@ -309,6 +353,11 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 			// 	return
 			// }
 
+			// Keep track of when the session was last used.
+			if err := mid.GatewayController.TouchSession(ctx, sessionID); err != nil {
+				mid.Logger.Warn("TouchSession error", slog.Any("err", err))
+			}
+
 			// Save our user information to the context.
 			// Save our user.
 			ctx = context.WithValue(ctx, constants.SessionUser, user)
@@ -323,15 +372,7 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 
 			// Save individual pieces of the user profile.
 			ctx = context.WithValue(ctx, constants.SessionID, sessionID)
-			ctx = context.WithValue(ctx, constants.SessionUserID, user.ID)
-			ctx = context.WithValue(ctx, constants.SessionUserRole, user.Role)
-			ctx = context.WithValue(ctx, constants.SessionUserHasStaffRole, user.HasStaffRole)
-			ctx = context.WithValue(ctx, constants.SessionUserName, user.Name)
-			ctx = context.WithValue(ctx, constants.SessionUserLexicalName, user.LexicalName)
-			ctx = context.WithValue(ctx, constants.SessionUserFirstName, user.FirstName)
-			ctx = context.WithValue(ctx, constants.SessionUserLastName, user.LastName)
-			ctx = context.WithValue(ctx, constants.SessionUserTenantID, user.TenantID)
-			ctx = context.WithValue(ctx, constants.SessionUserTenantName, user.TenantName)
+			ctx = withUserSession(ctx, user)
 		}
 
 		fn(w, r.WithContext(ctx))
@@ -340,18 +381,14 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 
 func (mid *middleware) IPAddressMiddleware(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Extract the IPAddress. Code taken from: https://stackoverflow.com/a/55738279
-		IPAddress := r.Header.Get("X-Real-Ip")
-		if IPAddress == "" {
-			IPAddress = r.Header.Get("X-Forwarded-For")
-		}
-		if IPAddress == "" {
-			IPAddress = r.RemoteAddr
-		}
+		// Extract the IPAddress. The forwarding headers are only trusted
+		// when sent by our reverse proxies since any client may set them.
+		IPAddress := requestIPAddress(r, mid.Config.HTTP.TrustedProxies)
 
 		// Save our IP address to the context.
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, constants.SessionIPAddress, IPAddress)
+		ctx = context.WithValue(ctx, constants.SessionUserAgent, r.UserAgent())
 		fn(w, r.WithContext(ctx)) // Flow to the next middleware.
 	}
 }
@@ -370,46 +407,87 @@ func (mid *middleware) ProtectedURLsMiddleware(fn http.HandlerFunc) http.Handler
 			return
 		}
 
-		// The following code will lookup the URL path in a whitelist and
-		// if the visited path matches then we will skip URL protection.
-		// We do this because a majority of API endpoints are protected
-		// by authorization.
-
-		urlSplit := ctx.Value("url_split").([]string)
-		skipPath := map[string]bool{
-			"health-check":    true,
-			"version":         true,
-			"greeting":        true,
-			"login":           true,
-			"refresh-token":   true,
-			"verify":          true,
-			"forgot-password": true,
-			"password-reset":  true,
-			"select-options":  true,
-		}
+		// Get our authorization information.
+		isAuthorized, ok := ctx.Value(constants.SessionIsAuthorized).(bool)
 
-		// DEVELOPERS NOTE:
-		// If the URL cannot be split into the size we want then skip running
-		// this middleware.
-		if len(urlSplit) < 3 {
-			fn(w, r.WithContext(ctx)) // Flow to the next middleware.
+		// Either accept continuing execution or return 401 error.
+		if !ok || !isAuthorized {
+			mid.Logger.Warn("attempting to access a protected endpoint")
+			httperror.ResponseError(w, httperror.NewForUnauthorized("attempting to access a protected endpoint"))
 			return
 		}
 
-		if skipPath[urlSplit[2]] {
-			fn(w, r.WithContext(ctx)) // Flow to the next middleware.
-		} else {
-			// Get our authorization information.
-			isAuthorized, ok := ctx.Value(constants.SessionIsAuthorized).(bool)
-
-			// Either accept continuing execution or return 401 error.
-			if ok && isAuthorized {
-				fn(w, r.WithContext(ctx)) // Flow to the next middleware.
-			} else {
-				mid.Logger.Warn("attempting to access a protected endpoint")
-				http.Error(w, "attempting to access a protected endpoint", http.StatusUnauthorized)
-				return
-			}
+		// Return 403 error if the role of the user is not allowed to access
+		// the matched route.
+		role, _ := ctx.Value(constants.SessionUserRole).(int8)
+		if route := router.RouteFromContext(ctx); route != nil && !route.IsRoleAllowed(role) {
+			mid.Logger.Warn("role does not grant access to endpoint",
+				slog.Int("role", int(role)),
+				slog.String("route", route.Pattern))
+			httperror.ResponseError(w, httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this"))
+			return
 		}
+
+		fn(w, r.WithContext(ctx)) // Flow to the next middleware.
+	}
+}
+
+// withUserSession function saves the individual pieces of the user profile
+// into the context.
+func withUserSession(ctx context.Context, user *user_s.User) context.Context {
+	ctx = context.WithValue(ctx, constants.SessionUserID, user.ID)
+	ctx = context.WithValue(ctx, constants.SessionUserRole, user.Role)
+	ctx = context.WithValue(ctx, constants.SessionUserHasStaffRole, user.HasStaffRole)
+	ctx = context.WithValue(ctx, constants.SessionUserName, user.Name)
+	ctx = context.WithValue(ctx, constants.SessionUserLexicalName, user.LexicalName)
+	ctx = context.WithValue(ctx, constants.SessionUserFirstName, user.FirstName)
+	ctx = context.WithValue(ctx, constants.SessionUserLastName, user.LastName)
+	ctx = context.WithValue(ctx, constants.SessionUserTenantID, user.TenantID)
+	ctx = context.WithValue(ctx, constants.SessionUserTenantName, user.TenantName)
+	return ctx
+}
+
+// requiredAPIKeyScope function returns the scope the API key must have to
+// access the route or an empty string if API keys are not allowed.
+func requiredAPIKeyScope(route *router.Route, method string) string {
+	if route == nil || route.APIKeyResource == "" {
+		return ""
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return route.APIKeyResource + ":read"
+	default:
+		return route.APIKeyResource + ":write"
 	}
 }
+
+// processAPIKey function authenticates the API key, enforces the scopes of
+// the key and saves the user of the key to the context.
+func (mid *middleware) processAPIKey(fn http.HandlerFunc, w http.ResponseWriter, r *http.Request, rawKey string) {
+	ctx := r.Context()
+
+	key, user, err := mid.APIKeyController.Authenticate(ctx, strings.TrimSpace(rawKey))
+	if err != nil {
+		mid.Logger.Warn("api key authentication failed", slog.Any("err", err))
+		httperror.ResponseError(w, httperror.NewForUnauthorized("invalid api key"))
+		return
+	}
+
+	scope := requiredAPIKeyScope(router.RouteFromContext(ctx), r.Method)
+	if scope == "" || !key.HasScope(scope) {
+		mid.Logger.Warn("api key missing scope",
+			slog.String("prefix", key.Prefix),
+			slog.String("scope", scope))
+		httperror.ResponseError(w, httperror.NewWithCode(http.StatusForbidden, httperror.CodeForbidden, "api key does not have the required scope"))
+		return
+	}
+
+	ctx = context.WithValue(ctx, constants.SessionIsAuthorized, true)
+	ctx = context.WithValue(ctx, constants.SessionID, "")
+	ctx = context.WithValue(ctx, constants.SessionAPIKey, key)
+	ctx = context.WithValue(ctx, constants.SessionUser, user)
+	ctx = withUserSession(ctx, user)
+
+	// Flow to the next middleware with our API key saved.
+	fn(w, r.WithContext(ctx))
+}
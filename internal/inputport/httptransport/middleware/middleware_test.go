@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMetricsMethod(t *testing.T) {
+	for method, expected := range map[string]string{
+		http.MethodGet:    http.MethodGet,
+		http.MethodPost:   http.MethodPost,
+		http.MethodDelete: http.MethodDelete,
+		"PROPFIND":        "OTHER",
+		"get":             "OTHER",
+		"X-RANDOM-12345":  "OTHER",
+	} {
+		if got := metricsMethod(method); got != expected {
+			t.Errorf("expected %q to be reported as %q but got %q", method, expected, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// RateLimitMiddleware returns `429 too many requests` if the IP address
+// exceeded the rate limits of the class of the matched route, ex: too many
+// login attempts.
+func (mid *middleware) RateLimitMiddleware(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		route := router.RouteFromContext(ctx)
+		if route == nil {
+			fn(w, r) // Flow to the next middleware.
+			return
+		}
+
+		if !mid.takeRateLimitToken(w, r, rateLimitIPKey(clientIPAddress(ctx)), route, 1) {
+			return
+		}
+
+		fn(w, r) // Flow to the next middleware.
+	}
+}
+
+// UserRateLimitMiddleware returns `429 too many requests` if the
+// authenticated user or its tenant exceeded the rate limits of the class of
+// the matched route. The limits of the tenant are multiplied since they are
+// shared by all its users.
+func (mid *middleware) UserRateLimitMiddleware(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		route := router.RouteFromContext(ctx)
+		if route == nil {
+			fn(w, r) // Flow to the next middleware.
+			return
+		}
+
+		if userID, ok := ctx.Value(constants.SessionUserID).(primitive.ObjectID); ok && !userID.IsZero() {
+			if !mid.takeRateLimitToken(w, r, "user:"+userID.Hex(), route, 1) {
+				return
+			}
+		}
+		if tenantID, ok := ctx.Value(constants.SessionUserTenantID).(primitive.ObjectID); ok && !tenantID.IsZero() {
+			if !mid.takeRateLimitToken(w, r, "tenant:"+tenantID.Hex(), route, mid.Config.RateLimit.TenantMultiplier) {
+				return
+			}
+		}
+
+		fn(w, r) // Flow to the next middleware.
+	}
+}
+
+// takeRateLimitToken function takes a token from the bucket of the key for
+// the class of the route and returns true if the request may proceed, else
+// the `429 too many requests` error was sent.
+func (mid *middleware) takeRateLimitToken(w http.ResponseWriter, r *http.Request, key string, route *router.Route, multiplier int) bool {
+	ctx := r.Context()
+	class := route.RateLimitClassOrDefault()
+	limit := mid.rateLimit(class)
+	limit.Requests *= multiplier
+
+	res, err := mid.RateLimiter.Allow(ctx, key+":"+class, limit)
+	if err != nil {
+		// Do not take our API down if the rate limiter is unavailable.
+		mid.Logger.WarnContext(ctx, "rate limiter error", slog.Any("err", err))
+		return true
+	}
+	if res.Allowed {
+		return true
+	}
+
+	mid.Logger.WarnContext(ctx, "rate limit exceeded",
+		slog.String("key", key),
+		slog.String("class", class),
+		slog.String("route", route.Pattern))
+	retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	httperror.ResponseError(w, httperror.NewWithCode(http.StatusTooManyRequests, httperror.CodeRateLimited, "too many requests, please try again later"))
+	return false
+}
+
+// rateLimit function returns the configured rate limit of the class.
+func (mid *middleware) rateLimit(class string) config.RateLimit {
+	switch class {
+	case router.RateLimitClassAuth:
+		return mid.Config.RateLimit.Auth
+	case router.RateLimitClassAIOperation:
+		return mid.Config.RateLimit.AIOperation
+	case router.RateLimitClassRead:
+		return mid.Config.RateLimit.Read
+	default:
+		return mid.Config.RateLimit.Write
+	}
+}
+
+// clientIPAddress function returns the IP address of the client saved by
+// our `IPAddressMiddleware`.
+func clientIPAddress(ctx context.Context) string {
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	return ipAddress
+}
+
+// rateLimitIPKey function returns the key of the bucket of the IP address.
+// The IPv6 addresses are grouped by their /64 network since a single client
+// is usually assigned a whole /64 and could rotate between its addresses.
+func rateLimitIPKey(ipAddress string) string {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil || ip.To4() != nil {
+		return "ip:" + ipAddress
+	}
+	return "ip:" + ip.Mask(net.CIDRMask(64, 128)).String() + "/64"
+}
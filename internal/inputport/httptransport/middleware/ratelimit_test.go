@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/ratelimiter"
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	cfg := &config.Conf{}
+	cfg.RateLimit.Auth = config.RateLimit{Requests: 2, Period: time.Minute}
+	cfg.RateLimit.Read = config.RateLimit{Requests: 100, Period: time.Minute}
+	cfg.RateLimit.TenantMultiplier = 2
+	mid := &middleware{
+		Config:      cfg,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RateLimiter: ratelimiter.NewMemoryLimiter(time.Hour, 1000),
+	}
+	fn := mid.RateLimitMiddleware(mid.UserRateLimitMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	login := &router.Route{Method: http.MethodPost, Pattern: "/api/v1/login", RateLimitClass: router.RateLimitClassAuth}
+	send := func(route *router.Route, ipAddress string, userID, tenantID primitive.ObjectID) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(route.Method, route.Pattern, nil)
+		ctx := router.WithMatch(r.Context(), route, nil)
+		ctx = context.WithValue(ctx, constants.SessionIPAddress, ipAddress)
+		ctx = context.WithValue(ctx, constants.SessionUserID, userID)
+		ctx = context.WithValue(ctx, constants.SessionUserTenantID, tenantID)
+		w := httptest.NewRecorder()
+		fn(w, r.WithContext(ctx))
+		return w
+	}
+
+	// The IP address is limited.
+	for i := 0; i < 2; i++ {
+		if w := send(login, "1.2.3.4", primitive.NilObjectID, primitive.NilObjectID); w.Code != http.StatusOK {
+			t.Fatalf("expected request to be allowed but got %d", w.Code)
+		}
+	}
+	w := send(login, "1.2.3.4", primitive.NilObjectID, primitive.NilObjectID)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "30" {
+		t.Fatalf("expected 429 with Retry-After 30 but got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// Other classes of routes have their own limits.
+	programs := &router.Route{Method: http.MethodGet, Pattern: "/api/v1/programs"}
+	if w := send(programs, "1.2.3.4", primitive.NilObjectID, primitive.NilObjectID); w.Code != http.StatusOK {
+		t.Fatalf("expected read request to be allowed but got %d", w.Code)
+	}
+
+	// Users are limited across IP addresses and tenants share the limits
+	// multiplied between their users.
+	tenantID := primitive.NewObjectID()
+	alice, bob, carol := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	send(login, "10.0.0.1", alice, tenantID)
+	send(login, "10.0.0.2", alice, tenantID)
+	if w := send(login, "10.0.0.3", alice, tenantID); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected user to be limited but got %d", w.Code)
+	}
+	send(login, "10.0.0.4", bob, tenantID)
+	send(login, "10.0.0.5", carol, tenantID)
+	if w := send(login, "10.0.0.6", carol, tenantID); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected tenant to be limited but got %d", w.Code)
+	}
+}
+
+func TestRateLimitMiddlewareIgnoresSpoofedHeaders(t *testing.T) {
+	cfg := &config.Conf{}
+	cfg.RateLimit.Auth = config.RateLimit{Requests: 2, Period: time.Minute}
+	mid := &middleware{
+		Config:      cfg,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RateLimiter: ratelimiter.NewMemoryLimiter(time.Hour, 1000),
+	}
+	fn := mid.IPAddressMiddleware(mid.RateLimitMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	login := &router.Route{Method: http.MethodPost, Pattern: "/api/v1/login", RateLimitClass: router.RateLimitClassAuth}
+
+	// Rotating the forwarding headers must not give a fresh bucket.
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		r := httptest.NewRequest(login.Method, login.Pattern, nil)
+		r.RemoteAddr = "203.0.113.7:4000"
+		r.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		r.Header.Set("X-Real-Ip", fmt.Sprintf("192.0.2.%d", i))
+		w := httptest.NewRecorder()
+		fn(w, r.WithContext(router.WithMatch(r.Context(), login, nil)))
+		if w.Code != want {
+			t.Fatalf("request %d: expected %d but got %d", i, want, w.Code)
+		}
+	}
+}
+
+func TestRateLimitIPKey(t *testing.T) {
+	for ipAddress, expected := range map[string]string{
+		"1.2.3.4":              "ip:1.2.3.4",
+		"::ffff:1.2.3.4":       "ip:::ffff:1.2.3.4",
+		"2001:db8:1:2:3:4:5:6": "ip:2001:db8:1:2::/64",
+		"2001:db8:1:2:ffff::1": "ip:2001:db8:1:2::/64",
+		"2001:db8:1:3::1":      "ip:2001:db8:1:3::/64",
+		"":                     "ip:",
+	} {
+		if got := rateLimitIPKey(ipAddress); got != expected {
+			t.Errorf("expected the key of %q to be %q but got %q", ipAddress, expected, got)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+// IfMatchMiddleware requires the `If-Match` header on the routes updating a
+// versioned record and saves the expected version to the context so our
+// controllers can reject the update with `412 precondition failed` if the
+// record was modified since the client retrieved it. The `*` value skips the
+// check and lets the client overwrite whatever version is on record.
+func (mid *middleware) IfMatchMiddleware(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route := router.RouteFromContext(r.Context())
+		if route == nil || !route.IsVersioned {
+			fn(w, r) // Flow to the next middleware.
+			return
+		}
+
+		ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+		if ifMatch == "" {
+			httperror.ResponseError(w, httperror.NewWithCode(http.StatusPreconditionRequired, httperror.CodePreconditionRequired, "the If-Match header is required"))
+			return
+		}
+		if ifMatch == "*" {
+			fn(w, r) // Flow to the next middleware.
+			return
+		}
+
+		version, ok := versioning.ParseETag(ifMatch)
+		if !ok {
+			httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("If-Match", "invalid ETag"))
+			return
+		}
+
+		// Flow to the next middleware.
+		fn(w, r.WithContext(versioning.WithExpectedVersion(r.Context(), version)))
+	}
+}
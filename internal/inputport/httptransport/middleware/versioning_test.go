@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+func TestIfMatchMiddleware(t *testing.T) {
+	mid := &middleware{}
+	fn := mid.IfMatchMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate the record being at version 2.
+		if err := versioning.CheckExpectedVersion(r.Context(), 2); err != nil {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name        string
+		isVersioned bool
+		ifMatch     string
+		wantStatus  int
+	}{
+		{name: "not versioned", isVersioned: false, wantStatus: http.StatusOK},
+		{name: "missing", isVersioned: true, wantStatus: http.StatusPreconditionRequired},
+		{name: "malformed", isVersioned: true, ifMatch: "2", wantStatus: http.StatusBadRequest},
+		{name: "any", isVersioned: true, ifMatch: "*", wantStatus: http.StatusOK},
+		{name: "current", isVersioned: true, ifMatch: versioning.ETag(2), wantStatus: http.StatusOK},
+		{name: "stale", isVersioned: true, ifMatch: versioning.ETag(1), wantStatus: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &router.Route{Method: http.MethodPut, Pattern: "/api/v1/program/{id}", IsVersioned: tt.isVersioned}
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/program/123", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+			r = r.WithContext(router.WithMatch(r.Context(), route, router.Params{"id": "123"}))
+			w := httptest.NewRecorder()
+			fn(w, r)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d but got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
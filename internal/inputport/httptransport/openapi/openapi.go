@@ -0,0 +1,239 @@
+package openapi
+
+import (
+	"strings"
+
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+	"github.com/bartmika/databoutique-backend/internal/utils/httperror"
+)
+
+// Document represents the subset of the OpenAPI 3 specification we generate
+// from our route table.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem represents the operations of a single path keyed by the lowercase
+// HTTP method, ex: `get`.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+const (
+	securityJWT    = "JWT"
+	securityAPIKey = "APIKey"
+)
+
+// Generate function returns the OpenAPI 3 document describing the routes. The
+// request and response schemas are derived from the `Request` and `Response`
+// types declared on every route.
+func Generate(info Info, routes []*router.Route) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				securityJWT: {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "Authorization",
+					Description: "The access token returned by the login endpoint, formatted as `JWT <token>`.",
+				},
+				securityAPIKey: {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "Authorization",
+					Description: "An API key created by an administrator, formatted as `Api-Key <key>`.",
+				},
+			},
+		},
+	}
+	reg := newRegistry()
+
+	for _, route := range routes {
+		item, ok := doc.Paths[route.Pattern]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[route.Pattern] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = newOperation(reg, route)
+	}
+
+	doc.Components.Schemas = reg.schemas
+	return doc
+}
+
+func newOperation(reg *registry, route *router.Route) *Operation {
+	op := &Operation{
+		OperationID: operationID(route),
+		Summary:     route.Method + " " + route.Pattern,
+		Tags:        []string{tag(route)},
+		Responses:   map[string]*Response{},
+	}
+
+	for _, segment := range strings.Split(strings.Trim(route.Pattern, "/"), "/") {
+		if len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     segment[1 : len(segment)-1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	// Retries of our `POST` requests are safe if the client sends the same
+	// idempotency key, see our `IdempotencyMiddleware`.
+	if route.IsIdempotent {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        "Idempotency-Key",
+			In:          "header",
+			Description: "Unique key of the request; retries with the same key and body replay the original response.",
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	// Updates of our versioned records must send the `ETag` of the record
+	// they retrieved, see our `IfMatchMiddleware`.
+	if route.IsVersioned {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        "If-Match",
+			In:          "header",
+			Description: "The `ETag` of the record being updated or `*` to overwrite any version.",
+			Required:    true,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	if route.Request != nil {
+		contentType := "application/json"
+		if route.IsMultipart {
+			contentType = "multipart/form-data"
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{contentType: {Schema: reg.schemaOf(route.Request)}},
+		}
+	}
+
+	if route.Response != nil {
+		op.Responses["200"] = &Response{
+			Description: "Successful response.",
+			Content:     map[string]*MediaType{"application/json": {Schema: reg.schemaOf(route.Response)}},
+		}
+	} else {
+		op.Responses["2XX"] = &Response{Description: "Successful response without a JSON body."}
+	}
+
+	// Every error is returned in our error envelope, ex:
+	// `{"code": "validation_error", "message": "...", "fields": {"email": "missing value"}}`.
+	errorContent := map[string]*MediaType{"application/json": {Schema: reg.schemaOf(httperror.ErrorResponse{})}}
+	op.Responses["400"] = &Response{Description: "Invalid request.", Content: errorContent}
+	if route.Request != nil {
+		op.Responses["413"] = &Response{Description: "The request body is too large.", Content: errorContent}
+	}
+	if strings.Contains(route.Pattern, "{") {
+		op.Responses["404"] = &Response{Description: "The record does not exist.", Content: errorContent}
+	}
+	if route.IsVersioned {
+		op.Responses["409"] = &Response{Description: "The record was modified by another request while being updated.", Content: errorContent}
+		op.Responses["412"] = &Response{Description: "The record was modified since the `If-Match` version was retrieved.", Content: errorContent}
+		op.Responses["428"] = &Response{Description: "The `If-Match` header is missing.", Content: errorContent}
+	}
+	op.Responses["429"] = &Response{Description: "Too many requests, retry after the number of seconds in the `Retry-After` header.", Content: errorContent}
+	op.Responses["500"] = &Response{Description: "Internal error, the details are logged with the request ID.", Content: errorContent}
+
+	// Public routes explicitly override any default security requirement.
+	op.Security = []map[string][]string{}
+	if !route.IsPublic {
+		op.Security = append(op.Security, map[string][]string{securityJWT: {}})
+		if route.APIKeyResource != "" {
+			op.Security = append(op.Security, map[string][]string{securityAPIKey: {}})
+		}
+		op.Responses["401"] = &Response{Description: "Missing or invalid credentials.", Content: errorContent}
+		if len(route.Roles) > 0 {
+			op.Responses["403"] = &Response{Description: "The user role is not allowed to access this route.", Content: errorContent}
+		}
+	}
+	return op
+}
+
+// operationID function returns a unique identifier of the route derived from
+// its method and pattern, ex: `GET /api/v1/tenant/{id}` becomes
+// `getTenantById`.
+func operationID(route *router.Route) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(route.Method))
+	path := strings.TrimPrefix(route.Pattern, "/api/v1")
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			sb.WriteString("By")
+			segment = strings.Trim(segment, "{}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' }) {
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return sb.String()
+}
+
+// tag function groups the routes by their API key resource or otherwise by
+// the first segment of their path.
+func tag(route *router.Route) string {
+	if route.APIKeyResource != "" {
+		return route.APIKeyResource
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(route.Pattern, "/api/v1"), "/"), "/")
+	return segments[0]
+}
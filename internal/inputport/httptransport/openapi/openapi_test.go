@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+)
+
+type testComment struct {
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testBase struct {
+	ID primitive.ObjectID `json:"id"`
+}
+
+type testItem struct {
+	testBase
+	Name     string         `json:"name"`
+	Secret   string         `json:"-"`
+	Status   int8           `json:"status"`
+	Comments []*testComment `json:"comments,omitempty"`
+	Parent   *testItem      `json:"parent"`
+	hidden   string
+}
+
+type testUpload struct {
+	File multipart.File `json:"file"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	reg := newRegistry()
+	s := reg.schemaOf(testItem{})
+	if s.Ref != "#/components/schemas/testItem" {
+		t.Fatalf("expected reference to component but got %q", s.Ref)
+	}
+
+	item := reg.schemas["testItem"]
+	for _, name := range []string{"id", "name", "status", "comments", "parent"} {
+		if _, ok := item.Properties[name]; !ok {
+			t.Errorf("expected property %q", name)
+		}
+	}
+	for _, name := range []string{"Secret", "hidden", "testBase"} {
+		if _, ok := item.Properties[name]; ok {
+			t.Errorf("unexpected property %q", name)
+		}
+	}
+	if p := item.Properties["id"]; p.Type != "string" || p.Pattern == "" {
+		t.Errorf("expected object ID to be a hex string but got %+v", p)
+	}
+	if p := item.Properties["status"]; p.Type != "integer" {
+		t.Errorf("expected integer but got %+v", p)
+	}
+	if p := item.Properties["parent"]; p.Ref != "#/components/schemas/testItem" {
+		t.Errorf("expected recursive reference but got %+v", p)
+	}
+	if p := item.Properties["comments"]; p.Type != "array" || p.Items.Ref != "#/components/schemas/testComment" {
+		t.Errorf("expected array of comments but got %+v", p)
+	}
+	if p := reg.schemas["testComment"].Properties["created_at"]; p.Format != "date-time" {
+		t.Errorf("expected date-time but got %+v", p)
+	}
+	if p := reg.schemaOf(testUpload{}); reg.schemas["testUpload"].Properties["file"].Format != "binary" {
+		t.Errorf("expected binary file but got %+v", p)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	rt := router.New([]router.Route{
+		{Method: http.MethodPost, Pattern: "/api/v1/login", Handler: noop, IsPublic: true, Request: testComment{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/item/{id}", Handler: noop, APIKeyResource: "items", Response: testItem{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/item/{id}", Handler: noop, Roles: []int8{1}, Request: testUpload{}, IsMultipart: true, IsVersioned: true},
+	})
+	doc := Generate(Info{Title: "test", Version: "v1.0"}, rt.Routes())
+
+	login := (*doc.Paths["/api/v1/login"])["post"]
+	if len(login.Security) != 0 || login.RequestBody.Content["application/json"] == nil {
+		t.Errorf("expected public JSON route but got %+v", login)
+	}
+
+	get := (*doc.Paths["/api/v1/item/{id}"])["get"]
+	if get.OperationID != "getItemById" {
+		t.Errorf("expected operation ID %q but got %q", "getItemById", get.OperationID)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].Name != "id" || get.Parameters[0].In != "path" {
+		t.Errorf("expected id path parameter but got %+v", get.Parameters)
+	}
+	if len(get.Security) != 2 || get.Responses["200"] == nil {
+		t.Errorf("expected JWT or API key route with a response body but got %+v", get)
+	}
+	if nf := get.Responses["404"]; nf == nil || nf.Content["application/json"].Schema.Ref != "#/components/schemas/ErrorResponse" {
+		t.Errorf("expected not found response in our error envelope but got %+v", get.Responses["404"])
+	}
+
+	put := (*doc.Paths["/api/v1/item/{id}"])["put"]
+	if put.RequestBody.Content["multipart/form-data"] == nil {
+		t.Errorf("expected multipart request but got %+v", put.RequestBody)
+	}
+	if put.Responses["403"] == nil || put.Responses["2XX"] == nil {
+		t.Errorf("expected forbidden and empty success responses but got %+v", put.Responses)
+	}
+	if len(put.Parameters) != 2 || put.Parameters[1].Name != "If-Match" || !put.Parameters[1].Required {
+		t.Errorf("expected required If-Match header but got %+v", put.Parameters)
+	}
+	if put.Responses["412"] == nil || put.Responses["428"] == nil || get.Responses["412"] != nil {
+		t.Errorf("expected precondition responses only on the versioned route but got %+v", put.Responses)
+	}
+}
+
+func TestSwaggerUIHandler(t *testing.T) {
+	h := SwaggerUIHandler("/api/v1/openapi.json")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger-initializer.js", nil))
+	if !strings.Contains(w.Body.String(), `"/api/v1/openapi.json"`) {
+		t.Fatalf("expected initializer to load our specification but got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "swagger-ui") {
+		t.Fatalf("expected Swagger UI index page but got %d", w.Code)
+	}
+}
@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Schema represents the subset of the JSON schema used by OpenAPI 3 that is
+// needed to describe our request and response types.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	objectIDType   = reflect.TypeOf(primitive.ObjectID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	fileType       = reflect.TypeOf((*multipart.File)(nil)).Elem()
+)
+
+// registry keeps track of the named structs we've converted into component
+// schemas so every type is only described once and referenced by `$ref`.
+type registry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newRegistry() *registry {
+	return &registry{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// schemaOf function returns the schema of the value's type.
+func (reg *registry) schemaOf(v any) *Schema {
+	return reg.schemaOfType(reflect.TypeOf(v))
+}
+
+func (reg *registry) schemaOfType(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case objectIDType:
+		return &Schema{Type: "string", Pattern: "^[0-9a-f]{24}$", Nullable: nullable}
+	case rawMessageType:
+		return &Schema{}
+	case fileType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float", Nullable: nullable}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double", Nullable: nullable}
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		// Our handlers encode nil slices as `null`.
+		return &Schema{Type: "array", Items: reg.schemaOfType(t.Elem()), Nullable: true}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reg.schemaOfType(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return reg.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + reg.register(t)}
+	}
+
+	// Interfaces (ex: the `before` and `after` of our audit events) accept any
+	// JSON value.
+	return &Schema{}
+}
+
+// register function adds the named struct to the component schemas and
+// returns its name.
+func (reg *registry) register(t reflect.Type) string {
+	if name, ok := reg.names[t]; ok {
+		return name
+	}
+
+	// Different modules may declare structs with the same name so prefix the
+	// name with the module to keep them unique, ex: `executable.Message`.
+	name := t.Name()
+	if _, taken := reg.schemas[name]; taken {
+		name = moduleName(t.PkgPath()) + "." + t.Name()
+	}
+
+	// Register the name before building the properties to support recursive
+	// types.
+	reg.names[t] = name
+	reg.schemas[name] = &Schema{}
+	*reg.schemas[name] = *reg.structSchema(t)
+	return name
+}
+
+func (reg *registry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	reg.addFields(s, t)
+	return s
+}
+
+// addFields function adds the exported fields of the struct to the schema
+// following the same rules as `encoding/json`.
+func (reg *registry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				reg.addFields(s, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if strings.Contains(opts, "string") {
+			s.Properties[name] = &Schema{Type: "string"}
+			continue
+		}
+		s.Properties[name] = reg.schemaOfType(field.Type)
+	}
+}
+
+// moduleName function returns the module of the package path, ex:
+// `.../internal/app/executable/datastore` returns `executable`.
+func moduleName(pkgPath string) string {
+	dir, pkg := path.Split(pkgPath)
+	switch pkg {
+	case "controller", "datastore", "httptransport":
+		return path.Base(dir)
+	}
+	return pkg
+}
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// SwaggerUIHandler function returns the HTTP handler which serves the
+// embedded Swagger UI configured to load the document at `specURL`. The
+// handler expects the mount prefix to be stripped, ex:
+//
+//	mux.Handle("/api/v1/docs/", http.StripPrefix("/api/v1/docs", openapi.SwaggerUIHandler("/api/v1/openapi.json")))
+func SwaggerUIHandler(specURL string) http.Handler {
+	initializer := fmt.Sprintf(swaggerInitializer, specURL)
+	files := http.FileServer(http.FS(swaggerFiles.FS))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/") == "swagger-initializer.js" {
+			w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+			fmt.Fprint(w, initializer)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
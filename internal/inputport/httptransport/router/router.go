@@ -0,0 +1,230 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Route represents a single API endpoint and the metadata our middleware uses
+// to authorize the request.
+type Route struct {
+	// Method is the HTTP method this route responds to, ex: `GET`.
+	Method string
+
+	// Pattern is the slash-separated path of the route where the segments
+	// wrapped in braces are named parameters, ex: `/api/v1/tenant/{id}`.
+	Pattern string
+
+	// Handler is the function which will handle the request.
+	Handler http.HandlerFunc
+
+	// IsPublic indicates the route can be accessed without authentication.
+	IsPublic bool
+
+	// Roles is the list of user roles allowed to access this route. If empty
+	// then any authenticated user may access it.
+	Roles []int8
+
+	// APIKeyResource is the resource name used by the scopes of our API keys,
+	// ex: `tenants` requires `tenants:read` or `tenants:write`. If empty then
+	// this route cannot be accessed with an API key.
+	APIKeyResource string
+
+	// Request is a zero value of the type decoded from the request body, ex:
+	// `executable_c.ExecutableCreateRequestIDO{}`. It is only used to document
+	// the route in our OpenAPI specification; nil means there is no body.
+	Request any
+
+	// IsMultipart indicates the request body is sent as `multipart/form-data`
+	// instead of JSON.
+	IsMultipart bool
+
+	// Response is a zero value of the type encoded in the response body, ex:
+	// `executable_s.Executable{}`. It is only used to document the route in
+	// our OpenAPI specification; nil means there is no JSON body.
+	Response any
+
+	// HasSecretResponse indicates the response body contains secrets, ex:
+	// the generated API key, and therefore must never be saved.
+	HasSecretResponse bool
+
+	// IsIdempotent indicates the `POST` route supports the `Idempotency-Key`
+	// header, see our `IdempotencyMiddleware`. The responses of the route are
+	// saved and replayed to retries so only routes which never respond with
+	// secrets may opt in.
+	IsIdempotent bool
+
+	// IsVersioned indicates the route updates a versioned record and therefore
+	// requires the `If-Match` header with the ETag of the record.
+	IsVersioned bool
+
+	// RateLimitClass is the class of rate limits applied to this route, ex:
+	// `RateLimitClassAuth`. If empty then `RateLimitClassRead` applies to
+	// the `GET` routes and `RateLimitClassWrite` to the others.
+	RateLimitClass string
+
+	// MaxBodySize is the maximum size in bytes of the request body. If zero
+	// then our configured limit of the JSON or the file upload requests
+	// applies depending on `IsMultipart`.
+	MaxBodySize int64
+
+	segments []string
+}
+
+// The classes of rate limits of our routes.
+const (
+	RateLimitClassAuth        = "auth"
+	RateLimitClassAIOperation = "ai_operation"
+	RateLimitClassRead        = "read"
+	RateLimitClassWrite       = "write"
+)
+
+// RateLimitClassOrDefault returns the class of rate limits of the route.
+func (r *Route) RateLimitClassOrDefault() string {
+	if r.RateLimitClass != "" {
+		return r.RateLimitClass
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return RateLimitClassRead
+	}
+	return RateLimitClassWrite
+}
+
+// IsRoleAllowed returns true if the user role is allowed to access the route.
+func (r *Route) IsRoleAllowed(role int8) bool {
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, allowed := range r.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Params represents the named parameters extracted from the URL path.
+type Params map[string]string
+
+// Router matches the incoming requests against our route table.
+type Router struct {
+	routes []*Route
+}
+
+// New constructor returns the router for the given route table. The routes
+// are matched in the order they were declared. This function panics if a
+// pattern is invalid or declared twice for the same method since the route
+// table is static and must be correct at startup.
+func New(routes []Route) *Router {
+	rt := &Router{}
+	seen := map[string]bool{}
+	for i := range routes {
+		route := routes[i]
+		if route.Method == "" || route.Handler == nil || !strings.HasPrefix(route.Pattern, "/") {
+			panic(fmt.Sprintf("router: invalid route %s %s", route.Method, route.Pattern))
+		}
+		if route.IsIdempotent && (route.Method != http.MethodPost || route.IsPublic || route.HasSecretResponse) {
+			panic(fmt.Sprintf("router: route %s %s cannot be idempotent", route.Method, route.Pattern))
+		}
+		key := route.Method + " " + route.Pattern
+		if seen[key] {
+			panic(fmt.Sprintf("router: duplicate route %s", key))
+		}
+		seen[key] = true
+
+		route.segments = splitPath(route.Pattern)
+		rt.routes = append(rt.routes, &route)
+	}
+	return rt
+}
+
+// Routes returns the route table of this router.
+func (rt *Router) Routes() []*Route {
+	return rt.routes
+}
+
+// Match function returns the route and the path parameters for the request.
+// If no route matched then the returned route is nil and `allowed` contains
+// the methods supported by the path; an empty `allowed` means the path does
+// not exist.
+func (rt *Router) Match(method string, path string) (route *Route, params Params, allowed []string) {
+	segments := splitPath(path)
+	for _, candidate := range rt.routes {
+		p, ok := candidate.match(segments)
+		if !ok {
+			continue
+		}
+		if candidate.Method == method {
+			return candidate, p, nil
+		}
+		allowed = append(allowed, candidate.Method)
+	}
+	return nil, nil, allowed
+}
+
+func (r *Route) match(segments []string) (Params, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	var params Params
+	for i, s := range r.segments {
+		if name, ok := paramName(s); ok {
+			if segments[i] == "" {
+				return nil, false
+			}
+			if params == nil {
+				params = Params{}
+			}
+			params[name] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func paramName(segment string) (string, bool) {
+	if len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+type matchKey struct{}
+
+type matched struct {
+	route  *Route
+	params Params
+}
+
+// WithMatch function saves the matched route and its parameters to the
+// context so our middleware and handlers can read it.
+func WithMatch(ctx context.Context, route *Route, params Params) context.Context {
+	return context.WithValue(ctx, matchKey{}, &matched{route: route, params: params})
+}
+
+// RouteFromContext function returns the matched route of the request or nil
+// if the request did not match any route.
+func RouteFromContext(ctx context.Context) *Route {
+	if m, ok := ctx.Value(matchKey{}).(*matched); ok {
+		return m.route
+	}
+	return nil
+}
+
+// Param function returns the named parameter of the matched route or an
+// empty string if it does not exist.
+func Param(r *http.Request, name string) string {
+	if m, ok := r.Context().Value(matchKey{}).(*matched); ok {
+		return m.params[name]
+	}
+	return ""
+}
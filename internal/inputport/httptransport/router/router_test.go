@@ -0,0 +1,110 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func newTestRouter() *Router {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	return New([]Route{
+		{Method: http.MethodGet, Pattern: "/api/v1/tenants", Handler: noop},
+		{Method: http.MethodPost, Pattern: "/api/v1/tenants", Handler: noop},
+		{Method: http.MethodGet, Pattern: "/api/v1/tenants/select-options", Handler: noop, IsPublic: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/tenant/{id}", Handler: noop},
+		{Method: http.MethodPost, Pattern: "/api/v1/invitation/{id}/resend", Handler: noop},
+	})
+}
+
+func TestMatch(t *testing.T) {
+	rt := newTestRouter()
+
+	tests := []struct {
+		method      string
+		path        string
+		wantPattern string
+		wantParams  Params
+		wantAllowed []string
+	}{
+		{http.MethodGet, "/api/v1/tenants", "/api/v1/tenants", nil, nil},
+		{http.MethodPost, "/api/v1/tenants/", "/api/v1/tenants", nil, nil},
+		{http.MethodGet, "/api/v1/tenants/select-options", "/api/v1/tenants/select-options", nil, nil},
+		{http.MethodGet, "/api/v1/tenant/abc", "/api/v1/tenant/{id}", Params{"id": "abc"}, nil},
+		{http.MethodPost, "/api/v1/invitation/abc/resend", "/api/v1/invitation/{id}/resend", Params{"id": "abc"}, nil},
+		{http.MethodDelete, "/api/v1/tenants", "", nil, []string{http.MethodGet, http.MethodPost}},
+		{http.MethodPost, "/api/v1/invitation//resend", "", nil, nil},
+		{http.MethodGet, "/api/v1/unknown", "", nil, nil},
+	}
+	for _, tt := range tests {
+		route, params, allowed := rt.Match(tt.method, tt.path)
+		pattern := ""
+		if route != nil {
+			pattern = route.Pattern
+		}
+		if pattern != tt.wantPattern {
+			t.Errorf("%s %s: expected route %q but got %q", tt.method, tt.path, tt.wantPattern, pattern)
+		}
+		if !reflect.DeepEqual(params, tt.wantParams) {
+			t.Errorf("%s %s: expected params %v but got %v", tt.method, tt.path, tt.wantParams, params)
+		}
+		if !reflect.DeepEqual(allowed, tt.wantAllowed) {
+			t.Errorf("%s %s: expected allowed %v but got %v", tt.method, tt.path, tt.wantAllowed, allowed)
+		}
+	}
+}
+
+func TestParamFromContext(t *testing.T) {
+	rt := newTestRouter()
+	route, params, _ := rt.Match(http.MethodGet, "/api/v1/tenant/abc")
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/tenant/abc", nil)
+	r = r.WithContext(WithMatch(r.Context(), route, params))
+
+	if got := RouteFromContext(r.Context()); got != route {
+		t.Fatalf("expected route %v but got %v", route, got)
+	}
+	if got := Param(r, "id"); got != "abc" {
+		t.Fatalf("expected param %q but got %q", "abc", got)
+	}
+	if got := Param(r, "missing"); got != "" {
+		t.Fatalf("expected empty param but got %q", got)
+	}
+}
+
+func TestIsRoleAllowed(t *testing.T) {
+	anyone := &Route{}
+	if !anyone.IsRoleAllowed(5) {
+		t.Fatal("expected route without roles to allow any role")
+	}
+	restricted := &Route{Roles: []int8{1, 2}}
+	if !restricted.IsRoleAllowed(2) || restricted.IsRoleAllowed(3) {
+		t.Fatal("expected route to only allow the declared roles")
+	}
+}
+
+func TestNewPanicsOnDuplicateRoute(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	New([]Route{
+		{Method: http.MethodGet, Pattern: "/api/v1/tenants", Handler: noop},
+		{Method: http.MethodGet, Pattern: "/api/v1/tenants", Handler: noop},
+	})
+}
+
+func TestNewPanicsOnIdempotentSecretRoute(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	New([]Route{
+		{Method: http.MethodPost, Pattern: "/api/v1/api-keys", Handler: noop, IsIdempotent: true, HasSecretResponse: true},
+	})
+}
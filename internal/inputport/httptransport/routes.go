@@ -0,0 +1,233 @@
+package httptransport
+
+import (
+	"net/http"
+
+	apikey_c "github.com/bartmika/databoutique-backend/internal/app/apikey/controller"
+	apikey_s "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	assistant_c "github.com/bartmika/databoutique-backend/internal/app/assistant/controller"
+	assistant_s "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
+	assistantfile_c "github.com/bartmika/databoutique-backend/internal/app/assistantfile/controller"
+	assistantfile_s "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
+	assistantmessage_c "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/controller"
+	assistantmessage_s "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
+	assistantthread_c "github.com/bartmika/databoutique-backend/internal/app/assistantthread/controller"
+	assistantthread_s "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
+	attachment_c "github.com/bartmika/databoutique-backend/internal/app/attachment/controller"
+	attachment_s "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
+	auditevent_s "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	executable_c "github.com/bartmika/databoutique-backend/internal/app/executable/controller"
+	executable_s "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
+	gateway_c "github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
+	gateway_s "github.com/bartmika/databoutique-backend/internal/app/gateway/datastore"
+	gateway "github.com/bartmika/databoutique-backend/internal/app/gateway/httptransport"
+	health_c "github.com/bartmika/databoutique-backend/internal/app/health/controller"
+	howhear_s "github.com/bartmika/databoutique-backend/internal/app/howhear/datastore"
+	invitation_c "github.com/bartmika/databoutique-backend/internal/app/invitation/controller"
+	invitation_s "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	program_c "github.com/bartmika/databoutique-backend/internal/app/program/controller"
+	program_s "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
+	programcategory_c "github.com/bartmika/databoutique-backend/internal/app/programcategory/controller"
+	programcategory_s "github.com/bartmika/databoutique-backend/internal/app/programcategory/datastore"
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+	tenant "github.com/bartmika/databoutique-backend/internal/app/tenant/httptransport"
+	uploaddirectory_c "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/controller"
+	uploaddirectory_s "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
+	uploadfile_c "github.com/bartmika/databoutique-backend/internal/app/uploadfile/controller"
+	uploadfile_s "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
+	user_c "github.com/bartmika/databoutique-backend/internal/app/user/controller"
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+	user "github.com/bartmika/databoutique-backend/internal/app/user/httptransport"
+	webhook_c "github.com/bartmika/databoutique-backend/internal/app/webhook/controller"
+	webhook_s "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+)
+
+var (
+	// executiveRoles are the roles allowed to manage our tenants and read our
+	// audit log.
+	executiveRoles = []int8{user_s.UserRoleExecutive}
+
+	// administratorRoles are the roles allowed to manage the integrations and
+	// the members of a tenant.
+	administratorRoles = []int8{user_s.UserRoleExecutive, user_s.UserRoleManagement}
+)
+
+// routes function returns the route table of our API. Every route declares
+// its method, path pattern, handler, authorization requirements and rate
+// limits which are enforced by our middleware.
+func (port *httpTransportInputPort) routes() []router.Route {
+	return []router.Route{
+		// --- GATEWAY & PROFILE --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/health-check", Handler: port.Gateway.HealthCheck, IsPublic: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/health/live", Handler: port.Health.Liveness, IsPublic: true, Response: health_c.HealthResponseIDO{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/health/ready", Handler: port.Health.Readiness, IsPublic: true, Response: health_c.HealthResponseIDO{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/health/openai", Handler: port.Health.OpenAI, Roles: executiveRoles, Response: health_c.DependencyResult{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/version", Handler: port.Gateway.Version, IsPublic: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/openapi.json", Handler: port.OpenAPISpecification, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/api/v1/greeting", Handler: port.Gateway.Greet, IsPublic: true, Request: gateway.GreetingRequest{}, Response: gateway.GreetingResponse{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/login", Handler: port.Gateway.Login, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway.LoginRequestIDO{}, Response: gateway_s.LoginResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/login/2fa", Handler: port.Gateway.LoginTwoFactor, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.LoginTwoFactorRequestIDO{}, Response: gateway_s.LoginResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/login/2fa/enroll", Handler: port.Gateway.LoginTwoFactorEnroll, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.LoginTwoFactorRequestIDO{}, Response: gateway_c.TwoFactorEnrollmentResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/login/oidc", Handler: port.Gateway.OIDCLoginStart, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.OIDCLoginStartRequestIDO{}, Response: gateway_c.OIDCLoginStartResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/login/oidc/callback", Handler: port.Gateway.OIDCLoginCallback, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.OIDCLoginCallbackRequestIDO{}, Response: gateway_s.LoginResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/register", Handler: port.Gateway.UserRegister, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.UserRegisterRequestIDO{}, Response: gateway_s.LoginResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/register/tenant", Handler: port.Gateway.TenantRegister, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.TenantRegisterRequestIDO{}, Response: gateway_s.LoginResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/refresh-token", Handler: port.Gateway.RefreshToken, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway.RefreshTokenRequestIDO{}, Response: gateway.RefreshTokenResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/verify", Handler: port.Gateway.Verify, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.VerifyRequestIDO{}, Response: gateway_c.VerifyResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/verify/resend", Handler: port.Gateway.VerifyResend, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.VerifyResendRequestIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/logout", Handler: port.Gateway.Logout},
+		{Method: http.MethodGet, Pattern: "/api/v1/profile", Handler: port.Gateway.Profile, Response: user_s.User{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/profile", Handler: port.Gateway.ProfileUpdate, Request: user_s.User{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/profile/change-password", Handler: port.Gateway.ProfileChangePassword, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.ProfileChangePasswordRequestIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/profile/oidc/link", Handler: port.Gateway.ProfileOIDCLinkStart, RateLimitClass: router.RateLimitClassAuth, Response: gateway_c.OIDCLoginStartResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/profile/2fa/generate", Handler: port.Gateway.ProfileTwoFactorGenerate, Response: gateway_c.TwoFactorEnrollmentResponseIDO{}, HasSecretResponse: true},
+		{Method: http.MethodPost, Pattern: "/api/v1/profile/2fa/verify", Handler: port.Gateway.ProfileTwoFactorVerify, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.ProfileTwoFactorVerifyRequestIDO{}, Response: gateway_c.TwoFactorRecoveryCodesResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/profile/2fa/disable", Handler: port.Gateway.ProfileTwoFactorDisable, RateLimitClass: router.RateLimitClassAuth, Request: gateway_c.ProfileTwoFactorDisableRequestIDO{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/profile/sessions", Handler: port.Gateway.ProfileSessionList, Response: session_s.SessionListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/profile/sessions/revoke-all", Handler: port.Gateway.ProfileSessionRevokeAll},
+		{Method: http.MethodDelete, Pattern: "/api/v1/profile/session/{id}", Handler: withID(port.Gateway.ProfileSessionRevoke)},
+		{Method: http.MethodPost, Pattern: "/api/v1/forgot-password", Handler: port.Gateway.ForgotPassword, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway.ForgotPasswordRequestIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/password-reset", Handler: port.Gateway.PasswordReset, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: gateway.PasswordResetRequestIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/executive-visit-tenant", Handler: port.Gateway.ExecutiveVisitsTenant, Request: gateway_c.ExecutiveVisitsTenantRequest{}},
+
+		// --- ORGANIZATION --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/tenants", Handler: port.Tenant.List, Roles: executiveRoles, APIKeyResource: "tenants", Response: tenant_s.TenantListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/tenants", Handler: port.Tenant.Create, Roles: executiveRoles, APIKeyResource: "tenants", Request: tenant_s.Tenant{}, Response: tenant_s.Tenant{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/tenant/{id}", Handler: withID(port.Tenant.GetByID), APIKeyResource: "tenants", Response: tenant_s.Tenant{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/tenant/{id}", Handler: withID(port.Tenant.UpdateByID), APIKeyResource: "tenants", IsVersioned: true, Request: tenant_s.Tenant{}, Response: tenant_s.Tenant{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/tenant/{id}/oidc", Handler: withID(port.Tenant.UpdateOIDCByID), Roles: administratorRoles, IsVersioned: true, Request: tenant_s.TenantOIDCConfig{}, Response: tenant_s.Tenant{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/tenant/{id}", Handler: withID(port.Tenant.DeleteByID), Roles: executiveRoles, APIKeyResource: "tenants"},
+		{Method: http.MethodPost, Pattern: "/api/v1/tenants/operation/create-comment", Handler: port.Tenant.OperationCreateComment, APIKeyResource: "tenants", Request: tenant.TenantOperationCreateCommentRequest{}, Response: tenant_s.Tenant{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/tenants/select-options", Handler: port.Tenant.ListAsSelectOptionByFilter, Roles: executiveRoles, APIKeyResource: "tenants", Response: []tenant_s.TenantAsSelectOption{}},
+
+		// --- UPLOAD DIRECTORY --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/upload-directories", Handler: port.UploadDirectory.List, APIKeyResource: "upload-directories", Response: uploaddirectory_s.UploadDirectoryPaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/upload-directories", Handler: port.UploadDirectory.Create, APIKeyResource: "upload-directories", Request: uploaddirectory_c.UploadDirectoryCreateRequestIDO{}, Response: uploaddirectory_s.UploadDirectory{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/upload-directory/{id}", Handler: withID(port.UploadDirectory.GetByID), APIKeyResource: "upload-directories", Response: uploaddirectory_s.UploadDirectory{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/upload-directory/{id}", Handler: withID(port.UploadDirectory.UpdateByID), APIKeyResource: "upload-directories", IsVersioned: true, Request: uploaddirectory_c.UploadDirectoryUpdateRequestIDO{}, Response: uploaddirectory_s.UploadDirectory{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/upload-directory/{id}", Handler: withID(port.UploadDirectory.DeleteByID), APIKeyResource: "upload-directories"},
+		{Method: http.MethodGet, Pattern: "/api/v1/upload-directories/select-options", Handler: port.UploadDirectory.ListAsSelectOptionByFilter, APIKeyResource: "upload-directories", Response: []uploaddirectory_s.UploadDirectoryAsSelectOption{}},
+
+		// --- UPLOAD FILE --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/upload-files", Handler: port.UploadFile.List, APIKeyResource: "upload-files", Response: uploadfile_s.UploadFilePaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/upload-files", Handler: port.UploadFile.Create, APIKeyResource: "upload-files", Request: uploadfile_c.UploadFileCreateRequestIDO{}, IsMultipart: true, Response: uploadfile_s.UploadFile{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/upload-file/{id}", Handler: withID(port.UploadFile.GetByID), APIKeyResource: "upload-files", Response: uploadfile_s.UploadFile{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/upload-file/{id}", Handler: withID(port.UploadFile.UpdateByID), APIKeyResource: "upload-files", IsVersioned: true, Request: uploadfile_c.UploadFileUpdateRequestIDO{}, IsMultipart: true, Response: uploadfile_s.UploadFile{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/upload-file/{id}", Handler: withID(port.UploadFile.DeleteByID), APIKeyResource: "upload-files"},
+		{Method: http.MethodGet, Pattern: "/api/v1/upload-files/select-options", Handler: port.UploadFile.ListAsSelectOptionByFilter, APIKeyResource: "upload-files", Response: []uploadfile_s.UploadFileAsSelectOption{}},
+
+		// --- PROGRAM CATEGORY --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/program-categories", Handler: port.ProgramCategory.List, APIKeyResource: "program-categories", Response: programcategory_s.ProgramCategoryPaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/program-categories", Handler: port.ProgramCategory.Create, APIKeyResource: "program-categories", Request: programcategory_c.ProgramCategoryCreateRequestIDO{}, Response: programcategory_s.ProgramCategory{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/program-category/{id}", Handler: withID(port.ProgramCategory.GetByID), APIKeyResource: "program-categories", Response: programcategory_s.ProgramCategory{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/program-category/{id}", Handler: withID(port.ProgramCategory.UpdateByID), APIKeyResource: "program-categories", IsVersioned: true, Request: programcategory_c.ProgramCategoryUpdateRequestIDO{}, Response: programcategory_s.ProgramCategory{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/program-category/{id}", Handler: withID(port.ProgramCategory.DeleteByID), APIKeyResource: "program-categories"},
+		{Method: http.MethodGet, Pattern: "/api/v1/program-categories/select-options", Handler: port.ProgramCategory.ListAsSelectOptionByFilter, APIKeyResource: "program-categories", Response: []programcategory_s.ProgramCategoryAsSelectOption{}},
+
+		// --- PROGRAM --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/programs", Handler: port.Program.List, APIKeyResource: "programs", Response: program_s.ProgramPaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/programs", Handler: port.Program.Create, APIKeyResource: "programs", Request: program_c.ProgramCreateRequestIDO{}, Response: program_s.Program{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/program/{id}", Handler: withID(port.Program.GetByID), APIKeyResource: "programs", Response: program_s.Program{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/program/{id}", Handler: withID(port.Program.UpdateByID), APIKeyResource: "programs", IsVersioned: true, Request: program_c.ProgramUpdateRequestIDO{}, Response: program_s.Program{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/program/{id}", Handler: withID(port.Program.DeleteByID), APIKeyResource: "programs"},
+		{Method: http.MethodGet, Pattern: "/api/v1/programs/select-options", Handler: port.Program.ListAsSelectOptionByFilter, APIKeyResource: "programs", Response: []program_s.ProgramAsSelectOption{}},
+
+		// --- EXECUTABLE --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/executables", Handler: port.Executable.List, APIKeyResource: "executables", Response: executable_s.ExecutablePaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/executables", Handler: port.Executable.Create, APIKeyResource: "executables", RateLimitClass: router.RateLimitClassAIOperation, Request: executable_c.ExecutableCreateRequestIDO{}, Response: executable_s.Executable{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/executable/{id}", Handler: withID(port.Executable.GetByID), APIKeyResource: "executables", Response: executable_s.Executable{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/executable/{id}", Handler: withID(port.Executable.UpdateByID), APIKeyResource: "executables", IsVersioned: true, Request: executable_c.ExecutableUpdateRequestIDO{}, Response: executable_s.Executable{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/executable/{id}", Handler: withID(port.Executable.DeleteByID), APIKeyResource: "executables"},
+		{Method: http.MethodGet, Pattern: "/api/v1/executables/select-options", Handler: port.Executable.ListAsSelectOptionByFilter, APIKeyResource: "executables", Response: []executable_s.ExecutableAsSelectOption{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/executables/operations/question-submission", Handler: port.Executable.QuestionSubmissionOperation, APIKeyResource: "executables", RateLimitClass: router.RateLimitClassAIOperation, Request: executable_c.QuestionSubmissionOperationRequestIDO{}, Response: executable_s.Executable{}, IsIdempotent: true},
+
+		// --- ASSISTANT FILE --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-files", Handler: port.AssistantFile.List, APIKeyResource: "assistant-files", Response: assistantfile_s.AssistantFilePaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/assistant-files", Handler: port.AssistantFile.Create, APIKeyResource: "assistant-files", Request: assistantfile_c.AssistantFileCreateRequestIDO{}, IsMultipart: true, Response: assistantfile_s.AssistantFile{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-file/{id}", Handler: withID(port.AssistantFile.GetByID), APIKeyResource: "assistant-files", Response: assistantfile_s.AssistantFile{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/assistant-file/{id}", Handler: withID(port.AssistantFile.UpdateByID), APIKeyResource: "assistant-files", IsVersioned: true, Request: assistantfile_c.AssistantFileUpdateRequestIDO{}, IsMultipart: true, Response: assistantfile_s.AssistantFile{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/assistant-file/{id}", Handler: withID(port.AssistantFile.DeleteByID), APIKeyResource: "assistant-files"},
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-files/select-options", Handler: port.AssistantFile.ListAsSelectOptionByFilter, APIKeyResource: "assistant-files", Response: []assistantfile_s.AssistantFileAsSelectOption{}},
+
+		// --- ASSISTANT --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/assistants", Handler: port.Assistant.List, APIKeyResource: "assistants", Response: assistant_s.AssistantPaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/assistants", Handler: port.Assistant.Create, APIKeyResource: "assistants", Request: assistant_c.AssistantCreateRequestIDO{}, Response: assistant_s.Assistant{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant/{id}", Handler: withID(port.Assistant.GetByID), APIKeyResource: "assistants", Response: assistant_s.Assistant{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/assistant/{id}", Handler: withID(port.Assistant.UpdateByID), APIKeyResource: "assistants", IsVersioned: true, Request: assistant_c.AssistantUpdateRequestIDO{}, Response: assistant_s.Assistant{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/assistant/{id}", Handler: withID(port.Assistant.DeleteByID), APIKeyResource: "assistants"},
+		{Method: http.MethodGet, Pattern: "/api/v1/assistants/select-options", Handler: port.Assistant.ListAsSelectOptionByFilter, APIKeyResource: "assistants", Response: []assistant_s.AssistantAsSelectOption{}},
+
+		// --- ASSISTANT THREAD --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-threads", Handler: port.AssistantThread.List, APIKeyResource: "assistant-threads", Response: assistantthread_s.AssistantThreadPaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/assistant-threads", Handler: port.AssistantThread.Create, APIKeyResource: "assistant-threads", RateLimitClass: router.RateLimitClassAIOperation, Request: assistantthread_c.AssistantThreadCreateRequestIDO{}, Response: assistantthread_s.AssistantThread{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-thread/{id}", Handler: withID(port.AssistantThread.GetByID), APIKeyResource: "assistant-threads", Response: assistantthread_s.AssistantThread{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/assistant-thread/{id}", Handler: withID(port.AssistantThread.UpdateByID), APIKeyResource: "assistant-threads", IsVersioned: true, Request: assistantthread_c.AssistantThreadUpdateRequestIDO{}, Response: assistantthread_s.AssistantThread{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/assistant-thread/{id}", Handler: withID(port.AssistantThread.DeleteByID), APIKeyResource: "assistant-threads"},
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-threads/select-options", Handler: port.AssistantThread.ListAsSelectOptionByFilter, APIKeyResource: "assistant-threads", Response: []assistantthread_s.AssistantThreadAsSelectOption{}},
+
+		// --- ASSISTANT MESSAGE --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-messages", Handler: port.AssistantMessage.List, APIKeyResource: "assistant-messages", Response: assistantmessage_s.AssistantMessagePaginationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/assistant-messages", Handler: port.AssistantMessage.Create, APIKeyResource: "assistant-messages", RateLimitClass: router.RateLimitClassAIOperation, Request: assistantmessage_c.AssistantMessageCreateRequestIDO{}, Response: assistantmessage_s.AssistantMessage{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/assistant-message/{id}", Handler: withID(port.AssistantMessage.GetByID), APIKeyResource: "assistant-messages", Response: assistantmessage_s.AssistantMessage{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/assistant-message/{id}", Handler: withID(port.AssistantMessage.UpdateByID), APIKeyResource: "assistant-messages", IsVersioned: true, Request: assistantmessage_c.AssistantMessageUpdateRequestIDO{}, Response: assistantmessage_s.AssistantMessage{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/assistant-message/{id}", Handler: withID(port.AssistantMessage.DeleteByID), APIKeyResource: "assistant-messages"},
+
+		// --- HOW HEAR --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/select-options/how-hear-about-us-items", Handler: port.HowHear.PublicListAsSelectOptions, IsPublic: true, Response: []howhear_s.HowHearAboutUsItemAsSelectOption{}},
+
+		// --- USERS --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/users", Handler: port.User.List, APIKeyResource: "users", Response: user_s.UserListResult{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/users/count", Handler: port.User.Count, APIKeyResource: "users", Response: user_c.UserCountResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/users", Handler: port.User.Create, APIKeyResource: "users", Request: user_c.UserCreateRequestIDO{}, Response: user_s.User{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/user/{id}", Handler: withID(port.User.GetByID), APIKeyResource: "users", Response: user_s.User{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/user/{id}", Handler: withID(port.User.UpdateByID), APIKeyResource: "users", IsVersioned: true, Request: user_c.UserUpdateRequestIDO{}, Response: user_s.User{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/user/{id}", Handler: withID(port.User.DeleteByID), APIKeyResource: "users"},
+		{Method: http.MethodPost, Pattern: "/api/v1/users/operation/create-comment", Handler: port.User.OperationCreateComment, APIKeyResource: "users", Request: user.UserOperationCreateCommentRequest{}, Response: user_s.User{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/users/select-options", Handler: port.User.ListAsSelectOptions, APIKeyResource: "users", Response: []user_s.UserAsSelectOption{}},
+
+		// --- ATTACHMENTS --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/attachments", Handler: port.Attachment.List, APIKeyResource: "attachments", Response: attachment_s.AttachmentListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/attachments", Handler: port.Attachment.Create, APIKeyResource: "attachments", Request: attachment_c.AttachmentCreateRequestIDO{}, IsMultipart: true, Response: attachment_s.Attachment{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/attachment/{id}", Handler: withID(port.Attachment.GetByID), APIKeyResource: "attachments", Response: attachment_s.Attachment{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/attachment/{id}", Handler: withID(port.Attachment.UpdateByID), APIKeyResource: "attachments", IsVersioned: true, Request: attachment_c.AttachmentUpdateRequestIDO{}, IsMultipart: true, Response: attachment_s.Attachment{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/attachment/{id}", Handler: withID(port.Attachment.DeleteByID), APIKeyResource: "attachments"},
+
+		// --- API KEY --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/api-keys", Handler: port.APIKey.List, Roles: administratorRoles, Response: apikey_s.APIKeyListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/api-keys", Handler: port.APIKey.Create, Roles: administratorRoles, Request: apikey_c.APIKeyCreateRequestIDO{}, Response: apikey_c.APIKeyCreateResponseIDO{}, HasSecretResponse: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/api-key/{id}", Handler: withID(port.APIKey.GetByID), Roles: administratorRoles, Response: apikey_s.APIKey{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/api-key/{id}", Handler: withID(port.APIKey.UpdateByID), Roles: administratorRoles, IsVersioned: true, Request: apikey_c.APIKeyUpdateRequestIDO{}, Response: apikey_s.APIKey{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/api-key/{id}", Handler: withID(port.APIKey.DeleteByID), Roles: administratorRoles},
+
+		// --- INVITATION --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/invitations", Handler: port.Invitation.ListPending, Roles: administratorRoles, Response: invitation_s.InvitationListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/invitations", Handler: port.Invitation.Create, Roles: administratorRoles, Request: invitation_c.InvitationCreateRequestIDO{}, Response: invitation_s.Invitation{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/invitation/{id}", Handler: withID(port.Invitation.GetByID), Roles: administratorRoles, Response: invitation_s.Invitation{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/invitation/{id}", Handler: withID(port.Invitation.RevokeByID), Roles: administratorRoles},
+		{Method: http.MethodPost, Pattern: "/api/v1/invitation/{id}/resend", Handler: withID(port.Invitation.ResendByID), Roles: administratorRoles, Response: invitation_s.Invitation{}, IsIdempotent: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/accept-invitation", Handler: port.Invitation.GetByToken, IsPublic: true, Response: invitation_c.InvitationDetailResponseIDO{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/accept-invitation", Handler: port.Invitation.Accept, IsPublic: true, RateLimitClass: router.RateLimitClassAuth, Request: invitation_c.InvitationAcceptRequestIDO{}, Response: user_s.User{}},
+
+		// --- WEBHOOK --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/webhooks", Handler: port.Webhook.List, Roles: administratorRoles, Response: webhook_s.WebhookListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/webhooks", Handler: port.Webhook.Create, Roles: administratorRoles, Request: webhook_c.WebhookCreateRequestIDO{}, Response: webhook_c.WebhookCreateResponseIDO{}, HasSecretResponse: true},
+		{Method: http.MethodGet, Pattern: "/api/v1/webhook/{id}", Handler: withID(port.Webhook.GetByID), Roles: administratorRoles, Response: webhook_s.Webhook{}},
+		{Method: http.MethodPut, Pattern: "/api/v1/webhook/{id}", Handler: withID(port.Webhook.UpdateByID), Roles: administratorRoles, IsVersioned: true, Request: webhook_c.WebhookUpdateRequestIDO{}, Response: webhook_s.Webhook{}},
+		{Method: http.MethodDelete, Pattern: "/api/v1/webhook/{id}", Handler: withID(port.Webhook.DeleteByID), Roles: administratorRoles},
+		{Method: http.MethodGet, Pattern: "/api/v1/webhook/{id}/deliveries", Handler: withID(port.Webhook.ListDeliveriesByID), Roles: administratorRoles, Response: webhook_s.WebhookDeliveryListResult{}},
+		{Method: http.MethodPost, Pattern: "/api/v1/webhook/{id}/test", Handler: withID(port.Webhook.SendTestEventByID), Roles: administratorRoles, Response: webhook_s.WebhookDelivery{}},
+
+		// --- AUDIT EVENT --- //
+		{Method: http.MethodGet, Pattern: "/api/v1/audit-events", Handler: port.AuditEvent.List, Roles: executiveRoles, Response: auditevent_s.AuditEventPaginationListResult{}},
+		{Method: http.MethodGet, Pattern: "/api/v1/audit-events/export-csv", Handler: port.AuditEvent.ExportAsCSV, Roles: executiveRoles},
+	}
+}
+
+// withID function adapts the handlers which take the `{id}` path parameter.
+func withID(fn func(w http.ResponseWriter, r *http.Request, id string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fn(w, r, router.Param(r, "id"))
+	}
+}
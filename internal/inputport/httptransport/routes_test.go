@@ -0,0 +1,87 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/openapi"
+	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/router"
+)
+
+// TestOpenAPICoversRoutes fails if a registered route is missing from our
+// OpenAPI specification or if its documented body does not match the route.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	port := &httpTransportInputPort{}
+	rt := router.New(port.routes())
+	routes := rt.Routes()
+	doc := openapi.Generate(openapi.Info{Title: "test", Version: "v1.0"}, routes)
+
+	// Encode and decode the document so we verify what the clients receive.
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("failed encoding document: %v", err)
+	}
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string                     `json:"operationId"`
+			RequestBody *struct{ Content any }     `json:"requestBody"`
+			Responses   map[string]json.RawMessage `json:"responses"`
+			Security    []map[string][]string      `json:"security"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		t.Fatalf("failed decoding document: %v", err)
+	}
+
+	operationIDs := map[string]string{}
+	for _, route := range routes {
+		key := route.Method + " " + route.Pattern
+		op, ok := spec.Paths[route.Pattern][strings.ToLower(route.Method)]
+		if !ok {
+			t.Errorf("%s: missing from the OpenAPI specification", key)
+			continue
+		}
+		if other, dup := operationIDs[op.OperationID]; dup {
+			t.Errorf("%s: operation ID %q already used by %s", key, op.OperationID, other)
+		}
+		operationIDs[op.OperationID] = key
+
+		if (route.Request != nil) != (op.RequestBody != nil) {
+			t.Errorf("%s: expected request body to be documented=%v", key, route.Request != nil)
+		}
+		if _, ok := op.Responses["200"]; ok != (route.Response != nil) {
+			t.Errorf("%s: expected response body to be documented=%v", key, route.Response != nil)
+		}
+		if route.IsPublic != (len(op.Security) == 0) {
+			t.Errorf("%s: expected public=%v but got security %v", key, route.IsPublic, op.Security)
+		}
+	}
+
+	for pattern, item := range spec.Paths {
+		for method := range item {
+			if route, _, _ := rt.Match(strings.ToUpper(method), pattern); route == nil {
+				t.Errorf("%s %s: documented but not registered", method, pattern)
+			}
+		}
+	}
+}
+
+func TestOpenAPISpecificationHandler(t *testing.T) {
+	port := &httpTransportInputPort{}
+	port.Router = router.New(port.routes())
+	port.OpenAPI = openapi.Generate(openapi.Info{Title: "test", Version: "v1.0"}, port.Router.Routes())
+
+	w := httptest.NewRecorder()
+	port.OpenAPISpecification(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+
+	var spec map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("expected JSON document but got error: %v", err)
+	}
+	if spec["openapi"] != "3.0.3" {
+		t.Fatalf("expected OpenAPI 3.0.3 document but got %v", spec["openapi"])
+	}
+}
@@ -0,0 +1,41 @@
+package httptransport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rs/cors"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// newCORS function returns the CORS handler allowing the origins and methods
+// of our configuration to call our API from a browser.
+func newCORS(cfg *config.Conf) *cors.Cors {
+	return cors.New(cors.Options{
+		AllowedOrigins: cfg.HTTP.CORSAllowedOrigins,
+		AllowedMethods: cfg.HTTP.CORSAllowedMethods,
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", "If-Match", "X-Request-ID"},
+		ExposedHeaders: []string{"ETag", "Idempotent-Replayed", "Retry-After", "X-Request-ID"},
+		MaxAge:         600, // seconds.
+	})
+}
+
+// securityHeaders function returns the handler which sets the standard
+// security headers on every response before calling `next`.
+func securityHeaders(cfg *config.Conf, next http.Handler) http.Handler {
+	var hsts string
+	if cfg.HTTP.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HTTP.HSTSMaxAge.Seconds())) + "; includeSubDomains"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		if hsts != "" {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+		next.ServeHTTP(w, r)
+	})
+}
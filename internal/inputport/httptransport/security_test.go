@@ -0,0 +1,49 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+func TestSecurityHeadersAndCORS(t *testing.T) {
+	cfg := &config.Conf{}
+	cfg.HTTP.CORSAllowedOrigins = []string{"https://databoutique.ca"}
+	cfg.HTTP.CORSAllowedMethods = []string{"GET", "PUT"}
+	cfg.HTTP.HSTSMaxAge = 24 * time.Hour
+	handler := securityHeaders(cfg, newCORS(cfg).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/programs", nil)
+	r.Header.Set("Origin", "https://databoutique.ca")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=86400; includeSubDomains" {
+		t.Errorf("unexpected HSTS header %q", got)
+	}
+	if w.Header().Get("X-Content-Type-Options") != "nosniff" || w.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("expected security headers but got %v", w.Header())
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://databoutique.ca" {
+		t.Errorf("expected allowed origin but got %q", got)
+	}
+
+	// Preflight requests of other origins or methods are not allowed.
+	for _, tt := range []struct{ origin, method string }{
+		{"https://evil.example", http.MethodGet},
+		{"https://databoutique.ca", http.MethodDelete},
+	} {
+		r := httptest.NewRequest(http.MethodOptions, "/api/v1/programs", nil)
+		r.Header.Set("Origin", tt.origin)
+		r.Header.Set("Access-Control-Request-Method", tt.method)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("expected %s %s to be rejected but got allowed origin %q", tt.method, tt.origin, got)
+		}
+	}
+}
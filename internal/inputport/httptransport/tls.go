@@ -0,0 +1,108 @@
+package httptransport
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// How often the certificate files are checked for changes.
+const certificateReloadInterval = 10 * time.Second
+
+// certificateReloader serves the certificate of our TLS configuration and
+// reloads it once the certificate or key file changed, ex: after a renewal.
+type certificateReloader struct {
+	Logger   *slog.Logger
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+
+	// now returns the current time and is replaced by our tests.
+	now func() time.Time
+}
+
+func newCertificateReloader(loggerp *slog.Logger, certFile string, keyFile string) (*certificateReloader, error) {
+	cr := &certificateReloader{
+		Logger:   loggerp,
+		certFile: certFile,
+		keyFile:  keyFile,
+		now:      time.Now,
+	}
+	if err := cr.load(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// GetCertificate function returns the current certificate and implements
+// the `tls.Config.GetCertificate` callback.
+func (cr *certificateReloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if now := cr.now(); now.Sub(cr.checkedAt) >= certificateReloadInterval {
+		cr.checkedAt = now
+		if modTime, err := cr.latestModTime(); err == nil && !modTime.Equal(cr.modTime) {
+			// Keep serving the previous certificate if the new files are
+			// invalid, ex: only one of the files was written so far.
+			if err := cr.loadLocked(); err != nil {
+				cr.Logger.Error("failed reloading TLS certificate", slog.Any("error", err))
+			} else {
+				cr.Logger.Info("TLS certificate reloaded")
+			}
+		}
+	}
+	return cr.cert, nil
+}
+
+// TLSConfig function returns the TLS configuration of our server which
+// negotiates HTTP/2 with the clients supporting it.
+func (cr *certificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{"h2", "http/1.1"},
+		GetCertificate: cr.GetCertificate,
+	}
+}
+
+func (cr *certificateReloader) load() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.loadLocked()
+}
+
+func (cr *certificateReloader) loadLocked() error {
+	modTime, err := cr.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return err
+	}
+	cr.cert = &cert
+	cr.modTime = modTime
+	return nil
+}
+
+// latestModTime function returns the most recent modification time of the
+// certificate and key files.
+func (cr *certificateReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{cr.certFile, cr.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
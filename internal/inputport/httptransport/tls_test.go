@@ -0,0 +1,106 @@
+package httptransport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate function writes a self-signed certificate for the common
+// name along with its key to the files.
+func writeCertificate(t *testing.T, certFile string, keyFile string, commonName string, modTime time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{certFile, keyFile} {
+		if err := os.Chtimes(name, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func commonName(t *testing.T, cr *certificateReloader) string {
+	t.Helper()
+	cert, err := cr.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	modTime := time.Now().Add(-time.Hour)
+	writeCertificate(t, certFile, keyFile, "old.databoutique.ca", modTime)
+
+	cr, err := newCertificateReloader(slog.New(slog.NewTextHandler(io.Discard, nil)), certFile, keyFile)
+	if err != nil {
+		t.Fatalf("failed loading certificate: %v", err)
+	}
+	now := time.Now()
+	cr.now = func() time.Time { return now }
+	if got := commonName(t, cr); got != "old.databoutique.ca" {
+		t.Fatalf("expected the old certificate but got %q", got)
+	}
+
+	// The renewed certificate is served once the files were checked again.
+	writeCertificate(t, certFile, keyFile, "new.databoutique.ca", modTime.Add(time.Minute))
+	if got := commonName(t, cr); got != "old.databoutique.ca" {
+		t.Fatalf("expected the files not to be checked yet but got %q", got)
+	}
+	now = now.Add(certificateReloadInterval)
+	if got := commonName(t, cr); got != "new.databoutique.ca" {
+		t.Fatalf("expected the renewed certificate but got %q", got)
+	}
+
+	// Invalid files do not replace the current certificate.
+	if err := os.WriteFile(keyFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(certificateReloadInterval)
+	if got := commonName(t, cr); got != "new.databoutique.ca" {
+		t.Fatalf("expected the current certificate to be kept but got %q", got)
+	}
+
+	// Missing files fail on startup.
+	if _, err := newCertificateReloader(cr.Logger, filepath.Join(dir, "missing.pem"), keyFile); err == nil {
+		t.Fatal("expected missing certificate to fail")
+	}
+}
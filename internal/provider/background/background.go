@@ -0,0 +1,90 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// ErrShuttingDown is returned by `Go` when the provider is no longer
+// accepting work because `Shutdown` was called.
+var ErrShuttingDown = errors.New("background provider is shutting down")
+
+// Provider provides interface for running long-lived background jobs which
+// must be drained (and given the chance to checkpoint) on graceful shutdown.
+type Provider interface {
+	// Go runs `fn` in a new goroutine. The context passed into `fn` keeps the
+	// values of `ctx` (request ID, trace span, etc) but not its cancellation;
+	// instead it is cancelled when `Shutdown` is called so the job can
+	// checkpoint its progress and return.
+	Go(ctx context.Context, name string, fn func(ctx context.Context)) error
+
+	// Shutdown signals all running jobs to stop and waits for them to return
+	// or for `ctx` to expire, whichever happens first.
+	Shutdown(ctx context.Context) error
+}
+
+type backgroundProvider struct {
+	Logger   *slog.Logger
+	root     context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	stopping bool
+}
+
+// NewProvider constructor that returns the default background job runner.
+func NewProvider(loggerp *slog.Logger) Provider {
+	root, cancel := context.WithCancel(context.Background())
+	return &backgroundProvider{
+		Logger: loggerp,
+		root:   root,
+		cancel: cancel,
+	}
+}
+
+func (p *backgroundProvider) Go(ctx context.Context, name string, fn func(ctx context.Context)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopping {
+		p.Logger.WarnContext(ctx, "background job rejected during shutdown", slog.String("job", name))
+		return ErrShuttingDown
+	}
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(p.root, cancel)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer stop()
+		defer cancel()
+		fn(jobCtx)
+	}()
+	return nil
+}
+
+func (p *backgroundProvider) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.stopping = true
+	p.mu.Unlock()
+
+	// Signal every running job to checkpoint and return.
+	p.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.Logger.Info("background jobs drained")
+		return nil
+	case <-ctx.Done():
+		p.Logger.Warn("background jobs did not drain before deadline")
+		return ctx.Err()
+	}
+}
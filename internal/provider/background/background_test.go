@@ -0,0 +1,65 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestShutdownCancelsAndDrainsJobs(t *testing.T) {
+	p := NewProvider(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The job must keep the request values but not its cancellation.
+	reqCtx, reqCancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "req-123"))
+	started := make(chan struct{})
+	checkpointed := make(chan string, 1)
+	err := p.Go(reqCtx, "test", func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		checkpointed <- ctx.Value(ctxKey{}).(string)
+	})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	<-started
+	reqCancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	select {
+	case v := <-checkpointed:
+		if v != "req-123" {
+			t.Fatalf("expected %q but got %q", "req-123", v)
+		}
+	default:
+		t.Fatal("expected job to return before shutdown completed")
+	}
+
+	if err := p.Go(context.Background(), "late", func(ctx context.Context) {}); !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("expected %v but got %v", ErrShuttingDown, err)
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	p := NewProvider(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	release := make(chan struct{})
+	defer close(release)
+	_ = p.Go(context.Background(), "stuck", func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v but got %v", context.DeadlineExceeded, err)
+	}
+}
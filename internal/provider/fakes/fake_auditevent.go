@@ -0,0 +1,18 @@
+package fakes
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditevent_c "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
+)
+
+// AuditEvent discards the audit events.
+type AuditEvent struct {
+	auditevent_c.AuditEventController
+}
+
+func (a *AuditEvent) Record(ctx context.Context, action string, resourceType string, resourceID primitive.ObjectID, before interface{}, after interface{}) error {
+	return nil
+}
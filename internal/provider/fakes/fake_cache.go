@@ -0,0 +1,32 @@
+package fakes
+
+import (
+	"context"
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/cache/mongodbcache"
+)
+
+// Cache keeps the values in memory and ignores the expiry.
+type Cache struct {
+	mongodbcache.Cacher
+	Values map[string][]byte
+}
+
+func NewCache() *Cache {
+	return &Cache{Values: make(map[string][]byte)}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.Values[key], nil
+}
+
+func (c *Cache) SetWithExpiry(ctx context.Context, key string, val []byte, expiry time.Duration) error {
+	c.Values[key] = val
+	return nil
+}
+
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	delete(c.Values, key)
+	return nil
+}
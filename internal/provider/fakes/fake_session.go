@@ -0,0 +1,43 @@
+package fakes
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	session_s "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
+)
+
+// SessionStorer keeps the sessions in memory.
+type SessionStorer struct {
+	session_s.SessionStorer
+	Sessions map[primitive.ObjectID]*session_s.Session
+}
+
+func NewSessionStorer() *SessionStorer {
+	return &SessionStorer{Sessions: make(map[primitive.ObjectID]*session_s.Session)}
+}
+
+func (s *SessionStorer) Create(ctx context.Context, m *session_s.Session) error {
+	s.Sessions[m.ID] = m
+	return nil
+}
+
+func (s *SessionStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*session_s.Session, error) {
+	return s.Sessions[id], nil
+}
+
+func (s *SessionStorer) UpdateByID(ctx context.Context, m *session_s.Session) error {
+	s.Sessions[m.ID] = m
+	return nil
+}
+
+func (s *SessionStorer) ListActiveByUserID(ctx context.Context, userID primitive.ObjectID) (*session_s.SessionListResult, error) {
+	res := &session_s.SessionListResult{Results: []*session_s.Session{}}
+	for _, m := range s.Sessions {
+		if m.UserID == userID && m.Status == session_s.SessionStatusActive {
+			res.Results = append(res.Results, m)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,35 @@
+package fakes
+
+import (
+	"time"
+
+	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+)
+
+// TemplatedEmailer records the codes and tokens it was asked to email. If
+// `Err` is set then sending fails with it.
+type TemplatedEmailer struct {
+	templatedemailer.TemplatedEmailer
+	Err    error
+	Tokens []string
+}
+
+func (e *TemplatedEmailer) SendVerificationEmail(email, verificationCode, firstName string) error {
+	return e.send(verificationCode)
+}
+
+func (e *TemplatedEmailer) SendForgotPasswordEmail(email, verificationCode, firstName string) error {
+	return e.send(verificationCode)
+}
+
+func (e *TemplatedEmailer) SendUserInvitationEmail(email, firstName, tenantName, inviterName, token string, expiresAt time.Time) error {
+	return e.send(token)
+}
+
+func (e *TemplatedEmailer) send(token string) error {
+	if e.Err != nil {
+		return e.Err
+	}
+	e.Tokens = append(e.Tokens, token)
+	return nil
+}
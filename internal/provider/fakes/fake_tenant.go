@@ -0,0 +1,37 @@
+package fakes
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	tenant_s "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
+)
+
+// TenantStorer keeps the tenants in memory.
+type TenantStorer struct {
+	tenant_s.TenantStorer
+	Tenants map[primitive.ObjectID]*tenant_s.Tenant
+}
+
+func NewTenantStorer(tenants ...*tenant_s.Tenant) *TenantStorer {
+	s := &TenantStorer{Tenants: make(map[primitive.ObjectID]*tenant_s.Tenant)}
+	for _, t := range tenants {
+		s.Tenants[t.ID] = t
+	}
+	return s
+}
+
+func (s *TenantStorer) Create(ctx context.Context, m *tenant_s.Tenant) error {
+	s.Tenants[m.ID] = m
+	return nil
+}
+
+func (s *TenantStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*tenant_s.Tenant, error) {
+	return s.Tenants[id], nil
+}
+
+func (s *TenantStorer) UpdateByID(ctx context.Context, m *tenant_s.Tenant) error {
+	s.Tenants[m.ID] = m
+	return nil
+}
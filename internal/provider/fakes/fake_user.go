@@ -0,0 +1,63 @@
+package fakes
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	user_s "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
+)
+
+// UserStorer keeps the users in memory. The users are returned by pointer so
+// the tests can inspect the changes made by our controllers.
+type UserStorer struct {
+	user_s.UserStorer
+	Users map[primitive.ObjectID]*user_s.User
+}
+
+func NewUserStorer(users ...*user_s.User) *UserStorer {
+	s := &UserStorer{Users: make(map[primitive.ObjectID]*user_s.User)}
+	for _, u := range users {
+		s.Users[u.ID] = u
+	}
+	return s
+}
+
+func (s *UserStorer) Create(ctx context.Context, m *user_s.User) error {
+	s.Users[m.ID] = m
+	return nil
+}
+
+func (s *UserStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*user_s.User, error) {
+	return s.Users[id], nil
+}
+
+func (s *UserStorer) GetByEmail(ctx context.Context, email string) (*user_s.User, error) {
+	return s.find(func(u *user_s.User) bool { return u.Email == email }), nil
+}
+
+func (s *UserStorer) GetByVerificationCode(ctx context.Context, verificationCode string) (*user_s.User, error) {
+	return s.find(func(u *user_s.User) bool { return u.EmailVerificationCode == verificationCode }), nil
+}
+
+func (s *UserStorer) GetByPasswordResetCode(ctx context.Context, passwordResetCode string) (*user_s.User, error) {
+	return s.find(func(u *user_s.User) bool { return u.PasswordResetCode == passwordResetCode }), nil
+}
+
+func (s *UserStorer) GetByOIDCSubject(ctx context.Context, issuer string, subject string) (*user_s.User, error) {
+	return s.find(func(u *user_s.User) bool { return u.OIDCIssuer == issuer && u.OIDCSubject == subject }), nil
+}
+
+func (s *UserStorer) UpdateByID(ctx context.Context, m *user_s.User) error {
+	s.Users[m.ID] = m
+	return nil
+}
+
+func (s *UserStorer) find(match func(u *user_s.User) bool) *user_s.User {
+	for _, u := range s.Users {
+		if match(u) {
+			return u
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Package fakes provides in-memory fakes of our storers and adapters which
+// are shared by the unit tests of our controllers. The fakes embed the
+// interface they implement so the methods not overridden panic since the
+// embedded interface is nil.
+package fakes
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// NewConfig function returns the configuration used by our controller tests.
+func NewConfig() *config.Conf {
+	conf := &config.Conf{}
+	conf.AppServer.HMACSecret = []byte("test-secret")
+	return conf
+}
+
+// NewLogger function returns a logger which discards everything.
+func NewLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -11,7 +11,9 @@ import (
 type Provider interface {
 	GenerateJWTToken(uuid string, ad time.Duration) (string, time.Time, error)
 	GenerateJWTTokenPair(uuid string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error)
+	GenerateJWTTokenPairForFamily(uuid string, familyID string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, string, error)
 	ProcessJWTToken(reqToken string) (string, error)
+	ProcessJWTRefreshToken(reqToken string) (string, string, string, error)
 }
 
 type jwtProvider struct {
@@ -35,6 +37,15 @@ func (p jwtProvider) GenerateJWTTokenPair(uuid string, ad time.Duration, rd time
 	return jwt_utils.GenerateJWTTokenPair(p.hmacSecret, uuid, ad, rd)
 }
 
+// GenerateJWTTokenPairForFamily Generate the `access token` and `refresh token` where the refresh token belongs to the token family.
+func (p jwtProvider) GenerateJWTTokenPairForFamily(uuid string, familyID string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, string, error) {
+	return jwt_utils.GenerateJWTTokenPairForFamily(p.hmacSecret, uuid, familyID, ad, rd)
+}
+
 func (p jwtProvider) ProcessJWTToken(reqToken string) (string, error) {
 	return jwt_utils.ProcessJWTToken(p.hmacSecret, reqToken)
 }
+
+func (p jwtProvider) ProcessJWTRefreshToken(reqToken string) (string, string, string, error) {
+	return jwt_utils.ProcessJWTRefreshToken(p.hmacSecret, reqToken)
+}
@@ -1,11 +1,27 @@
 package jwt_utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
+const (
+	// TokenTypeAccess is the `typ` claim value of access tokens.
+	TokenTypeAccess = "access"
+
+	// TokenTypeRefresh is the `typ` claim value of refresh tokens.
+	TokenTypeRefresh = "refresh"
+)
+
+var (
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrInvalidTokenType = errors.New("invalid token type")
+)
+
 // GenerateJWTToken Generate the `access token` for the secret key.
 func GenerateJWTToken(hmacSecret []byte, uuid string, ad time.Duration) (string, time.Time, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
@@ -55,18 +71,107 @@ func GenerateJWTTokenPair(hmacSecret []byte, uuid string, ad time.Duration, rd t
 	return tokenString, expiresIn, refreshTokenString, refreshExpiresIn, nil
 }
 
-// ProcessJWTToken validates either the `access token` or `refresh token` and returns either the `uuid` if success or error on failure.
+// GenerateJWTTokenPairForFamily Generate the `access token` and `refresh token` for the secret key where
+// the refresh token belongs to the token family. Every refresh token gets a unique identifier which is
+// returned so the caller can remember which refresh token of the family is currently valid.
+func GenerateJWTTokenPairForFamily(hmacSecret []byte, uuid string, familyID string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, string, error) {
+	//
+	// Generate token.
+	//
+	token := jwt.New(jwt.SigningMethodHS256)
+	expiresIn := time.Now().Add(ad)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["session_uuid"] = uuid
+	claims["typ"] = TokenTypeAccess
+	claims["exp"] = expiresIn.Unix()
+
+	tokenString, err := token.SignedString(hmacSecret)
+	if err != nil {
+		return "", time.Now(), "", time.Now(), "", err
+	}
+
+	//
+	// Generate refresh token.
+	//
+	tokenID, err := generateTokenID()
+	if err != nil {
+		return "", time.Now(), "", time.Now(), "", err
+	}
+	refreshToken := jwt.New(jwt.SigningMethodHS256)
+	refreshExpiresIn := time.Now().Add(rd)
+	rtClaims := refreshToken.Claims.(jwt.MapClaims)
+	rtClaims["session_uuid"] = uuid
+	rtClaims["family_id"] = familyID
+	rtClaims["jti"] = tokenID
+	rtClaims["typ"] = TokenTypeRefresh
+	rtClaims["exp"] = refreshExpiresIn.Unix()
+
+	refreshTokenString, err := refreshToken.SignedString(hmacSecret)
+	if err != nil {
+		return "", time.Now(), "", time.Now(), "", err
+	}
+
+	return tokenString, expiresIn, refreshTokenString, refreshExpiresIn, tokenID, nil
+}
+
+// ProcessJWTToken validates the `access token` and returns either the `uuid` if success or error on failure.
+// Refresh tokens which belong to a token family are rejected so they cannot be used to access the API.
 func ProcessJWTToken(hmacSecret []byte, reqToken string) (string, error) {
+	claims, err := parseClaims(hmacSecret, reqToken)
+	if err != nil {
+		return "", err
+	}
+	if typ, _ := claims["typ"].(string); typ == TokenTypeRefresh {
+		return "", ErrInvalidTokenType
+	}
+	uuid, ok := claims["session_uuid"].(string)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	return uuid, nil
+}
+
+// ProcessJWTRefreshToken validates the `refresh token` and returns the `uuid`, the token family and the
+// unique identifier of the refresh token. Please note tokens issued before token families existed will
+// return empty values for the family and token identifier.
+func ProcessJWTRefreshToken(hmacSecret []byte, reqToken string) (string, string, string, error) {
+	claims, err := parseClaims(hmacSecret, reqToken)
+	if err != nil {
+		return "", "", "", err
+	}
+	if typ, _ := claims["typ"].(string); typ == TokenTypeAccess {
+		return "", "", "", ErrInvalidTokenType
+	}
+	uuid, ok := claims["session_uuid"].(string)
+	if !ok {
+		return "", "", "", ErrInvalidToken
+	}
+	familyID, _ := claims["family_id"].(string)
+	tokenID, _ := claims["jti"].(string)
+	return uuid, familyID, tokenID, nil
+}
+
+func parseClaims(hmacSecret []byte, reqToken string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(reqToken, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
 		return hmacSecret, nil
 	})
-	if err == nil && token.Valid {
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			uuid := claims["session_uuid"].(string)
-			// m["exp"] := string(claims["exp"].(float64))
-			return uuid, nil
-		}
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func generateTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
-	return "", err
+	return hex.EncodeToString(b), nil
 }
@@ -53,3 +53,121 @@ func TestProcessJWTToken(t *testing.T) {
 		t.Errorf("jwt claim is wrong, got %v but was expecting %v", actualUUID, sampleUUID)
 	}
 }
+
+func TestGenerateJWTTokenPairForFamily(t *testing.T) {
+	sampleHMACSecret := []byte("123secret")
+	sampleUUID := "xxx-xxx-xxx-xxx"
+	sampleFamilyID := "yyy-yyy-yyy-yyy"
+	sampleAccessDuration := 100 * time.Second
+	sampleRefreshDuration := sampleAccessDuration + 72*time.Hour
+
+	actualAccessToken, _, actualRefreshToken, _, actualTokenID, err := GenerateJWTTokenPairForFamily(sampleHMACSecret, sampleUUID, sampleFamilyID, sampleAccessDuration, sampleRefreshDuration)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	if actualAccessToken == "" {
+		t.Error("access token does not exist")
+	}
+	if actualRefreshToken == "" {
+		t.Error("refresh token does not exist")
+	}
+	if actualTokenID == "" {
+		t.Error("refresh token id does not exist")
+	}
+
+	actualUUID, actualFamilyID, actualRefreshTokenID, err := ProcessJWTRefreshToken(sampleHMACSecret, actualRefreshToken)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	if sampleUUID != actualUUID {
+		t.Errorf("jwt claim is wrong, got %v but was expecting %v", actualUUID, sampleUUID)
+	}
+	if sampleFamilyID != actualFamilyID {
+		t.Errorf("jwt family claim is wrong, got %v but was expecting %v", actualFamilyID, sampleFamilyID)
+	}
+	if actualTokenID != actualRefreshTokenID {
+		t.Errorf("jwt id claim is wrong, got %v but was expecting %v", actualRefreshTokenID, actualTokenID)
+	}
+}
+
+func TestGenerateJWTTokenPairForFamilyRotation(t *testing.T) {
+	sampleHMACSecret := []byte("123secret")
+	sampleFamilyID := "yyy-yyy-yyy-yyy"
+	sampleAccessDuration := 100 * time.Second
+	sampleRefreshDuration := sampleAccessDuration + 72*time.Hour
+
+	_, _, firstRefreshToken, _, firstTokenID, err := GenerateJWTTokenPairForFamily(sampleHMACSecret, "session-1", sampleFamilyID, sampleAccessDuration, sampleRefreshDuration)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	_, _, secondRefreshToken, _, secondTokenID, err := GenerateJWTTokenPairForFamily(sampleHMACSecret, "session-2", sampleFamilyID, sampleAccessDuration, sampleRefreshDuration)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	if firstTokenID == secondTokenID {
+		t.Error("rotated refresh tokens must have unique ids")
+	}
+	if firstRefreshToken == secondRefreshToken {
+		t.Error("rotated refresh tokens must be unique")
+	}
+
+	// The rotated token still parses which lets the caller detect the reuse
+	// by comparing the id against the most recent id of the family.
+	_, firstFamilyID, reusedTokenID, err := ProcessJWTRefreshToken(sampleHMACSecret, firstRefreshToken)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	if firstFamilyID != sampleFamilyID {
+		t.Errorf("jwt family claim is wrong, got %v but was expecting %v", firstFamilyID, sampleFamilyID)
+	}
+	if reusedTokenID == secondTokenID {
+		t.Error("reused refresh token must not match the most recent token id")
+	}
+}
+
+func TestProcessJWTTokenRejectsTokenType(t *testing.T) {
+	sampleHMACSecret := []byte("123secret")
+	sampleAccessDuration := 100 * time.Second
+	sampleRefreshDuration := sampleAccessDuration + 72*time.Hour
+
+	actualAccessToken, _, actualRefreshToken, _, _, err := GenerateJWTTokenPairForFamily(sampleHMACSecret, "xxx-xxx-xxx-xxx", "yyy-yyy-yyy-yyy", sampleAccessDuration, sampleRefreshDuration)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	if _, err := ProcessJWTToken(sampleHMACSecret, actualRefreshToken); err != ErrInvalidTokenType {
+		t.Errorf("refresh token must not be accepted as access token, got %v", err)
+	}
+	if _, _, _, err := ProcessJWTRefreshToken(sampleHMACSecret, actualAccessToken); err != ErrInvalidTokenType {
+		t.Errorf("access token must not be accepted as refresh token, got %v", err)
+	}
+}
+
+func TestProcessJWTRefreshTokenWithoutFamily(t *testing.T) {
+	sampleHMACSecret := []byte("123secret")
+	sampleUUID := "xxx-xxx-xxx-xxx"
+
+	_, _, actualRefreshToken, _, err := GenerateJWTTokenPair(sampleHMACSecret, sampleUUID, 100*time.Second, 72*time.Hour)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	actualUUID, actualFamilyID, actualTokenID, err := ProcessJWTRefreshToken(sampleHMACSecret, actualRefreshToken)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	if actualUUID != sampleUUID {
+		t.Errorf("jwt claim is wrong, got %v but was expecting %v", actualUUID, sampleUUID)
+	}
+	if actualFamilyID != "" || actualTokenID != "" {
+		t.Error("legacy refresh token must not have a token family")
+	}
+}
+
+func TestProcessJWTTokenWrongSecret(t *testing.T) {
+	actualAccessToken, _, _, _, _, err := GenerateJWTTokenPairForFamily([]byte("123secret"), "xxx-xxx-xxx-xxx", "yyy-yyy-yyy-yyy", 100*time.Second, 72*time.Hour)
+	if err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	if _, err := ProcessJWTToken([]byte("456secret"), actualAccessToken); err == nil {
+		t.Error("token signed with a different secret must be rejected")
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+)
+
+// RequestIDKey is the attribute key used for the request ID in log records.
+const RequestIDKey = "request_id"
+
+// contextHandler decorates a handler so records logged with a context, ex:
+// `Logger.ErrorContext(ctx, ...)`, include the request ID saved in that
+// context by our middleware and the trace ID of the current span.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String(RequestIDKey, id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// RequestIDFromContext returns the request ID of the originating API request
+// or an empty string if the context did not come from one.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(constants.SessionRequestID).(string)
+	return id
+}
+
+// FromContext returns a logger which tags every record with the request ID
+// found in the context. This is useful for background jobs which outlive the
+// request and were handed a logger instead of the request context.
+func FromContext(ctx context.Context, lg *slog.Logger) *slog.Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return lg.With(slog.String(RequestIDKey, id))
+	}
+	return lg
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+)
+
+func TestContextHandlerAddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	lg := slog.New(&contextHandler{Handler: slog.NewJSONHandler(&buf, nil)})
+
+	ctx := context.WithValue(context.Background(), constants.SessionRequestID, "req-123")
+	lg.With(slog.String("module", "test")).InfoContext(ctx, "hello")
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if rec[RequestIDKey] != "req-123" {
+		t.Fatalf("expected request id %q but got %v", "req-123", rec[RequestIDKey])
+	}
+
+	buf.Reset()
+	lg.Info("hello")
+	if bytes.Contains(buf.Bytes(), []byte(RequestIDKey)) {
+		t.Fatalf("expected no request id but got %s", buf.String())
+	}
+}
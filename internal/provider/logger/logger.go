@@ -3,21 +3,49 @@ package logger
 import (
 	"log/slog"
 	"os"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
 )
 
-func NewProvider() *slog.Logger {
+func NewProvider(appCfg *config.Conf) *slog.Logger {
 	// create a logging level variable
 	// the level is Info by default
 	var loggingLevel = new(slog.LevelVar)
 
 	// Pass the loggingLevel to the new logger being created so we can change it later at any time. Also adding source file information.
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{AddSource: true, Level: loggingLevel}))
+	opts := &slog.HandlerOptions{AddSource: true, Level: loggingLevel}
+
+	var handler slog.Handler
+	switch appCfg.Log.Format {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	default:
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	// Wrap our handler so every record logged with a context will include the
+	// request ID of the originating API request.
+	logger := slog.New(&contextHandler{Handler: handler})
 
-	// set the level to debug
-	loggingLevel.Set(slog.LevelDebug)
+	// set the level from our configuration.
+	loggingLevel.Set(parseLevel(appCfg.Log.Level))
 
-	// // Set the logger for the application
-	// slog.SetDefault(logger)
+	// Set the logger for the application so the packages without a logger
+	// injected, ex: `httperror`, log in the same format.
+	slog.SetDefault(logger)
 
 	return logger
 }
+
+func parseLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
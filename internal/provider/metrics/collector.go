@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// executableCollector counts the executables by status at scrape time so
+// the result always reflects our database instead of this process.
+type executableCollector struct {
+	desc *prometheus.Desc
+
+	mu      sync.RWMutex
+	counter func(ctx context.Context) (map[string]int64, error)
+}
+
+func (c *executableCollector) setCounter(fn func(ctx context.Context) (map[string]int64, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counter = fn
+}
+
+func (c *executableCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *executableCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	fn := c.counter
+	c.mu.RUnlock()
+	if fn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	counts, err := fn(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
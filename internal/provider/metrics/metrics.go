@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "databoutique"
+
+// UnmatchedRoute is the route label used for requests which did not match
+// any of our API endpoints, this keeps the cardinality of our labels bounded.
+const UnmatchedRoute = "unmatched"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	mongoTransactionFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mongodb_transaction_failures_total",
+		Help:      "Total number of MongoDB transactions which were aborted or failed to commit.",
+	}, []string{"reason"})
+
+	openAIRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "openai_requests_total",
+		Help:      "Total number of OpenAI API calls by operation.",
+	}, []string{"operation"})
+
+	openAIRequestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "openai_request_errors_total",
+		Help:      "Total number of OpenAI API calls which failed by operation.",
+	}, []string{"operation"})
+
+	openAIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "openai_request_duration_seconds",
+		Help:      "Latency of OpenAI API calls by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	openAIRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "openai_run_duration_seconds",
+		Help:      "Time taken by OpenAI assistant runs to reach a final status.",
+		Buckets:   []float64{15, 30, 60, 120, 300, 600, 1200, 1800},
+	}, []string{"status"})
+
+	backgroundJobQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "background_job_queue_depth",
+		Help:      "Number of background jobs waiting or running by queue.",
+	}, []string{"queue"})
+
+	executables = &executableCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "executables"),
+			"Number of executables by status.",
+			[]string{"status"}, nil,
+		),
+	}
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		mongoTransactionFailuresTotal,
+		openAIRequestsTotal,
+		openAIRequestErrorsTotal,
+		openAIRequestDuration,
+		openAIRunDuration,
+		backgroundJobQueueDepth,
+		executables,
+	)
+}
+
+// Handler returns the HTTP handler which exposes our metrics in the
+// Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records the outcome of a single HTTP request.
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequestsTotal.WithLabelValues(route, method, code).Inc()
+	httpRequestDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+// IncMongoTransactionFailure records a failed MongoDB transaction.
+func IncMongoTransactionFailure(reason string) {
+	mongoTransactionFailuresTotal.WithLabelValues(reason).Inc()
+}
+
+// ObserveOpenAIRequest records the outcome of a single OpenAI API call.
+func ObserveOpenAIRequest(operation string, duration time.Duration, failed bool) {
+	openAIRequestsTotal.WithLabelValues(operation).Inc()
+	openAIRequestDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if failed {
+		openAIRequestErrorsTotal.WithLabelValues(operation).Inc()
+	}
+}
+
+// ObserveOpenAIRun records how long an OpenAI assistant run took to finish.
+func ObserveOpenAIRun(status string, duration time.Duration) {
+	openAIRunDuration.WithLabelValues(status).Observe(duration.Seconds())
+}
+
+// IncBackgroundJob records a background job was submitted to the queue. Call
+// `DecBackgroundJob` when the job finishes.
+func IncBackgroundJob(queue string) {
+	backgroundJobQueueDepth.WithLabelValues(queue).Inc()
+}
+
+// DecBackgroundJob records a background job in the queue has finished.
+func DecBackgroundJob(queue string) {
+	backgroundJobQueueDepth.WithLabelValues(queue).Dec()
+}
+
+// SetBackgroundJobQueueDepth records the number of jobs waiting in a queue
+// which is persisted outside of this process, ex: our database.
+func SetBackgroundJobQueueDepth(queue string, depth int64) {
+	backgroundJobQueueDepth.WithLabelValues(queue).Set(float64(depth))
+}
+
+// SetExecutableStatusCounter sets the function used to count the executables
+// by status whenever our metrics are scraped.
+func SetExecutableStatusCounter(fn func(ctx context.Context) (map[string]int64, error)) {
+	executables.setCounter(fn)
+}
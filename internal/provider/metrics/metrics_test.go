@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestOpenAIOperation(t *testing.T) {
+	for path, want := range map[string]string{
+		"/v1/assistants":                                "POST /v1/assistants",
+		"/v1/threads/thread_abc123/runs":                "POST /v1/threads/{id}/runs",
+		"/v1/threads/thread_abc123/runs/run_abc123":     "POST /v1/threads/{id}/runs/{id}",
+		"/v1/threads/thread_abc123/messages/msg_abc123": "POST /v1/threads/{id}/messages/{id}",
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "https://api.openai.com"+path, nil)
+		if got := openAIOperation(req); got != want {
+			t.Fatalf("expected %q but got %q", want, got)
+		}
+	}
+}
+
+func TestRouteFromContext(t *testing.T) {
+	if got := RouteFromContext(context.Background()); got != UnmatchedRoute {
+		t.Fatalf("expected %q but got %q", UnmatchedRoute, got)
+	}
+
+	ctx := WithRoute(context.Background())
+	if got := RouteFromContext(ctx); got != UnmatchedRoute {
+		t.Fatalf("expected %q but got %q", UnmatchedRoute, got)
+	}
+	SetRoute(ctx, "/api/v1/executable/{id}")
+	if got := RouteFromContext(ctx); got != "/api/v1/executable/{id}" {
+		t.Fatalf("expected %q but got %q", "/api/v1/executable/{id}", got)
+	}
+}
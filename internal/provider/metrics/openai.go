@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// openAIResources are the path segments of the OpenAI API which are kept as
+// is in our operation label, every other segment is an identifier.
+var openAIResources = map[string]bool{
+	"v1":                  true,
+	"assistants":          true,
+	"threads":             true,
+	"messages":            true,
+	"runs":                true,
+	"steps":               true,
+	"files":               true,
+	"content":             true,
+	"cancel":              true,
+	"submit_tool_outputs": true,
+	"vector_stores":       true,
+	"file_batches":        true,
+	"chat":                true,
+	"completions":         true,
+	"embeddings":          true,
+	"models":              true,
+}
+
+// NewOpenAIOrgClient returns the same client as `openai.NewOrgClient` but
+// instrumented to record the count, latency and errors of every API call and
+// to trace every API call as a child span of the context it was called with.
+func NewOpenAIOrgClient(apiKey, orgKey string) *openai.Client {
+	config := openai.DefaultConfig(apiKey)
+	config.OrgID = orgKey
+	config.HTTPClient = &http.Client{
+		Transport: otelhttp.NewTransport(
+			&openAITransport{next: http.DefaultTransport},
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return "openai " + openAIOperation(r)
+			}),
+		),
+	}
+	return openai.NewClientWithConfig(config)
+}
+
+type openAITransport struct {
+	next http.RoundTripper
+}
+
+func (t *openAITransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation := openAIOperation(req)
+	startAt := time.Now()
+	res, err := t.next.RoundTrip(req)
+	ObserveOpenAIRequest(operation, time.Since(startAt), err != nil || res.StatusCode >= http.StatusBadRequest)
+	return res, err
+}
+
+// openAIOperation returns the method and path template of the API call, ex:
+// `POST /v1/threads/{id}/runs`.
+func openAIOperation(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, s := range segments {
+		if !openAIResources[s] {
+			segments[i] = "{id}"
+		}
+	}
+	return req.Method + " /" + strings.Join(segments, "/")
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import "context"
+
+type routeKey struct{}
+
+// WithRoute returns a context which can record the matched route of the
+// request. This must be called by our middleware before the request is
+// routed so `SetRoute` can report back the route.
+func WithRoute(ctx context.Context) context.Context {
+	route := UnmatchedRoute
+	return context.WithValue(ctx, routeKey{}, &route)
+}
+
+// SetRoute records the route template, ex: `/api/v1/executable/{id}`, which
+// matched the request. We use the template instead of the raw path so the
+// cardinality of our labels stays bounded.
+func SetRoute(ctx context.Context, route string) {
+	if r, ok := ctx.Value(routeKey{}).(*string); ok {
+		*r = route
+	}
+}
+
+// RouteFromContext returns the route recorded by `SetRoute`.
+func RouteFromContext(ctx context.Context) string {
+	if r, ok := ctx.Value(routeKey{}).(*string); ok {
+		return *r
+	}
+	return UnmatchedRoute
+}
@@ -5,17 +5,21 @@ import (
 	"log"
 	"log/slog"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 
 	c "github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/provider/metrics"
 )
 
 // NewProvider function will initiate a connection with MongoDB and supply an instance of the connection to our app. This code is useful for dependency injection.
 func NewProvider(appCfg *c.Conf, logger *slog.Logger) *mongo.Client {
 	logger.Debug("mongodb storage initializing...")
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(appCfg.DB.URI))
+	opts := options.Client().ApplyURI(appCfg.DB.URI).SetMonitor(newMonitor())
+	client, err := mongo.Connect(context.TODO(), opts)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -27,3 +31,29 @@ func NewProvider(appCfg *c.Conf, logger *slog.Logger) *mongo.Client {
 	logger.Debug("mongodb storage initialized successfully")
 	return client
 }
+
+// newMonitor function returns a command monitor which traces every operation
+// and counts the transactions that were rolled back or failed to commit.
+func newMonitor() *event.CommandMonitor {
+	// Do not record the command as it may contain sensitive user data.
+	tracer := otelmongo.NewMonitor(otelmongo.WithCommandAttributeDisabled(true))
+
+	return &event.CommandMonitor{
+		Started: tracer.Started,
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			tracer.Succeeded(ctx, e)
+			if e.CommandName == "abortTransaction" {
+				metrics.IncMongoTransactionFailure("aborted")
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			tracer.Failed(ctx, e)
+			switch e.CommandName {
+			case "commitTransaction":
+				metrics.IncMongoTransactionFailure("commit_failed")
+			case "abortTransaction":
+				metrics.IncMongoTransactionFailure("abort_failed")
+			}
+		},
+	}
+}
@@ -0,0 +1,375 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrNonceMismatch  = errors.New("id token nonce does not match")
+)
+
+// Config represents the relying party settings the tenant registered with
+// their identity provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims represents the verified claims of the `id_token` returned by the
+// identity provider.
+type Claims struct {
+	Issuer        string                 `json:"iss"`
+	Subject       string                 `json:"sub"`
+	Email         string                 `json:"email"`
+	EmailVerified bool                   `json:"email_verified"`
+	Name          string                 `json:"name"`
+	GivenName     string                 `json:"given_name"`
+	FamilyName    string                 `json:"family_name"`
+	Raw           map[string]interface{} `json:"-"`
+}
+
+// Values function returns the values of the claim as a list of strings.
+// Identity providers return roles and groups either as a single string or
+// as an array so we support both.
+func (c *Claims) Values(name string) []string {
+	switch v := c.Raw[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Provider provides an interface for the OpenID Connect authorization code
+// flow against an external identity provider.
+type Provider interface {
+	AuthCodeURL(ctx context.Context, cfg *Config, state, nonce string) (string, error)
+	Exchange(ctx context.Context, cfg *Config, code, nonce string) (*Claims, error)
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type issuerState struct {
+	discovery *discoveryDocument
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+type oidcProvider struct {
+	client       *http.Client
+	discoveryTTL time.Duration
+
+	// The minimum duration between the refreshes forced by an unknown
+	// signing key so forged tokens cannot make us hammer the issuer.
+	minRefreshInterval time.Duration
+
+	mu              sync.Mutex
+	issuers         map[string]*issuerState
+	forcedRefreshAt map[string]time.Time
+}
+
+// NewProvider constructor that returns the default OpenID Connect provider.
+func NewProvider() Provider {
+	return &oidcProvider{
+		client:             &http.Client{Timeout: 10 * time.Second},
+		discoveryTTL:       time.Hour,
+		minRefreshInterval: time.Minute,
+		issuers:            make(map[string]*issuerState),
+		forcedRefreshAt:    make(map[string]time.Time),
+	}
+}
+
+// AuthCodeURL function returns the URL of the identity provider which the
+// user must be redirected to in order to authenticate.
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, cfg *Config, state, nonce string) (string, error) {
+	is, err := p.getIssuerState(ctx, cfg.Issuer, false)
+	if err != nil {
+		return "", err
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", cfg.ClientID)
+	v.Set("redirect_uri", cfg.RedirectURL)
+	v.Set("scope", strings.Join(scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+
+	endpoint := is.discovery.AuthorizationEndpoint
+	if strings.Contains(endpoint, "?") {
+		return endpoint + "&" + v.Encode(), nil
+	}
+	return endpoint + "?" + v.Encode(), nil
+}
+
+// Exchange function trades the authorization code for the tokens and
+// returns the claims of the verified `id_token`.
+func (p *oidcProvider) Exchange(ctx context.Context, cfg *Config, code, nonce string) (*Claims, error) {
+	is, err := p.getIssuerState(ctx, cfg.Issuer, false)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, is.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tokenRes struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tokenRes); err != nil {
+		return nil, fmt.Errorf("failed decoding token response with status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tokenRes.Error != "" {
+		return nil, fmt.Errorf("token endpoint returned %d: %s %s", resp.StatusCode, tokenRes.Error, tokenRes.ErrorDescription)
+	}
+	if tokenRes.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response is missing the id_token", ErrInvalidIDToken)
+	}
+
+	return p.verifyIDToken(ctx, cfg, is, tokenRes.IDToken, nonce)
+}
+
+// verifyIDToken function checks the signature and the standard claims of
+// the `id_token` as defined in section 3.1.3.7 of the OpenID Connect spec.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, cfg *Config, is *issuerState, rawIDToken, nonce string) (*Claims, error) {
+	mapClaims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, mapClaims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		if key := findKey(is.keys, kid); key != nil {
+			return key, nil
+		}
+
+		// The identity provider may have rotated its signing keys so we
+		// refresh our copy once before giving up.
+		refreshed, err := p.getIssuerState(ctx, cfg.Issuer, true)
+		if err != nil {
+			return nil, err
+		}
+		if key := findKey(refreshed.keys, kid); key != nil {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	raw := map[string]interface{}(mapClaims)
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	claims := &Claims{Raw: raw}
+	if err := json.Unmarshal(b, claims); err != nil {
+		// Some identity providers return `email_verified` as a string.
+		claims.EmailVerified = fmt.Sprint(raw["email_verified"]) == "true"
+		claims.Issuer, _ = raw["iss"].(string)
+		claims.Subject, _ = raw["sub"].(string)
+		claims.Email, _ = raw["email"].(string)
+		claims.Name, _ = raw["name"].(string)
+		claims.GivenName, _ = raw["given_name"].(string)
+		claims.FamilyName, _ = raw["family_name"].(string)
+	}
+
+	if claims.Issuer != is.discovery.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, claims.Issuer)
+	}
+	if !hasAudience(raw["aud"], cfg.ClientID) {
+		return nil, fmt.Errorf("%w: client is not in the audience", ErrInvalidIDToken)
+	}
+	if _, ok := raw["exp"]; !ok {
+		return nil, fmt.Errorf("%w: missing expiry", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	if tokenNonce, _ := raw["nonce"].(string); tokenNonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+	return claims, nil
+}
+
+// getIssuerState function returns the discovery document and signing keys
+// of the issuer, fetching them if they were not cached or have expired. The
+// forced refreshes of an issuer are limited to one per `minRefreshInterval`.
+func (p *oidcProvider) getIssuerState(ctx context.Context, issuer string, forceRefresh bool) (*issuerState, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	p.mu.Lock()
+	is, ok := p.issuers[issuer]
+	if ok && forceRefresh {
+		if time.Since(p.forcedRefreshAt[issuer]) < p.minRefreshInterval {
+			forceRefresh = false
+		} else {
+			p.forcedRefreshAt[issuer] = time.Now()
+		}
+	}
+	p.mu.Unlock()
+	if ok && !forceRefresh && time.Since(is.fetchedAt) < p.discoveryTTL {
+		return is, nil
+	}
+
+	var doc discoveryDocument
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("failed fetching discovery document: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", doc.Issuer, issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing required endpoints")
+	}
+
+	keys, err := p.getKeys(ctx, doc.JWKSURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching signing keys: %w", err)
+	}
+
+	is = &issuerState{
+		discovery: &doc,
+		keys:      keys,
+		fetchedAt: time.Now(),
+	}
+	p.mu.Lock()
+	p.issuers[issuer] = is
+	p.mu.Unlock()
+	return is, nil
+}
+
+func (p *oidcProvider) getKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (p *oidcProvider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, u)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// findKey function returns the key by the key ID. If the token did not
+// specify a key ID then we only accept it if there is a single key.
+func findKey(keys map[string]*rsa.PublicKey, kid string) *rsa.PublicKey {
+	if key, ok := keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return nil
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
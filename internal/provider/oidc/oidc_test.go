@@ -0,0 +1,277 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// stubIdentityProvider is a minimal OpenID Connect identity provider which
+// issues an `id_token` with the claims set by the test.
+type stubIdentityProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	claims jwt.MapClaims
+
+	// tokenKid overrides the `kid` of the issued tokens if set.
+	tokenKid string
+	// keyRequests counts the requests of the signing keys.
+	keyRequests atomic.Int32
+}
+
+func newStubIdentityProvider(t *testing.T) *stubIdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed generating key: %v", err)
+	}
+	idp := &stubIdentityProvider{key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.keyRequests.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": idp.kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(idp.key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "client-123" || clientSecret != "secret-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		if r.FormValue("grant_type") != "authorization_code" || r.FormValue("code") != "code-123" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+		token.Header["kid"] = idp.kid
+		if idp.tokenKid != "" {
+			token.Header["kid"] = idp.tokenKid
+		}
+		idToken, err := token.SignedString(idp.key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-123",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	idp.claims = jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"sub":            "subject-123",
+		"aud":            []string{"client-123"},
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          "nonce-123",
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+		"groups":         []string{"engineering", "admins"},
+	}
+	return idp
+}
+
+func (idp *stubIdentityProvider) config() *Config {
+	return &Config{
+		Issuer:       idp.server.URL,
+		ClientID:     "client-123",
+		ClientSecret: "secret-123",
+		RedirectURL:  "https://app.example.com/sso/oidc/callback",
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	idp := newStubIdentityProvider(t)
+	p := NewProvider()
+
+	actualURL, err := p.AuthCodeURL(context.Background(), idp.config(), "state-123", "nonce-123")
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	u, err := url.Parse(actualURL)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !strings.HasPrefix(actualURL, idp.server.URL+"/authorize?") {
+		t.Errorf("authorization url is wrong, got %v", actualURL)
+	}
+	q := u.Query()
+	expected := map[string]string{
+		"response_type": "code",
+		"client_id":     "client-123",
+		"redirect_uri":  "https://app.example.com/sso/oidc/callback",
+		"scope":         "openid email profile",
+		"state":         "state-123",
+		"nonce":         "nonce-123",
+	}
+	for k, v := range expected {
+		if q.Get(k) != v {
+			t.Errorf("query parameter %v is wrong, got %v but was expecting %v", k, q.Get(k), v)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	idp := newStubIdentityProvider(t)
+	p := NewProvider()
+
+	claims, err := p.Exchange(context.Background(), idp.config(), "code-123", "nonce-123")
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if claims.Subject != "subject-123" {
+		t.Errorf("subject is wrong, got %v", claims.Subject)
+	}
+	if claims.Email != "jane@example.com" || !claims.EmailVerified {
+		t.Errorf("email claims are wrong, got %v %v", claims.Email, claims.EmailVerified)
+	}
+	if groups := claims.Values("groups"); len(groups) != 2 || groups[1] != "admins" {
+		t.Errorf("groups claim is wrong, got %v", groups)
+	}
+}
+
+func TestExchangeRejectsInvalidIDTokens(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(idp *stubIdentityProvider)
+		code   string
+		nonce  string
+		err    error
+	}{
+		{
+			name:   "wrong nonce",
+			modify: func(idp *stubIdentityProvider) {},
+			code:   "code-123",
+			nonce:  "another-nonce",
+			err:    ErrNonceMismatch,
+		},
+		{
+			name:   "wrong audience",
+			modify: func(idp *stubIdentityProvider) { idp.claims["aud"] = "another-client" },
+			code:   "code-123",
+			nonce:  "nonce-123",
+			err:    ErrInvalidIDToken,
+		},
+		{
+			name:   "wrong issuer",
+			modify: func(idp *stubIdentityProvider) { idp.claims["iss"] = "https://evil.example.com" },
+			code:   "code-123",
+			nonce:  "nonce-123",
+			err:    ErrInvalidIDToken,
+		},
+		{
+			name:   "expired",
+			modify: func(idp *stubIdentityProvider) { idp.claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+			code:   "code-123",
+			nonce:  "nonce-123",
+			err:    ErrInvalidIDToken,
+		},
+		{
+			name: "signed by unknown key",
+			modify: func(idp *stubIdentityProvider) {
+				idp.key, _ = rsa.GenerateKey(rand.Reader, 2048)
+				idp.kid = "key-2"
+				// The provider only cached the previous key.
+			},
+			code:  "code-123",
+			nonce: "nonce-123",
+			err:   nil, // Key rotation is supported so this must succeed.
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idp := newStubIdentityProvider(t)
+			p := NewProvider()
+
+			// Warm the cache so key rotation is exercised.
+			if _, err := p.AuthCodeURL(context.Background(), idp.config(), "state", "nonce"); err != nil {
+				t.Fatalf("received an error %v", err)
+			}
+			tc.modify(idp)
+
+			_, err := p.Exchange(context.Background(), idp.config(), tc.code, tc.nonce)
+			if tc.err == nil && err != nil {
+				t.Fatalf("received an error %v", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Fatalf("error is wrong, got %v but was expecting %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestExchangeWithWrongClientSecret(t *testing.T) {
+	idp := newStubIdentityProvider(t)
+	p := NewProvider()
+
+	cfg := idp.config()
+	cfg.ClientSecret = "wrong"
+	if _, err := p.Exchange(context.Background(), cfg, "code-123", "nonce-123"); err == nil {
+		t.Fatal("expected an error for the wrong client secret")
+	}
+}
+
+func TestExchangeThrottlesKeyRefreshes(t *testing.T) {
+	idp := newStubIdentityProvider(t)
+	p := NewProvider().(*oidcProvider)
+
+	// Tokens with unknown keys only force a single refresh per interval.
+	idp.tokenKid = "unknown"
+	for i := 0; i < 5; i++ {
+		if _, err := p.Exchange(context.Background(), idp.config(), "code-123", "nonce-123"); !errors.Is(err, ErrInvalidIDToken) {
+			t.Fatalf("error is wrong, got %v but was expecting %v", err, ErrInvalidIDToken)
+		}
+	}
+	if n := idp.keyRequests.Load(); n != 2 {
+		t.Fatalf("expected the keys to be fetched twice but got %d", n)
+	}
+
+	// The keys are refreshed again once the interval passed.
+	p.forcedRefreshAt[idp.server.URL] = time.Now().Add(-p.minRefreshInterval)
+	idp.tokenKid = ""
+	idp.key, _ = rsa.GenerateKey(rand.Reader, 2048)
+	idp.kid = "key-2"
+	if _, err := p.Exchange(context.Background(), idp.config(), "code-123", "nonce-123"); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if n := idp.keyRequests.Load(); n != 3 {
+		t.Fatalf("expected the keys to be fetched three times but got %d", n)
+	}
+}
@@ -0,0 +1,135 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Provider provides an interface for time-based one-time passwords as
+// defined in RFC 6238 and used by authenticator apps.
+type Provider interface {
+	GenerateSecret() (string, error)
+	GenerateProvisioningURI(secret, issuer, accountName string) string
+	ValidateCode(secret, code string, afterStep int64) (int64, bool)
+	GenerateRecoveryCodes(count int) ([]string, error)
+}
+
+type totpProvider struct {
+	digits int
+	period int64
+	skew   int64
+}
+
+// NewProvider constructor that returns the default TOTP provider which
+// is compatible with Google Authenticator, Authy, 1Password, etc.
+func NewProvider() Provider {
+	return &totpProvider{
+		digits: 6,
+		period: 30,
+		skew:   1, // Accept the previous and next time-step to account for clock drift.
+	}
+}
+
+// GenerateSecret function returns a random base32 encoded secret.
+func (p *totpProvider) GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
+
+// GenerateProvisioningURI function returns the `otpauth://` URI which the
+// frontend can render as a QR code for authenticator apps to scan.
+func (p *totpProvider) GenerateProvisioningURI(secret, issuer, accountName string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprintf("%d", p.digits))
+	v.Set("period", fmt.Sprintf("%d", p.period))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// ValidateCode function checks the code against the current time-step and
+// the adjacent time-steps and returns the time-step of the code. The codes of
+// the time-steps at or before `afterStep`, ex: the time-step of the last
+// accepted code, are rejected so a code cannot be replayed.
+func (p *totpProvider) ValidateCode(secret, code string, afterStep int64) (int64, bool) {
+	return p.validateCodeAt(secret, code, afterStep, time.Now())
+}
+
+func (p *totpProvider) validateCodeAt(secret, code string, afterStep int64, t time.Time) (int64, bool) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != p.digits {
+		return 0, false
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	counter := t.Unix() / p.period
+	for i := -p.skew; i <= p.skew; i++ {
+		if counter+i <= afterStep {
+			continue
+		}
+		expected := p.generateCode(key, uint64(counter+i))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter + i, true
+		}
+	}
+	return 0, false
+}
+
+// generateCode function implements the HOTP algorithm from RFC 4226.
+func (p *totpProvider) generateCode(key []byte, counter uint64) string {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(buf)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < p.digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", p.digits, value%mod)
+}
+
+// GenerateRecoveryCodes function returns single-use codes the user can
+// enter instead of a TOTP code if they lose access to their device.
+func (p *totpProvider) GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		s := hex.EncodeToString(b)
+		codes = append(codes, s[:5]+"-"+s[5:])
+	}
+	return codes, nil
+}
@@ -0,0 +1,111 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The test vectors of the SHA1 variant from RFC 6238 Appendix B.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestValidateCodeRFC6238(t *testing.T) {
+	p := &totpProvider{digits: 8, period: 30, skew: 0}
+	for _, v := range rfc6238Vectors {
+		if _, ok := p.validateCodeAt(rfc6238Secret, v.code, 0, time.Unix(v.unix, 0)); !ok {
+			t.Errorf("expected %s to be valid at %d", v.code, v.unix)
+		}
+	}
+
+	// The default provider uses the last six digits.
+	d := NewProvider().(*totpProvider)
+	for _, v := range rfc6238Vectors {
+		if _, ok := d.validateCodeAt(rfc6238Secret, v.code[2:], 0, time.Unix(v.unix, 0)); !ok {
+			t.Errorf("expected %s to be valid at %d", v.code[2:], v.unix)
+		}
+	}
+}
+
+func TestValidateCodeSkew(t *testing.T) {
+	p := NewProvider().(*totpProvider)
+	now := time.Unix(1111111111, 0)
+	code := "050471" // The code of `now`.
+
+	for _, tc := range []struct {
+		offset time.Duration
+		valid  bool
+	}{
+		{0, true},
+		{-30 * time.Second, true},
+		{30 * time.Second, true},
+		{-60 * time.Second, false},
+		{60 * time.Second, false},
+	} {
+		if _, got := p.validateCodeAt(rfc6238Secret, code, 0, now.Add(tc.offset)); got != tc.valid {
+			t.Errorf("expected the code to be valid=%v at offset %v", tc.valid, tc.offset)
+		}
+	}
+}
+
+func TestValidateCodeInvalidInput(t *testing.T) {
+	p := NewProvider().(*totpProvider)
+	now := time.Unix(1111111111, 0)
+
+	if _, ok := p.validateCodeAt(strings.ToLower(rfc6238Secret), "050 471", 0, now); !ok {
+		t.Error("expected the spaces and lower case secret to be accepted")
+	}
+	for _, code := range []string{"", "05047", "0504711", "abcdef"} {
+		if _, ok := p.validateCodeAt(rfc6238Secret, code, 0, now); ok {
+			t.Errorf("expected %q to be invalid", code)
+		}
+	}
+	if _, ok := p.validateCodeAt("not base32!", "050471", 0, now); ok {
+		t.Error("expected an invalid secret to be rejected")
+	}
+}
+
+func TestValidateCodeReplay(t *testing.T) {
+	p := NewProvider().(*totpProvider)
+	now := time.Unix(1111111111, 0)
+	code := "050471" // The code of `now`.
+
+	step, ok := p.validateCodeAt(rfc6238Secret, code, 0, now)
+	if !ok || step != 1111111111/30 {
+		t.Fatalf("expected the code of step %d but got %d %v", 1111111111/30, step, ok)
+	}
+
+	// The accepted code is rejected for the rest of its validity window.
+	for _, offset := range []time.Duration{0, 30 * time.Second} {
+		if _, ok := p.validateCodeAt(rfc6238Secret, code, step, now.Add(offset)); ok {
+			t.Errorf("expected the replayed code to be rejected at offset %v", offset)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	p := NewProvider()
+	a, err := p.GenerateSecret()
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	b, _ := p.GenerateSecret()
+	if len(a) != 32 || a == b {
+		t.Fatalf("expected unique 160-bit secrets but got %q and %q", a, b)
+	}
+	if _, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(a); err != nil {
+		t.Fatalf("expected a base32 secret but got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+)
+
+// instrumentationName is the name of the tracer used by our application code.
+const instrumentationName = "github.com/bartmika/databoutique-backend"
+
+// Provider provides interface for flushing and stopping our tracing.
+type Provider interface {
+	Shutdown(ctx context.Context) error
+}
+
+type noopProvider struct{}
+
+func (noopProvider) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// NewProvider constructor sets up OpenTelemetry tracing exported via OTLP
+// over HTTP. If no exporter endpoint was configured then tracing is a no-op.
+func NewProvider(appCfg *config.Conf, logger *slog.Logger) Provider {
+	// Always propagate the W3C trace context so our callers can correlate
+	// their traces even when we are not exporting our own.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if appCfg.Tracing.OTLPEndpoint == "" {
+		logger.Debug("tracing disabled")
+		return noopProvider{}
+	}
+
+	u, err := url.Parse(appCfg.Tracing.OTLPEndpoint)
+	if err != nil || u.Host == "" {
+		log.Fatalf("Invalid value for environment variable DATABOUTIQUE_BACKEND_OTEL_EXPORTER_OTLP_ENDPOINT: %s", appCfg.Tracing.OTLPEndpoint)
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if u.Path != "" && u.Path != "/" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(appCfg.Tracing.ServiceName))
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	logger.Debug("tracing initialized", slog.String("endpoint", appCfg.Tracing.OTLPEndpoint))
+	return tp
+}
+
+// Start function creates a span and a context containing the newly-created
+// span using our application tracer.
+func Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, spanName, opts...)
+}
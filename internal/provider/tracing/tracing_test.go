@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bartmika/databoutique-backend/internal/config"
+	"github.com/bartmika/databoutique-backend/internal/config/constants"
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
+	"github.com/bartmika/databoutique-backend/internal/provider/logger"
+)
+
+func TestNewProviderWithoutEndpoint(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(prevPropagator) })
+
+	p := NewProvider(&config.Conf{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, ok := p.(noopProvider); !ok {
+		t.Fatalf("expected a no-op provider but got %T", p)
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if otel.GetTracerProvider() != prev {
+		t.Fatal("expected the global tracer provider to be unchanged")
+	}
+
+	// The trace context of our callers is still propagated.
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	h := http.Header{}
+	h.Set("traceparent", traceparent)
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(h))
+	if sc := trace.SpanContextFromContext(ctx); !sc.IsRemote() || sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected the remote span context to be extracted but got %+v", sc)
+	}
+}
+
+func TestBackgroundJobKeepsSpanAndRequestID(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	bg := background.NewProvider(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Simulate a request which hands off work to a background job.
+	reqCtx, cancel := context.WithCancel(context.WithValue(context.Background(), constants.SessionRequestID, "req-123"))
+	reqCtx, reqSpan := Start(reqCtx, "request")
+	done := make(chan struct{})
+	var requestID string
+	err := bg.Go(reqCtx, "test", func(ctx context.Context) {
+		defer close(done)
+		_, span := Start(ctx, "job")
+		defer span.End()
+		requestID = logger.RequestIDFromContext(ctx)
+	})
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	cancel()
+	reqSpan.End()
+	<-done
+
+	// The job span is a child of the request span.
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans but got %d", len(spans))
+	}
+	var job sdktrace.ReadOnlySpan
+	for _, s := range spans {
+		if s.Name() == "job" {
+			job = s
+		}
+	}
+	if job == nil || job.Parent().SpanID() != reqSpan.SpanContext().SpanID() || job.SpanContext().TraceID() != reqSpan.SpanContext().TraceID() {
+		t.Fatalf("expected the job span to be a child of the request span but got %+v", job)
+	}
+
+	// The job logs with the request ID of the request.
+	if requestID != "req-123" {
+		t.Fatalf("expected request id %q but got %q", "req-123", requestID)
+	}
+}
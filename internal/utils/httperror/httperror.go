@@ -3,15 +3,52 @@ package httperror
 // This package introduces a new `error` type that combines an HTTP status code and a message.
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
 )
 
+// The following are the machine-readable codes of our error responses so
+// clients do not need to parse the human-readable message.
+const (
+	CodeValidation           = "validation_error"
+	CodeBadRequest           = "bad_request"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodePreconditionFailed   = "precondition_failed"
+	CodePreconditionRequired = "precondition_required"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+	CodeUnavailable          = "service_unavailable"
+)
+
+// ErrorResponse is the JSON envelope of every error returned by our API.
+type ErrorResponse struct {
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
 // HTTPError represents an http error that occurred while handling a request
 type HTTPError struct {
-	Code   int                `json:"-"` // HTTP Status code. We use `-` to skip json marshaling.
-	Errors *map[string]string `json:"-"` // The original error. Same reason as above.
+	Code      int                `json:"-"` // HTTP Status code. We use `-` to skip json marshaling.
+	Errors    *map[string]string `json:"-"` // The original error. Same reason as above.
+	ErrorCode string             `json:"-"` // Machine-readable code, defaults to the code of the HTTP status.
+	Message   string             `json:"-"` // Human-readable message, defaults to the message of the HTTP status.
 }
 
 // New creates a new HTTPError instance with a multi-field errors.
@@ -54,8 +91,36 @@ func NewForForbiddenWithSingleField(field string, message string) error {
 	}
 }
 
+// NewForNotFoundWithSingleField create a new HTTPError instance pertaining to 404 not found for a single field. This is a convinience constructor.
+func NewForNotFoundWithSingleField(field string, message string) error {
+	return HTTPError{
+		Code:   http.StatusNotFound,
+		Errors: &map[string]string{field: message},
+	}
+}
+
+// NewForUnauthorized create a new HTTPError instance pertaining to 401 unauthorized with the message. This is a convinience constructor.
+func NewForUnauthorized(message string) error {
+	return HTTPError{
+		Code:    http.StatusUnauthorized,
+		Message: message,
+	}
+}
+
+// NewWithCode create a new HTTPError instance without field errors but with the machine-readable code and the message.
+func NewWithCode(statusCode int, code string, message string) error {
+	return HTTPError{
+		Code:      statusCode,
+		ErrorCode: code,
+		Message:   message,
+	}
+}
+
 // Error function used to implement the `error` interface for returning errors.
 func (err HTTPError) Error() string {
+	if err.Errors == nil && err.Message != "" {
+		return err.Message
+	}
 	b, e := json.Marshal(err.Errors)
 	if e != nil { // Defensive code
 		return e.Error()
@@ -63,29 +128,116 @@ func (err HTTPError) Error() string {
 	return string(b)
 }
 
-// ResponseError function returns the HTTP error response based on the httpcode used.
+// ResponseError function writes the error as our JSON error envelope. The
+// internal errors are masked from the client but logged in full along with
+// the request ID so they can be correlated.
 func ResponseError(rw http.ResponseWriter, err error) {
-	// Copied from:
-	// https://dev.to/tigorlazuardi/go-creating-custom-error-wrapper-and-do-proper-error-equality-check-11k7
+	requestID := rw.Header().Get("X-Request-ID")
 
-	rw.Header().Set("Content-Type", "Application/json")
+	var ew HTTPError
+	if !errors.As(err, &ew) {
+		ew = fromInternalError(err)
+		if ew.Code == http.StatusInternalServerError {
+			slog.Error("internal error",
+				slog.String("request_id", requestID),
+				slog.Any("error", err))
+		}
+	}
 
-	//
-	// CASE 1 OF 2: Handle API Errors.
-	//
+	if ew.Code == 0 { // Defensive code
+		ew.Code = http.StatusInternalServerError
+	}
 
-	var ew HTTPError
-	if errors.As(err, &ew) {
-		rw.WriteHeader(ew.Code)
-		_ = json.NewEncoder(rw).Encode(ew.Errors)
-		return
+	res := &ErrorResponse{
+		Code:      ew.ErrorCode,
+		Message:   ew.Message,
+		RequestID: requestID,
+	}
+	if ew.Errors != nil && len(*ew.Errors) > 0 {
+		res.Fields = *ew.Errors
+	}
+	if res.Code == "" {
+		res.Code = codeForStatus(ew.Code, res.Fields)
+	}
+	if res.Message == "" {
+		res.Message = messageFor(ew.Code, res.Fields)
 	}
 
-	//
-	// CASE 2 OF 2: Handle non ErrorWrapper types.
-	//
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(ew.Code)
+	_ = json.NewEncoder(rw).Encode(res)
+}
 
-	rw.WriteHeader(http.StatusInternalServerError)
+// fromInternalError function converts the errors returned by our
+// dependencies into the HTTP error clients should see.
+func fromInternalError(err error) HTTPError {
+	var invalidByte hex.InvalidByteError
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytes):
+		return HTTPError{Code: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("the request body must not be larger than %d bytes", maxBytes.Limit)}
+	case errors.Is(err, primitive.ErrInvalidHex), errors.As(err, &invalidByte):
+		return HTTPError{Code: http.StatusBadRequest, ErrorCode: CodeBadRequest, Message: "invalid id"}
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return HTTPError{Code: http.StatusNotFound, Message: "does not exist"}
+	case errors.Is(err, versioning.ErrConflict):
+		return HTTPError{Code: http.StatusConflict, Message: err.Error()}
+	case errors.Is(err, versioning.ErrPreconditionFailed):
+		return HTTPError{Code: http.StatusPreconditionFailed, Message: err.Error()}
+	}
+	return HTTPError{Code: http.StatusInternalServerError, Message: "an internal error occurred"}
+}
 
-	_ = json.NewEncoder(rw).Encode(err.Error())
+func codeForStatus(statusCode int, fields map[string]string) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		if len(fields) > 0 {
+			return CodeValidation
+		}
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusPreconditionRequired:
+		return CodePreconditionRequired
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+	if statusCode >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// messageFor function returns the message of the response. Our errors often
+// carry their message in the `message` or `non_field_error` field so we reuse
+// it, otherwise the message is derived from the field or the HTTP status.
+func messageFor(statusCode int, fields map[string]string) string {
+	if len(fields) == 1 {
+		for key, msg := range fields {
+			if key == "message" || key == "non_field_error" {
+				return msg
+			}
+			if statusCode != http.StatusBadRequest {
+				return key + ": " + msg
+			}
+		}
+	}
+	if len(fields) > 0 {
+		return "one or more fields are invalid"
+	}
+	return http.StatusText(statusCode)
 }
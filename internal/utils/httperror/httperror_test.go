@@ -0,0 +1,126 @@
+package httperror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/bartmika/databoutique-backend/internal/utils/versioning"
+)
+
+func TestResponseError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+		wantFields map[string]string
+	}{
+		{
+			name:       "validation",
+			err:        NewForBadRequest(&map[string]string{"email": "missing value", "password": "missing value"}),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeValidation,
+			wantMsg:    "one or more fields are invalid",
+			wantFields: map[string]string{"email": "missing value", "password": "missing value"},
+		},
+		{
+			name:       "not found",
+			err:        NewForNotFoundWithSingleField("id", "does not exist"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   CodeNotFound,
+			wantMsg:    "id: does not exist",
+			wantFields: map[string]string{"id": "does not exist"},
+		},
+		{
+			name:       "message field",
+			err:        NewForForbiddenWithSingleField("message", "you do not belong to this Tenant"),
+			wantStatus: http.StatusForbidden,
+			wantCode:   CodeForbidden,
+			wantMsg:    "you do not belong to this Tenant",
+			wantFields: map[string]string{"message": "you do not belong to this Tenant"},
+		},
+		{
+			name:       "code",
+			err:        NewWithCode(http.StatusTooManyRequests, CodeRateLimited, "slow down"),
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   CodeRateLimited,
+			wantMsg:    "slow down",
+		},
+		{
+			name:       "no documents",
+			err:        mongo.ErrNoDocuments,
+			wantStatus: http.StatusNotFound,
+			wantCode:   CodeNotFound,
+			wantMsg:    "does not exist",
+		},
+		{
+			name:       "invalid id",
+			err:        primitive.ErrInvalidHex,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeBadRequest,
+			wantMsg:    "invalid id",
+		},
+		{
+			name:       "version conflict",
+			err:        versioning.ErrConflict,
+			wantStatus: http.StatusConflict,
+			wantCode:   CodeConflict,
+			wantMsg:    versioning.ErrConflict.Error(),
+		},
+		{
+			name:       "precondition failed",
+			err:        versioning.ErrPreconditionFailed,
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   CodePreconditionFailed,
+			wantMsg:    versioning.ErrPreconditionFailed.Error(),
+		},
+		{
+			name:       "internal",
+			err:        errors.New("executable does not exist for id: 123"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+			wantMsg:    "an internal error occurred",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set("X-Request-ID", "req-1")
+			ResponseError(w, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d but got %d", tt.wantStatus, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Fatalf("expected JSON content type but got %q", ct)
+			}
+			if strings.Contains(w.Body.String(), "executable") {
+				t.Fatalf("expected internal error to be masked but got %s", w.Body.String())
+			}
+
+			var res ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("failed decoding response: %v", err)
+			}
+			if res.Code != tt.wantCode || res.Message != tt.wantMsg || res.RequestID != "req-1" {
+				t.Errorf("expected %q %q req-1 but got %+v", tt.wantCode, tt.wantMsg, res)
+			}
+			if len(res.Fields) != len(tt.wantFields) {
+				t.Fatalf("expected fields %v but got %v", tt.wantFields, res.Fields)
+			}
+			for k, v := range tt.wantFields {
+				if res.Fields[k] != v {
+					t.Errorf("expected field %q=%q but got %q", k, v, res.Fields[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package versioning
+
+// This package implements the optimistic concurrency control of our records.
+// Every update increments the `version` of the record and only succeeds if
+// the record still has the version which was retrieved, so concurrent updates
+// cannot silently overwrite each other.
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrConflict is returned by our datastores if the record was updated by
+// somebody else after it was retrieved.
+var ErrConflict = errors.New("the record was modified by another request")
+
+// ErrPreconditionFailed is returned if the version of the record does not
+// match the `If-Match` header of the request.
+var ErrPreconditionFailed = errors.New("the record was modified since it was retrieved")
+
+type expectedVersionKey struct{}
+
+// Filter function returns the filter matching the record only if it still
+// has the version. Records saved before versioning was introduced do not
+// have the field and are treated as version zero.
+func Filter(id primitive.ObjectID, version int64) bson.D {
+	if version == 0 {
+		return bson.D{{"_id", id}, {"$or", bson.A{
+			bson.M{"version": 0},
+			bson.M{"version": bson.M{"$exists": false}},
+		}}}
+	}
+	return bson.D{{"_id", id}, {"version", version}}
+}
+
+// ETag function returns the `ETag` header value of the version.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseETag function returns the version of the `ETag` header value, weak
+// validators are accepted as well, ex: `W/"3"`.
+func ParseETag(etag string) (int64, bool) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64)
+	if err != nil || version < 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// WithExpectedVersion function returns the context of the request which
+// must only update the record if it has the version.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// CheckExpectedVersion function returns `ErrPreconditionFailed` if the
+// context expects another version than the current version of the record.
+func CheckExpectedVersion(ctx context.Context, current int64) error {
+	if expected, ok := ctx.Value(expectedVersionKey{}).(int64); ok && expected != current {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package versioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		etag   string
+		want   int64
+		wantOK bool
+	}{
+		{etag: ETag(0), want: 0, wantOK: true},
+		{etag: ETag(42), want: 42, wantOK: true},
+		{etag: `W/"7"`, want: 7, wantOK: true},
+		{etag: ` "3" `, want: 3, wantOK: true},
+		{etag: `3`, wantOK: false},
+		{etag: `"abc"`, wantOK: false},
+		{etag: `"-1"`, wantOK: false},
+		{etag: `"`, wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseETag(tt.etag)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParseETag(%q) = %d, %v but expected %d, %v", tt.etag, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCheckExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+	if err := CheckExpectedVersion(ctx, 5); err != nil {
+		t.Fatalf("expected no check without an expected version but got %v", err)
+	}
+
+	ctx = WithExpectedVersion(ctx, 5)
+	if err := CheckExpectedVersion(ctx, 5); err != nil {
+		t.Fatalf("expected matching version to pass but got %v", err)
+	}
+	if err := CheckExpectedVersion(ctx, 6); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected precondition failed but got %v", err)
+	}
+}
@@ -1,30 +1,60 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 	_ "time/tzdata" // Important b/c some servers don't allow access to timezone file so we need to embed it with our binary.
 
+	"go.mongodb.org/mongo-driver/mongo"
 	_ "go.uber.org/automaxprocs" // Automatically set GOMAXPROCS to match Linux container CPU quota.
 
+	am_c "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/controller"
+	executable_c "github.com/bartmika/databoutique-backend/internal/app/executable/controller"
+	webhook_c "github.com/bartmika/databoutique-backend/internal/app/webhook/controller"
 	http "github.com/bartmika/databoutique-backend/internal/inputport/httptransport"
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
 )
 
+// shutdownTimeout is how long we wait for the in-flight requests and the
+// background jobs to finish before the application exits.
+const shutdownTimeout = 30 * time.Second
+
 type Application struct {
-	Logger        *slog.Logger
-	HTTPTransport http.InputPortServer
+	Logger           *slog.Logger
+	Tracing          tracing.Provider
+	Background       background.Provider
+	DbClient         *mongo.Client
+	HTTPTransport    http.InputPortServer
+	Webhook          webhook_c.WebhookController
+	Executable       executable_c.ExecutableController
+	AssistantMessage am_c.AssistantMessageController
 }
 
 // NewApplication is application construction function which is automatically called by `Google Wire` dependency injection library.
 func NewApplication(
 	loggerp *slog.Logger,
+	tracingp tracing.Provider,
+	bg background.Provider,
+	client *mongo.Client,
 	httpTransport http.InputPortServer,
+	webhook webhook_c.WebhookController,
+	executable executable_c.ExecutableController,
+	assistantMessage am_c.AssistantMessageController,
 ) Application {
 	return Application{
-		Logger:        loggerp,
-		HTTPTransport: httpTransport,
+		Logger:           loggerp,
+		Tracing:          tracingp,
+		Background:       bg,
+		DbClient:         client,
+		HTTPTransport:    httpTransport,
+		Webhook:          webhook,
+		Executable:       executable,
+		AssistantMessage: assistantMessage,
 	}
 }
 
@@ -35,6 +65,21 @@ func (a Application) Execute() {
 	// Run in background the HTTP server.
 	go a.HTTPTransport.Run()
 
+	// Run in background the worker which delivers the outgoing webhooks.
+	ctx := context.Background()
+	if err := a.Background.Go(ctx, "webhook.delivery", a.Webhook.RunDeliveryWorker); err != nil {
+		a.Logger.Error("failed starting webhook delivery worker", slog.Any("error", err))
+	}
+
+	// Run in background the workers which resume the OpenAI runs which were
+	// interrupted, ex: by our last shutdown or the crash of another replica.
+	if err := a.Background.Go(ctx, "executable.resume", a.Executable.RunResumeWorker); err != nil {
+		a.Logger.Error("failed starting executable resume worker", slog.Any("error", err))
+	}
+	if err := a.Background.Go(ctx, "assistant_message.resume", a.AssistantMessage.RunResumeWorker); err != nil {
+		a.Logger.Error("failed starting assistant message resume worker", slog.Any("error", err))
+	}
+
 	a.Logger.Info("Application started")
 
 	// Run the main loop blocking code while other input ports run in background.
@@ -43,8 +88,36 @@ func (a Application) Execute() {
 	a.Shutdown()
 }
 
+// Shutdown function stops the application in the following order: stop
+// accepting requests and drain the in-flight requests, signal our background
+// jobs to checkpoint their progress and wait for them, flush our traces and
+// finally close our database connection.
 func (a Application) Shutdown() {
-	a.HTTPTransport.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := a.HTTPTransport.Shutdown(ctx); err != nil {
+		a.Logger.Error("failed shutting down http server", slog.Any("error", err))
+	}
+
+	if err := a.Background.Shutdown(ctx); err != nil {
+		a.Logger.Error("failed draining background jobs", slog.Any("error", err))
+	}
+
+	// DEVELOPERS NOTE: We use a separate deadline for the following so we can
+	// still flush our traces and close our database if the above timed out.
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer closeCancel()
+
+	// Flush any spans which were not exported yet.
+	if err := a.Tracing.Shutdown(closeCtx); err != nil {
+		a.Logger.Error("failed shutting down tracing", slog.Any("error", err))
+	}
+
+	if err := a.DbClient.Disconnect(closeCtx); err != nil {
+		a.Logger.Error("failed disconnecting from mongodb", slog.Any("error", err))
+	}
+
 	a.Logger.Info("Application shutdown")
 }
 
@@ -9,39 +9,57 @@ import (
 	"github.com/bartmika/databoutique-backend/internal/config"
 
 	"github.com/bartmika/databoutique-backend/internal/adapter/cache/mongodbcache"
-	"github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer"
+	"github.com/bartmika/databoutique-backend/internal/adapter/lease"
+	"github.com/bartmika/databoutique-backend/internal/adapter/ratelimiter"
 	s3_storage "github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
 
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
 	"github.com/bartmika/databoutique-backend/internal/provider/jwt"
 	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
 	"github.com/bartmika/databoutique-backend/internal/provider/logger"
 	"github.com/bartmika/databoutique-backend/internal/provider/mongodb"
+	"github.com/bartmika/databoutique-backend/internal/provider/oidc"
 	"github.com/bartmika/databoutique-backend/internal/provider/password"
+	"github.com/bartmika/databoutique-backend/internal/provider/totp"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
 
 	"github.com/bartmika/databoutique-backend/internal/provider/time"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
 
+	ds_apikey "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
 	ds_assistant "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
 	ds_assistantfile "github.com/bartmika/databoutique-backend/internal/app/assistantfile/datastore"
 	ds_assistantmessage "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/datastore"
 	ds_assistantthread "github.com/bartmika/databoutique-backend/internal/app/assistantthread/datastore"
 	ds_attachment "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
+	ds_auditevent "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	ds_idempotencykey "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/datastore"
+	ds_invitation "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	ds_webhook "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
 
 	ds_exec "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	ds_howhear "github.com/bartmika/databoutique-backend/internal/app/howhear/datastore"
 	ds_program "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	ds_programcategory "github.com/bartmika/databoutique-backend/internal/app/programcategory/datastore"
+	ds_session "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
 	ds_tenant "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	ds_uploaddirectory "github.com/bartmika/databoutique-backend/internal/app/uploaddirectory/datastore"
 	ds_uploadfile "github.com/bartmika/databoutique-backend/internal/app/uploadfile/datastore"
 	ds_user "github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 
+	uc_apikey "github.com/bartmika/databoutique-backend/internal/app/apikey/controller"
 	uc_assistant "github.com/bartmika/databoutique-backend/internal/app/assistant/controller"
 	uc_assistantfile "github.com/bartmika/databoutique-backend/internal/app/assistantfile/controller"
 	uc_assistantmessage "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/controller"
 	uc_assistantthread "github.com/bartmika/databoutique-backend/internal/app/assistantthread/controller"
 	uc_attachment "github.com/bartmika/databoutique-backend/internal/app/attachment/controller"
+	uc_auditevent "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
+	uc_health "github.com/bartmika/databoutique-backend/internal/app/health/controller"
+	uc_idempotencykey "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/controller"
+	uc_invitation "github.com/bartmika/databoutique-backend/internal/app/invitation/controller"
+	uc_webhook "github.com/bartmika/databoutique-backend/internal/app/webhook/controller"
 
 	uc_exec "github.com/bartmika/databoutique-backend/internal/app/executable/controller"
 	uc_gateway "github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
@@ -54,11 +72,16 @@ import (
 	uc_tenant "github.com/bartmika/databoutique-backend/internal/app/tenant/controller"
 	uc_user "github.com/bartmika/databoutique-backend/internal/app/user/controller"
 
+	http_apikey "github.com/bartmika/databoutique-backend/internal/app/apikey/httptransport"
 	http_assistant "github.com/bartmika/databoutique-backend/internal/app/assistant/httptransport"
 	http_assistantfile "github.com/bartmika/databoutique-backend/internal/app/assistantfile/httptransport"
 	http_assistantmessage "github.com/bartmika/databoutique-backend/internal/app/assistantmessage/httptransport"
 	http_assistantthread "github.com/bartmika/databoutique-backend/internal/app/assistantthread/httptransport"
 	http_attachment "github.com/bartmika/databoutique-backend/internal/app/attachment/httptransport"
+	http_auditevent "github.com/bartmika/databoutique-backend/internal/app/auditevent/httptransport"
+	http_health "github.com/bartmika/databoutique-backend/internal/app/health/httptransport"
+	http_invitation "github.com/bartmika/databoutique-backend/internal/app/invitation/httptransport"
+	http_webhook "github.com/bartmika/databoutique-backend/internal/app/webhook/httptransport"
 
 	http_exec "github.com/bartmika/databoutique-backend/internal/app/executable/httptransport"
 	http_gate "github.com/bartmika/databoutique-backend/internal/app/gateway/httptransport"
@@ -85,17 +108,23 @@ func InitializeEvent() Application {
 
 		// PROVIDERS SECTION
 		logger.NewProvider,
+		tracing.NewProvider,
 		uuid.NewProvider,
 		time.NewProvider,
 		jwt.NewProvider,
 		password.NewProvider,
+		totp.NewProvider,
+		oidc.NewProvider,
 		kmutex.NewProvider,
+		background.NewProvider,
 		mongodb.NewProvider,
 
 		// TODO
-		mailgun.NewEmailer,
+		emailer.NewEmailer,
 		templatedemailer.NewTemplatedEmailer,
 		mongodbcache.NewCache,
+		ratelimiter.NewLimiter,
+		lease.NewLeaser,
 		s3_storage.NewStorage,
 
 		// ADAPTERS SECTION
@@ -114,6 +143,13 @@ func InitializeEvent() Application {
 		ds_uploadfile.NewDatastore,
 		ds_program.NewDatastore,
 		ds_exec.NewDatastore,
+		ds_session.NewDatastore,
+		ds_apikey.NewDatastore,
+		ds_invitation.NewDatastore,
+		ds_webhook.NewDatastore,
+		ds_webhook.NewDeliveryDatastore,
+		ds_auditevent.NewDatastore,
+		ds_idempotencykey.NewDatastore,
 
 		// USECASE
 		uc_tenant.NewController,
@@ -130,6 +166,12 @@ func InitializeEvent() Application {
 		uc_uploadfile.NewController,
 		uc_program.NewController,
 		uc_exec.NewController,
+		uc_apikey.NewController,
+		uc_invitation.NewController,
+		uc_webhook.NewController,
+		uc_auditevent.NewController,
+		uc_health.NewController,
+		uc_idempotencykey.NewController,
 
 		// HTTP TRANSPORT SECTION
 		http_tenant.NewHandler,
@@ -146,6 +188,11 @@ func InitializeEvent() Application {
 		http_uploadfile.NewHandler,
 		http_program.NewHandler,
 		http_exec.NewHandler,
+		http_apikey.NewHandler,
+		http_invitation.NewHandler,
+		http_webhook.NewHandler,
+		http_auditevent.NewHandler,
+		http_health.NewHandler,
 
 		// INPUT PORT SECTION
 		http_middleware.NewMiddleware,
@@ -8,9 +8,14 @@ package main
 
 import (
 	"github.com/bartmika/databoutique-backend/internal/adapter/cache/mongodbcache"
-	"github.com/bartmika/databoutique-backend/internal/adapter/emailer/mailgun"
+	"github.com/bartmika/databoutique-backend/internal/adapter/emailer"
+	"github.com/bartmika/databoutique-backend/internal/adapter/lease"
+	"github.com/bartmika/databoutique-backend/internal/adapter/ratelimiter"
 	"github.com/bartmika/databoutique-backend/internal/adapter/storage/s3"
 	"github.com/bartmika/databoutique-backend/internal/adapter/templatedemailer"
+	controller15 "github.com/bartmika/databoutique-backend/internal/app/apikey/controller"
+	datastore15 "github.com/bartmika/databoutique-backend/internal/app/apikey/datastore"
+	httptransport16 "github.com/bartmika/databoutique-backend/internal/app/apikey/httptransport"
 	controller7 "github.com/bartmika/databoutique-backend/internal/app/assistant/controller"
 	datastore6 "github.com/bartmika/databoutique-backend/internal/app/assistant/datastore"
 	httptransport7 "github.com/bartmika/databoutique-backend/internal/app/assistant/httptransport"
@@ -26,20 +31,31 @@ import (
 	controller5 "github.com/bartmika/databoutique-backend/internal/app/attachment/controller"
 	datastore4 "github.com/bartmika/databoutique-backend/internal/app/attachment/datastore"
 	httptransport5 "github.com/bartmika/databoutique-backend/internal/app/attachment/httptransport"
+	controller18 "github.com/bartmika/databoutique-backend/internal/app/auditevent/controller"
+	datastore18 "github.com/bartmika/databoutique-backend/internal/app/auditevent/datastore"
+	httptransport19 "github.com/bartmika/databoutique-backend/internal/app/auditevent/httptransport"
 	controller14 "github.com/bartmika/databoutique-backend/internal/app/executable/controller"
 	datastore13 "github.com/bartmika/databoutique-backend/internal/app/executable/datastore"
 	httptransport14 "github.com/bartmika/databoutique-backend/internal/app/executable/httptransport"
 	"github.com/bartmika/databoutique-backend/internal/app/gateway/controller"
 	httptransport2 "github.com/bartmika/databoutique-backend/internal/app/gateway/httptransport"
+	controller19 "github.com/bartmika/databoutique-backend/internal/app/health/controller"
+	httptransport20 "github.com/bartmika/databoutique-backend/internal/app/health/httptransport"
 	controller4 "github.com/bartmika/databoutique-backend/internal/app/howhear/controller"
 	datastore3 "github.com/bartmika/databoutique-backend/internal/app/howhear/datastore"
 	httptransport4 "github.com/bartmika/databoutique-backend/internal/app/howhear/httptransport"
+	controller20 "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/controller"
+	datastore19 "github.com/bartmika/databoutique-backend/internal/app/idempotencykey/datastore"
+	controller16 "github.com/bartmika/databoutique-backend/internal/app/invitation/controller"
+	datastore16 "github.com/bartmika/databoutique-backend/internal/app/invitation/datastore"
+	httptransport17 "github.com/bartmika/databoutique-backend/internal/app/invitation/httptransport"
 	controller13 "github.com/bartmika/databoutique-backend/internal/app/program/controller"
 	datastore12 "github.com/bartmika/databoutique-backend/internal/app/program/datastore"
 	httptransport13 "github.com/bartmika/databoutique-backend/internal/app/program/httptransport"
 	controller10 "github.com/bartmika/databoutique-backend/internal/app/programcategory/controller"
 	datastore9 "github.com/bartmika/databoutique-backend/internal/app/programcategory/datastore"
 	httptransport10 "github.com/bartmika/databoutique-backend/internal/app/programcategory/httptransport"
+	datastore14 "github.com/bartmika/databoutique-backend/internal/app/session/datastore"
 	controller2 "github.com/bartmika/databoutique-backend/internal/app/tenant/controller"
 	datastore2 "github.com/bartmika/databoutique-backend/internal/app/tenant/datastore"
 	"github.com/bartmika/databoutique-backend/internal/app/tenant/httptransport"
@@ -52,15 +68,22 @@ import (
 	controller3 "github.com/bartmika/databoutique-backend/internal/app/user/controller"
 	"github.com/bartmika/databoutique-backend/internal/app/user/datastore"
 	httptransport3 "github.com/bartmika/databoutique-backend/internal/app/user/httptransport"
+	controller17 "github.com/bartmika/databoutique-backend/internal/app/webhook/controller"
+	datastore17 "github.com/bartmika/databoutique-backend/internal/app/webhook/datastore"
+	httptransport18 "github.com/bartmika/databoutique-backend/internal/app/webhook/httptransport"
 	"github.com/bartmika/databoutique-backend/internal/config"
 	httptransport15 "github.com/bartmika/databoutique-backend/internal/inputport/httptransport"
 	"github.com/bartmika/databoutique-backend/internal/inputport/httptransport/middleware"
+	"github.com/bartmika/databoutique-backend/internal/provider/background"
 	"github.com/bartmika/databoutique-backend/internal/provider/jwt"
 	"github.com/bartmika/databoutique-backend/internal/provider/kmutex"
 	"github.com/bartmika/databoutique-backend/internal/provider/logger"
 	"github.com/bartmika/databoutique-backend/internal/provider/mongodb"
+	"github.com/bartmika/databoutique-backend/internal/provider/oidc"
 	"github.com/bartmika/databoutique-backend/internal/provider/password"
 	"github.com/bartmika/databoutique-backend/internal/provider/time"
+	"github.com/bartmika/databoutique-backend/internal/provider/totp"
+	"github.com/bartmika/databoutique-backend/internal/provider/tracing"
 	"github.com/bartmika/databoutique-backend/internal/provider/uuid"
 )
 
@@ -72,61 +95,85 @@ import (
 // Injectors from wire.go:
 
 func InitializeEvent() Application {
-	slogLogger := logger.NewProvider()
 	conf := config.New()
+	slogLogger := logger.NewProvider(conf)
+	tracingProvider := tracing.NewProvider(conf, slogLogger)
 	provider := uuid.NewProvider()
 	timeProvider := time.NewProvider()
 	jwtProvider := jwt.NewProvider(conf)
 	passwordProvider := password.NewProvider()
+	totpProvider := totp.NewProvider()
+	oidcProvider := oidc.NewProvider()
 	kmutexProvider := kmutex.NewProvider()
+	backgroundProvider := background.NewProvider(slogLogger)
 	client := mongodb.NewProvider(conf, slogLogger)
 	cacher := mongodbcache.NewCache(conf, slogLogger, client)
-	emailer := mailgun.NewEmailer(conf, slogLogger, provider)
-	templatedEmailer := templatedemailer.NewTemplatedEmailer(conf, slogLogger, provider, emailer)
+	emailerEmailer := emailer.NewEmailer(conf, slogLogger, provider)
+	templatedEmailer := templatedemailer.NewTemplatedEmailer(conf, slogLogger, provider, emailerEmailer)
 	userStorer := datastore.NewDatastore(conf, slogLogger, client)
 	tenantStorer := datastore2.NewDatastore(conf, slogLogger, client)
 	howHearAboutUsItemStorer := datastore3.NewDatastore(conf, slogLogger, client)
-	gatewayController := controller.NewController(conf, slogLogger, provider, jwtProvider, passwordProvider, kmutexProvider, cacher, templatedEmailer, client, userStorer, tenantStorer, howHearAboutUsItemStorer)
-	middlewareMiddleware := middleware.NewMiddleware(conf, slogLogger, provider, timeProvider, jwtProvider, gatewayController)
+	sessionStorer := datastore14.NewDatastore(conf, slogLogger, client)
+	gatewayController := controller.NewController(conf, slogLogger, provider, jwtProvider, passwordProvider, totpProvider, oidcProvider, kmutexProvider, cacher, templatedEmailer, client, userStorer, tenantStorer, sessionStorer, howHearAboutUsItemStorer)
+	auditEventStorer := datastore18.NewDatastore(conf, slogLogger, client)
+	auditEventController := controller18.NewController(conf, slogLogger, auditEventStorer)
+	apiKeyStorer := datastore15.NewDatastore(conf, slogLogger, client)
+	apiKeyController := controller15.NewController(conf, slogLogger, provider, passwordProvider, kmutexProvider, client, userStorer, apiKeyStorer, auditEventController)
+	idempotencyKeyStorer := datastore19.NewDatastore(conf, slogLogger, client)
+	idempotencyKeyController := controller20.NewController(conf, slogLogger, idempotencyKeyStorer)
+	limiter := ratelimiter.NewLimiter(conf, slogLogger, client)
+	middlewareMiddleware := middleware.NewMiddleware(conf, slogLogger, provider, timeProvider, jwtProvider, gatewayController, apiKeyController, idempotencyKeyController, limiter)
 	s3Storager := s3.NewStorage(conf, slogLogger, provider)
-	tenantController := controller2.NewController(conf, slogLogger, provider, kmutexProvider, s3Storager, emailer, client, tenantStorer)
+	leaser := lease.NewLeaser(conf, slogLogger, client)
+	tenantController := controller2.NewController(conf, slogLogger, provider, kmutexProvider, s3Storager, emailerEmailer, client, tenantStorer, auditEventController)
 	handler := httptransport.NewHandler(slogLogger, tenantController)
 	httptransportHandler := httptransport2.NewHandler(slogLogger, gatewayController)
-	userController := controller3.NewController(conf, slogLogger, provider, passwordProvider, kmutexProvider, client, tenantStorer, userStorer, templatedEmailer)
+	userController := controller3.NewController(conf, slogLogger, provider, passwordProvider, kmutexProvider, client, tenantStorer, userStorer, templatedEmailer, auditEventController)
 	handler2 := httptransport3.NewHandler(slogLogger, userController)
-	howHearAboutUsItemController := controller4.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, userStorer, howHearAboutUsItemStorer)
+	howHearAboutUsItemController := controller4.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, userStorer, howHearAboutUsItemStorer, auditEventController)
 	handler3 := httptransport4.NewHandler(slogLogger, howHearAboutUsItemController)
 	attachmentStorer := datastore4.NewDatastore(conf, slogLogger, client)
-	attachmentController := controller5.NewController(conf, slogLogger, provider, s3Storager, client, emailer, attachmentStorer, userStorer)
+	attachmentController := controller5.NewController(conf, slogLogger, provider, s3Storager, client, emailerEmailer, attachmentStorer, userStorer, auditEventController)
 	handler4 := httptransport5.NewHandler(attachmentController)
 	assistantFileStorer := datastore5.NewDatastore(conf, slogLogger, client)
-	assistantFileController := controller6.NewController(conf, slogLogger, provider, s3Storager, client, emailer, tenantStorer, assistantFileStorer, userStorer)
+	assistantFileController := controller6.NewController(conf, slogLogger, provider, s3Storager, client, emailerEmailer, tenantStorer, assistantFileStorer, userStorer, auditEventController)
 	handler5 := httptransport6.NewHandler(assistantFileController)
 	assistantStorer := datastore6.NewDatastore(conf, slogLogger, client)
-	assistantController := controller7.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, client, templatedEmailer, tenantStorer, userStorer, assistantFileStorer, assistantStorer)
+	assistantController := controller7.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, client, templatedEmailer, tenantStorer, userStorer, assistantFileStorer, assistantStorer, auditEventController)
 	handler6 := httptransport7.NewHandler(slogLogger, assistantController)
 	assistantThreadStorer := datastore7.NewDatastore(conf, slogLogger, client)
 	assistantMessageStorer := datastore8.NewDatastore(conf, slogLogger, client)
-	assistantThreadController := controller8.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, client, templatedEmailer, tenantStorer, userStorer, assistantFileStorer, assistantStorer, assistantThreadStorer, assistantMessageStorer)
+	assistantThreadController := controller8.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, backgroundProvider, client, templatedEmailer, tenantStorer, userStorer, assistantFileStorer, assistantStorer, assistantThreadStorer, assistantMessageStorer, auditEventController)
 	handler7 := httptransport8.NewHandler(slogLogger, assistantThreadController)
-	assistantMessageController := controller9.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, tenantStorer, userStorer, assistantFileStorer, assistantStorer, assistantThreadStorer, assistantMessageStorer)
+	assistantMessageController := controller9.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, backgroundProvider, leaser, templatedEmailer, client, tenantStorer, userStorer, assistantFileStorer, assistantStorer, assistantThreadStorer, assistantMessageStorer, auditEventController)
 	handler8 := httptransport9.NewHandler(slogLogger, assistantMessageController)
 	programCategoryStorer := datastore9.NewDatastore(conf, slogLogger, client)
-	programCategoryController := controller10.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, userStorer, programCategoryStorer)
+	programCategoryController := controller10.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, userStorer, programCategoryStorer, auditEventController)
 	handler9 := httptransport10.NewHandler(slogLogger, programCategoryController)
 	uploadDirectoryStorer := datastore10.NewDatastore(conf, slogLogger, client)
-	uploadDirectoryController := controller11.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, userStorer, uploadDirectoryStorer)
+	uploadDirectoryController := controller11.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, userStorer, uploadDirectoryStorer, auditEventController)
 	handler10 := httptransport11.NewHandler(slogLogger, uploadDirectoryController)
 	uploadFileStorer := datastore11.NewDatastore(conf, slogLogger, client)
-	uploadFileController := controller12.NewController(conf, slogLogger, provider, s3Storager, client, emailer, tenantStorer, uploadDirectoryStorer, uploadFileStorer, userStorer)
+	webhookStorer := datastore17.NewDatastore(conf, slogLogger, client)
+	webhookDeliveryStorer := datastore17.NewDeliveryDatastore(conf, slogLogger, client)
+	webhookController := controller17.NewController(conf, slogLogger, provider, kmutexProvider, client, webhookStorer, webhookDeliveryStorer, auditEventController)
+	uploadFileController := controller12.NewController(conf, slogLogger, provider, s3Storager, client, emailerEmailer, tenantStorer, uploadDirectoryStorer, uploadFileStorer, userStorer, webhookController, auditEventController)
 	handler11 := httptransport12.NewHandler(uploadFileController)
 	programStorer := datastore12.NewDatastore(conf, slogLogger, client)
 	executableStorer := datastore13.NewDatastore(conf, slogLogger, client)
-	programController := controller13.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, tenantStorer, userStorer, uploadDirectoryStorer, uploadFileStorer, programStorer, executableStorer)
+	programController := controller13.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, backgroundProvider, templatedEmailer, client, tenantStorer, userStorer, uploadDirectoryStorer, uploadFileStorer, programStorer, executableStorer, auditEventController)
 	handler12 := httptransport13.NewHandler(slogLogger, programController)
-	executableController := controller14.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, templatedEmailer, client, tenantStorer, userStorer, uploadDirectoryStorer, uploadFileStorer, programStorer, executableStorer)
+	executableController := controller14.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, backgroundProvider, leaser, templatedEmailer, client, tenantStorer, userStorer, uploadDirectoryStorer, uploadFileStorer, programStorer, executableStorer, webhookController, auditEventController)
 	handler13 := httptransport14.NewHandler(slogLogger, executableController)
-	inputPortServer := httptransport15.NewInputPort(conf, slogLogger, middlewareMiddleware, handler, httptransportHandler, handler2, handler3, handler4, handler5, handler6, handler7, handler8, handler9, handler10, handler11, handler12, handler13)
-	application := NewApplication(slogLogger, inputPortServer)
+	handler14 := httptransport16.NewHandler(slogLogger, apiKeyController)
+	invitationStorer := datastore16.NewDatastore(conf, slogLogger, client)
+	invitationController := controller16.NewController(conf, slogLogger, provider, passwordProvider, kmutexProvider, client, templatedEmailer, userStorer, tenantStorer, invitationStorer, auditEventController)
+	handler15 := httptransport17.NewHandler(slogLogger, invitationController)
+	handler16 := httptransport18.NewHandler(slogLogger, webhookController)
+	handler17 := httptransport19.NewHandler(slogLogger, auditEventController)
+	healthController := controller19.NewController(conf, slogLogger, s3Storager, client, tenantStorer)
+	handler18 := httptransport20.NewHandler(slogLogger, healthController)
+	inputPortServer := httptransport15.NewInputPort(conf, slogLogger, middlewareMiddleware, handler, httptransportHandler, handler2, handler3, handler4, handler5, handler6, handler7, handler8, handler9, handler10, handler11, handler12, handler13, handler14, handler15, handler16, handler17, handler18)
+	application := NewApplication(slogLogger, tracingProvider, backgroundProvider, client, inputPortServer, webhookController, executableController, assistantMessageController)
 	return application
 }